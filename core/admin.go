@@ -101,17 +101,21 @@ func getAllHandlers(hoverfly *Hoverfly) []handlers.AdminHandler {
 		&v2.HoverflyModeHandler{Hoverfly: hoverfly},
 		&v2.HoverflyMiddlewareHandler{Hoverfly: hoverfly},
 		&v2.HoverflyUsageHandler{Hoverfly: hoverfly},
+		&v2.MetricsHandler{Hoverfly: hoverfly.Metrics},
 		&v2.HoverflyVersionHandler{Hoverfly: hoverfly},
 		&v2.HoverflyUpstreamProxyHandler{Hoverfly: hoverfly},
 		&v2.HoverflyPACHandler{Hoverfly: hoverfly},
 		&v2.HoverflyCORSHandler{Hoverfly: hoverfly},
 		&v2.SimulationHandler{Hoverfly: hoverfly},
+		&v2.SimulationSetsHandler{Hoverfly: hoverfly},
 		&v2.CacheHandler{Hoverfly: hoverfly},
 		&v2.LogsHandler{Hoverfly: hoverfly.StoreLogsHook},
-		&v2.JournalHandler{Hoverfly: hoverfly.Journal},
+		&v2.JournalHandler{Hoverfly: hoverfly.Journal, Version: hoverfly.version},
 		&v2.ShutdownHandler{},
 		&v2.StateHandler{Hoverfly: hoverfly},
 		&v2.DiffHandler{Hoverfly: hoverfly},
+		&v2.UnmatchedHandler{Hoverfly: hoverfly, Version: hoverfly.version},
+		&v2.SessionsHandler{Hoverfly: hoverfly, Version: hoverfly.version},
 	}
 
 	return list
@@ -24,6 +24,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 	"time"
@@ -35,9 +36,11 @@ import (
 	hvc "github.com/SpectoLabs/hoverfly/core/certs"
 	cs "github.com/SpectoLabs/hoverfly/core/cors"
 	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/journal"
 	"github.com/SpectoLabs/hoverfly/core/matching"
 	mw "github.com/SpectoLabs/hoverfly/core/middleware"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/session"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -53,7 +56,9 @@ func (i *arrayFlags) Set(value string) error {
 }
 
 var importFlags arrayFlags
+var importWatchFlags arrayFlags
 var destinationFlags arrayFlags
+var grpcDescriptorSetFlags arrayFlags
 
 const boltBackend = "boltdb"
 const inmemoryBackend = "memory"
@@ -93,6 +98,7 @@ var (
 
 	tlsVerification    = flag.Bool("tls-verification", true, "Turn on/off tls verification for outgoing requests (will not try to verify certificates)")
 	plainHttpTunneling = flag.Bool("plain-http-tunneling", false, "Use plain http tunneling to host with non-443 port")
+	http2              = flag.Bool("http2", false, "Accept HTTP/2 from clients, which gRPC needs, negotiated over TLS or as cleartext HTTP/2 on plain http tunnels and the webserver")
 
 	upstreamProxy = flag.String("upstream-proxy", "", "Specify an upstream proxy for hoverfly to route traffic through")
 	httpsOnly     = flag.Bool("https-only", false, "Allow only secure secure requests to be proxied by hoverfly")
@@ -104,10 +110,18 @@ var (
 	logsFormat = flag.String("logs", "plaintext", "Specify format for logs, options are \"plaintext\" and \"json\"")
 	logsSize   = flag.Int("logs-size", 1000, "Set the amount of logs to be stored in memory")
 
-	journalSize   = flag.Int("journal-size", 1000, "Set the size of request/response journal")
-	cacheSize     = flag.Int("cache-size", 1000, "Set the size of request/response cache")
-	cors          = flag.Bool("cors", false, "Enable CORS support")
-	noImportCheck = flag.Bool("no-import-check", false, "Skip duplicate request check when importing simulations")
+	journalSize     = flag.Int("journal-size", 1000, "Set the size of request/response journal")
+	journalDB       = flag.String("journal-db", "", "A path to a BoltDB file to keep the journal in, so that it survives restarts")
+	journalMaxBytes = flag.Int64("journal-max-bytes", 0, "Remove the oldest journal entries once their URLs, headers and bodies take up more than this many bytes (entries are only limited by journal-size by default)")
+	journalMaxAge   = flag.Duration("journal-max-age", 0, "Remove journal entries older than this, such as 24h (entries are kept until the journal is full by default)")
+	cacheSize       = flag.Int("cache-size", 1000, "Set the size of request/response cache")
+	cors            = flag.Bool("cors", false, "Enable CORS support")
+	noImportCheck   = flag.Bool("no-import-check", false, "Skip duplicate request check when importing simulations")
+
+	sessionHeader       = flag.String("session-header", "", "Header that puts requests in a session with its own simulation, state and journal, such as Hoverfly-Session. Sessions are disabled without it")
+	sessionsByProxyUser = flag.Bool("sessions-by-proxy-user", false, "Put requests without a session header in a session for the user they authenticated with the proxy as")
+	sessionsLimit       = flag.Int("sessions-limit", session.DefaultLimit, "Set the most sessions kept, the least recently used session is removed to make room for a new one, set to 0 for no limit")
+	sessionIdleTimeout  = flag.Duration("session-idle-timeout", session.DefaultIdleTimeout, "Remove sessions that have not been used for this long, set to 0 to keep them until they are deleted")
 
 	clientAuthenticationDestination = flag.String("client-authentication-destination", "", "Regular expression of destination with client authentication")
 	clientAuthenticationClientCert  = flag.String("client-authentication-client-cert", "", "Path to the client certification file used for authentication")
@@ -180,6 +194,8 @@ func main() {
 	hoverfly := hv.NewHoverfly()
 
 	flag.Var(&importFlags, "import", "Import from file or from URL (i.e. '-import my_service.json' or '-import http://mypage.com/service_x.json'")
+	flag.Var(&importWatchFlags, "import-watch", "Import from files or directories, and import again each time they change, replacing the simulation (i.e. '-import-watch simulations/' or '-import-watch my_service.json'")
+	flag.Var(&grpcDescriptorSetFlags, "grpc-descriptor-set", "Decode the messages of the gRPC methods in a descriptor set into JSON, as written by 'protoc --include_imports --descriptor_set_out' (i.e. '-grpc-descriptor-set items.pb')")
 	flag.Var(&destinationFlags, "dest", "Specify which hosts to process (i.e. '-dest fooservice.org -dest barservice.org -dest catservice.org') - other hosts will be ignored will passthrough'")
 	flag.Parse()
 	if *logsFormat == "json" {
@@ -212,7 +228,28 @@ func main() {
 	}
 
 	hoverfly.StoreLogsHook.LogsLimit = *logsSize
+	if *journalDB != "" {
+		journalStorage, err := journal.OpenBoltStorage(*journalDB)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"journal-db": *journalDB,
+			}).Fatal("Failed to open journal database")
+		}
+		defer journalStorage.Close()
+
+		hoverfly.Journal = journal.NewJournalWithStorage(journalStorage)
+		log.WithFields(log.Fields{
+			"journal-db": *journalDB,
+		}).Info("Keeping journal in BoltDB")
+	}
+
 	hoverfly.Journal.EntryLimit = *journalSize
+	hoverfly.Journal.MaxBytes = *journalMaxBytes
+	hoverfly.Journal.MaxAge = *journalMaxAge
+
+	hoverfly.Sessions.Limit = *sessionsLimit
+	hoverfly.Sessions.IdleTimeout = *sessionIdleTimeout
 
 	// getting settings
 	cfg := hv.InitSettings()
@@ -296,6 +333,20 @@ func main() {
 
 	cfg.HttpsOnly = *httpsOnly
 	cfg.PlainHttpTunneling = *plainHttpTunneling
+	cfg.Http2 = *http2
+
+	for _, descriptorSet := range grpcDescriptorSetFlags {
+		data, err := ioutil.ReadFile(descriptorSet)
+		if err == nil {
+			err = hoverfly.GrpcDescriptors.Add(data)
+		}
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":               err.Error(),
+				"grpc-descriptor-set": descriptorSet,
+			}).Fatal("Failed to load gRPC descriptor set")
+		}
+	}
 
 	if *cors {
 		cfg.CORS = *cs.DefaultCORSConfigs()
@@ -307,6 +358,12 @@ func main() {
 		log.Info("Import check has been disabled")
 	}
 
+	cfg.SessionHeader = *sessionHeader
+	cfg.SessionsByProxyUser = *sessionsByProxyUser
+	if *sessionsByProxyUser && !*authEnabled {
+		log.Warn("Sessions by proxy user need authentication to be enabled with -auth")
+	}
+
 	cfg.ClientAuthenticationDestination = *clientAuthenticationDestination
 	cfg.ClientAuthenticationClientCert = *clientAuthenticationClientCert
 	cfg.ClientAuthenticationClientKey = *clientAuthenticationClientKey
@@ -471,6 +528,20 @@ func main() {
 		}
 	}
 
+	if len(importWatchFlags) > 0 {
+		if len(importFlags) > 0 {
+			log.Fatal("-import and -import-watch cannot be used together, as the watched files replace the whole simulation")
+		}
+
+		err := hoverfly.WatchImports(importWatchFlags)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":        err.Error(),
+				"import-watch": importWatchFlags,
+			}).Fatal("Failed to import given resource")
+		}
+	}
+
 	// start metrics registry flush
 	if *metrics {
 		hoverfly.Counter.Init()
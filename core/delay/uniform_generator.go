@@ -0,0 +1,33 @@
+package delay
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var uniformRandom = rand.New(rand.NewSource(time.Now().UnixNano()))
+var uniformRandomMutex sync.Mutex
+
+type UniformGenerator struct {
+	Min int
+	Max int
+}
+
+func NewUniformGenerator(min int, max int) *UniformGenerator {
+	return &UniformGenerator{
+		Min: min,
+		Max: max,
+	}
+}
+
+func (g *UniformGenerator) GenerateDelay() int {
+	if g.Max <= g.Min {
+		return g.Min
+	}
+
+	uniformRandomMutex.Lock()
+	defer uniformRandomMutex.Unlock()
+
+	return g.Min + uniformRandom.Intn(g.Max-g.Min+1)
+}
@@ -0,0 +1,29 @@
+package delay
+
+import (
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestUniformGenerator_GenerateDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	gen := NewUniformGenerator(100, 200)
+
+	seen := map[int]bool{}
+	for i := 0; i < 1000; i++ {
+		delay := gen.GenerateDelay()
+		Expect(delay).To(BeNumerically(">=", 100))
+		Expect(delay).To(BeNumerically("<=", 200))
+		seen[delay] = true
+	}
+
+	Expect(len(seen)).To(BeNumerically(">", 1), "generated values must vary")
+}
+
+func TestUniformGenerator_GenerateDelay_ReturnsMinIfMaxIsNotGreater(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(NewUniformGenerator(100, 100).GenerateDelay()).To(Equal(100))
+	Expect(NewUniformGenerator(100, 50).GenerateDelay()).To(Equal(100))
+}
@@ -4,6 +4,8 @@ import "github.com/SpectoLabs/hoverfly/core/models"
 
 type HoverflyError struct {
 	Message string
+	// ClosestMiss is the pair that came closest when matching failed, if any
+	ClosestMiss *models.ClosestMiss
 }
 
 func (err HoverflyError) Error() string {
@@ -29,7 +31,8 @@ func MatchingFailedError(closestMiss *models.ClosestMiss) *HoverflyError {
 		message = message + closestMiss.GetMessage()
 	}
 	return &HoverflyError{
-		Message: message,
+		Message:     message,
+		ClosestMiss: closestMiss,
 	}
 }
 
@@ -0,0 +1,147 @@
+package hoverfly
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+	log "github.com/sirupsen/logrus"
+)
+
+type responseWriterKey struct{}
+
+// withResponseWriter makes the response writer available from the request context,
+// so that an injected fault can flush what has been written before breaking the response
+func withResponseWriter(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), responseWriterKey{}, w)))
+	})
+}
+
+// injectFault makes a simulated response misbehave when it is written to the client.
+// Connection resets and empty responses close the client connection straight away,
+// the other faults replace the response body with one that breaks or slows down the
+// response while it is being written.
+func (hf *Hoverfly) injectFault(request *http.Request, response *http.Response, fault *models.ResponseFault) {
+	conn := hf.getClientConn(request)
+
+	if fault.ClosesConnection() {
+		if conn == nil {
+			log.WithField("remoteAddr", request.RemoteAddr).Warn("Unable to inject fault, client connection not found")
+			return
+		}
+		if fault.ConnectionReset {
+			resetConn(conn)
+		}
+		conn.Close()
+		return
+	}
+
+	breakAt := -1
+	if fault.TruncateBodyAt > 0 {
+		breakAt = fault.TruncateBodyAt
+	} else if fault.MalformedChunked {
+		breakAt = int(response.ContentLength) / 2
+	}
+
+	if fault.MalformedChunked {
+		response.Header.Del("Content-Length")
+		response.Header.Set("Transfer-Encoding", "chunked")
+		response.ContentLength = -1
+	}
+
+	flusher, _ := request.Context().Value(responseWriterKey{}).(http.Flusher)
+
+	response.Body = &faultyBody{
+		body:    response.Body,
+		fault:   fault,
+		breakAt: breakAt,
+		conn:    conn,
+		flusher: flusher,
+	}
+}
+
+func (hf *Hoverfly) getClientConn(request *http.Request) net.Conn {
+	if hf.SL == nil {
+		return nil
+	}
+
+	return hf.SL.GetConn(request.RemoteAddr)
+}
+
+// resetConn makes closing the connection send a TCP RST rather than a FIN
+func resetConn(conn net.Conn) {
+	if tracked, ok := conn.(*trackedConn); ok {
+		conn = tracked.Conn
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		tcpConn.SetLinger(0)
+	}
+}
+
+type faultyBody struct {
+	body    io.ReadCloser
+	fault   *models.ResponseFault
+	breakAt int
+	written int
+	conn    net.Conn
+	flusher http.Flusher
+}
+
+func (b *faultyBody) Read(p []byte) (int, error) {
+	// Push what has been written so far to the client, otherwise it would sit in the
+	// response buffer until the whole response has been written
+	if b.flusher != nil && b.written > 0 {
+		b.flusher.Flush()
+	}
+
+	if b.breakAt >= 0 && b.written >= b.breakAt {
+		return 0, b.breakResponse()
+	}
+
+	max := len(p)
+	if b.breakAt >= 0 && b.breakAt-b.written < max {
+		max = b.breakAt - b.written
+	}
+
+	rate := b.fault.BandwidthBytesPerSec
+	if rate > 0 {
+		// Drip the body out in tenths of a second
+		chunk := rate / 10
+		if chunk < 1 {
+			chunk = 1
+		}
+		if chunk < max {
+			max = chunk
+		}
+	}
+
+	n, err := b.body.Read(p[:max])
+	if rate > 0 && n > 0 {
+		time.Sleep(time.Duration(n) * time.Second / time.Duration(rate))
+	}
+	b.written += n
+
+	return n, err
+}
+
+func (b *faultyBody) Close() error {
+	return b.body.Close()
+}
+
+// breakResponse stops the response part way through. A truncated body is left
+// short of its Content-Length. A malformed chunked body is followed by an invalid
+// chunk size when the connection is plain HTTP, otherwise the terminating chunk is
+// never written.
+func (b *faultyBody) breakResponse() error {
+	if b.fault.MalformedChunked && b.flusher != nil && b.conn != nil {
+		b.conn.Write([]byte("zz\r\n"))
+		b.conn.Close()
+	}
+
+	return io.ErrUnexpectedEOF
+}
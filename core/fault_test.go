@@ -0,0 +1,145 @@
+package hoverfly
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+// startHoverflyWithResponse starts Hoverfly on the port, simulating the response for requests to the path
+func startHoverflyWithResponse(port string, webserver bool, path string, response models.ResponseDetails) *Hoverfly {
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Cfg.ProxyPort = port
+	unit.Cfg.Webserver = webserver
+	unit.Cfg.SetMode("simulate")
+
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   path,
+				},
+			},
+		},
+		Response: response,
+	})
+
+	Expect(unit.StartProxy()).To(BeNil())
+
+	return unit
+}
+
+func faultyResponse(fault *models.ResponseFault) models.ResponseDetails {
+	return models.ResponseDetails{
+		Status: 200,
+		Body:   "0123456789",
+		Fault:  fault,
+	}
+}
+
+func Test_Hoverfly_Webserver_ConnectionResetFault_ClosesConnectionWithoutResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9781", true, "/fault", faultyResponse(&models.ResponseFault{ConnectionReset: true}))
+	defer unit.StopProxy()
+
+	_, err := http.Get("http://localhost:9781/fault")
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_Hoverfly_Webserver_EmptyResponseFault_ClosesConnectionWithoutResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9782", true, "/fault", faultyResponse(&models.ResponseFault{EmptyResponse: true}))
+	defer unit.StopProxy()
+
+	_, err := http.Get("http://localhost:9782/fault")
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("EOF"))
+}
+
+func Test_Hoverfly_Webserver_TruncateBodyAtFault_EndsBodyEarly(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9783", true, "/fault", faultyResponse(&models.ResponseFault{TruncateBodyAt: 4}))
+	defer unit.StopProxy()
+
+	response, err := http.Get("http://localhost:9783/fault")
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(200))
+	Expect(response.ContentLength).To(Equal(int64(10)))
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).ToNot(BeNil())
+	Expect(string(body)).To(Equal("0123"))
+}
+
+func Test_Hoverfly_Webserver_MalformedChunkedFault_BreaksChunkedBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9784", true, "/fault", faultyResponse(&models.ResponseFault{MalformedChunked: true}))
+	defer unit.StopProxy()
+
+	response, err := http.Get("http://localhost:9784/fault")
+	Expect(err).To(BeNil())
+	Expect(response.TransferEncoding).To(Equal([]string{"chunked"}))
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).ToNot(BeNil())
+	Expect(string(body)).To(Equal("01234"))
+}
+
+func Test_Hoverfly_Webserver_BandwidthFault_SlowsDownBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9785", true, "/fault", faultyResponse(&models.ResponseFault{BandwidthBytesPerSec: 20}))
+	defer unit.StopProxy()
+
+	start := time.Now()
+	response, err := http.Get("http://localhost:9785/fault")
+	Expect(err).To(BeNil())
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("0123456789"))
+	Expect(time.Since(start)).To(BeNumerically(">=", 450*time.Millisecond))
+}
+
+func Test_Hoverfly_Proxy_TruncateBodyAtFault_EndsBodyEarly(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9786", false, "/fault", faultyResponse(&models.ResponseFault{TruncateBodyAt: 4}))
+	defer unit.StopProxy()
+
+	proxyUrl, _ := url.Parse(fmt.Sprintf("http://localhost:%s", unit.Cfg.ProxyPort))
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyUrl)}}
+
+	response, err := client.Get("http://hoverfly.io/fault")
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(200))
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).ToNot(BeNil())
+	Expect(string(body)).To(Equal("0123"))
+}
+
+func Test_Hoverfly_Proxy_ConnectionResetFault_ClosesConnectionWithoutResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9787", false, "/fault", faultyResponse(&models.ResponseFault{ConnectionReset: true}))
+	defer unit.StopProxy()
+
+	proxyUrl, _ := url.Parse(fmt.Sprintf("http://localhost:%s", unit.Cfg.ProxyPort))
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyUrl)}}
+
+	_, err := client.Get("http://hoverfly.io/fault")
+	Expect(err).ToNot(BeNil())
+}
@@ -0,0 +1,438 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Protobuf field types, as numbered in FieldDescriptorProto
+const (
+	typeDouble   = 1
+	typeFloat    = 2
+	typeInt64    = 3
+	typeUint64   = 4
+	typeInt32    = 5
+	typeFixed64  = 6
+	typeFixed32  = 7
+	typeBool     = 8
+	typeString   = 9
+	typeMessage  = 11
+	typeBytes    = 12
+	typeUint32   = 13
+	typeEnum     = 14
+	typeSfixed32 = 15
+	typeSfixed64 = 16
+	typeSint32   = 17
+	typeSint64   = 18
+)
+
+const labelRepeated = 3
+
+// Descriptors holds the services and messages of registered descriptor sets, so that
+// the messages sent to their methods can be decoded. A descriptor set is a serialized
+// FileDescriptorSet, as written by protoc with --descriptor_set_out and --include_imports.
+type Descriptors struct {
+	mutex    sync.RWMutex
+	messages map[string]*messageDescriptor
+	enums    map[string]map[int32]string
+	methods  map[string]string
+}
+
+type messageDescriptor struct {
+	fields   map[int]fieldDescriptor
+	mapEntry bool
+}
+
+type fieldDescriptor struct {
+	jsonName string
+	kind     int
+	typeName string
+	repeated bool
+}
+
+func NewDescriptors() *Descriptors {
+	return &Descriptors{
+		messages: map[string]*messageDescriptor{},
+		enums:    map[string]map[int32]string{},
+		methods:  map[string]string{},
+	}
+}
+
+// Add registers the services and messages of a descriptor set. Nothing is registered
+// if the descriptor set cannot be read.
+func (this *Descriptors) Add(descriptorSet []byte) error {
+	files, err := readFields(descriptorSet)
+	if err != nil {
+		return fmt.Errorf("Descriptor set is not a FileDescriptorSet: %s", err.Error())
+	}
+
+	added := NewDescriptors()
+	for _, file := range files {
+		if file.number != 1 || file.wireType != wireBytes {
+			continue
+		}
+		if err := added.addFile(file.bytes); err != nil {
+			return fmt.Errorf("Descriptor set is not a FileDescriptorSet: %s", err.Error())
+		}
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for name, message := range added.messages {
+		this.messages[name] = message
+	}
+	for name, enum := range added.enums {
+		this.enums[name] = enum
+	}
+	for name, inputType := range added.methods {
+		this.methods[name] = inputType
+	}
+
+	return nil
+}
+
+// DecodeRequest decodes a message sent to a method into its JSON form, with the field
+// names, 64-bit integers as strings, bytes in base64 and enums by name, as in the
+// proto3 JSON mapping
+func (this *Descriptors) DecodeRequest(service, method string, message []byte) (string, error) {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	inputType, found := this.methods[service+"/"+method]
+	if !found {
+		return "", fmt.Errorf("%s/%s is not a registered gRPC method", service, method)
+	}
+
+	decoded, err := this.decodeMessage(inputType, message)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func (this *Descriptors) addFile(data []byte) error {
+	fields, err := readFields(data)
+	if err != nil {
+		return err
+	}
+
+	var packageName string
+	for _, field := range fields {
+		if field.number == 2 && field.wireType == wireBytes {
+			packageName = string(field.bytes)
+		}
+	}
+
+	for _, field := range fields {
+		if field.wireType != wireBytes {
+			continue
+		}
+
+		switch field.number {
+		case 4:
+			err = this.addMessage(packageName, field.bytes)
+		case 5:
+			err = this.addEnum(packageName, field.bytes)
+		case 6:
+			err = this.addService(packageName, field.bytes)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *Descriptors) addMessage(scope string, data []byte) error {
+	fields, err := readFields(data)
+	if err != nil {
+		return err
+	}
+
+	name := fullName(scope, getString(fields, 1))
+	message := &messageDescriptor{fields: map[int]fieldDescriptor{}}
+	this.messages[name] = message
+
+	for _, field := range fields {
+		if field.wireType != wireBytes {
+			continue
+		}
+
+		switch field.number {
+		case 2:
+			err = message.addField(field.bytes)
+		case 3:
+			err = this.addMessage(name, field.bytes)
+		case 4:
+			err = this.addEnum(name, field.bytes)
+		case 7:
+			var options []wireField
+			if options, err = readFields(field.bytes); err == nil {
+				message.mapEntry = getVarint(options, 7) != 0
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *messageDescriptor) addField(data []byte) error {
+	fields, err := readFields(data)
+	if err != nil {
+		return err
+	}
+
+	name := getString(fields, 1)
+	jsonName := getString(fields, 10)
+	if jsonName == "" {
+		jsonName = toJsonName(name)
+	}
+
+	this.fields[int(getVarint(fields, 3))] = fieldDescriptor{
+		jsonName: jsonName,
+		kind:     int(getVarint(fields, 5)),
+		typeName: strings.TrimPrefix(getString(fields, 6), "."),
+		repeated: getVarint(fields, 4) == labelRepeated,
+	}
+	return nil
+}
+
+func (this *Descriptors) addEnum(scope string, data []byte) error {
+	fields, err := readFields(data)
+	if err != nil {
+		return err
+	}
+
+	values := map[int32]string{}
+	this.enums[fullName(scope, getString(fields, 1))] = values
+
+	for _, field := range fields {
+		if field.number != 2 || field.wireType != wireBytes {
+			continue
+		}
+
+		valueFields, err := readFields(field.bytes)
+		if err != nil {
+			return err
+		}
+		values[int32(getVarint(valueFields, 2))] = getString(valueFields, 1)
+	}
+	return nil
+}
+
+func (this *Descriptors) addService(scope string, data []byte) error {
+	fields, err := readFields(data)
+	if err != nil {
+		return err
+	}
+
+	service := fullName(scope, getString(fields, 1))
+	for _, field := range fields {
+		if field.number != 2 || field.wireType != wireBytes {
+			continue
+		}
+
+		methodFields, err := readFields(field.bytes)
+		if err != nil {
+			return err
+		}
+		this.methods[service+"/"+getString(methodFields, 1)] = strings.TrimPrefix(getString(methodFields, 2), ".")
+	}
+	return nil
+}
+
+func (this *Descriptors) decodeMessage(typeName string, data []byte) (map[string]interface{}, error) {
+	message, found := this.messages[typeName]
+	if !found {
+		return nil, fmt.Errorf("%s is not a registered protobuf message", typeName)
+	}
+
+	fields, err := readFields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := map[string]interface{}{}
+	for _, field := range fields {
+		// Fields that are not in the descriptor are skipped, as they are by protobuf itself
+		descriptor, found := message.fields[field.number]
+		if !found {
+			continue
+		}
+
+		values := []wireField{field}
+		if wireType, packable := packedWireType(descriptor.kind); packable && field.wireType == wireBytes {
+			if values, err = readPacked(field.number, wireType, field.bytes); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, value := range values {
+			if err := this.decodeField(decoded, descriptor, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return decoded, nil
+}
+
+func (this *Descriptors) decodeField(decoded map[string]interface{}, descriptor fieldDescriptor, field wireField) error {
+	value, err := this.decodeValue(descriptor, field)
+	if err != nil {
+		return fmt.Errorf("Field %s cannot be decoded: %s", descriptor.jsonName, err.Error())
+	}
+
+	if entryType, isMap := this.messages[descriptor.typeName]; isMap && entryType.mapEntry && descriptor.kind == typeMessage {
+		entries, _ := decoded[descriptor.jsonName].(map[string]interface{})
+		if entries == nil {
+			entries = map[string]interface{}{}
+			decoded[descriptor.jsonName] = entries
+		}
+
+		entry := value.(map[string]interface{})
+		key := entry["key"]
+		if key == nil {
+			key = ""
+		}
+		entries[fmt.Sprint(key)] = entry["value"]
+		return nil
+	}
+
+	if descriptor.repeated {
+		values, _ := decoded[descriptor.jsonName].([]interface{})
+		decoded[descriptor.jsonName] = append(values, value)
+		return nil
+	}
+
+	decoded[descriptor.jsonName] = value
+	return nil
+}
+
+func (this *Descriptors) decodeValue(descriptor fieldDescriptor, field wireField) (interface{}, error) {
+	expectedWireType, _ := packedWireType(descriptor.kind)
+	if descriptor.kind == typeString || descriptor.kind == typeBytes || descriptor.kind == typeMessage {
+		expectedWireType = wireBytes
+	}
+	if field.wireType != expectedWireType {
+		return nil, fmt.Errorf("wire type %d does not match its type", field.wireType)
+	}
+
+	value := field.value
+	switch descriptor.kind {
+	case typeDouble:
+		return jsonFloat(math.Float64frombits(value)), nil
+	case typeFloat:
+		return jsonFloat(float64(math.Float32frombits(uint32(value)))), nil
+	case typeInt64, typeSfixed64:
+		return strconv.FormatInt(int64(value), 10), nil
+	case typeUint64, typeFixed64:
+		return strconv.FormatUint(value, 10), nil
+	case typeSint64:
+		return strconv.FormatInt(int64(value>>1)^-int64(value&1), 10), nil
+	case typeInt32, typeSfixed32:
+		return int32(value), nil
+	case typeUint32, typeFixed32:
+		return uint32(value), nil
+	case typeSint32:
+		return int32(value>>1) ^ -int32(value&1), nil
+	case typeBool:
+		return value != 0, nil
+	case typeEnum:
+		if name, found := this.enums[descriptor.typeName][int32(value)]; found {
+			return name, nil
+		}
+		return int32(value), nil
+	case typeString:
+		return string(field.bytes), nil
+	case typeBytes:
+		return base64.StdEncoding.EncodeToString(field.bytes), nil
+	case typeMessage:
+		return this.decodeMessage(descriptor.typeName, field.bytes)
+	}
+	return nil, fmt.Errorf("type %d is not supported", descriptor.kind)
+}
+
+// packedWireType returns the wire type of the values of a field type, when a repeated
+// field of that type can be packed
+func packedWireType(kind int) (int, bool) {
+	switch kind {
+	case typeInt64, typeUint64, typeInt32, typeBool, typeUint32, typeEnum, typeSint32, typeSint64:
+		return wireVarint, true
+	case typeDouble, typeFixed64, typeSfixed64:
+		return wireFixed64, true
+	case typeFloat, typeFixed32, typeSfixed32:
+		return wireFixed32, true
+	}
+	return -1, false
+}
+
+// jsonFloat returns the strings that JSON uses for the floats it cannot represent as numbers
+func jsonFloat(value float64) interface{} {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "Infinity"
+	case math.IsInf(value, -1):
+		return "-Infinity"
+	}
+	return value
+}
+
+func fullName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// toJsonName converts a field name to lower camel case, for descriptors without JSON names
+func toJsonName(name string) string {
+	var jsonName []byte
+	upper := false
+	for i := 0; i < len(name); i++ {
+		if name[i] == '_' {
+			upper = true
+			continue
+		}
+		if upper && 'a' <= name[i] && name[i] <= 'z' {
+			jsonName = append(jsonName, name[i]-'a'+'A')
+		} else {
+			jsonName = append(jsonName, name[i])
+		}
+		upper = false
+	}
+	return string(jsonName)
+}
+
+func getString(fields []wireField, number int) string {
+	var value string
+	for _, field := range fields {
+		if field.number == number && field.wireType == wireBytes {
+			value = string(field.bytes)
+		}
+	}
+	return value
+}
+
+func getVarint(fields []wireField, number int) uint64 {
+	var value uint64
+	for _, field := range fields {
+		if field.number == number && field.wireType == wireVarint {
+			value = field.value
+		}
+	}
+	return value
+}
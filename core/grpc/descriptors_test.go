@@ -0,0 +1,123 @@
+package grpc_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/grpc"
+	. "github.com/onsi/gomega"
+)
+
+func varintField(number int, value uint64) []byte {
+	return append(uvarint(uint64(number)<<3), uvarint(value)...)
+}
+
+func bytesField(number int, value []byte) []byte {
+	field := append(uvarint(uint64(number)<<3|2), uvarint(uint64(len(value)))...)
+	return append(field, value...)
+}
+
+func fixed64Field(number int, value uint64) []byte {
+	field := make([]byte, 8)
+	binary.LittleEndian.PutUint64(field, value)
+	return append(uvarint(uint64(number)<<3|1), field...)
+}
+
+func uvarint(value uint64) []byte {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	return encoded[:binary.PutUvarint(encoded, value)]
+}
+
+func join(fields ...[]byte) []byte {
+	var message []byte
+	for _, field := range fields {
+		message = append(message, field...)
+	}
+	return message
+}
+
+func newItemsDescriptors() *grpc.Descriptors {
+	descriptorSet, err := ioutil.ReadFile("testdata/items.pb")
+	Expect(err).To(BeNil())
+
+	descriptors := grpc.NewDescriptors()
+	Expect(descriptors.Add(descriptorSet)).To(Succeed())
+	return descriptors
+}
+
+func Test_Descriptors_DecodeRequest_DecodesMessageIntoJson(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newItemsDescriptors()
+
+	item := join(
+		bytesField(1, []byte("book")),
+		varintField(2, 1000),
+		bytesField(3, append(uvarint(1), uvarint(2)...)),
+		varintField(4, 1),
+		bytesField(5, join(bytesField(1, []byte("colour")), bytesField(2, []byte("red")))),
+		bytesField(6, []byte{0, 1}),
+		varintField(7, 3),
+		fixed64Field(8, math.Float64bits(1.5)),
+		varintField(9, 1),
+	)
+	message := join(bytesField(1, []byte("1")), bytesField(2, item))
+
+	decoded, err := unit.DecodeRequest("shop.Items", "GetItem", message)
+	Expect(err).To(BeNil())
+	Expect(decoded).To(MatchJSON(`{
+		"id": "1",
+		"item": {
+			"name": "book",
+			"price": "1000",
+			"sizes": [1, 2],
+			"kind": "BOOK",
+			"labels": {"colour": "red"},
+			"data": "AAE=",
+			"offset": -2,
+			"weight": 1.5,
+			"inStock": true
+		}
+	}`))
+}
+
+func Test_Descriptors_DecodeRequest_SkipsUnknownFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newItemsDescriptors()
+
+	decoded, err := unit.DecodeRequest("shop.Items", "GetItem", join(bytesField(1, []byte("1")), varintField(15, 1)))
+	Expect(err).To(BeNil())
+	Expect(decoded).To(Equal(`{"id":"1"}`))
+}
+
+func Test_Descriptors_DecodeRequest_ReturnsErrorForUnknownMethods(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newItemsDescriptors()
+
+	_, err := unit.DecodeRequest("shop.Items", "DeleteItem", []byte{})
+	Expect(err).To(MatchError("shop.Items/DeleteItem is not a registered gRPC method"))
+}
+
+func Test_Descriptors_DecodeRequest_ReturnsErrorForMalformedMessages(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newItemsDescriptors()
+
+	_, err := unit.DecodeRequest("shop.Items", "GetItem", []byte{0x0a, 0x05, 'a'})
+	Expect(err).ToNot(BeNil())
+
+	_, err = unit.DecodeRequest("shop.Items", "GetItem", varintField(1, 1))
+	Expect(err).To(MatchError("Field id cannot be decoded: wire type 0 does not match its type"))
+}
+
+func Test_Descriptors_Add_ReturnsErrorForInvalidDescriptorSets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := grpc.NewDescriptors()
+
+	Expect(unit.Add([]byte("not a descriptor set"))).ToNot(Succeed())
+}
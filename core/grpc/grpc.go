@@ -0,0 +1,100 @@
+// Package grpc reads gRPC calls, so that Hoverfly can match them on the service and
+// method they call and on the message they send.
+package grpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	ContentType = "application/grpc"
+
+	// Trailers that carry the status of a call
+	StatusTrailer  = "Grpc-Status"
+	MessageTrailer = "Grpc-Message"
+
+	encodingHeader = "Grpc-Encoding"
+	prefixLength   = 5
+)
+
+// IsGrpc returns true if the headers are those of a gRPC call. gRPC-Web calls are not
+// included, as they are not sent over HTTP/2.
+func IsGrpc(headers map[string][]string) bool {
+	for _, contentType := range headers["Content-Type"] {
+		if contentType == ContentType || strings.HasPrefix(contentType, ContentType+"+") || strings.HasPrefix(contentType, ContentType+";") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMethodPath splits the path of a gRPC call, such as "/helloworld.Greeter/SayHello",
+// into the full name of its service and the name of its method
+func ParseMethodPath(path string) (string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("%s is not the path of a gRPC method", path)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ReadMessages splits the body of a gRPC request or response into its length-prefixed
+// messages. Compressed messages are only supported for the gzip encoding.
+func ReadMessages(body []byte, headers map[string][]string) ([][]byte, error) {
+	var messages [][]byte
+	for len(body) > 0 {
+		if len(body) < prefixLength {
+			return nil, errors.New("gRPC message is missing its length prefix")
+		}
+
+		compressed := body[0] == 1
+		length := binary.BigEndian.Uint32(body[1:prefixLength])
+		if uint32(len(body)-prefixLength) < length {
+			return nil, errors.New("gRPC message is shorter than its length prefix")
+		}
+
+		message := body[prefixLength : prefixLength+int(length)]
+		body = body[prefixLength+int(length):]
+
+		if compressed {
+			var err error
+			if message, err = decompress(message, headers); err != nil {
+				return nil, err
+			}
+		}
+
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+func decompress(message []byte, headers map[string][]string) ([]byte, error) {
+	encoding := ""
+	if values := headers[encodingHeader]; len(values) > 0 {
+		encoding = values[0]
+	}
+	if encoding != "gzip" {
+		return nil, fmt.Errorf("gRPC message encoding %q is not supported", encoding)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(message))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return ioutil.ReadAll(reader)
+}
+
+// WriteMessage adds the length prefix of an uncompressed gRPC message
+func WriteMessage(message []byte) []byte {
+	framed := make([]byte, prefixLength, prefixLength+len(message))
+	binary.BigEndian.PutUint32(framed[1:], uint32(len(message)))
+	return append(framed, message...)
+}
@@ -0,0 +1,84 @@
+package grpc_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/grpc"
+	. "github.com/onsi/gomega"
+)
+
+func Test_IsGrpc_ReturnsTrueForGrpcContentTypes(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(grpc.IsGrpc(map[string][]string{"Content-Type": {"application/grpc"}})).To(BeTrue())
+	Expect(grpc.IsGrpc(map[string][]string{"Content-Type": {"application/grpc+proto"}})).To(BeTrue())
+}
+
+func Test_IsGrpc_ReturnsFalseForOtherContentTypes(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(grpc.IsGrpc(nil)).To(BeFalse())
+	Expect(grpc.IsGrpc(map[string][]string{"Content-Type": {"application/json"}})).To(BeFalse())
+	Expect(grpc.IsGrpc(map[string][]string{"Content-Type": {"application/grpc-web"}})).To(BeFalse())
+}
+
+func Test_ParseMethodPath_ReturnsServiceAndMethod(t *testing.T) {
+	RegisterTestingT(t)
+
+	service, method, err := grpc.ParseMethodPath("/shop.Items/GetItem")
+	Expect(err).To(BeNil())
+	Expect(service).To(Equal("shop.Items"))
+	Expect(method).To(Equal("GetItem"))
+}
+
+func Test_ParseMethodPath_ReturnsErrorForOtherPaths(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, path := range []string{"/", "/shop.Items", "/shop.Items/", "/shop/Items/GetItem"} {
+		_, _, err := grpc.ParseMethodPath(path)
+		Expect(err).ToNot(BeNil(), path)
+	}
+}
+
+func Test_ReadMessages_SplitsLengthPrefixedMessages(t *testing.T) {
+	RegisterTestingT(t)
+
+	body := append(grpc.WriteMessage([]byte("one")), grpc.WriteMessage([]byte{})...)
+
+	messages, err := grpc.ReadMessages(body, nil)
+	Expect(err).To(BeNil())
+	Expect(messages).To(Equal([][]byte{[]byte("one"), {}}))
+}
+
+func Test_ReadMessages_DecompressesGzipMessages(t *testing.T) {
+	RegisterTestingT(t)
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte("one"))
+	writer.Close()
+
+	body := grpc.WriteMessage(compressed.Bytes())
+	body[0] = 1
+
+	messages, err := grpc.ReadMessages(body, map[string][]string{"Grpc-Encoding": {"gzip"}})
+	Expect(err).To(BeNil())
+	Expect(messages).To(Equal([][]byte{[]byte("one")}))
+
+	_, err = grpc.ReadMessages(body, map[string][]string{"Grpc-Encoding": {"snappy"}})
+	Expect(err).To(MatchError(`gRPC message encoding "snappy" is not supported`))
+}
+
+func Test_ReadMessages_ReturnsErrorForTruncatedMessages(t *testing.T) {
+	RegisterTestingT(t)
+
+	body := grpc.WriteMessage([]byte("one"))
+
+	_, err := grpc.ReadMessages(body[:3], nil)
+	Expect(err).ToNot(BeNil())
+
+	_, err = grpc.ReadMessages(body[:len(body)-1], nil)
+	Expect(err).ToNot(BeNil())
+}
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errMalformed = errors.New("protobuf message is malformed")
+
+// wireField is a field read from a protobuf message, without knowing its type. The value
+// of varint and fixed size fields is held as it was read, and length-delimited fields
+// hold their bytes.
+type wireField struct {
+	number   int
+	wireType int
+	value    uint64
+	bytes    []byte
+}
+
+func readFields(data []byte) ([]wireField, error) {
+	var fields []wireField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errMalformed
+		}
+
+		field := wireField{number: int(key >> 3), wireType: int(key & 7)}
+		var err error
+		if data, err = readValue(&field, data[n:]); err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// readPacked reads the values of a packed repeated field, which all have the given wire type
+func readPacked(number, wireType int, data []byte) ([]wireField, error) {
+	var fields []wireField
+	for len(data) > 0 {
+		field := wireField{number: number, wireType: wireType}
+		var err error
+		if data, err = readValue(&field, data); err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// readValue reads the value of the field from the start of the data, and returns the rest
+func readValue(field *wireField, data []byte) ([]byte, error) {
+	switch field.wireType {
+	case wireVarint:
+		value, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errMalformed
+		}
+		field.value = value
+		return data[n:], nil
+	case wireFixed64:
+		if len(data) < 8 {
+			return nil, errMalformed
+		}
+		field.value = binary.LittleEndian.Uint64(data)
+		return data[8:], nil
+	case wireFixed32:
+		if len(data) < 4 {
+			return nil, errMalformed
+		}
+		field.value = uint64(binary.LittleEndian.Uint32(data))
+		return data[4:], nil
+	case wireBytes:
+		length, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < length {
+			return nil, errMalformed
+		}
+		field.bytes = data[n : n+int(length)]
+		return data[n+int(length):], nil
+	}
+	return nil, fmt.Errorf("protobuf wire type %d is not supported", field.wireType)
+}
@@ -0,0 +1,158 @@
+package hoverfly
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/grpc"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/http2"
+)
+
+// getItemRequest is a call to shop.Items/GetItem, from grpc/testdata/items.proto, for item "1"
+var getItemRequest = grpc.WriteMessage([]byte{0x0a, 0x01, '1'})
+
+func newHttp2ProxyClient(proxyServer *httptest.Server) *http.Client {
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	transport := &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	Expect(http2.ConfigureTransport(transport)).To(Succeed())
+	return &http.Client{Transport: transport}
+}
+
+func newGrpcRequest(destination string) *http.Request {
+	request, err := http.NewRequest(http.MethodPost, destination+"/shop.Items/GetItem", bytes.NewReader(getItemRequest))
+	Expect(err).To(BeNil())
+	request.Header.Set("Content-Type", grpc.ContentType)
+	request.Header.Set("Te", "trailers")
+	return request
+}
+
+func Test_NewProxy_SimulatesGrpcCallsOverHttp2(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{Http2: true})
+	unit.Cfg.SetMode("simulate")
+
+	descriptorSet, err := ioutil.ReadFile("grpc/testdata/items.pb")
+	Expect(err).To(BeNil())
+	Expect(unit.GrpcDescriptors.Add(descriptorSet)).To(Succeed())
+
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Grpc: &models.GrpcRequestMatcher{
+				Service: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "shop.Items"}},
+				Method:  []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "GetItem"}},
+				Message: []models.RequestFieldMatchers{{Matcher: matchers.Json, Value: `{"id": "1"}`}},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: http.StatusOK,
+			Body:   "response",
+			Headers: map[string][]string{
+				"Content-Type": {grpc.ContentType},
+				"Trailer":      {grpc.StatusTrailer},
+				"Grpc-Status":  {"0"},
+			},
+		},
+	})
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	response, err := newHttp2ProxyClient(proxyServer).Do(newGrpcRequest("https://test.com"))
+	Expect(err).To(BeNil())
+	Expect(response.ProtoMajor).To(Equal(2))
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(response.Header.Get("Content-Type")).To(Equal(grpc.ContentType))
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("response"))
+	Expect(response.Trailer.Get(grpc.StatusTrailer)).To(Equal("0"))
+}
+
+func Test_NewProxy_CapturesGrpcCallsOverHttp2(t *testing.T) {
+	RegisterTestingT(t)
+
+	destination := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", grpc.ContentType)
+		w.Header().Set("Trailer", grpc.StatusTrailer)
+		w.Write([]byte("response"))
+		w.Header().Set(grpc.StatusTrailer, "0")
+	}))
+	Expect(http2.ConfigureServer(destination.Config, nil)).To(Succeed())
+	destination.TLS = destination.Config.TLSConfig
+	destination.StartTLS()
+	defer destination.Close()
+
+	unit := NewHoverflyWithConfiguration(&Configuration{Http2: true})
+	unit.Cfg.SetMode("capture")
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	response, err := newHttp2ProxyClient(proxyServer).Do(newGrpcRequest(destination.URL))
+	Expect(err).To(BeNil())
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("response"))
+	Expect(response.Trailer.Get(grpc.StatusTrailer)).To(Equal("0"))
+
+	pairs := unit.Simulation.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(1))
+	Expect(pairs[0].RequestMatcher.Body).To(BeNil())
+	Expect(pairs[0].RequestMatcher.Grpc).To(Equal(&models.GrpcRequestMatcher{
+		Service: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "shop.Items"}},
+		Method:  []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "GetItem"}},
+		Message: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "CgEx"}},
+	}))
+	Expect(pairs[0].Response.Headers).To(HaveKeyWithValue("Trailer", []string{grpc.StatusTrailer}))
+	Expect(pairs[0].Response.Headers).To(HaveKeyWithValue(grpc.StatusTrailer, []string{"0"}))
+}
+
+func Test_NewWebserverProxy_SimulatesGrpcCallsOverCleartextHttp2(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{Webserver: true, Http2: true})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Grpc: &models.GrpcRequestMatcher{
+				Message: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "CgEx"}},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: http.StatusOK,
+			Body:   "response",
+			Headers: map[string][]string{
+				"Trailer":     {grpc.StatusTrailer},
+				"Grpc-Status": {"0"},
+			},
+		},
+	})
+	unit.Proxy = NewWebserverProxy(unit)
+
+	webserver := httptest.NewServer(unit.newProxyHandler())
+	defer webserver.Close()
+
+	client := &http.Client{Transport: h2cTransport}
+	response, err := client.Do(newGrpcRequest(webserver.URL))
+	Expect(err).To(BeNil())
+	Expect(response.ProtoMajor).To(Equal(2))
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("response"))
+	Expect(response.Trailer.Get(grpc.StatusTrailer)).To(Equal("0"))
+}
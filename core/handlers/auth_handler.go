@@ -56,6 +56,7 @@ func (a *AuthHandler) RequireTokenAuthentication(w http.ResponseWriter, req *htt
 		if len(authorizationValue) > 6 && strings.ToUpper(authorizationValue[0:7]) == "BEARER " {
 			if authentication.IsJwtTokenValid(authorizationValue[7:], a.AB, a.SecretKey, a.JWTExpirationDelta) {
 				next(w, req)
+				return
 			}
 		}
 	}
@@ -83,22 +83,42 @@ func detectContentType(body []byte) string {
 
 type WebSocketHandler func() ([]byte, error)
 
-func NewWebsocket(handler WebSocketHandler, w http.ResponseWriter, r *http.Request) {
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
 
-	var wsUpgrader = websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		CheckOrigin: func(r *http.Request) bool {
-			return true
-		},
-	}
+// sameOriginWsUpgrader leaves the origin check to the default, which refuses pages from other origins
+var sameOriginWsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
 
-	conn, err := wsUpgrader.Upgrade(w, r, nil)
+// UpgradeWebsocket upgrades the request to a WebSocket connection, as long as it comes from a client without an
+// origin or from the same origin as the request, logging any failure
+func UpgradeWebsocket(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
+	return upgradeWebsocket(&sameOriginWsUpgrader, w, r)
+}
+
+func upgradeWebsocket(upgrader *websocket.Upgrader, w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
+	conn, err := upgrader.Upgrade(w, r, nil)
 
 	if err != nil {
 		log.WithFields(log.Fields{
 			"error": err,
 		}).Error("failed to upgrade websocket")
+	}
+
+	return conn, err
+}
+
+func NewWebsocket(handler WebSocketHandler, w http.ResponseWriter, r *http.Request) {
+
+	conn, err := upgradeWebsocket(&wsUpgrader, w, r)
+	if err != nil {
 		return
 	}
 
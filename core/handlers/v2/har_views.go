@@ -0,0 +1,216 @@
+package v2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SpectoLabs/hoverfly/core/util"
+)
+
+const HARVersion = "1.2"
+
+// HARView is an HTTP Archive, the format browsers and many other tools use to
+// import and export HTTP traffic. See http://www.softwareishard.com/blog/har-12-spec/
+type HARView struct {
+	Log *HARLogView `json:"log"`
+}
+
+type HARLogView struct {
+	Version string         `json:"version"`
+	Creator HARCreatorView `json:"creator"`
+	Entries []HAREntryView `json:"entries"`
+}
+
+type HARCreatorView struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntryView struct {
+	StartedDateTime string          `json:"startedDateTime"`
+	Time            float64         `json:"time"`
+	Request         HARRequestView  `json:"request"`
+	Response        HARResponseView `json:"response"`
+	Cache           struct{}        `json:"cache"`
+	Timings         HARTimingsView  `json:"timings"`
+}
+
+type HARRequestView struct {
+	Method      string             `json:"method"`
+	URL         string             `json:"url"`
+	HTTPVersion string             `json:"httpVersion"`
+	Cookies     []HARNameValueView `json:"cookies"`
+	Headers     []HARNameValueView `json:"headers"`
+	QueryString []HARNameValueView `json:"queryString"`
+	PostData    *HARPostDataView   `json:"postData,omitempty"`
+	HeadersSize int                `json:"headersSize"`
+	BodySize    int                `json:"bodySize"`
+}
+
+type HARResponseView struct {
+	Status      int                `json:"status"`
+	StatusText  string             `json:"statusText"`
+	HTTPVersion string             `json:"httpVersion"`
+	Cookies     []HARNameValueView `json:"cookies"`
+	Headers     []HARNameValueView `json:"headers"`
+	Content     HARContentView     `json:"content"`
+	RedirectURL string             `json:"redirectURL"`
+	HeadersSize int                `json:"headersSize"`
+	BodySize    int                `json:"bodySize"`
+}
+
+type HARNameValueView struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARPostDataView struct {
+	MimeType string             `json:"mimeType"`
+	Text     string             `json:"text"`
+	Params   []HARNameValueView `json:"params,omitempty"`
+}
+
+type HARContentView struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type HARTimingsView struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NewHARViewFromJournalEntries builds an HTTP Archive with an entry for each journal entry
+func NewHARViewFromJournalEntries(journalEntries []JournalEntryView, version string) HARView {
+	entries := []HAREntryView{}
+
+	for _, journalEntry := range journalEntries {
+		entries = append(entries, HAREntryView{
+			StartedDateTime: journalEntry.TimeStarted,
+			Time:            journalEntry.Latency,
+			Request:         newHARRequestView(journalEntry.Request),
+			Response:        newHARResponseView(journalEntry.Response),
+			Timings: HARTimingsView{
+				Wait: journalEntry.Latency,
+			},
+		})
+	}
+
+	return HARView{
+		Log: &HARLogView{
+			Version: HARVersion,
+			Creator: HARCreatorView{
+				Name:    "Hoverfly",
+				Version: version,
+			},
+			Entries: entries,
+		},
+	}
+}
+
+func newHARRequestView(request RequestDetailsView) HARRequestView {
+	requestURL := util.PointerToString(request.Scheme) + "://" + util.PointerToString(request.Destination) + util.PointerToString(request.Path)
+	if query := util.PointerToString(request.Query); query != "" {
+		requestURL += "?" + query
+	}
+
+	queryString := []HARNameValueView{}
+	query, _ := url.ParseQuery(util.PointerToString(request.Query))
+	for _, name := range sortedKeys(query) {
+		for _, value := range query[name] {
+			queryString = append(queryString, HARNameValueView{Name: name, Value: value})
+		}
+	}
+
+	harRequest := HARRequestView{
+		Method:      util.PointerToString(request.Method),
+		URL:         requestURL,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValueView{},
+		Headers:     newHARHeaders(request.Headers),
+		QueryString: queryString,
+		HeadersSize: -1,
+		BodySize:    len(util.PointerToString(request.Body)),
+	}
+
+	if body := util.PointerToString(request.Body); body != "" {
+		harRequest.PostData = &HARPostDataView{
+			MimeType: http.Header(request.Headers).Get("Content-Type"),
+			Text:     body,
+		}
+	}
+
+	return harRequest
+}
+
+// newHARResponseView converts a journal response. The content of an HTTP Archive holds
+// the decoded body, so a gzipped body is decompressed, and anything that is still not
+// text is left base64 encoded
+func newHARResponseView(response ResponseDetailsView) HARResponseView {
+	headers := http.Header(response.Headers)
+
+	body := []byte(response.Body)
+	if response.EncodedBody {
+		body, _ = base64.StdEncoding.DecodeString(response.Body)
+	}
+	bodySize := len(body)
+
+	if strings.Contains(headers.Get("Content-Encoding"), "gzip") {
+		if reader, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			if decompressed, err := ioutil.ReadAll(reader); err == nil {
+				body = decompressed
+			}
+		}
+	}
+
+	content := HARContentView{
+		Size:     len(body),
+		MimeType: headers.Get("Content-Type"),
+		Text:     string(body),
+	}
+	if !utf8.Valid(body) {
+		content.Text = base64.StdEncoding.EncodeToString(body)
+		content.Encoding = "base64"
+	}
+
+	return HARResponseView{
+		Status:      response.Status,
+		StatusText:  http.StatusText(response.Status),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValueView{},
+		Headers:     newHARHeaders(response.Headers),
+		Content:     content,
+		RedirectURL: headers.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    bodySize,
+	}
+}
+
+func newHARHeaders(headers map[string][]string) []HARNameValueView {
+	harHeaders := []HARNameValueView{}
+	for _, name := range sortedKeys(headers) {
+		for _, value := range headers[name] {
+			harHeaders = append(harHeaders, HARNameValueView{Name: name, Value: value})
+		}
+	}
+	return harHeaders
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,114 @@
+package v2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/util"
+	. "github.com/onsi/gomega"
+)
+
+func Test_NewHARViewFromJournalEntries_ConvertsRequestsAndResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHARViewFromJournalEntries([]JournalEntryView{
+		{
+			Request: RequestDetailsView{
+				Method:      util.StringToPointer("POST"),
+				Scheme:      util.StringToPointer("http"),
+				Destination: util.StringToPointer("test.com"),
+				Path:        util.StringToPointer("/users"),
+				Query:       util.StringToPointer("b=2&a=1"),
+				Body:        util.StringToPointer(`{"name": "Ben"}`),
+				Headers: map[string][]string{
+					"Content-Type": {"application/json"},
+					"Accept":       {"text/plain", "application/json"},
+				},
+			},
+			Response: ResponseDetailsView{
+				Status: 302,
+				Body:   "moved",
+				Headers: map[string][]string{
+					"Location": {"http://test.com/other"},
+				},
+			},
+			Mode:        "simulate",
+			TimeStarted: "2018-01-01T10:00:00.000Z",
+			Latency:     12.5,
+		},
+	}, "v1.0.0")
+
+	Expect(unit.Log.Version).To(Equal("1.2"))
+	Expect(unit.Log.Creator.Version).To(Equal("v1.0.0"))
+	Expect(unit.Log.Entries).To(HaveLen(1))
+
+	entry := unit.Log.Entries[0]
+	Expect(entry.StartedDateTime).To(Equal("2018-01-01T10:00:00.000Z"))
+	Expect(entry.Time).To(Equal(12.5))
+	Expect(entry.Timings.Wait).To(Equal(12.5))
+
+	Expect(entry.Request.Method).To(Equal("POST"))
+	Expect(entry.Request.URL).To(Equal("http://test.com/users?b=2&a=1"))
+	Expect(entry.Request.QueryString).To(Equal([]HARNameValueView{{"a", "1"}, {"b", "2"}}))
+	Expect(entry.Request.Headers).To(Equal([]HARNameValueView{
+		{"Accept", "text/plain"},
+		{"Accept", "application/json"},
+		{"Content-Type", "application/json"},
+	}))
+	Expect(entry.Request.PostData).To(Equal(&HARPostDataView{MimeType: "application/json", Text: `{"name": "Ben"}`}))
+	Expect(entry.Request.BodySize).To(Equal(15))
+
+	Expect(entry.Response.Status).To(Equal(302))
+	Expect(entry.Response.StatusText).To(Equal("Found"))
+	Expect(entry.Response.RedirectURL).To(Equal("http://test.com/other"))
+	Expect(entry.Response.Content.Text).To(Equal("moved"))
+	Expect(entry.Response.Content.Encoding).To(BeEmpty())
+	Expect(entry.Response.Content.Size).To(Equal(5))
+}
+
+func Test_NewHARViewFromJournalEntries_DecompressesGzippedResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	writer.Write([]byte("hello world"))
+	writer.Close()
+
+	unit := NewHARViewFromJournalEntries([]JournalEntryView{
+		{
+			Response: ResponseDetailsView{
+				Status:      200,
+				Body:        base64.StdEncoding.EncodeToString(gzipped.Bytes()),
+				EncodedBody: true,
+				Headers: map[string][]string{
+					"Content-Encoding": {"gzip"},
+				},
+			},
+		},
+	}, "")
+
+	content := unit.Log.Entries[0].Response.Content
+	Expect(content.Text).To(Equal("hello world"))
+	Expect(content.Size).To(Equal(11))
+	Expect(unit.Log.Entries[0].Response.BodySize).To(Equal(gzipped.Len()))
+}
+
+func Test_NewHARViewFromJournalEntries_KeepsBinaryResponsesEncoded(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHARViewFromJournalEntries([]JournalEntryView{
+		{
+			Response: ResponseDetailsView{
+				Status:      200,
+				Body:        base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00}),
+				EncodedBody: true,
+			},
+		},
+	}, "")
+
+	content := unit.Log.Entries[0].Response.Content
+	Expect(content.Text).To(Equal(base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00})))
+	Expect(content.Encoding).To(Equal("base64"))
+}
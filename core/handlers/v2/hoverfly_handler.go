@@ -23,6 +23,7 @@ type Hoverfly interface {
 	ClearState()
 	GetUpstreamProxy() string
 	IsWebServer() bool
+	GetImportWatch() *ImportWatchView
 }
 
 type HoverflyHandler struct {
@@ -51,6 +52,7 @@ func (this *HoverflyHandler) Get(w http.ResponseWriter, req *http.Request, next
 	hoverflyView.Version = this.Hoverfly.GetVersion()
 	hoverflyView.UpstreamProxy = this.Hoverfly.GetUpstreamProxy()
 	hoverflyView.IsWebServer = this.Hoverfly.IsWebServer()
+	hoverflyView.ImportWatch = this.Hoverfly.GetImportWatch()
 
 	bytes, _ := json.Marshal(hoverflyView)
 
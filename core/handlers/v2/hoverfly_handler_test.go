@@ -73,6 +73,13 @@ func (this *HoverflyStub) IsWebServer() bool {
 	return false
 }
 
+func (this *HoverflyStub) GetImportWatch() *ImportWatchView {
+	return &ImportWatchView{
+		Paths: []string{"simulations"},
+		Error: "test-error",
+	}
+}
+
 func TestHoverflyHandlerGetReturnsTheCorrectMode(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -97,6 +104,8 @@ func TestHoverflyHandlerGetReturnsTheCorrectMode(t *testing.T) {
 	Expect(hoverflyView.Version).To(Equal("test-version"))
 	Expect(hoverflyView.UpstreamProxy).To(Equal("test-proxy.com:8080"))
 	Expect(hoverflyView.IsWebServer).To(BeFalse())
+	Expect(hoverflyView.ImportWatch.Paths).To(ConsistOf("simulations"))
+	Expect(hoverflyView.ImportWatch.Error).To(Equal("test-error"))
 }
 
 func Test_HoverflyHandler_Options_GetsOptions(t *testing.T) {
@@ -1,27 +1,37 @@
 package v2
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
 	"net/http"
 
 	"github.com/SpectoLabs/hoverfly/core/handlers"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	"github.com/codegangsta/negroni"
 	"github.com/go-zoo/bone"
+	"github.com/gorilla/websocket"
 	"strconv"
 	"time"
 )
 
 const DefaultJournalLimit = 25
 
+const JournalFormatHAR = "har"
+
 type HoverflyJournal interface {
 	GetEntries(offset int, limit int, from *time.Time, to *time.Time, sort string) (JournalView, error)
 	GetFilteredEntries(journalEntryFilterView JournalEntryFilterView) ([]JournalEntryView, error)
 	DeleteEntries() error
+	Subscribe(journalEntryFilterView JournalEntryFilterView) (<-chan JournalEntryView, func(), error)
+	Verify(journalVerifyView JournalVerifyView) (JournalVerificationView, error)
 }
 
 type JournalHandler struct {
 	Hoverfly HoverflyJournal
+	Version  string
 }
 
 func (this *JournalHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
@@ -40,6 +50,31 @@ func (this *JournalHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandl
 	mux.Options("/api/v2/journal", negroni.New(
 		negroni.HandlerFunc(this.Options),
 	))
+
+	mux.Post("/api/v2/journal/verify", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostVerify),
+	))
+	mux.Options("/api/v2/journal/verify", negroni.New(
+		negroni.HandlerFunc(this.OptionsVerify),
+	))
+
+	mux.Get("/api/v2/journal/stream", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetStream),
+	))
+	mux.Post("/api/v2/journal/stream", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetStream),
+	))
+	mux.Options("/api/v2/journal/stream", negroni.New(
+		negroni.HandlerFunc(this.OptionsStream),
+	))
+
+	mux.Get("/api/v2/ws/journal", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetWS),
+	))
 }
 
 func (this *JournalHandler) Get(response http.ResponseWriter, request *http.Request, next http.HandlerFunc) {
@@ -50,9 +85,19 @@ func (this *JournalHandler) Get(response http.ResponseWriter, request *http.Requ
 	fromTime := util.GetUnixTimeQueryParam(request, "from")
 	toTime := util.GetUnixTimeQueryParam(request, "to")
 	sort := queryParams.Get("sort")
+	format := queryParams.Get("format")
+
+	if format != "" && format != JournalFormatHAR {
+		handlers.WriteErrorResponse(response, fmt.Sprintf("'%s' is not a valid format, use %s", format, JournalFormatHAR), http.StatusBadRequest)
+		return
+	}
 
 	if limit == 0 {
 		limit = DefaultJournalLimit
+		// An HTTP Archive is an export of the whole journal unless asked otherwise
+		if format == JournalFormatHAR {
+			limit = math.MaxInt32
+		}
 	}
 
 	journalView, err := this.Hoverfly.GetEntries(offset, limit, fromTime, toTime, sort)
@@ -61,7 +106,12 @@ func (this *JournalHandler) Get(response http.ResponseWriter, request *http.Requ
 		return
 	}
 
-	bytes, _ := json.Marshal(journalView)
+	var bytes []byte
+	if format == JournalFormatHAR {
+		bytes, _ = json.Marshal(NewHARViewFromJournalEntries(journalView.Journal, this.Version))
+	} else {
+		bytes, _ = json.Marshal(journalView)
+	}
 	handlers.WriteResponse(response, bytes)
 }
 
@@ -74,7 +124,7 @@ func (this *JournalHandler) Post(response http.ResponseWriter, request *http.Req
 	if err != nil {
 		handlers.WriteErrorResponse(response, err.Error(), http.StatusBadRequest)
 		return
-	} else if journalEntryFilterView.Request == nil {
+	} else if journalEntryFilterView.Request == nil && journalEntryFilterView.Status == 0 && !journalEntryFilterView.Unmatched {
 		handlers.WriteErrorResponse(response, "No \"request\" object in search parameters", http.StatusBadRequest)
 		return
 	}
@@ -105,3 +155,199 @@ func (this *JournalHandler) Options(w http.ResponseWriter, r *http.Request, next
 	w.Header().Add("Allow", "OPTIONS, GET, DELETE, POST")
 	handlers.WriteResponse(w, []byte(""))
 }
+
+// PostVerify checks the journal against the verification in the request body. A verification
+// that fails is still a successful request, with the result saying why it failed.
+func (this *JournalHandler) PostVerify(response http.ResponseWriter, request *http.Request, next http.HandlerFunc) {
+	var journalVerifyView JournalVerifyView
+
+	err := handlers.ReadFromRequest(request, &journalVerifyView)
+	if err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := validateJournalVerifyView(journalVerifyView); err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	verificationView, err := this.Hoverfly.Verify(journalVerifyView)
+	if err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, _ := json.Marshal(verificationView)
+	handlers.WriteResponse(response, bytes)
+}
+
+func (this *JournalHandler) OptionsVerify(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+// GetStream sends each new journal entry that matches the filter in the request body as a
+// Server-Sent Event. Without a request body, every new entry is sent.
+func (this *JournalHandler) GetStream(response http.ResponseWriter, request *http.Request, next http.HandlerFunc) {
+	journalEntryFilterView, err := readJournalStreamFilter(request)
+	if err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := response.(http.Flusher)
+	if !ok {
+		handlers.WriteErrorResponse(response, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	entries, unsubscribe, err := this.Hoverfly.Subscribe(journalEntryFilterView)
+	if err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer unsubscribe()
+
+	response.Header().Set("Content-Type", "text/event-stream")
+	response.Header().Set("Cache-Control", "no-cache")
+	response.Header().Set("Connection", "keep-alive")
+	response.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			entryBytes, _ := json.Marshal(entry)
+			if _, err := fmt.Fprintf(response, "data: %s\n\n", entryBytes); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-request.Context().Done():
+			return
+		}
+	}
+}
+
+// GetWS sends each new journal entry over a WebSocket. The first message from the client
+// is the filter, which is the same as the body of POST /api/v2/journal, or empty for every entry.
+func (this *JournalHandler) GetWS(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	conn, err := handlers.UpgradeWebsocket(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		return
+	}
+
+	var journalEntryFilterView JournalEntryFilterView
+	if len(bytes.TrimSpace(message)) > 0 && json.Unmarshal(message, &journalEntryFilterView) != nil {
+		writeWebsocketError(conn, "Malformed JSON")
+		return
+	}
+
+	entries, unsubscribe, err := this.Hoverfly.Subscribe(journalEntryFilterView)
+	if err != nil {
+		writeWebsocketError(conn, err.Error())
+		return
+	}
+	defer unsubscribe()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(entry); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func (this *JournalHandler) OptionsStream(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func readJournalStreamFilter(request *http.Request) (JournalEntryFilterView, error) {
+	var journalEntryFilterView JournalEntryFilterView
+
+	if request.Body == nil {
+		return journalEntryFilterView, nil
+	}
+	defer request.Body.Close()
+
+	body, _ := ioutil.ReadAll(request.Body)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return journalEntryFilterView, nil
+	}
+
+	if err := json.Unmarshal(body, &journalEntryFilterView); err != nil {
+		return journalEntryFilterView, fmt.Errorf("Malformed JSON")
+	}
+
+	return journalEntryFilterView, nil
+}
+
+func writeWebsocketError(conn *websocket.Conn, message string) {
+	conn.WriteJSON(handlers.ErrorView{Error: message})
+}
+
+func validateJournalVerifyView(journalVerifyView JournalVerifyView) error {
+	hasFilter := journalVerifyView.Request != nil || journalVerifyView.Status != 0 || journalVerifyView.Unmatched
+	hasCount := journalVerifyView.Times != nil || journalVerifyView.AtLeast != nil || journalVerifyView.AtMost != nil || journalVerifyView.Never
+
+	if len(journalVerifyView.InOrder) > 0 {
+		if hasFilter || hasCount {
+			return fmt.Errorf("inOrder cannot be combined with request, status, times, atLeast, atMost or never")
+		}
+		for i, filter := range journalVerifyView.InOrder {
+			if filter.Request == nil && filter.Status == 0 && !filter.Unmatched {
+				return fmt.Errorf("No \"request\" object in inOrder[%d]", i)
+			}
+		}
+		return nil
+	}
+
+	if !hasFilter {
+		return fmt.Errorf("No \"request\" object in verification")
+	}
+
+	if journalVerifyView.Times != nil && (journalVerifyView.AtLeast != nil || journalVerifyView.AtMost != nil || journalVerifyView.Never) {
+		return fmt.Errorf("times cannot be combined with atLeast, atMost or never")
+	}
+	if journalVerifyView.Never && (journalVerifyView.AtLeast != nil || journalVerifyView.AtMost != nil) {
+		return fmt.Errorf("never cannot be combined with atLeast or atMost")
+	}
+
+	for _, count := range []*int{journalVerifyView.Times, journalVerifyView.AtLeast, journalVerifyView.AtMost} {
+		if count != nil && *count < 0 {
+			return fmt.Errorf("times, atLeast and atMost cannot be negative")
+		}
+	}
+	if journalVerifyView.AtLeast != nil && journalVerifyView.AtMost != nil && *journalVerifyView.AtLeast > *journalVerifyView.AtMost {
+		return fmt.Errorf("atLeast cannot be greater than atMost")
+	}
+
+	return nil
+}
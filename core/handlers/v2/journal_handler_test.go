@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"testing"
 
@@ -11,7 +12,13 @@ import (
 
 	"time"
 
+	"net/http/httptest"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/go-zoo/bone"
+	"github.com/gorilla/websocket"
 	. "github.com/onsi/gomega"
 )
 
@@ -24,6 +31,9 @@ type HoverflyJournalStub struct {
 	from                   *time.Time
 	to                     *time.Time
 	journalEntryFilterView JournalEntryFilterView
+	streamedEntries        []JournalEntryView
+	unsubscribed           bool
+	journalVerifyView      JournalVerifyView
 }
 
 func (this *HoverflyJournalStub) GetEntries(offset int, limit int, from *time.Time, to *time.Time, sort string) (JournalView, error) {
@@ -75,6 +85,36 @@ func (this *HoverflyJournalStub) DeleteEntries() error {
 	return nil
 }
 
+// Subscribe sends the streamed entries and then closes the channel, as if the subscription had ended
+func (this *HoverflyJournalStub) Subscribe(journalEntryFilterView JournalEntryFilterView) (<-chan JournalEntryView, func(), error) {
+	if this.error {
+		return nil, nil, fmt.Errorf("journal error")
+	}
+
+	this.journalEntryFilterView = journalEntryFilterView
+
+	entries := make(chan JournalEntryView, len(this.streamedEntries))
+	for _, entry := range this.streamedEntries {
+		entries <- entry
+	}
+	close(entries)
+
+	return entries, func() { this.unsubscribed = true }, nil
+}
+
+func (this *HoverflyJournalStub) Verify(journalVerifyView JournalVerifyView) (JournalVerificationView, error) {
+	if this.error {
+		return JournalVerificationView{}, fmt.Errorf("journal error")
+	}
+
+	this.journalVerifyView = journalVerifyView
+	return JournalVerificationView{
+		Passed:  false,
+		Message: "Found 0 matching requests, expected at least 1",
+		Closest: []JournalVerificationMissView{{Entry: JournalEntryView{Mode: "test"}, MissedFields: []string{"path"}}},
+	}, nil
+}
+
 func Test_JournalHandler_Get_ReturnsJournal(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -95,6 +135,46 @@ func Test_JournalHandler_Get_ReturnsJournal(t *testing.T) {
 	Expect(journalView.Journal[0].Mode).To(Equal("test"))
 }
 
+func Test_JournalHandler_Get_ReturnsJournalAsHAR(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{}
+	unit := JournalHandler{Hoverfly: stubHoverfly, Version: "v1.0.0"}
+
+	request, err := http.NewRequest("GET", "/api/v2/journal?format=har", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var harView HARView
+	Expect(json.Unmarshal(response.Body.Bytes(), &harView)).To(Succeed())
+
+	Expect(harView.Log.Version).To(Equal("1.2"))
+	Expect(harView.Log.Creator).To(Equal(HARCreatorView{Name: "Hoverfly", Version: "v1.0.0"}))
+	Expect(harView.Log.Entries).To(HaveLen(1))
+	Expect(stubHoverfly.limit).To(Equal(math.MaxInt32))
+}
+
+func Test_JournalHandler_Get_ReturnsErrorForUnknownFormat(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{}
+	unit := JournalHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("GET", "/api/v2/journal?format=csv", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("'csv' is not a valid format, use har"))
+}
+
 func Test_JournalHandler_Get_SetDefaultPagingQueryIfNotSpecified(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -245,6 +325,38 @@ func Test_JournalHandler_Post_CallsFilter(t *testing.T) {
 	Expect(stubHoverfly.journalEntryFilterView.Request.Path[0].Value).To(Equal("*"))
 }
 
+func Test_JournalHandler_Post_CallsFilterWithOnlyAStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal", bytes.NewBufferString(`{"status": 502}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	Expect(stubHoverfly.journalEntryFilterView.Request).To(BeNil())
+	Expect(stubHoverfly.journalEntryFilterView.Status).To(Equal(502))
+}
+
+func Test_JournalHandler_Post_CallsFilterWithOnlyUnmatched(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal", bytes.NewBufferString(`{"unmatched": true}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.journalEntryFilterView.Unmatched).To(BeTrue())
+}
+
 func Test_JournalHandler_Post_MalformedJson(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -392,3 +504,247 @@ func unmarshalJournalView(buffer *bytes.Buffer) (JournalView, error) {
 
 	return journalView, nil
 }
+
+func Test_JournalHandler_GetStream_SendsEntriesAsServerSentEvents(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := HoverflyJournalStub{
+		streamedEntries: []JournalEntryView{{Mode: "simulate"}, {Mode: "capture"}},
+	}
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("GET", "/api/v2/journal/stream", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.GetStream, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+
+	events := strings.Split(strings.TrimSpace(response.Body.String()), "\n\n")
+	Expect(events).To(HaveLen(2))
+	Expect(events[0]).To(HavePrefix("data: "))
+
+	var entry JournalEntryView
+	Expect(json.Unmarshal([]byte(strings.TrimPrefix(events[1], "data: ")), &entry)).To(Succeed())
+	Expect(entry.Mode).To(Equal("capture"))
+
+	Expect(stubHoverfly.unsubscribed).To(BeTrue())
+}
+
+func Test_JournalHandler_GetStream_UsesFilterFromRequestBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/stream", bytes.NewBufferString(`{"request": {"path": [{"matcher": "glob", "value": "/api/*"}]}, "status": 500}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.GetStream, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.journalEntryFilterView.Request.Path[0].Value).To(Equal("/api/*"))
+	Expect(stubHoverfly.journalEntryFilterView.Status).To(Equal(500))
+}
+
+func Test_JournalHandler_GetStream_MalformedJson(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/stream", bytes.NewBufferString("werw{{}[][{}"))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.GetStream, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Malformed JSON"))
+}
+
+func Test_JournalHandler_GetStream_JournalError(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := HoverflyJournalStub{error: true}
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("GET", "/api/v2/journal/stream", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.GetStream, request)
+
+	Expect(response.Code).To(Equal(http.StatusInternalServerError))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("journal error"))
+}
+
+func Test_JournalHandler_GetWS_SendsEntriesMatchingTheFilterMessage(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := HoverflyJournalStub{
+		streamedEntries: []JournalEntryView{{Mode: "simulate"}},
+	}
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v2/ws/journal", nil)
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	Expect(conn.WriteMessage(websocket.TextMessage, []byte(`{"status": 200}`))).To(Succeed())
+
+	var entry JournalEntryView
+	Expect(conn.ReadJSON(&entry)).To(Succeed())
+	Expect(entry.Mode).To(Equal("simulate"))
+
+	Expect(stubHoverfly.journalEntryFilterView.Status).To(Equal(200))
+}
+
+func Test_JournalHandler_GetWS_RequiresAuthentication(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := JournalHandler{Hoverfly: &HoverflyJournalStub{}}
+
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{Enabled: true})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	_, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v2/ws/journal", nil)
+	Expect(err).To(Equal(websocket.ErrBadHandshake))
+	Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+}
+
+func Test_JournalHandler_GetWS_RefusesOtherOrigins(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := JournalHandler{Hoverfly: &HoverflyJournalStub{}}
+
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	_, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v2/ws/journal",
+		http.Header{"Origin": {"http://example.com"}})
+	Expect(err).To(Equal(websocket.ErrBadHandshake))
+	Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+}
+
+func Test_JournalHandler_OptionsStream_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("OPTIONS", "/api/v2/journal/stream", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.OptionsStream, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, GET, POST"))
+}
+
+func Test_JournalHandler_PostVerify_ReturnsVerificationResult(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/verify", bytes.NewBufferString(`{
+		"request": {"path": [{"matcher": "exact", "value": "/basket"}]},
+		"status": 200,
+		"atLeast": 2
+	}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PostVerify, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var verificationView JournalVerificationView
+	Expect(json.Unmarshal(response.Body.Bytes(), &verificationView)).To(Succeed())
+	Expect(verificationView.Passed).To(BeFalse())
+	Expect(verificationView.Message).To(Equal("Found 0 matching requests, expected at least 1"))
+	Expect(verificationView.Closest).To(HaveLen(1))
+	Expect(verificationView.Closest[0].MissedFields).To(Equal([]string{"path"}))
+
+	Expect(stubHoverfly.journalVerifyView.Request.Path[0].Value).To(Equal("/basket"))
+	Expect(stubHoverfly.journalVerifyView.Status).To(Equal(200))
+	Expect(*stubHoverfly.journalVerifyView.AtLeast).To(Equal(2))
+}
+
+func Test_JournalHandler_PostVerify_RejectsInvalidVerifications(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	invalidVerifications := map[string]string{
+		`werw{{}[][{}`: "Malformed JSON",
+		`{"times": 1}`: "No \"request\" object in verification",
+		`{"request": {}, "times": 1, "atLeast": 1}`:       "times cannot be combined with atLeast, atMost or never",
+		`{"request": {}, "never": true, "atMost": 1}`:     "never cannot be combined with atLeast or atMost",
+		`{"request": {}, "atLeast": -1}`:                  "times, atLeast and atMost cannot be negative",
+		`{"request": {}, "atLeast": 2, "atMost": 1}`:      "atLeast cannot be greater than atMost",
+		`{"request": {}, "inOrder": [{"request": {}}]}`:   "inOrder cannot be combined with request, status, times, atLeast, atMost or never",
+		`{"inOrder": [{"request": {}}, {"headers": {}}]}`: "No \"request\" object in inOrder[1]",
+	}
+
+	for body, expectedError := range invalidVerifications {
+		request, err := http.NewRequest("POST", "/api/v2/journal/verify", bytes.NewBufferString(body))
+		Expect(err).To(BeNil())
+
+		response := makeRequestOnHandler(unit.PostVerify, request)
+
+		Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+		errorView, err := unmarshalErrorView(response.Body)
+		Expect(err).To(BeNil())
+		Expect(errorView.Error).To(Equal(expectedError))
+	}
+}
+
+func Test_JournalHandler_PostVerify_JournalError(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := HoverflyJournalStub{error: true}
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/verify", bytes.NewBufferString(`{"inOrder": [{"status": 200}]}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PostVerify, request)
+
+	Expect(response.Code).To(Equal(http.StatusInternalServerError))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("journal error"))
+}
+
+func Test_JournalHandler_OptionsVerify_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflyJournalStub
+	unit := JournalHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("OPTIONS", "/api/v2/journal/verify", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.OptionsVerify, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, POST"))
+}
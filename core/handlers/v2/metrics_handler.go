@@ -0,0 +1,40 @@
+package v2
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/metrics"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflyMetrics interface {
+	WriteTo(io.Writer) (int64, error)
+}
+
+// MetricsHandler exposes the Hoverfly metrics for Prometheus to scrape
+type MetricsHandler struct {
+	Hoverfly HoverflyMetrics
+}
+
+func (this *MetricsHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/metrics", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Options("/metrics", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+}
+
+func (this *MetricsHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	w.Header().Set("Content-Type", metrics.PrometheusContentType)
+	this.Hoverfly.WriteTo(w)
+}
+
+func (this *MetricsHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET")
+	handlers.WriteResponse(w, []byte(""))
+}
@@ -0,0 +1,43 @@
+package v2
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/metrics"
+	. "github.com/onsi/gomega"
+)
+
+func TestMetricsHandler_Get_ReturnsMetricsInPrometheusFormat(t *testing.T) {
+	RegisterTestingT(t)
+
+	prometheusMetrics := metrics.NewPrometheusMetrics(func() float64 { return 3 })
+	prometheusMetrics.CountRequest("simulate", "test.com", "GET", 200, 10*time.Millisecond)
+
+	unit := MetricsHandler{Hoverfly: prometheusMetrics}
+
+	request, err := http.NewRequest("GET", "/metrics", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Content-Type")).To(Equal("text/plain; version=0.0.4; charset=utf-8"))
+	Expect(response.Body.String()).To(ContainSubstring(`hoverfly_requests_total{mode="simulate",destination="test.com",method="GET",status="200"} 1`))
+	Expect(response.Body.String()).To(ContainSubstring("hoverfly_simulation_pairs 3"))
+}
+
+func Test_MetricsHandler_Options_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := MetricsHandler{Hoverfly: metrics.NewPrometheusMetrics(func() float64 { return 0 })}
+
+	request, err := http.NewRequest("OPTIONS", "/metrics", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Options, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, GET"))
+}
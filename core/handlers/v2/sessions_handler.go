@@ -0,0 +1,293 @@
+package v2
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflySessions interface {
+	GetSessions() SessionsView
+	CreateSession(string)
+	GetSessionSimulation(string) (SimulationViewV5, error)
+	PutSessionSimulation(string, SimulationViewV5, bool) SimulationImportResult
+	GetSessionState(string) (HoverflyState, error)
+	GetSessionJournal(string) (HoverflyJournal, error)
+	DeleteSession(string) error
+}
+
+// SessionsHandler manages sessions, which isolate the simulation, state and journal of the
+// requests sent with the same session header. The state and journal of a session have the
+// same API as those of Hoverfly, under /api/v2/sessions/:name.
+type SessionsHandler struct {
+	Hoverfly HoverflySessions
+	Version  string
+}
+
+func (this *SessionsHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/sessions", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetAll),
+	))
+	mux.Options("/api/v2/sessions", negroni.New(
+		negroni.HandlerFunc(this.OptionsAll),
+	))
+
+	mux.Delete("/api/v2/sessions/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Delete),
+	))
+	mux.Options("/api/v2/sessions/:name", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+
+	mux.Get("/api/v2/sessions/:name/simulation", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetSimulation),
+	))
+	mux.Put("/api/v2/sessions/:name/simulation", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PutSimulation),
+	))
+	mux.Post("/api/v2/sessions/:name/simulation", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostSimulation),
+	))
+	mux.Options("/api/v2/sessions/:name/simulation", negroni.New(
+		negroni.HandlerFunc(this.OptionsSimulation),
+	))
+
+	mux.Get("/api/v2/sessions/:name/state", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetState),
+	))
+	mux.Put("/api/v2/sessions/:name/state", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PutState),
+	))
+	mux.Patch("/api/v2/sessions/:name/state", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PatchState),
+	))
+	mux.Delete("/api/v2/sessions/:name/state", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.DeleteState),
+	))
+	mux.Options("/api/v2/sessions/:name/state", negroni.New(
+		negroni.HandlerFunc(this.OptionsState),
+	))
+
+	mux.Get("/api/v2/sessions/:name/journal", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetJournal),
+	))
+	mux.Post("/api/v2/sessions/:name/journal", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostJournal),
+	))
+	mux.Delete("/api/v2/sessions/:name/journal", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.DeleteJournal),
+	))
+	mux.Options("/api/v2/sessions/:name/journal", negroni.New(
+		negroni.HandlerFunc(this.OptionsJournal),
+	))
+
+	mux.Post("/api/v2/sessions/:name/journal/verify", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostJournalVerify),
+	))
+	mux.Options("/api/v2/sessions/:name/journal/verify", negroni.New(
+		negroni.HandlerFunc(this.OptionsJournalVerify),
+	))
+}
+
+func (this *SessionsHandler) GetAll(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	bytes, _ := json.Marshal(this.Hoverfly.GetSessions())
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SessionsHandler) Delete(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.Hoverfly.DeleteSession(bone.GetValue(req, "name"))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	this.GetAll(w, req, next)
+}
+
+func (this *SessionsHandler) GetSimulation(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	simulationView, err := this.Hoverfly.GetSessionSimulation(bone.GetValue(req, "name"))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(simulationView)
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SessionsHandler) PutSimulation(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addSimulation(w, req, true)
+	if err != nil {
+		return
+	}
+
+	this.GetSimulation(w, req, next)
+}
+
+func (this *SessionsHandler) PostSimulation(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addSimulation(w, req, false)
+	if err != nil {
+		return
+	}
+
+	this.GetSimulation(w, req, next)
+}
+
+func (this *SessionsHandler) GetState(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	stateHandler, err := this.getStateHandler(req)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	stateHandler.Get(w, req, next)
+}
+
+func (this *SessionsHandler) PutState(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	this.Hoverfly.CreateSession(bone.GetValue(req, "name"))
+
+	stateHandler, _ := this.getStateHandler(req)
+	stateHandler.Put(w, req, next)
+}
+
+func (this *SessionsHandler) PatchState(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	this.Hoverfly.CreateSession(bone.GetValue(req, "name"))
+
+	stateHandler, _ := this.getStateHandler(req)
+	stateHandler.Patch(w, req, next)
+}
+
+func (this *SessionsHandler) DeleteState(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	stateHandler, err := this.getStateHandler(req)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	stateHandler.Delete(w, req, next)
+}
+
+func (this *SessionsHandler) GetJournal(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	journalHandler, err := this.getJournalHandler(req)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	journalHandler.Get(w, req, next)
+}
+
+func (this *SessionsHandler) PostJournal(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	journalHandler, err := this.getJournalHandler(req)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	journalHandler.Post(w, req, next)
+}
+
+func (this *SessionsHandler) DeleteJournal(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	journalHandler, err := this.getJournalHandler(req)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	journalHandler.Delete(w, req, next)
+}
+
+func (this *SessionsHandler) PostJournalVerify(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	journalHandler, err := this.getJournalHandler(req)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	journalHandler.PostVerify(w, req, next)
+}
+
+func (this *SessionsHandler) OptionsAll(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SessionsHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SessionsHandler) OptionsSimulation(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, PUT, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SessionsHandler) OptionsState(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, DELETE, PUT, PATCH")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SessionsHandler) OptionsJournal(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, POST, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SessionsHandler) OptionsJournalVerify(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+// addSimulation adds the simulation in the request body to the session, creating it if it does not exist
+func (this *SessionsHandler) addSimulation(w http.ResponseWriter, req *http.Request, overrideExisting bool) error {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	simulationView, err := NewSimulationViewFromRequestBody(body)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	result := this.Hoverfly.PutSessionSimulation(bone.GetValue(req, "name"), simulationView, overrideExisting)
+
+	return writeSimulationImportResult(w, result, body)
+}
+
+// getStateHandler returns a handler for the state of the session, so that it has the same API as the state of Hoverfly
+func (this *SessionsHandler) getStateHandler(req *http.Request) (*StateHandler, error) {
+	sessionState, err := this.Hoverfly.GetSessionState(bone.GetValue(req, "name"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &StateHandler{Hoverfly: sessionState}, nil
+}
+
+// getJournalHandler returns a handler for the journal of the session, so that it has the same API as the journal of Hoverfly
+func (this *SessionsHandler) getJournalHandler(req *http.Request) (*JournalHandler, error) {
+	sessionJournal, err := this.Hoverfly.GetSessionJournal(bone.GetValue(req, "name"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournalHandler{Hoverfly: sessionJournal, Version: this.Version}, nil
+}
@@ -0,0 +1,348 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type SessionStateStub struct {
+	state map[string]string
+}
+
+func (this *SessionStateStub) GetState() map[string]string {
+	return this.state
+}
+
+func (this *SessionStateStub) SetState(state map[string]string) {
+	this.state = state
+}
+
+func (this *SessionStateStub) PatchState(toPatch map[string]string) {
+	for key, value := range toPatch {
+		this.state[key] = value
+	}
+}
+
+func (this *SessionStateStub) ClearState() {
+	this.state = map[string]string{}
+}
+
+type HoverflySessionsStub struct {
+	created          string
+	name             string
+	simulation       SimulationViewV5
+	overrideExisting bool
+	importResult     SimulationImportResult
+	state            *SessionStateStub
+	journal          *HoverflyJournalStub
+	deleted          bool
+}
+
+func newHoverflySessionsStub() *HoverflySessionsStub {
+	return &HoverflySessionsStub{
+		state:   &SessionStateStub{state: map[string]string{"page": "home"}},
+		journal: &HoverflyJournalStub{},
+	}
+}
+
+func (this *HoverflySessionsStub) GetSessions() SessionsView {
+	return SessionsView{
+		Sessions: []SessionView{
+			{Name: "first", Created: "2018-01-01T00:00:00.000Z", Pairs: 2},
+		},
+	}
+}
+
+func (this *HoverflySessionsStub) CreateSession(name string) {
+	this.created = name
+}
+
+func (this *HoverflySessionsStub) GetSessionSimulation(name string) (SimulationViewV5, error) {
+	if name != "first" {
+		return SimulationViewV5{}, errors.New("Session " + name + " not found")
+	}
+	return this.simulation, nil
+}
+
+func (this *HoverflySessionsStub) PutSessionSimulation(name string, simulation SimulationViewV5, overrideExisting bool) SimulationImportResult {
+	this.name = name
+	this.simulation = simulation
+	this.overrideExisting = overrideExisting
+	return this.importResult
+}
+
+func (this *HoverflySessionsStub) GetSessionState(name string) (HoverflyState, error) {
+	if name != "first" && name != this.created {
+		return nil, errors.New("Session " + name + " not found")
+	}
+	return this.state, nil
+}
+
+func (this *HoverflySessionsStub) GetSessionJournal(name string) (HoverflyJournal, error) {
+	if name != "first" {
+		return nil, errors.New("Session " + name + " not found")
+	}
+	return this.journal, nil
+}
+
+func (this *HoverflySessionsStub) DeleteSession(name string) error {
+	if name != "first" {
+		return errors.New("Session " + name + " not found")
+	}
+	this.deleted = true
+	return nil
+}
+
+func Test_SessionsHandler_GetAll_ReturnsTheSessions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("GET", "/api/v2/sessions", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	body, _ := ioutil.ReadAll(response.Body)
+	Expect(string(body)).To(Equal(`{"sessions":[{"name":"first","created":"2018-01-01T00:00:00.000Z","pairs":2}]}`))
+}
+
+func Test_SessionsHandler_Delete_DeletesTheSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("DELETE", "/api/v2/sessions/first", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.deleted).To(BeTrue())
+}
+
+func Test_SessionsHandler_Delete_ReturnsNotFoundForAnUnknownSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("DELETE", "/api/v2/sessions/second", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Session second not found"))
+}
+
+func Test_SessionsHandler_PutSimulation_ReplacesTheSessionSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("PUT", "/api/v2/sessions/first/simulation", bytes.NewBufferString(simulationSetBody))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.name).To(Equal("first"))
+	Expect(stubHoverfly.overrideExisting).To(BeTrue())
+	Expect(stubHoverfly.simulation.RequestResponsePairs).To(HaveLen(1))
+
+	simulationView := SimulationViewV5{}
+	Expect(json.NewDecoder(response.Body).Decode(&simulationView)).To(Succeed())
+	Expect(simulationView.RequestResponsePairs).To(HaveLen(1))
+}
+
+func Test_SessionsHandler_PostSimulation_AppendsToTheSessionSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("POST", "/api/v2/sessions/first/simulation", bytes.NewBufferString(simulationSetBody))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.overrideExisting).To(BeFalse())
+}
+
+func Test_SessionsHandler_PutSimulation_ReturnsBadRequestForInvalidSimulations(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("PUT", "/api/v2/sessions/first/simulation", bytes.NewBufferString("{}"))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+}
+
+func Test_SessionsHandler_GetSimulation_ReturnsNotFoundForAnUnknownSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("GET", "/api/v2/sessions/second/simulation", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+}
+
+func Test_SessionsHandler_GetState_ReturnsTheSessionState(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("GET", "/api/v2/sessions/first/state", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	body, _ := ioutil.ReadAll(response.Body)
+	Expect(string(body)).To(Equal(`{"state":{"page":"home"}}`))
+}
+
+func Test_SessionsHandler_GetState_ReturnsNotFoundForAnUnknownSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("GET", "/api/v2/sessions/second/state", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+}
+
+func Test_SessionsHandler_PutState_CreatesTheSessionAndSetsItsState(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("PUT", "/api/v2/sessions/second/state", bytes.NewBufferString(`{"state":{"page":"checkout"}}`))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.created).To(Equal("second"))
+	Expect(stubHoverfly.state.state).To(Equal(map[string]string{"page": "checkout"}))
+}
+
+func Test_SessionsHandler_PatchState_PatchesTheSessionState(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("PATCH", "/api/v2/sessions/first/state", bytes.NewBufferString(`{"state":{"basket":"full"}}`))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.state.state).To(Equal(map[string]string{"page": "home", "basket": "full"}))
+}
+
+func Test_SessionsHandler_DeleteState_ClearsTheSessionState(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("DELETE", "/api/v2/sessions/first/state", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.state.state).To(BeEmpty())
+}
+
+func Test_SessionsHandler_GetJournal_ReturnsTheSessionJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("GET", "/api/v2/sessions/first/journal", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	journalView, err := unmarshalJournalView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+	Expect(journalView.Limit).To(Equal(DefaultJournalLimit))
+}
+
+func Test_SessionsHandler_GetJournal_ReturnsNotFoundForAnUnknownSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	request, _ := http.NewRequest("GET", "/api/v2/sessions/second/journal", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+}
+
+func Test_SessionsHandler_PostJournal_FiltersTheSessionJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("POST", "/api/v2/sessions/first/journal", bytes.NewBufferString(`{"unmatched": true}`))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.journal.journalEntryFilterView.Unmatched).To(BeTrue())
+}
+
+func Test_SessionsHandler_DeleteJournal_DeletesTheSessionJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("DELETE", "/api/v2/sessions/first/journal", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.journal.deleted).To(BeTrue())
+}
+
+func Test_SessionsHandler_PostJournalVerify_VerifiesTheSessionJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newHoverflySessionsStub()
+	unit := &SessionsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("POST", "/api/v2/sessions/first/journal/verify", bytes.NewBufferString(`{"request": {"path": [{"matcher": "exact", "value": "/path"}]}}`))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.journal.journalVerifyView.Request.Path).To(HaveLen(1))
+}
+
+func Test_SessionsHandler_Options_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SessionsHandler{Hoverfly: newHoverflySessionsStub()}
+
+	for path, allow := range map[string]string{
+		"/api/v2/sessions":                      "OPTIONS, GET",
+		"/api/v2/sessions/first":                "OPTIONS, DELETE",
+		"/api/v2/sessions/first/simulation":     "OPTIONS, GET, PUT, POST",
+		"/api/v2/sessions/first/state":          "OPTIONS, GET, DELETE, PUT, PATCH",
+		"/api/v2/sessions/first/journal":        "OPTIONS, GET, POST, DELETE",
+		"/api/v2/sessions/first/journal/verify": "OPTIONS, POST",
+	} {
+		request, _ := http.NewRequest("OPTIONS", path, nil)
+		response := makeRequestOnRoutes(unit, request)
+
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Header().Get("Allow")).To(Equal(allow), path)
+	}
+}
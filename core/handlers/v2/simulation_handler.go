@@ -20,6 +20,10 @@ type HoverflySimulation interface {
 	GetFilteredSimulation(string) (SimulationViewV5, error)
 	PutSimulation(SimulationViewV5) SimulationImportResult
 	DeleteSimulation()
+	GetSimulationFromOpenAPISpec([]byte) (SimulationViewV5, error)
+	GetSimulationFromWireMockMappings([]byte) (SimulationViewV5, SimulationImportResult, error)
+	GetSimulationFromPostmanCollection([]byte) (SimulationViewV5, SimulationImportResult, error)
+	LintSimulation(SimulationViewV5) SimulationLintView
 }
 
 type SimulationHandler struct {
@@ -47,6 +51,54 @@ func (this *SimulationHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHa
 		negroni.HandlerFunc(this.Options),
 	))
 
+	mux.Put("/api/v2/simulation/openapi", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PutOpenAPI),
+	))
+	mux.Post("/api/v2/simulation/openapi", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostOpenAPI),
+	))
+	mux.Options("/api/v2/simulation/openapi", negroni.New(
+		negroni.HandlerFunc(this.OptionsImport),
+	))
+
+	mux.Put("/api/v2/simulation/wiremock", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PutWireMock),
+	))
+	mux.Post("/api/v2/simulation/wiremock", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostWireMock),
+	))
+	mux.Options("/api/v2/simulation/wiremock", negroni.New(
+		negroni.HandlerFunc(this.OptionsImport),
+	))
+
+	mux.Put("/api/v2/simulation/postman", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PutPostman),
+	))
+	mux.Post("/api/v2/simulation/postman", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostPostman),
+	))
+	mux.Options("/api/v2/simulation/postman", negroni.New(
+		negroni.HandlerFunc(this.OptionsImport),
+	))
+
+	mux.Get("/api/v2/simulation/lint", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetLint),
+	))
+	mux.Post("/api/v2/simulation/lint", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PostLint),
+	))
+	mux.Options("/api/v2/simulation/lint", negroni.New(
+		negroni.HandlerFunc(this.OptionsLint),
+	))
+
 	mux.Get("/api/v2/simulation/schema", negroni.New(
 		negroni.HandlerFunc(am.RequireTokenAuthentication),
 		negroni.HandlerFunc(this.GetSchema),
@@ -106,11 +158,103 @@ func (this *SimulationHandler) Options(w http.ResponseWriter, r *http.Request, n
 }
 
 func (this *SimulationHandler) GetSchema(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
-	bytes, _ := json.Marshal(SimulationViewV5Schema)
+	bytes, _ := json.Marshal(SimulationViewV6Schema)
 
 	handlers.WriteResponse(w, bytes)
 }
 
+// GetLint finds the problems in the simulation that is loaded
+func (this *SimulationHandler) GetLint(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	simulationView, err := this.Hoverfly.GetSimulation()
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, _ := json.Marshal(this.Hoverfly.LintSimulation(simulationView))
+
+	handlers.WriteResponse(w, bytes)
+}
+
+// PostLint finds the problems in the simulation in the request body, without loading it
+func (this *SimulationHandler) PostLint(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	simulationView, err := NewSimulationViewFromRequestBody(body)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bytes, _ := json.Marshal(this.Hoverfly.LintSimulation(simulationView))
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationHandler) PutOpenAPI(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addOpenAPISimulation(w, req, true)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationHandler) PostOpenAPI(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addOpenAPISimulation(w, req, false)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationHandler) PutWireMock(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addConvertedSimulation(w, req, this.Hoverfly.GetSimulationFromWireMockMappings, true)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationHandler) PostWireMock(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addConvertedSimulation(w, req, this.Hoverfly.GetSimulationFromWireMockMappings, false)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationHandler) PutPostman(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addConvertedSimulation(w, req, this.Hoverfly.GetSimulationFromPostmanCollection, true)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationHandler) PostPostman(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addConvertedSimulation(w, req, this.Hoverfly.GetSimulationFromPostmanCollection, false)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationHandler) OptionsImport(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, PUT, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SimulationHandler) OptionsLint(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
+
 func (this *SimulationHandler) OptionsSchema(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	w.Header().Add("Allow", "OPTIONS, GET")
 }
@@ -124,11 +268,51 @@ func (this *SimulationHandler) addSimulation(w http.ResponseWriter, req *http.Re
 		return err
 	}
 
+	return this.importSimulation(w, simulationView, body, overrideExisting, nil)
+}
+
+func (this *SimulationHandler) addOpenAPISimulation(w http.ResponseWriter, req *http.Request, overrideExisting bool) error {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	simulationView, err := this.Hoverfly.GetSimulationFromOpenAPISpec(body)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	return this.importSimulation(w, simulationView, body, overrideExisting, nil)
+}
+
+// addConvertedSimulation imports a simulation converted from another tool's format,
+// reporting anything that could not be converted along with the import warnings
+func (this *SimulationHandler) addConvertedSimulation(w http.ResponseWriter, req *http.Request, convert func([]byte) (SimulationViewV5, SimulationImportResult, error), overrideExisting bool) error {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	simulationView, conversionResult, err := convert(body)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	return this.importSimulation(w, simulationView, body, overrideExisting, conversionResult.WarningMessages)
+}
+
+func (this *SimulationHandler) importSimulation(w http.ResponseWriter, simulationView SimulationViewV5, body []byte, overrideExisting bool, conversionWarnings []SimulationImportWarning) error {
 	if overrideExisting {
 		this.Hoverfly.DeleteSimulation()
 	}
 
 	result := this.Hoverfly.PutSimulation(simulationView)
+	if len(conversionWarnings) > 0 {
+		result.WarningMessages = append(conversionWarnings, result.WarningMessages...)
+	}
+
+	return writeSimulationImportResult(w, result, body)
+}
+
+// writeSimulationImportResult writes an error or the warnings of an import, returning
+// an error if it wrote either so that the handler does not write the simulation
+func writeSimulationImportResult(w http.ResponseWriter, result SimulationImportResult, body []byte) error {
 	if result.err != nil {
 
 		log.WithFields(log.Fields{
@@ -136,7 +320,7 @@ func (this *SimulationHandler) addSimulation(w http.ResponseWriter, req *http.Re
 		}).Debug(result.err.Error())
 
 		handlers.WriteErrorResponse(w, "An error occurred: "+result.err.Error(), http.StatusInternalServerError)
-		return err
+		return result.err
 	}
 	if len(result.WarningMessages) > 0 {
 		bytes, _ := util.JSONMarshal(result)
@@ -15,10 +15,12 @@ import (
 )
 
 type HoverflySimulationStub struct {
-	Deleted    bool
-	Simulation SimulationViewV5
-	UrlPattern string
-	Filtered   bool
+	Deleted     bool
+	Simulation  SimulationViewV5
+	UrlPattern  string
+	Filtered    bool
+	OpenAPISpec []byte
+	Converted   []byte
 }
 
 func (this HoverflySimulationStub) GetSimulation() (SimulationViewV5, error) {
@@ -71,6 +73,48 @@ func (this *HoverflySimulationStub) PutSimulation(simulation SimulationViewV5) S
 	return SimulationImportResult{}
 }
 
+func (this *HoverflySimulationStub) GetSimulationFromOpenAPISpec(spec []byte) (SimulationViewV5, error) {
+	this.OpenAPISpec = spec
+	if string(spec) == "invalid" {
+		return SimulationViewV5{}, fmt.Errorf("Unable to parse OpenAPI spec")
+	}
+	return this.GetSimulation()
+}
+
+func (this *HoverflySimulationStub) GetSimulationFromWireMockMappings(mappings []byte) (SimulationViewV5, SimulationImportResult, error) {
+	return this.convert(mappings)
+}
+
+func (this *HoverflySimulationStub) GetSimulationFromPostmanCollection(collection []byte) (SimulationViewV5, SimulationImportResult, error) {
+	return this.convert(collection)
+}
+
+func (this *HoverflySimulationStub) convert(data []byte) (SimulationViewV5, SimulationImportResult, error) {
+	this.Converted = data
+	result := SimulationImportResult{}
+	switch string(data) {
+	case "invalid":
+		return SimulationViewV5{}, result, fmt.Errorf("Unable to parse mappings")
+	case "unsupported":
+		result.AddNotImportedWarning("mappings[0].request.cookies", "cookie matching is not supported")
+	}
+	simulation, _ := this.GetSimulation()
+	return simulation, result, nil
+}
+
+func (this *HoverflySimulationStub) LintSimulation(simulation SimulationViewV5) SimulationLintView {
+	this.Simulation = simulation
+	return SimulationLintView{
+		Problems: []SimulationLintProblemView{
+			{
+				Pair:    0,
+				Field:   "path",
+				Message: "test-problem",
+			},
+		},
+	}
+}
+
 type HoverflySimulationErrorStub struct{}
 
 func (this HoverflySimulationErrorStub) GetSimulation() (SimulationViewV5, error) {
@@ -89,6 +133,22 @@ func (this *HoverflySimulationErrorStub) PutSimulation(simulation SimulationView
 	}
 }
 
+func (this *HoverflySimulationErrorStub) LintSimulation(simulation SimulationViewV5) SimulationLintView {
+	return SimulationLintView{}
+}
+
+func (this *HoverflySimulationErrorStub) GetSimulationFromOpenAPISpec(spec []byte) (SimulationViewV5, error) {
+	return SimulationViewV5{}, fmt.Errorf("error")
+}
+
+func (this *HoverflySimulationErrorStub) GetSimulationFromWireMockMappings(mappings []byte) (SimulationViewV5, SimulationImportResult, error) {
+	return SimulationViewV5{}, SimulationImportResult{}, fmt.Errorf("error")
+}
+
+func (this *HoverflySimulationErrorStub) GetSimulationFromPostmanCollection(collection []byte) (SimulationViewV5, SimulationImportResult, error) {
+	return SimulationViewV5{}, SimulationImportResult{}, fmt.Errorf("error")
+}
+
 type HoverflySimulationWarningStub struct{}
 
 func (this HoverflySimulationWarningStub) GetSimulation() (SimulationViewV5, error) {
@@ -107,6 +167,22 @@ func (this *HoverflySimulationWarningStub) PutSimulation(simulation SimulationVi
 	}
 }
 
+func (this *HoverflySimulationWarningStub) LintSimulation(simulation SimulationViewV5) SimulationLintView {
+	return SimulationLintView{}
+}
+
+func (this *HoverflySimulationWarningStub) GetSimulationFromOpenAPISpec(spec []byte) (SimulationViewV5, error) {
+	return SimulationViewV5{}, nil
+}
+
+func (this *HoverflySimulationWarningStub) GetSimulationFromWireMockMappings(mappings []byte) (SimulationViewV5, SimulationImportResult, error) {
+	return SimulationViewV5{}, SimulationImportResult{}, nil
+}
+
+func (this *HoverflySimulationWarningStub) GetSimulationFromPostmanCollection(collection []byte) (SimulationViewV5, SimulationImportResult, error) {
+	return SimulationViewV5{}, SimulationImportResult{}, nil
+}
+
 func TestSimulationHandler_Get_ReturnsSimulation(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -576,6 +652,216 @@ func TestSimulationHandler_Post_NotCallsDelete(t *testing.T) {
 	Expect(stubHoverfly.Deleted).To(BeFalse())
 }
 
+func TestSimulationHandler_PutOpenAPI_ReplacesSimulationWithOneBuiltFromTheSpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("PUT", "", ioutil.NopCloser(bytes.NewBufferString("openapi: 3.0.0")))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PutOpenAPI, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	Expect(string(stubHoverfly.OpenAPISpec)).To(Equal("openapi: 3.0.0"))
+	Expect(stubHoverfly.Deleted).To(BeTrue())
+	Expect(stubHoverfly.Simulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(stubHoverfly.Simulation.RequestResponsePairs[0].Response.Body).To(Equal("test-body"))
+}
+
+func TestSimulationHandler_PostOpenAPI_AddsSimulationBuiltFromTheSpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "", ioutil.NopCloser(bytes.NewBufferString("openapi: 3.0.0")))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PostOpenAPI, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	Expect(stubHoverfly.Deleted).To(BeFalse())
+	Expect(stubHoverfly.Simulation.RequestResponsePairs).To(HaveLen(1))
+}
+
+func TestSimulationHandler_PutOpenAPI_ReturnsErrorIfSpecIsInvalid(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("PUT", "", ioutil.NopCloser(bytes.NewBufferString("invalid")))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PutOpenAPI, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Unable to parse OpenAPI spec"))
+
+	Expect(stubHoverfly.Deleted).To(BeFalse())
+}
+
+func TestSimulationHandler_PutWireMock_ReplacesSimulationWithOneBuiltFromTheMappings(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("PUT", "", ioutil.NopCloser(bytes.NewBufferString(`{"mappings": []}`)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PutWireMock, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	Expect(string(stubHoverfly.Converted)).To(Equal(`{"mappings": []}`))
+	Expect(stubHoverfly.Deleted).To(BeTrue())
+	Expect(stubHoverfly.Simulation.RequestResponsePairs).To(HaveLen(1))
+}
+
+func TestSimulationHandler_PostPostman_AddsSimulationBuiltFromTheCollection(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "", ioutil.NopCloser(bytes.NewBufferString(`{"info": {}}`)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PostPostman, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	Expect(string(stubHoverfly.Converted)).To(Equal(`{"info": {}}`))
+	Expect(stubHoverfly.Deleted).To(BeFalse())
+	Expect(stubHoverfly.Simulation.RequestResponsePairs).To(HaveLen(1))
+}
+
+func TestSimulationHandler_PutWireMock_ReturnsWarningsForAnythingNotImported(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("PUT", "", ioutil.NopCloser(bytes.NewBufferString("unsupported")))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PutWireMock, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	result := SimulationImportResult{}
+	Expect(json.Unmarshal(response.Body.Bytes(), &result)).To(Succeed())
+	Expect(result.WarningMessages).To(HaveLen(1))
+	Expect(result.WarningMessages[0].Message).To(Equal("WARNING: mappings[0].request.cookies could not be imported, cookie matching is not supported"))
+	Expect(stubHoverfly.Simulation.RequestResponsePairs).To(HaveLen(1))
+}
+
+func TestSimulationHandler_PutPostman_ReturnsErrorIfCollectionIsInvalid(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("PUT", "", ioutil.NopCloser(bytes.NewBufferString("invalid")))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PutPostman, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Unable to parse mappings"))
+
+	Expect(stubHoverfly.Deleted).To(BeFalse())
+}
+
+func TestSimulationHandler_GetLint_LintsTheSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("GET", "", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.GetLint, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var lintView SimulationLintView
+	Expect(json.Unmarshal(response.Body.Bytes(), &lintView)).To(Succeed())
+	Expect(lintView.Problems).To(HaveLen(1))
+	Expect(lintView.Problems[0].Message).To(Equal("test-problem"))
+
+	Expect(stubHoverfly.Simulation.RequestResponsePairs[0].Response.Body).To(Equal("test-body"))
+}
+
+func TestSimulationHandler_PostLint_LintsTheSimulationInTheBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "", ioutil.NopCloser(bytes.NewBufferString(`
+	{
+		"data": {
+			"pairs": [
+				{
+					"request": {
+						"path": [{"matcher": "regex", "value": "/one["}]
+					},
+					"response": {
+						"status": 200
+					}
+				}
+			]
+		},
+		"meta": {
+			"schemaVersion": "v5"
+		}
+	}`)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PostLint, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	Expect(stubHoverfly.Simulation.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("/one["))
+	Expect(stubHoverfly.Deleted).To(BeFalse())
+}
+
+func TestSimulationHandler_PostLint_ReturnsErrorIfJsonDoesntMatchSchema(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "", ioutil.NopCloser(bytes.NewBufferString(`{"data": {}}`)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PostLint, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Invalid JSON, missing \"meta\" object"))
+}
+
+func Test_SimulationHandler_OptionsLint_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	var stubHoverfly HoverflySimulationStub
+	unit := SimulationHandler{Hoverfly: &stubHoverfly}
+
+	request, err := http.NewRequest("OPTIONS", "/api/v2/simulation/lint", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.OptionsLint, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, GET, POST"))
+}
+
 func Test_SimulationHandler_Options_GetsOptions(t *testing.T) {
 	RegisterTestingT(t)
 
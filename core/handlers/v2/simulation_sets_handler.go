@@ -0,0 +1,182 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflySimulationSets interface {
+	GetSimulationSets() SimulationSetsView
+	GetSimulationSet(string) (SimulationViewV5, error)
+	PutSimulationSet(string, SimulationSetSettingsView, SimulationViewV5, bool) SimulationImportResult
+	UpdateSimulationSet(string, SimulationSetSettingsView) error
+	DeleteSimulationSet(string) error
+}
+
+// SimulationSetsHandler manages named simulations, which are matched alongside the
+// default simulation of /api/v2/simulation without replacing it
+type SimulationSetsHandler struct {
+	Hoverfly HoverflySimulationSets
+}
+
+func (this *SimulationSetsHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/simulations", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetAll),
+	))
+	mux.Options("/api/v2/simulations", negroni.New(
+		negroni.HandlerFunc(this.OptionsAll),
+	))
+
+	mux.Get("/api/v2/simulations/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Put("/api/v2/simulations/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Put),
+	))
+	mux.Post("/api/v2/simulations/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Post),
+	))
+	mux.Patch("/api/v2/simulations/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Patch),
+	))
+	mux.Delete("/api/v2/simulations/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Delete),
+	))
+	mux.Options("/api/v2/simulations/:name", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+}
+
+func (this *SimulationSetsHandler) GetAll(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	bytes, _ := json.Marshal(this.Hoverfly.GetSimulationSets())
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationSetsHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	simulationView, err := this.Hoverfly.GetSimulationSet(bone.GetValue(req, "name"))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(simulationView)
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationSetsHandler) Put(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addSimulation(w, req, true)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationSetsHandler) Post(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.addSimulation(w, req, false)
+	if err != nil {
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationSetsHandler) Patch(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	var settings SimulationSetSettingsView
+	err := json.Unmarshal(body, &settings)
+	if err != nil {
+		handlers.WriteErrorResponse(w, "Malformed JSON", http.StatusBadRequest)
+		return
+	}
+
+	err = this.Hoverfly.UpdateSimulationSet(bone.GetValue(req, "name"), settings)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	this.GetAll(w, req, next)
+}
+
+func (this *SimulationSetsHandler) Delete(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.Hoverfly.DeleteSimulationSet(bone.GetValue(req, "name"))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	this.GetAll(w, req, next)
+}
+
+func (this *SimulationSetsHandler) OptionsAll(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SimulationSetsHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, PUT, POST, PATCH, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+// addSimulation adds the simulation in the request body to the named set. The enabled
+// and priority query parameters change the settings of the set.
+func (this *SimulationSetsHandler) addSimulation(w http.ResponseWriter, req *http.Request, overrideExisting bool) error {
+	settings, err := readSimulationSetSettings(req)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	body, _ := ioutil.ReadAll(req.Body)
+
+	simulationView, err := NewSimulationViewFromRequestBody(body)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	result := this.Hoverfly.PutSimulationSet(bone.GetValue(req, "name"), settings, simulationView, overrideExisting)
+
+	return writeSimulationImportResult(w, result, body)
+}
+
+func readSimulationSetSettings(req *http.Request) (SimulationSetSettingsView, error) {
+	settings := SimulationSetSettingsView{}
+	query := req.URL.Query()
+
+	if value := query.Get("enabled"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return settings, fmt.Errorf("enabled must be true or false")
+		}
+		settings.Enabled = &enabled
+	}
+
+	if value := query.Get("priority"); value != "" {
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return settings, fmt.Errorf("priority must be a whole number")
+		}
+		settings.Priority = &priority
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,207 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type HoverflySimulationSetsStub struct {
+	name             string
+	settings         SimulationSetSettingsView
+	simulation       SimulationViewV5
+	overrideExisting bool
+	importResult     SimulationImportResult
+	deleted          bool
+}
+
+func (this *HoverflySimulationSetsStub) GetSimulationSets() SimulationSetsView {
+	return SimulationSetsView{
+		Simulations: []SimulationSetView{
+			{Name: "users", Enabled: true, Priority: 1, Pairs: 2},
+		},
+	}
+}
+
+func (this *HoverflySimulationSetsStub) GetSimulationSet(name string) (SimulationViewV5, error) {
+	if name != "users" {
+		return SimulationViewV5{}, errors.New("Simulation " + name + " not found")
+	}
+	return this.simulation, nil
+}
+
+func (this *HoverflySimulationSetsStub) PutSimulationSet(name string, settings SimulationSetSettingsView, simulation SimulationViewV5, overrideExisting bool) SimulationImportResult {
+	this.name = name
+	this.settings = settings
+	this.simulation = simulation
+	this.overrideExisting = overrideExisting
+	return this.importResult
+}
+
+func (this *HoverflySimulationSetsStub) UpdateSimulationSet(name string, settings SimulationSetSettingsView) error {
+	if name != "users" {
+		return errors.New("Simulation " + name + " not found")
+	}
+	this.name = name
+	this.settings = settings
+	return nil
+}
+
+func (this *HoverflySimulationSetsStub) DeleteSimulationSet(name string) error {
+	if name != "users" {
+		return errors.New("Simulation " + name + " not found")
+	}
+	this.deleted = true
+	return nil
+}
+
+const simulationSetBody = `{"data": {"pairs": [{"request": {"path": [{"matcher": "exact", "value": "/users"}]}, "response": {"status": 200, "body": "users"}}]}, "meta": {"schemaVersion": "v5"}}`
+
+func Test_SimulationSetsHandler_GetAll_ReturnsTheSimulationSets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SimulationSetsHandler{Hoverfly: &HoverflySimulationSetsStub{}}
+
+	request, _ := http.NewRequest("GET", "/api/v2/simulations", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	body, _ := ioutil.ReadAll(response.Body)
+	Expect(string(body)).To(Equal(`{"simulations":[{"name":"users","enabled":true,"priority":1,"pairs":2}]}`))
+}
+
+func Test_SimulationSetsHandler_Get_ReturnsNotFoundForAnUnknownSet(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SimulationSetsHandler{Hoverfly: &HoverflySimulationSetsStub{}}
+
+	request, _ := http.NewRequest("GET", "/api/v2/simulations/payments", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Simulation payments not found"))
+}
+
+func Test_SimulationSetsHandler_Put_ReplacesTheSetWithTheSettingsInTheQuery(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationSetsStub{}
+	unit := &SimulationSetsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("PUT", "/api/v2/simulations/users?priority=-2&enabled=false", bytes.NewBufferString(simulationSetBody))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.name).To(Equal("users"))
+	Expect(*stubHoverfly.settings.Priority).To(Equal(-2))
+	Expect(*stubHoverfly.settings.Enabled).To(BeFalse())
+	Expect(stubHoverfly.overrideExisting).To(BeTrue())
+
+	var simulationView SimulationViewV5
+	body, _ := ioutil.ReadAll(response.Body)
+	Expect(json.Unmarshal(body, &simulationView)).To(Succeed())
+	Expect(simulationView.RequestResponsePairs[0].Response.Body).To(Equal("users"))
+}
+
+func Test_SimulationSetsHandler_Post_AppendsToTheSetWithoutChangingItsSettings(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationSetsStub{}
+	unit := &SimulationSetsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("POST", "/api/v2/simulations/users", bytes.NewBufferString(simulationSetBody))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.overrideExisting).To(BeFalse())
+	Expect(stubHoverfly.settings).To(Equal(SimulationSetSettingsView{}))
+}
+
+func Test_SimulationSetsHandler_Put_ReturnsBadRequestForInvalidSettings(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationSetsStub{}
+	unit := &SimulationSetsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("PUT", "/api/v2/simulations/users?priority=high", bytes.NewBufferString(simulationSetBody))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+	Expect(stubHoverfly.name).To(BeEmpty())
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("priority must be a whole number"))
+}
+
+func Test_SimulationSetsHandler_Put_ReturnsBadRequestForAnInvalidSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationSetsStub{}
+	unit := &SimulationSetsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("PUT", "/api/v2/simulations/users", bytes.NewBufferString(`{"data": {}}`))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+	Expect(stubHoverfly.name).To(BeEmpty())
+}
+
+func Test_SimulationSetsHandler_Patch_UpdatesTheSettings(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationSetsStub{}
+	unit := &SimulationSetsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("PATCH", "/api/v2/simulations/users", bytes.NewBufferString(`{"enabled": false}`))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(*stubHoverfly.settings.Enabled).To(BeFalse())
+	Expect(stubHoverfly.settings.Priority).To(BeNil())
+}
+
+func Test_SimulationSetsHandler_Patch_ReturnsNotFoundForAnUnknownSet(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SimulationSetsHandler{Hoverfly: &HoverflySimulationSetsStub{}}
+
+	request, _ := http.NewRequest("PATCH", "/api/v2/simulations/payments", bytes.NewBufferString(`{"enabled": false}`))
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+}
+
+func Test_SimulationSetsHandler_Delete_DeletesTheSet(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationSetsStub{}
+	unit := &SimulationSetsHandler{Hoverfly: stubHoverfly}
+
+	request, _ := http.NewRequest("DELETE", "/api/v2/simulations/users", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.deleted).To(BeTrue())
+}
+
+func Test_SimulationSetsHandler_Options_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &SimulationSetsHandler{Hoverfly: &HoverflySimulationSetsStub{}}
+
+	request, _ := http.NewRequest("OPTIONS", "/api/v2/simulations/users", nil)
+	response := makeRequestOnRoutes(unit, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, GET, PUT, POST, PATCH, DELETE"))
+}
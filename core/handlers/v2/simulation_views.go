@@ -32,7 +32,17 @@ func NewSimulationViewFromRequestBody(responseBody []byte) (SimulationViewV5, er
 
 	schemaVersion := jsonMap["meta"].(map[string]interface{})["schemaVersion"].(string)
 
-	if schemaVersion == "v5" {
+	if schemaVersion == "v6" {
+		err := ValidateSimulation(jsonMap, SimulationViewV6Schema)
+		if err != nil {
+			return simulationView, errors.New(fmt.Sprintf("Invalid %s simulation: ", schemaVersion) + err.Error())
+		}
+
+		err = json.Unmarshal(responseBody, &simulationView)
+		if err != nil {
+			return SimulationViewV5{}, err
+		}
+	} else if schemaVersion == "v5" {
 		err := ValidateSimulation(jsonMap, SimulationViewV5Schema)
 		if err != nil {
 			return simulationView, errors.New(fmt.Sprintf("Invalid %s simulation: ", schemaVersion) + err.Error())
@@ -141,16 +151,45 @@ func BuildSimulationView(
 	delayLogNormalView v1.ResponseDelayLogNormalPayloadView,
 	version string,
 ) SimulationViewV5 {
-	return SimulationViewV5{
-		DataViewV5{
-			RequestResponsePairs: pairViews,
-			GlobalActions: GlobalActionsView{
-				Delays:          delayView.Data,
-				DelaysLogNormal: delayLogNormalView.Data,
-			},
+	dataView := DataViewV5{
+		RequestResponsePairs: pairViews,
+		GlobalActions: GlobalActionsView{
+			Delays:          delayView.Data,
+			DelaysLogNormal: delayLogNormalView.Data,
 		},
-		*NewMetaView(version),
 	}
+
+	metaView := NewMetaView(version)
+	metaView.SchemaVersion = dataView.RequiredSchemaVersion()
+
+	return SimulationViewV5{
+		dataView,
+		*metaView,
+	}
+}
+
+// RequiredSchemaVersion returns the schema version a simulation with this data is exported as. It is
+// "v6" when the data has anything that only the v6 schema validates, which is response sequences, faults,
+// delays and chunks of a response, and WebSockets, so that they are validated again when imported.
+func (this DataViewV5) RequiredSchemaVersion() string {
+	if len(this.WebSockets) > 0 {
+		return "v6"
+	}
+	for _, pairView := range this.RequestResponsePairs {
+		if len(pairView.Responses) > 0 || pairView.Response.hasV6Fields() {
+			return "v6"
+		}
+	}
+	return "v5"
+}
+
+func (this ResponseDetailsViewV5) hasV6Fields() bool {
+	return this.Weight != 0 ||
+		this.Fault != nil ||
+		this.FixedDelay != 0 ||
+		this.LogNormalDelay != nil ||
+		this.UniformDelay != nil ||
+		len(this.Chunks) > 0
 }
 
 const deprecatedQueryMessage = "Usage of deprecated field `deprecatedQuery` on data.pairs[%v].request.deprecatedQuery, please update your simulation to use `query` field"
@@ -158,6 +197,20 @@ const deprecatedQueryDocs = "https://hoverfly.readthedocs.io/en/latest/pages/tro
 const ContentLengthAndTransferEncodingMessage = "Response contains both Content-Length and Transfer-Encoding headers on data.pairs[%v].response, please remove one of these headers"
 const ContentLengthMismatchMessage = "Response contains incorrect Content-Length header on data.pairs[%v].response, please correct or remove header"
 const pairIgnoredMessage = "data.pairs[%v] is not added due to a conflict with the existing simulation"
+const webSocketIgnoredMessage = "data.websockets[%v] is not added due to a conflict with the existing simulation"
+const notImportedMessage = "%s could not be imported, %s"
+
+// SimulationLintView lists the problems in a simulation that would not stop it being
+// imported, but would stop some of it from working as intended
+type SimulationLintView struct {
+	Problems []SimulationLintProblemView `json:"problems"`
+}
+
+type SimulationLintProblemView struct {
+	Pair    int    `json:"pair"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
 
 type SimulationImportResult struct {
 	err             error                     `json:"error,omitempty"`
@@ -208,3 +261,21 @@ func (s *SimulationImportResult) AddPairIgnoredWarning(requestNumber int) {
 	}
 	s.WarningMessages = append(s.WarningMessages, SimulationImportWarning{Message: warning})
 }
+
+func (s *SimulationImportResult) AddWebSocketIgnoredWarning(webSocketNumber int) {
+	warning := fmt.Sprintf("WARNING: %s", fmt.Sprintf(webSocketIgnoredMessage, webSocketNumber))
+	if s.WarningMessages == nil {
+		s.WarningMessages = []SimulationImportWarning{}
+	}
+	s.WarningMessages = append(s.WarningMessages, SimulationImportWarning{Message: warning})
+}
+
+// AddNotImportedWarning reports part of a simulation in another format that
+// has no equivalent in Hoverfly and has been left out
+func (s *SimulationImportResult) AddNotImportedWarning(location, reason string) {
+	warning := fmt.Sprintf("WARNING: %s", fmt.Sprintf(notImportedMessage, location, reason))
+	if s.WarningMessages == nil {
+		s.WarningMessages = []SimulationImportWarning{}
+	}
+	s.WarningMessages = append(s.WarningMessages, SimulationImportWarning{Message: warning})
+}
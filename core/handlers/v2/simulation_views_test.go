@@ -3,7 +3,9 @@ package v2_test
 import (
 	"testing"
 
+	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/util"
 	. "github.com/onsi/gomega"
 )
 
@@ -291,6 +293,280 @@ func Test_NewSimulationViewFromRequestBody_ReturnErrorMessagesOnInvalidSimulatio
 	Expect(simulation.GlobalActions.Delays).To(HaveLen(0))
 }
 
+func Test_NewSimulationViewFromRequestBody_CanCreateSimulationWithMatcherConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := v2.NewSimulationViewFromRequestBody([]byte(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {
+					"body": [
+						{
+							"matcher": "jsonpath",
+							"value": "$.id",
+							"config": {
+								"negate": true,
+								"doMatch": {
+									"matcher": "regex",
+									"value": "[0-9]+",
+									"config": {
+										"ignoreCase": true
+									}
+								}
+							}
+						}
+					]
+				},
+				"response": {
+					"status": 200
+				}
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v5"
+	}
+}`))
+
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulation.RequestResponsePairs[0].RequestMatcher.Body[0].Config).To(HaveKeyWithValue("negate", true))
+	Expect(simulation.RequestResponsePairs[0].RequestMatcher.Body[0].Config).To(HaveKey("doMatch"))
+}
+
+func Test_NewSimulationViewFromRequestBody_WontCreateSimulationWithInvalidMatcherConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := v2.NewSimulationViewFromRequestBody([]byte(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {
+					"body": [
+						{
+							"matcher": "jsonpath",
+							"value": "$.id",
+							"config": {
+								"doMatch": {
+									"matcher": "regex",
+									"value": "[0-9]+",
+									"config": {
+										"negate": "yes"
+									}
+								}
+							}
+						}
+					]
+				},
+				"response": {
+					"status": 200
+				}
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v5"
+	}
+}`))
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("negate"))
+}
+
+func Test_NewSimulationViewFromRequestBody_CanCreateSimulationWithResponseSequenceFromV6Payload(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := v2.NewSimulationViewFromRequestBody([]byte(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {
+					"path": [
+						{
+							"matcher": "exact",
+							"value": "/flaky"
+						}
+					]
+				},
+				"responses": [
+					{
+						"status": 200,
+						"body": "ok",
+						"weight": 4
+					},
+					{
+						"status": 503,
+						"body": "unavailable",
+						"weight": 1
+					}
+				],
+				"responseStrategy": "weighted"
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v6"
+	}
+}`))
+
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulation.RequestResponsePairs[0].ResponseStrategy).To(Equal("weighted"))
+	Expect(simulation.RequestResponsePairs[0].Responses).To(HaveLen(2))
+	Expect(simulation.RequestResponsePairs[0].Responses[0].Weight).To(Equal(4))
+	Expect(simulation.RequestResponsePairs[0].Responses[1].Status).To(Equal(503))
+}
+
+func Test_NewSimulationViewFromRequestBody_WontCreateSimulationWithUnknownResponseStrategy(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := v2.NewSimulationViewFromRequestBody([]byte(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {},
+				"responses": [
+					{
+						"status": 200
+					}
+				],
+				"responseStrategy": "shuffle"
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v6"
+	}
+}`))
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("responseStrategy"))
+}
+
+func Test_NewSimulationViewFromRequestBody_WontCreateV6SimulationWithoutAnyResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := v2.NewSimulationViewFromRequestBody([]byte(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {}
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v6"
+	}
+}`))
+
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_NewSimulationViewFromRequestBody_WontCreateV6SimulationWithBothResponseAndResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := v2.NewSimulationViewFromRequestBody([]byte(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {},
+				"response": {
+					"status": 200
+				},
+				"responses": [
+					{
+						"status": 200
+					}
+				]
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v6"
+	}
+}`))
+
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_BuildSimulationView_ExportsV6OnlyWhenAPairHasResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	delays := v1.ResponseDelayPayloadView{Data: []v1.ResponseDelayView{}}
+	delaysLogNormal := v1.ResponseDelayLogNormalPayloadView{Data: []v1.ResponseDelayLogNormalView{}}
+	pair := v2.RequestMatcherResponsePairViewV5{
+		Response: v2.ResponseDetailsViewV5{Status: 200, Body: "<ok>"},
+	}
+
+	simulation := v2.BuildSimulationView([]v2.RequestMatcherResponsePairViewV5{pair}, delays, delaysLogNormal, "test")
+	Expect(simulation.SchemaVersion).To(Equal("v5"))
+
+	exported, err := util.JSONMarshal(simulation.RequestResponsePairs[0])
+	Expect(err).To(BeNil())
+	Expect(string(exported)).To(ContainSubstring(`"response":{"status":200,"body":"<ok>"`))
+
+	pair.Responses = []v2.ResponseDetailsViewV5{pair.Response, {Status: 503}}
+	pair.ResponseStrategy = "cycle"
+
+	simulation = v2.BuildSimulationView([]v2.RequestMatcherResponsePairViewV5{pair}, delays, delaysLogNormal, "test")
+	Expect(simulation.SchemaVersion).To(Equal("v6"))
+
+	exported, err = util.JSONMarshal(simulation)
+	Expect(err).To(BeNil())
+	Expect(string(exported)).ToNot(ContainSubstring(`"response":`))
+	Expect(string(exported)).To(ContainSubstring(`"body":"<ok>"`))
+
+	imported, err := v2.NewSimulationViewFromRequestBody(exported)
+	Expect(err).To(BeNil())
+	Expect(imported.RequestResponsePairs[0].Responses).To(HaveLen(2))
+	Expect(imported.RequestResponsePairs[0].ResponseStrategy).To(Equal("cycle"))
+}
+
+func Test_BuildSimulationView_ExportsV6WhenAResponseHasAFault(t *testing.T) {
+	RegisterTestingT(t)
+
+	delays := v1.ResponseDelayPayloadView{Data: []v1.ResponseDelayView{}}
+	delaysLogNormal := v1.ResponseDelayLogNormalPayloadView{Data: []v1.ResponseDelayLogNormalView{}}
+	pair := v2.RequestMatcherResponsePairViewV5{
+		Response: v2.ResponseDetailsViewV5{
+			Status:       200,
+			Fault:        &v2.ResponseFaultView{TruncateBodyAt: 2},
+			UniformDelay: &v2.UniformDelayView{Min: 10, Max: 20},
+		},
+	}
+
+	simulation := v2.BuildSimulationView([]v2.RequestMatcherResponsePairViewV5{pair}, delays, delaysLogNormal, "test")
+	Expect(simulation.SchemaVersion).To(Equal("v6"))
+
+	exported, err := util.JSONMarshal(simulation)
+	Expect(err).To(BeNil())
+
+	imported, err := v2.NewSimulationViewFromRequestBody(exported)
+	Expect(err).To(BeNil())
+	Expect(imported.RequestResponsePairs[0].Response.Fault.TruncateBodyAt).To(Equal(2))
+	Expect(imported.RequestResponsePairs[0].Response.UniformDelay.Max).To(Equal(20))
+
+	pair.Response.Fault.TruncateBodyAt = -1
+
+	exported, err = util.JSONMarshal(v2.BuildSimulationView([]v2.RequestMatcherResponsePairViewV5{pair}, delays, delaysLogNormal, "test"))
+	Expect(err).To(BeNil())
+
+	_, err = v2.NewSimulationViewFromRequestBody(exported)
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("Invalid v6 simulation"))
+}
+
+func Test_DataViewV5_RequiredSchemaVersion_IsV6WithWebSockets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := v2.DataViewV5{RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{{}}}
+	Expect(unit.RequiredSchemaVersion()).To(Equal("v5"))
+
+	unit.WebSockets = []v2.WebSocketViewV5{{}}
+	Expect(unit.RequiredSchemaVersion()).To(Equal("v6"))
+}
+
 func Test_NewSimulationViewFromRequestBody_WontCreateSimulationFromUnknownSchemaVersion(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -65,6 +65,14 @@ type RequestDetailsView struct {
 	QueryMap    map[string][]string `json:"-"`
 	Body        *string             `json:"body"`
 	Headers     map[string][]string `json:"headers"`
+	Grpc        *GrpcRequestView    `json:"grpc,omitempty"`
+}
+
+// GrpcRequestView is the service, method and message of a gRPC call
+type GrpcRequestView struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+	Message string `json:"message"`
 }
 
 //Gets Path - required for interfaces.RequestMatcher
@@ -2,6 +2,7 @@ package v2
 
 import (
 	"github.com/SpectoLabs/hoverfly/core/interfaces"
+	"github.com/SpectoLabs/hoverfly/core/util"
 )
 
 type SimulationViewV5 struct {
@@ -12,11 +13,28 @@ type SimulationViewV5 struct {
 type DataViewV5 struct {
 	RequestResponsePairs []RequestMatcherResponsePairViewV5 `json:"pairs"`
 	GlobalActions        GlobalActionsView                  `json:"globalActions"`
+	WebSockets           []WebSocketViewV5                  `json:"websockets,omitempty"`
 }
 
 type RequestMatcherResponsePairViewV5 struct {
-	RequestMatcher RequestMatcherViewV5  `json:"request"`
-	Response       ResponseDetailsViewV5 `json:"response"`
+	RequestMatcher   RequestMatcherViewV5    `json:"request"`
+	Response         ResponseDetailsViewV5   `json:"response"`
+	Responses        []ResponseDetailsViewV5 `json:"responses,omitempty"`
+	ResponseStrategy string                  `json:"responseStrategy,omitempty"`
+}
+
+// MarshalJSON leaves out the response of a pair with a response sequence, as only
+// the responses of the sequence are sent
+func (this RequestMatcherResponsePairViewV5) MarshalJSON() ([]byte, error) {
+	type pairView RequestMatcherResponsePairViewV5
+	if len(this.Responses) == 0 {
+		return util.JSONMarshal(pairView(this))
+	}
+
+	return util.JSONMarshal(struct {
+		pairView
+		Response *ResponseDetailsViewV5 `json:"response,omitempty"`
+	}{pairView: pairView(this)})
 }
 
 // RequestDetailsView is used when marshalling and unmarshalling RequestDetails
@@ -30,10 +48,40 @@ type RequestMatcherViewV5 struct {
 	Query           *QueryMatcherViewV5        `json:"query,omitempty"`
 	RequiresState   map[string]string          `json:"requiresState,omitempty"`
 	DeprecatedQuery []MatcherViewV5            `json:"deprecatedQuery,omitempty"`
+	Grpc            *GrpcRequestMatcherViewV5  `json:"grpc,omitempty"`
 }
 
 type QueryMatcherViewV5 map[string][]MatcherViewV5
 
+// GrpcRequestMatcherViewV5 matches the service, method and message of a gRPC call
+type GrpcRequestMatcherViewV5 struct {
+	Service []MatcherViewV5 `json:"service,omitempty"`
+	Method  []MatcherViewV5 `json:"method,omitempty"`
+	Message []MatcherViewV5 `json:"message,omitempty"`
+}
+
+// WebSocketViewV5 is a simulated WebSocket, which accepts the handshakes its request matches
+// and then sends the frames of the first message each frame from the client matches
+type WebSocketViewV5 struct {
+	RequestMatcher RequestMatcherViewV5     `json:"request"`
+	Headers        map[string][]string      `json:"headers,omitempty"`
+	OnOpen         []WebSocketFrameViewV5   `json:"onOpen,omitempty"`
+	OnMessage      []WebSocketMessageViewV5 `json:"onMessage,omitempty"`
+}
+
+type WebSocketMessageViewV5 struct {
+	Body   []MatcherViewV5        `json:"body,omitempty"`
+	Frames []WebSocketFrameViewV5 `json:"frames"`
+}
+
+// WebSocketFrameViewV5 is a frame sent to the client, after a delay in milliseconds. The
+// body of a binary frame is base64 encoded.
+type WebSocketFrameViewV5 struct {
+	Body   string `json:"body"`
+	Binary bool   `json:"binary,omitempty"`
+	Delay  int    `json:"delay,omitempty"`
+}
+
 type MatcherViewV5 struct {
 	Matcher string                 `json:"matcher"`
 	Value   interface{}            `json:"value"`
@@ -58,6 +106,38 @@ type ResponseDetailsViewV5 struct {
 	Templated        bool                `json:"templated"`
 	TransitionsState map[string]string   `json:"transitionsState,omitempty"`
 	RemovesState     []string            `json:"removesState,omitempty"`
+	Weight           int                 `json:"weight,omitempty"`
+	Fault            *ResponseFaultView  `json:"fault,omitempty"`
+	FixedDelay       int                 `json:"fixedDelay,omitempty"`
+	LogNormalDelay   *LogNormalDelayView `json:"logNormalDelay,omitempty"`
+	UniformDelay     *UniformDelayView   `json:"uniformDelay,omitempty"`
+	Chunks           []ResponseChunkView `json:"chunks,omitempty"`
+}
+
+// ResponseChunkView is a part of a streamed response body, sent once its delay has passed
+type ResponseChunkView struct {
+	Body  string `json:"body"`
+	Delay int    `json:"delay,omitempty"`
+}
+
+type LogNormalDelayView struct {
+	Min    int `json:"min"`
+	Max    int `json:"max"`
+	Mean   int `json:"mean"`
+	Median int `json:"median"`
+}
+
+type UniformDelayView struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+type ResponseFaultView struct {
+	ConnectionReset      bool `json:"connectionReset,omitempty"`
+	EmptyResponse        bool `json:"emptyResponse,omitempty"`
+	MalformedChunked     bool `json:"malformedChunked,omitempty"`
+	TruncateBodyAt       int  `json:"truncateBodyAt,omitempty"`
+	BandwidthBytesPerSec int  `json:"bandwidthBytesPerSec,omitempty"`
 }
 
 //Gets Status - required for interfaces.Response
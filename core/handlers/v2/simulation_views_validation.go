@@ -527,6 +527,29 @@ var requestV5Definition = map[string]interface{}{
 				".{1,}": map[string]interface{}{"type": "string"},
 			},
 		},
+		"grpc": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"$ref": "#/definitions/field-matchers",
+					},
+				},
+				"method": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"$ref": "#/definitions/field-matchers",
+					},
+				},
+				"message": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"$ref": "#/definitions/field-matchers",
+					},
+				},
+			},
+		},
 	},
 }
 
@@ -537,6 +560,20 @@ var requestFieldMatchersV5Definition = map[string]interface{}{
 			"type": "string",
 		},
 		"value": map[string]interface{}{},
+		"config": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"negate": map[string]interface{}{
+					"type": "boolean",
+				},
+				"ignoreCase": map[string]interface{}{
+					"type": "boolean",
+				},
+				"doMatch": map[string]interface{}{
+					"$ref": "#/definitions/field-matchers",
+				},
+			},
+		},
 	},
 }
 
@@ -549,3 +586,297 @@ var v5MatchersMapDefinition = map[string]interface{}{
 		},
 	},
 }
+
+// V6 Schema
+
+var SimulationViewV6Schema = map[string]interface{}{
+	"description": "Hoverfly simulation schema",
+	"type":        "object",
+	"required": []string{
+		"data", "meta",
+	},
+	"additionalProperties": false,
+	"properties": map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"pairs": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"$ref": "#/definitions/request-response-pair",
+					},
+				},
+				"globalActions": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"delays": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"$ref": "#/definitions/delay",
+							},
+						},
+						"delaysLogNormal": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"$ref": "#/definitions/delay-log-normal",
+							},
+						},
+					},
+				},
+				"websockets": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"$ref": "#/definitions/websocket",
+					},
+				},
+			},
+		},
+		"meta": map[string]interface{}{
+			"$ref": "#/definitions/meta",
+		},
+	},
+	"definitions": map[string]interface{}{
+		"request-response-pair": requestResponsePairDefinitionV6,
+		"request":               requestV5Definition,
+		"response":              responseDefinitionV6,
+		"fault":                 faultDefinition,
+		"chunk":                 chunkDefinition,
+		"field-matchers":        requestFieldMatchersV5Definition,
+		"headers":               headersDefinition,
+		"request-headers":       v5MatchersMapDefinition,
+		"request-queries":       v5MatchersMapDefinition,
+		"delay":                 delaysDefinition,
+		"delay-log-normal":      delaysLogNormalDefinition,
+		"websocket":             webSocketDefinition,
+		"websocket-frame":       webSocketFrameDefinition,
+		"meta":                  metaDefinition,
+	},
+}
+
+var requestResponsePairDefinitionV6 = map[string]interface{}{
+	"type": "object",
+	"required": []string{
+		"request",
+	},
+	"oneOf": []interface{}{
+		map[string]interface{}{
+			"required": []string{"response"},
+		},
+		map[string]interface{}{
+			"required": []string{"responses"},
+		},
+	},
+	"properties": map[string]interface{}{
+		"request": map[string]interface{}{
+			"$ref": "#/definitions/request",
+		},
+		"response": map[string]interface{}{
+			"$ref": "#/definitions/response",
+		},
+		"responses": map[string]interface{}{
+			"type":     "array",
+			"minItems": 1,
+			"items": map[string]interface{}{
+				"$ref": "#/definitions/response",
+			},
+		},
+		"responseStrategy": map[string]interface{}{
+			"type": "string",
+			"enum": []string{
+				"sequential",
+				"cycle",
+				"random",
+				"weighted",
+			},
+		},
+	},
+}
+
+var responseDefinitionV6 = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"body": map[string]interface{}{
+			"type": "string",
+		},
+		"encodedBody": map[string]interface{}{
+			"type": "boolean",
+		},
+		"headers": map[string]interface{}{
+			"$ref": "#/definitions/headers",
+		},
+		"status": map[string]interface{}{
+			"type": "integer",
+		},
+		"templated": map[string]interface{}{
+			"type": "boolean",
+		},
+		"removesState": map[string]interface{}{
+			"type": "array",
+		},
+		"transitionsState": map[string]interface{}{
+			"type": "object",
+			"patternProperties": map[string]interface{}{
+				".{1,}": map[string]interface{}{"type": "string"},
+			},
+		},
+		"weight": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 1,
+		},
+		"fault": map[string]interface{}{
+			"$ref": "#/definitions/fault",
+		},
+		"fixedDelay": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 0,
+		},
+		"logNormalDelay": map[string]interface{}{
+			"type": "object",
+			"required": []string{
+				"mean", "median",
+			},
+			"properties": map[string]interface{}{
+				"min": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 0,
+				},
+				"max": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 0,
+				},
+				"mean": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 1,
+				},
+				"median": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 1,
+				},
+			},
+		},
+		"uniformDelay": map[string]interface{}{
+			"type": "object",
+			"required": []string{
+				"max", "min",
+			},
+			"properties": map[string]interface{}{
+				"min": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 0,
+				},
+				"max": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 0,
+				},
+			},
+		},
+		"chunks": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"$ref": "#/definitions/chunk",
+			},
+		},
+	},
+}
+
+var chunkDefinition = map[string]interface{}{
+	"type": "object",
+	"required": []string{
+		"body",
+	},
+	"properties": map[string]interface{}{
+		"body": map[string]interface{}{
+			"type": "string",
+		},
+		"delay": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 0,
+		},
+	},
+}
+
+var faultDefinition = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"connectionReset": map[string]interface{}{
+			"type": "boolean",
+		},
+		"emptyResponse": map[string]interface{}{
+			"type": "boolean",
+		},
+		"malformedChunked": map[string]interface{}{
+			"type": "boolean",
+		},
+		"truncateBodyAt": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 0,
+		},
+		"bandwidthBytesPerSec": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 0,
+		},
+	},
+}
+
+var webSocketDefinition = map[string]interface{}{
+	"type": "object",
+	"required": []string{
+		"request",
+	},
+	"properties": map[string]interface{}{
+		"request": map[string]interface{}{
+			"$ref": "#/definitions/request",
+		},
+		"headers": map[string]interface{}{
+			"$ref": "#/definitions/headers",
+		},
+		"onOpen": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"$ref": "#/definitions/websocket-frame",
+			},
+		},
+		"onMessage": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"required": []string{
+					"frames",
+				},
+				"properties": map[string]interface{}{
+					"body": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"$ref": "#/definitions/field-matchers",
+						},
+					},
+					"frames": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"$ref": "#/definitions/websocket-frame",
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+var webSocketFrameDefinition = map[string]interface{}{
+	"type": "object",
+	"required": []string{
+		"body",
+	},
+	"properties": map[string]interface{}{
+		"body": map[string]interface{}{
+			"type": "string",
+		},
+		"binary": map[string]interface{}{
+			"type": "boolean",
+		},
+		"delay": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 0,
+		},
+	},
+}
@@ -11,8 +11,15 @@ import (
 	"github.com/go-zoo/bone"
 )
 
+type HoverflyState interface {
+	GetState() map[string]string
+	SetState(map[string]string)
+	PatchState(map[string]string)
+	ClearState()
+}
+
 type StateHandler struct {
-	Hoverfly Hoverfly
+	Hoverfly HoverflyState
 }
 
 func (this *StateHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"github.com/SpectoLabs/hoverfly/core/handlers"
 	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +18,16 @@ func makeRequestOnHandler(handlerFunc negroni.HandlerFunc, request *http.Request
 	return responseRecorder
 }
 
+// makeRequestOnRoutes serves the request through the routes of the handler, so that the names in the path are read
+func makeRequestOnRoutes(handler handlers.AdminHandler, request *http.Request) *httptest.ResponseRecorder {
+	mux := bone.New()
+	handler.RegisterRoutes(mux, &handlers.AuthHandler{})
+
+	responseRecorder := httptest.NewRecorder()
+	mux.ServeHTTP(responseRecorder, request)
+	return responseRecorder
+}
+
 func unmarshalErrorView(buffer *bytes.Buffer) (handlers.ErrorView, error) {
 	body, err := ioutil.ReadAll(buffer)
 	if err != nil {
@@ -0,0 +1,86 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflyUnmatched interface {
+	GetUnmatchedRequests() UnmatchedRequestsView
+	DeleteUnmatchedRequests()
+}
+
+type UnmatchedHandler struct {
+	Hoverfly HoverflyUnmatched
+	Version  string
+}
+
+func (this *UnmatchedHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/unmatched", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Delete("/api/v2/unmatched", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Delete),
+	))
+	mux.Options("/api/v2/unmatched", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+}
+
+// Get returns the unmatched requests, or with format=simulation, a simulation
+// made of the pair suggested for each of them
+func (this *UnmatchedHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	unmatchedView := this.Hoverfly.GetUnmatchedRequests()
+
+	var bytes []byte
+	var err error
+
+	switch req.URL.Query().Get("format") {
+	case "":
+		bytes, err = json.Marshal(unmatchedView)
+	case "simulation":
+		bytes, err = json.Marshal(this.buildSuggestedSimulation(unmatchedView))
+	default:
+		handlers.WriteErrorResponse(w, "Unknown format, only simulation is supported", http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *UnmatchedHandler) Delete(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	this.Hoverfly.DeleteUnmatchedRequests()
+
+	this.Get(w, req, next)
+}
+
+func (this *UnmatchedHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *UnmatchedHandler) buildSuggestedSimulation(unmatchedView UnmatchedRequestsView) SimulationViewV5 {
+	pairs := []RequestMatcherResponsePairViewV5{}
+	for _, unmatched := range unmatchedView.Unmatched {
+		pairs = append(pairs, unmatched.SuggestedPair)
+	}
+
+	return BuildSimulationView(
+		pairs,
+		v1.ResponseDelayPayloadView{Data: []v1.ResponseDelayView{}},
+		v1.ResponseDelayLogNormalPayloadView{Data: []v1.ResponseDelayLogNormalView{}},
+		this.Version,
+	)
+}
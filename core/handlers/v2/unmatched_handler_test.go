@@ -0,0 +1,134 @@
+package v2
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+type HoverflyUnmatchedStub struct {
+	unmatched []UnmatchedRequestView
+}
+
+func (this *HoverflyUnmatchedStub) GetUnmatchedRequests() UnmatchedRequestsView {
+	return UnmatchedRequestsView{Unmatched: this.unmatched}
+}
+
+func (this *HoverflyUnmatchedStub) DeleteUnmatchedRequests() {
+	this.unmatched = []UnmatchedRequestView{}
+}
+
+func newUnmatchedHoverflyStub() *HoverflyUnmatchedStub {
+	path := "/about"
+	return &HoverflyUnmatchedStub{
+		unmatched: []UnmatchedRequestView{
+			{
+				Request: RequestDetailsView{Path: &path},
+				Count:   2,
+				ClosestMiss: &ClosestMissView{
+					MissedFields: []string{"path"},
+				},
+				SuggestedPair: RequestMatcherResponsePairViewV5{
+					RequestMatcher: RequestMatcherViewV5{
+						Path: []MatcherViewV5{NewMatcherView(matchers.Exact, "/about")},
+					},
+					Response: ResponseDetailsViewV5{Status: 200},
+				},
+			},
+		},
+	}
+}
+
+func Test_UnmatchedHandler_Get_ReturnsUnmatchedRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := UnmatchedHandler{Hoverfly: newUnmatchedHoverflyStub()}
+
+	request, err := http.NewRequest("GET", "/api/v2/unmatched", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var unmatchedView UnmatchedRequestsView
+	body, _ := ioutil.ReadAll(response.Body)
+	Expect(json.Unmarshal(body, &unmatchedView)).To(Succeed())
+
+	Expect(unmatchedView.Unmatched).To(HaveLen(1))
+	Expect(*unmatchedView.Unmatched[0].Request.Path).To(Equal("/about"))
+	Expect(unmatchedView.Unmatched[0].Count).To(Equal(2))
+	Expect(unmatchedView.Unmatched[0].ClosestMiss.MissedFields).To(Equal([]string{"path"}))
+}
+
+func Test_UnmatchedHandler_Get_ReturnsTheSuggestedPairsAsASimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := UnmatchedHandler{Hoverfly: newUnmatchedHoverflyStub(), Version: "test"}
+
+	request, err := http.NewRequest("GET", "/api/v2/unmatched?format=simulation", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var simulationView SimulationViewV5
+	body, _ := ioutil.ReadAll(response.Body)
+	Expect(json.Unmarshal(body, &simulationView)).To(Succeed())
+
+	Expect(simulationView.MetaView.SchemaVersion).To(Equal("v5"))
+	Expect(simulationView.MetaView.HoverflyVersion).To(Equal("test"))
+	Expect(simulationView.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulationView.RequestResponsePairs[0].RequestMatcher.Path).To(Equal([]MatcherViewV5{NewMatcherView(matchers.Exact, "/about")}))
+	Expect(simulationView.GlobalActions.Delays).To(BeEmpty())
+}
+
+func Test_UnmatchedHandler_Get_ReturnsBadRequestForAnUnknownFormat(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := UnmatchedHandler{Hoverfly: newUnmatchedHoverflyStub()}
+
+	request, err := http.NewRequest("GET", "/api/v2/unmatched?format=har", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Unknown format, only simulation is supported"))
+}
+
+func Test_UnmatchedHandler_Delete_DeletesUnmatchedRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := newUnmatchedHoverflyStub()
+	unit := UnmatchedHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("DELETE", "/api/v2/unmatched", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Delete, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.unmatched).To(BeEmpty())
+
+	body, _ := ioutil.ReadAll(response.Body)
+	Expect(string(body)).To(Equal(`{"unmatched":[]}`))
+}
+
+func Test_UnmatchedHandler_Options_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := UnmatchedHandler{Hoverfly: newUnmatchedHoverflyStub()}
+
+	request, err := http.NewRequest("OPTIONS", "/api/v2/unmatched", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Options, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, GET, DELETE"))
+}
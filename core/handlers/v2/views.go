@@ -1,6 +1,8 @@
 package v2
 
 import (
+	"encoding/json"
+
 	"github.com/SpectoLabs/hoverfly/core/metrics"
 )
 
@@ -38,6 +40,24 @@ type ModeArgumentsView struct {
 	MatchingStrategy 	*string  `json:"matchingStrategy,omitempty"`
 	Stateful         	bool     `json:"stateful,omitempty"`
 	OverwriteDuplicate 	bool  	 `json:"overwriteDuplicate,omitempty"`
+	CaptureMisses		bool	 `json:"captureMisses,omitempty"`
+	DiffRules		*DiffRulesView `json:"diffRules,omitempty"`
+}
+
+// DiffRulesView is how diff mode compares the simulated and actual responses. Ignored paths
+// are JSONPath expressions for JSON bodies, starting with $, or XPath expressions for XML
+// bodies. Values are normalised by replacing every match of each pattern before they are
+// compared, and numbers are equal when they are no further apart than the tolerance.
+type DiffRulesView struct {
+	Ignore           []string            `json:"ignore,omitempty"`
+	Normalise        []DiffNormaliseView `json:"normalise,omitempty"`
+	IgnoreArrayOrder bool                `json:"ignoreArrayOrder,omitempty"`
+	NumericTolerance float64             `json:"numericTolerance,omitempty"`
+}
+
+type DiffNormaliseView struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
 }
 
 type IsWebServerView struct {
@@ -61,6 +81,15 @@ type HoverflyView struct {
 	UsageView
 	VersionView
 	UpstreamProxyView
+	ImportWatch *ImportWatchView `json:"importWatch,omitempty"`
+}
+
+// ImportWatchView describes the files and directories that the simulation is imported from
+// each time they change, and the outcome of the last import
+type ImportWatchView struct {
+	Paths      []string `json:"paths"`
+	LastReload string   `json:"lastReload,omitempty"`
+	Error      string   `json:"error,omitempty"`
 }
 
 type LogsView struct {
@@ -82,6 +111,7 @@ type ClosestMissView struct {
 	Response       ResponseDetailsViewV5 `json:"response"`
 	RequestMatcher RequestMatcherViewV5  `json:"requestMatcher"`
 	MissedFields   []string              `json:"missedFields"`
+	MissReasons    []string              `json:"missReasons,omitempty"`
 }
 
 type JournalView struct {
@@ -97,10 +127,49 @@ type JournalEntryView struct {
 	Mode        string              `json:"mode"`
 	TimeStarted string              `json:"timeStarted"`
 	Latency     float64             `json:"latency"`
+	Match       *JournalMatchView   `json:"match,omitempty"`
+}
+
+// JournalMatchView is how the request of a journal entry was matched against the simulation,
+// with either the pair that matched or the closest miss
+type JournalMatchView struct {
+	Matched     bool                              `json:"matched"`
+	CacheHit    bool                              `json:"cacheHit"`
+	Pair        *RequestMatcherResponsePairViewV5 `json:"pair,omitempty"`
+	ClosestMiss *ClosestMissView                  `json:"closestMiss,omitempty"`
 }
 
 type JournalEntryFilterView struct {
-	Request *RequestMatcherViewV5 `json:"request"`
+	Request   *RequestMatcherViewV5 `json:"request"`
+	Status    int                   `json:"status,omitempty"`
+	Unmatched bool                  `json:"unmatched,omitempty"`
+}
+
+// JournalVerifyView checks how many journal entries match a filter, or that entries
+// matching each of the InOrder filters were received in that order. Without times,
+// atLeast, atMost or never, at least one matching entry is expected.
+type JournalVerifyView struct {
+	JournalEntryFilterView
+	Times   *int                     `json:"times,omitempty"`
+	AtLeast *int                     `json:"atLeast,omitempty"`
+	AtMost  *int                     `json:"atMost,omitempty"`
+	Never   bool                     `json:"never,omitempty"`
+	InOrder []JournalEntryFilterView `json:"inOrder,omitempty"`
+}
+
+type JournalVerificationView struct {
+	Passed  bool                          `json:"passed"`
+	Message string                        `json:"message"`
+	Count   int                           `json:"count"`
+	Matched []JournalEntryView            `json:"matched,omitempty"`
+	Closest []JournalVerificationMissView `json:"closest,omitempty"`
+}
+
+// JournalVerificationMissView is a journal entry that came closest to matching
+type JournalVerificationMissView struct {
+	Entry        JournalEntryView `json:"entry"`
+	MissedFields []string         `json:"missedFields"`
+	MissReasons  []string         `json:"missReasons,omitempty"`
 }
 
 type StateView struct {
@@ -124,8 +193,9 @@ type SimpleRequestDefinitionView struct {
 }
 
 type DiffReport struct {
-	Timestamp   string            `json:"timestamp"`
-	DiffEntries []DiffReportEntry `json:"diffEntries"`
+	Timestamp   string               `json:"timestamp"`
+	DiffEntries []DiffReportEntry    `json:"diffEntries"`
+	Patch       []JsonPatchOperation `json:"patch,omitempty"`
 }
 
 type DiffReportEntry struct {
@@ -133,3 +203,60 @@ type DiffReportEntry struct {
 	Expected string `json:"expected"`
 	Actual   string `json:"actual"`
 }
+
+// JsonPatchOperation is an RFC 6902 operation of a patch that turns the simulated response
+// into the actual response. The response is the document {"status": ..., "header": {...}, "body": ...}.
+// Value is left out of remove operations only, it is null when the actual value is null.
+type JsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type UnmatchedRequestsView struct {
+	Unmatched []UnmatchedRequestView `json:"unmatched"`
+}
+
+// UnmatchedRequestView is a distinct request that did not match the simulation. The
+// suggested pair matches the request exactly, so that it can be imported once its
+// response has been filled in.
+type UnmatchedRequestView struct {
+	Request       RequestDetailsView               `json:"request"`
+	Count         int                              `json:"count"`
+	FirstSeen     string                           `json:"firstSeen"`
+	LastSeen      string                           `json:"lastSeen"`
+	ClosestMiss   *ClosestMissView                 `json:"closestMiss,omitempty"`
+	SuggestedPair RequestMatcherResponsePairViewV5 `json:"suggestedPair"`
+}
+
+type SimulationSetsView struct {
+	Simulations []SimulationSetView `json:"simulations"`
+}
+
+// SimulationSetView describes a named simulation, which is matched alongside the
+// default simulation while it is enabled. Pairs is the number of pairs it has.
+type SimulationSetView struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled"`
+	Priority int    `json:"priority"`
+	Pairs    int    `json:"pairs"`
+}
+
+// SimulationSetSettingsView changes whether a named simulation is enabled and its
+// priority, leaving either unchanged when it is not set
+type SimulationSetSettingsView struct {
+	Enabled  *bool `json:"enabled,omitempty"`
+	Priority *int  `json:"priority,omitempty"`
+}
+
+type SessionsView struct {
+	Sessions []SessionView `json:"sessions"`
+}
+
+// SessionView describes a session, which has its own simulation, state and journal.
+// Pairs is the number of pairs in its simulation.
+type SessionView struct {
+	Name    string `json:"name"`
+	Created string `json:"created"`
+	Pairs   int    `json:"pairs"`
+}
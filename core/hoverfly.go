@@ -5,15 +5,19 @@ import (
 	"github.com/SpectoLabs/goproxy"
 	"github.com/SpectoLabs/hoverfly/core/authentication/backends"
 	"github.com/SpectoLabs/hoverfly/core/cache"
+	"github.com/SpectoLabs/hoverfly/core/grpc"
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/journal"
 	"github.com/SpectoLabs/hoverfly/core/matching"
 	"github.com/SpectoLabs/hoverfly/core/metrics"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/session"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/templating"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"net"
 	"net/http"
 	"sync"
@@ -27,6 +31,7 @@ type Hoverfly struct {
 	HTTP    *http.Client
 	Cfg     *Configuration
 	Counter *metrics.CounterByMode
+	Metrics *metrics.PrometheusMetrics
 
 	Proxy   *goproxy.ProxyHttpServer
 	SL      *StoppableListener
@@ -37,12 +42,23 @@ type Hoverfly struct {
 
 	state *state.State
 
-	Simulation    *models.Simulation
-	StoreLogsHook *StoreLogsHook
-	Journal       *journal.Journal
-	templator     *templating.Templator
+	Simulation     *models.Simulation
+	SimulationSets *models.SimulationSets
+	StoreLogsHook  *StoreLogsHook
+	Journal        *journal.Journal
+	Sessions       *session.Sessions
+	templator      *templating.Templator
+	importWatcher  *importWatcher
 
-	responsesDiff map[v2.SimpleRequestDefinitionView][]v2.DiffReport
+	// GrpcDescriptors decode the messages of gRPC calls into JSON, for the methods they describe
+	GrpcDescriptors *grpc.Descriptors
+
+	http2Mutex     sync.Mutex
+	http2Client    *http.Client
+	http2ClientFor *http.Client
+
+	responsesDiff     map[v2.SimpleRequestDefinitionView][]v2.DiffReport
+	unmatchedRequests *models.UnmatchedRequests
 }
 
 func NewHoverfly() *Hoverfly {
@@ -50,19 +66,26 @@ func NewHoverfly() *Hoverfly {
 	authBackend := backends.NewCacheBasedAuthBackend(cache.NewInMemoryCache(), cache.NewInMemoryCache())
 
 	hoverfly := &Hoverfly{
-		Simulation:     models.NewSimulation(),
-		Authentication: authBackend,
-		Counter:        metrics.NewModeCounter([]string{modes.Simulate, modes.Synthesize, modes.Modify, modes.Capture, modes.Spy, modes.Diff}),
-		StoreLogsHook:  NewStoreLogsHook(),
-		Journal:        journal.NewJournal(),
-		Cfg:            InitSettings(),
-		state:          state.NewState(),
-		templator:      templating.NewTemplator(),
-		responsesDiff:  make(map[v2.SimpleRequestDefinitionView][]v2.DiffReport),
+		Simulation:        models.NewSimulation(),
+		SimulationSets:    models.NewSimulationSets(),
+		Authentication:    authBackend,
+		Counter:           metrics.NewModeCounter([]string{modes.Simulate, modes.Synthesize, modes.Modify, modes.Capture, modes.Spy, modes.Diff}),
+		StoreLogsHook:     NewStoreLogsHook(),
+		Journal:           journal.NewJournal(),
+		Cfg:               InitSettings(),
+		state:             state.NewState(),
+		templator:         templating.NewTemplator(),
+		responsesDiff:     make(map[v2.SimpleRequestDefinitionView][]v2.DiffReport),
+		unmatchedRequests: models.NewUnmatchedRequests(),
+		GrpcDescriptors:   grpc.NewDescriptors(),
 	}
 
+	hoverfly.Sessions = session.NewSessions(hoverfly.newSessionJournal)
+
 	hoverfly.version = "v1.1.1"
 
+	hoverfly.Metrics = metrics.NewPrometheusMetrics(hoverfly.countSimulationPairs)
+
 	log.AddHook(hoverfly.StoreLogsHook)
 
 	modeMap := make(map[string]modes.Mode)
@@ -81,6 +104,19 @@ func NewHoverfly() *Hoverfly {
 	return hoverfly
 }
 
+// countSimulationPairs counts the pairs that requests can be matched against, which are the pairs
+// of the simulation, of the enabled simulation sets and of the sessions
+func (hf *Hoverfly) countSimulationPairs() float64 {
+	pairs := 0
+	for _, simulation := range hf.SimulationSets.GetByPriority(hf.Simulation) {
+		pairs += len(simulation.GetMatchingPairs())
+	}
+	for _, requestSession := range hf.Sessions.GetAll() {
+		pairs += len(requestSession.Simulation.GetMatchingPairs())
+	}
+	return float64(pairs)
+}
+
 func NewHoverflyWithConfiguration(cfg *Configuration) *Hoverfly {
 	hoverfly := NewHoverfly()
 
@@ -156,6 +192,7 @@ func (hf *Hoverfly) StartProxy() error {
 	}
 	hf.SL = sl
 	server := http.Server{}
+	handler := hf.newProxyHandler()
 
 	hf.Cfg.ProxyControlWG.Add(1)
 
@@ -165,13 +202,23 @@ func (hf *Hoverfly) StartProxy() error {
 			hf.Cfg.ProxyControlWG.Done()
 		}()
 		log.Info("serving proxy")
-		server.Handler = hf.Proxy
+		server.Handler = handler
 		log.Warn(server.Serve(sl))
 	}()
 
 	return nil
 }
 
+// newProxyHandler returns the handler that serves the proxy. The webserver has no TLS to
+// negotiate HTTP/2 with, so it accepts HTTP/2 in cleartext.
+func (hf *Hoverfly) newProxyHandler() http.Handler {
+	handler := withResponseWriter(hf.Proxy)
+	if hf.Cfg.Webserver && hf.Cfg.Http2 {
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
+	return handler
+}
+
 // StopProxy - stops proxy
 func (hf *Hoverfly) StopProxy() {
 	hf.SL.Stop()
@@ -187,13 +234,19 @@ func (hf *Hoverfly) processRequest(req *http.Request) *http.Response {
 			return response
 		}
 	}
+	requestSession := hf.getSession(req)
+
 	requestDetails, err := models.NewRequestDetailsFromHttpRequest(req)
 	if err != nil {
 		return modes.ErrorResponse(req, err, "Could not interpret HTTP request")
 	}
+	requestDetails.Grpc = models.NewGrpcRequestDetails(requestDetails, hf.GrpcDescriptors)
 
 	modeName := hf.Cfg.GetMode()
 	mode := hf.modeMap[modeName]
+	if requestSession != nil {
+		mode = hf.sessionMode(mode, requestSession)
+	}
 	response, err := mode.Process(req, requestDetails)
 
 	if err == nil && hf.Cfg.CORS.Enabled {
@@ -206,14 +259,29 @@ func (hf *Hoverfly) processRequest(req *http.Request) *http.Response {
 		return response
 	}
 
-	respDelay := hf.Simulation.ResponseDelays.GetDelay(requestDetails)
-	if respDelay != nil {
-		respDelay.Execute()
+	// Global delays are only a fallback for responses without a delay of their own
+	if modes.HasResponseDelay(response) {
+		return response
+	}
+
+	// Each named simulation has its own global delays, the first that applies is used
+	simulations := hf.SimulationSets.GetByPriority(hf.Simulation)
+	if requestSession != nil {
+		simulations = []*models.Simulation{requestSession.Simulation}
 	}
 
-	respDelayLogNormal := hf.Simulation.ResponseDelaysLogNormal.GetDelay(requestDetails)
-	if respDelayLogNormal != nil {
-		respDelayLogNormal.Execute()
+	for _, simulation := range simulations {
+		if respDelay := simulation.GetResponseDelays().GetDelay(requestDetails); respDelay != nil {
+			respDelay.Execute()
+			break
+		}
+	}
+
+	for _, simulation := range simulations {
+		if respDelayLogNormal := simulation.GetResponseDelaysLogNormal().GetDelay(requestDetails); respDelayLogNormal != nil {
+			respDelayLogNormal.Execute()
+			break
+		}
 	}
 
 	return response
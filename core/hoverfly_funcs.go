@@ -4,12 +4,16 @@ import (
 	"github.com/aymerick/raymond"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/SpectoLabs/hoverfly/core/errors"
+	"github.com/SpectoLabs/hoverfly/core/grpc"
 	"github.com/SpectoLabs/hoverfly/core/matching"
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/metrics"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
 )
@@ -24,6 +28,12 @@ func (hf *Hoverfly) DoRequest(request *http.Request) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// gRPC only works over HTTP/2
+	if grpc.IsGrpc(request.Header) {
+		client = hf.getHttp2Client(client)
+	}
+
 	resp, err := client.Do(request)
 
 	if err != nil {
@@ -42,28 +52,43 @@ func (hf *Hoverfly) DoRequest(request *http.Request) (*http.Response, error) {
 
 // GetResponse returns stored response from cache
 func (hf *Hoverfly) GetResponse(requestDetails models.RequestDetails) (*models.ResponseDetails, *errors.HoverflyError) {
+	return hf.getResponse(requestDetails, &hf.CacheMatcher, hf.SimulationSets.GetMatchingSimulations(hf.Simulation), hf.state, hf.unmatchedRequests)
+}
+
+// getResponse matches the request against the simulations, using and updating the given state. Requests
+// that do not match are added to the unmatched requests, unless they are nil.
+func (hf *Hoverfly) getResponse(requestDetails models.RequestDetails, cacheMatcher *matching.CacheMatcher, simulations []*models.Simulation, requestState *state.State, unmatchedRequests *models.UnmatchedRequests) (*models.ResponseDetails, *errors.HoverflyError) {
 
 	var response models.ResponseDetails
+	var responses *models.ResponseSequence
 	var cachedResponse *models.CachedResponse
+	var match *models.ResponseMatch
 
-	cachedResponse, cacheErr := hf.CacheMatcher.GetCachedResponse(&requestDetails)
+	cachedResponse, cacheErr := cacheMatcher.GetCachedResponse(&requestDetails)
 
 	// Get the cached response and return if there is a miss
 	if cacheErr == nil && cachedResponse.MatchingPair == nil {
+		hf.Metrics.CountMatch(metrics.MatchMiss)
+		if unmatchedRequests != nil {
+			unmatchedRequests.Add(requestDetails, cachedResponse.ClosestMiss)
+		}
 		return nil, errors.MatchingFailedError(cachedResponse.ClosestMiss)
 		// If it's cached, use that response
 	} else if cacheErr == nil {
+		hf.Metrics.CountMatch(metrics.MatchCacheHit)
 		response = cachedResponse.MatchingPair.Response
+		responses = cachedResponse.MatchingPair.Responses
+		match = &models.ResponseMatch{Pair: cachedResponse.MatchingPair, CacheHit: true}
 		//If it's not cached, perform matching to find a hit
 	} else {
 		mode := (hf.modeMap[modes.Simulate]).(*modes.SimulateMode)
 
 		// Matching
-		result := matching.Match(mode.MatchingStrategy, requestDetails, hf.Cfg.Webserver, hf.Simulation, hf.state)
+		result := matching.MatchSimulations(mode.MatchingStrategy, requestDetails, hf.Cfg.Webserver, simulations, requestState)
 
 		// Cache result
 		if result.Cachable {
-			cachedResponse, _ = hf.CacheMatcher.SaveRequestMatcherResponsePair(requestDetails, result.Pair, result.Error)
+			cachedResponse, _ = cacheMatcher.SaveRequestMatcherResponsePair(requestDetails, result.Pair, result.Error)
 		}
 
 		// If we miss, just return
@@ -76,12 +101,34 @@ func (hf *Hoverfly) GetResponse(requestDetails models.RequestDetails) (*models.R
 				"method":      requestDetails.Method,
 			}).Warn("Failed to find matching request from simulation")
 
+			hf.Metrics.CountMatch(metrics.MatchMiss)
+			if unmatchedRequests != nil {
+				unmatchedRequests.Add(requestDetails, result.Error.ClosestMiss)
+			}
 			return nil, errors.MatchingFailedError(result.Error.ClosestMiss)
 		} else {
+			hf.Metrics.CountMatch(metrics.MatchHit)
 			response = result.Pair.Response
+			responses = result.Pair.Responses
+			match = &models.ResponseMatch{Pair: result.Pair}
 		}
 	}
 
+	// Pairs with a response sequence pick the response for this request, each
+	// response has its own body so the cached template cannot be reused
+	if responses != nil {
+		response = responses.Next()
+		cachedResponse = nil
+	}
+
+	response.Match = match
+
+	if response.HasDelay() {
+		responseDelay := response.GenerateDelay()
+		log.Infof("Pausing for %dms before sending the response to simulate delays", responseDelay)
+		time.Sleep(time.Duration(responseDelay) * time.Millisecond)
+	}
+
 	// Templating applies at the end, once we have loaded a response. Comes BEFORE state transitions,
 	// as we use the current state in templates
 	if response.Templated == true {
@@ -97,28 +144,81 @@ func (hf *Hoverfly) GetResponse(requestDetails models.RequestDetails) (*models.R
 			}
 		}
 
-		responseBody, err :=  hf.templator.RenderTemplate(template, &requestDetails, hf.state.State)
+		responseBody, err :=  hf.templator.RenderTemplate(template, &requestDetails, requestState.State)
 
 		if err == nil {
 			response.Body = responseBody
 		} else {
 			log.Warnf("Failed to render response template: %s", err.Error())
 		}
+
+		response.Chunks = hf.renderResponseChunks(response.Chunks, &requestDetails, requestState)
 	}
 
 	// State transitions after we have the response
 	if response.TransitionsState != nil {
-		hf.state.PatchState(response.TransitionsState)
+		requestState.PatchState(response.TransitionsState)
 	}
 	if response.RemovesState != nil {
-		hf.state.RemoveState(response.RemovesState)
+		requestState.RemoveState(response.RemovesState)
 	}
 
 	return &response, nil
 }
 
+// renderResponseChunks renders the body of each chunk of a templated response. The chunks
+// are copied, as they are shared with the simulation.
+func (hf *Hoverfly) renderResponseChunks(chunks []models.ResponseChunk, requestDetails *models.RequestDetails, requestState *state.State) []models.ResponseChunk {
+	if len(chunks) == 0 {
+		return chunks
+	}
+
+	rendered := make([]models.ResponseChunk, len(chunks))
+	for i, chunk := range chunks {
+		rendered[i] = chunk
+		template, err := hf.templator.ParseTemplate(chunk.Body)
+		if err == nil {
+			var body string
+			if body, err = hf.templator.RenderTemplate(template, requestDetails, requestState.State); err == nil {
+				rendered[i].Body = body
+			}
+		}
+		if err != nil {
+			log.Warnf("Failed to render response chunk template: %s", err.Error())
+		}
+	}
+
+	return rendered
+}
+
 // save gets request fingerprint, extracts request body, status code and headers, then saves it to cache
 func (hf *Hoverfly) Save(request *models.RequestDetails, response *models.ResponseDetails, modeArgs *modes.ModeArguments) error {
+	if err := saveInSimulation(hf.Simulation, hf.state, request, response, modeArgs); err != nil {
+		return err
+	}
+
+	// Spy mode caches misses, which would otherwise hide the pair that has just been captured
+	hf.CacheMatcher.FlushCache()
+	return nil
+}
+
+// saveInSimulation adds a pair for the request and response to the simulation
+func saveInSimulation(simulation *models.Simulation, requestState *state.State, request *models.RequestDetails, response *models.ResponseDetails, modeArgs *modes.ModeArguments) error {
+	pair := newRequestMatcherResponsePair(request, response, modeArgs)
+	if modeArgs.Stateful {
+		simulation.AddPairInSequence(&pair, requestState)
+	} else if modeArgs.OverwriteDuplicate {
+		simulation.AddPairWithOverwritingDuplicate(&pair)
+	} else {
+		simulation.AddPair(&pair)
+	}
+
+	return nil
+}
+
+// newRequestMatcherResponsePair creates a pair that matches the request exactly, along
+// with the headers named in the mode arguments, and responds with the response
+func newRequestMatcherResponsePair(request *models.RequestDetails, response *models.ResponseDetails, modeArgs *modes.ModeArguments) models.RequestMatcherResponsePair {
 	body := []models.RequestFieldMatchers{
 		{
 			Matcher: matchers.Exact,
@@ -140,6 +240,22 @@ func (hf *Hoverfly) Save(request *models.RequestDetails, response *models.Respon
 				Value:   request.Body,
 			},
 		}
+	} else if contentType == "form" {
+		if formValue := buildFormMatcherValue(request); formValue != nil {
+			body = []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Form,
+					Value:   formValue,
+				},
+			}
+		}
+	}
+
+	// gRPC calls are matched on the message they send rather than on the framed body
+	var grpcMatcher *models.GrpcRequestMatcher
+	if request.Grpc != nil {
+		body = nil
+		grpcMatcher = newGrpcRequestMatcher(request.Grpc)
 	}
 
 	var headers map[string][]string
@@ -213,23 +329,83 @@ func (hf *Hoverfly) Save(request *models.RequestDetails, response *models.Respon
 			Query:   queries,
 			Body:    body,
 			Headers: requestHeaders,
+			Grpc:    grpcMatcher,
 		},
 		Response: *response,
 	}
-	if modeArgs.Stateful {
-		hf.Simulation.AddPairInSequence(&pair, hf.state)
-	} else if modeArgs.OverwriteDuplicate {
-		hf.Simulation.AddPairWithOverwritingDuplicate(&pair)
-	} else {
-		hf.Simulation.AddPair(&pair)
+
+	return pair
+}
+
+// newGrpcRequestMatcher creates a gRPC matcher that matches the call exactly. Messages
+// decoded with a descriptor set are matched as JSON, and streaming calls, which have no
+// message, are matched on their service and method alone.
+func newGrpcRequestMatcher(call *models.GrpcRequestDetails) *models.GrpcRequestMatcher {
+	grpcMatcher := &models.GrpcRequestMatcher{
+		Service: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   call.Service,
+			},
+		},
+		Method: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   call.Method,
+			},
+		},
 	}
 
-	return nil
+	if call.Message != "" {
+		messageMatcher := matchers.Exact
+		// Decoded messages are JSON objects, and "{" is never part of base64
+		if strings.HasPrefix(call.Message, "{") {
+			messageMatcher = matchers.Json
+		}
+		grpcMatcher.Message = []models.RequestFieldMatchers{
+			{
+				Matcher: messageMatcher,
+				Value:   call.Message,
+			},
+		}
+	}
+
+	return grpcMatcher
+}
+
+// buildFormMatcherValue creates a form matcher value with an exact matcher for each
+// value of each field in the request body. It is built from generic types so that it is
+// identical to a value which has been read from simulation JSON
+func buildFormMatcherValue(request *models.RequestDetails) map[string]interface{} {
+	fields, err := util.ParseFormBody(request.Body, request.Headers["Content-Type"][0])
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Warn("Failed to parse form body, falling back to exact matching")
+		return nil
+	}
+
+	formValue := map[string]interface{}{}
+	for field, values := range fields {
+		fieldMatchers := []interface{}{}
+		for _, value := range values {
+			fieldMatchers = append(fieldMatchers, map[string]interface{}{
+				"matcher": matchers.Exact,
+				"value":   value,
+			})
+		}
+		formValue[field] = fieldMatchers
+	}
+
+	return formValue
 }
 
 func (this Hoverfly) ApplyMiddleware(pair models.RequestResponsePair) (models.RequestResponsePair, error) {
 	if this.Cfg.Middleware.IsSet() {
-		return this.Cfg.Middleware.Execute(pair)
+		started := time.Now()
+		pair, err := this.Cfg.Middleware.Execute(pair)
+		this.Metrics.ObserveMiddleware(time.Since(started), err)
+		return pair, err
 	}
 
 	return pair, nil
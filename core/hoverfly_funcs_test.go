@@ -12,6 +12,7 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/matching"
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/metrics"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	. "github.com/onsi/gomega"
 )
@@ -244,6 +245,68 @@ func Test_Hoverfly_GetResponse_WillCacheMisses(t *testing.T) {
 	Expect(cachedResponse.ClosestMiss).To(BeNil())
 }
 
+func Test_Hoverfly_GetResponse_CountsMatchOutcomes(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "somehost.com",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+		},
+	})
+
+	unit.GetResponse(models.RequestDetails{Destination: "somehost.com"})
+	unit.GetResponse(models.RequestDetails{Destination: "somehost.com"})
+	unit.GetResponse(models.RequestDetails{Destination: "otherhost.com"})
+
+	Expect(unit.Metrics.Matches.Value(metrics.MatchHit)).To(Equal(float64(1)))
+	Expect(unit.Metrics.Matches.Value(metrics.MatchCacheHit)).To(Equal(float64(1)))
+	Expect(unit.Metrics.Matches.Value(metrics.MatchMiss)).To(Equal(float64(1)))
+}
+
+func Test_Hoverfly_GetResponse_RecordsHowTheRequestWasMatched(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "somehost.com",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+		},
+	})
+
+	response, err := unit.GetResponse(models.RequestDetails{Destination: "somehost.com"})
+	Expect(err).To(BeNil())
+	Expect(response.Match).ToNot(BeNil())
+	Expect(response.Match.CacheHit).To(BeFalse())
+	Expect(response.Match.Pair.RequestMatcher.Destination[0].Value).To(Equal("somehost.com"))
+
+	response, err = unit.GetResponse(models.RequestDetails{Destination: "somehost.com"})
+	Expect(err).To(BeNil())
+	Expect(response.Match.CacheHit).To(BeTrue())
+	Expect(response.Match.Pair.RequestMatcher.Destination[0].Value).To(Equal("somehost.com"))
+
+	_, err = unit.GetResponse(models.RequestDetails{Destination: "otherhost.com"})
+	Expect(err).ToNot(BeNil())
+	Expect(err.ClosestMiss).ToNot(BeNil())
+	Expect(err.ClosestMiss.MissedFields).To(ConsistOf("destination"))
+}
+
 func Test_Hoverfly_GetResponse_WillCacheClosestMiss(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -640,6 +703,101 @@ func Test_Hoverfly_GetResponse_TransitioningBetweenStatesWhenSimulating(t *testi
 	Expect(string(response.Body)).To(Equal(`empty`))
 }
 
+func Test_Hoverfly_GetResponse_ReturnsResponsesFromSequenceAndResetsWhenStateIsCleared(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	unit.Simulation.AddPair(models.NewRequestMatcherResponsePairFromView(&v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/flaky",
+				},
+			},
+		},
+		Responses: []v2.ResponseDetailsViewV5{
+			{
+				Status: 503,
+				Body:   "unavailable",
+			},
+			{
+				Status:    200,
+				Body:      "{{ Request.Path.[0] }}",
+				Templated: true,
+			},
+		},
+	}))
+
+	request := models.RequestDetails{
+		Path: "/flaky",
+	}
+
+	response, err := unit.GetResponse(request)
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(http.StatusServiceUnavailable))
+	Expect(response.Body).To(Equal("unavailable"))
+
+	response, err = unit.GetResponse(request)
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(http.StatusOK))
+	Expect(response.Body).To(Equal("flaky"))
+
+	response, err = unit.GetResponse(request)
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(http.StatusOK))
+
+	unit.ClearState()
+
+	response, err = unit.GetResponse(request)
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(http.StatusServiceUnavailable))
+}
+
+func Test_Hoverfly_GetResponse_ResetsSequencesOfSimulationSetsWhenStateIsCleared(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	unit.SimulationSets.GetOrCreate("flaky").AddPair(models.NewRequestMatcherResponsePairFromView(&v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/flaky",
+				},
+			},
+		},
+		Responses: []v2.ResponseDetailsViewV5{
+			{
+				Status: 503,
+			},
+			{
+				Status: 200,
+			},
+		},
+	}))
+
+	request := models.RequestDetails{
+		Path: "/flaky",
+	}
+
+	response, err := unit.GetResponse(request)
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(http.StatusServiceUnavailable))
+
+	response, err = unit.GetResponse(request)
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(http.StatusOK))
+
+	unit.ClearState()
+
+	response, err = unit.GetResponse(request)
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(http.StatusServiceUnavailable))
+}
+
 func Test_Hoverfly_GetResponse_GetNotRecordedRequest(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -940,6 +1098,55 @@ func Test_Hoverfly_Save_SavesRequestBodyAsXmlPathIfContentTypeIsXml(t *testing.T
 	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Value).To(Equal(`<xml>`))
 }
 
+func Test_Hoverfly_Save_SavesRequestBodyAsFormIfContentTypeIsForm(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Body: `username=hoverfly&tags=a&tags=b`,
+		Headers: map[string][]string{
+			"Content-Type": {"application/x-www-form-urlencoded"},
+		},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{})
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(1))
+
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body).To(HaveLen(1))
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Matcher).To(Equal("form"))
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Value).To(Equal(map[string]interface{}{
+		"username": []interface{}{
+			map[string]interface{}{"matcher": "exact", "value": "hoverfly"},
+		},
+		"tags": []interface{}{
+			map[string]interface{}{"matcher": "exact", "value": "a"},
+			map[string]interface{}{"matcher": "exact", "value": "b"},
+		},
+	}))
+
+	Expect(matchers.FormMatch(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Value, "tags=b&tags=a&username=hoverfly")).To(BeTrue())
+	Expect(matchers.FormMatch(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Value, "tags=a&username=hoverfly")).To(BeFalse())
+}
+
+func Test_Hoverfly_Save_SavesRequestBodyAsExactIfFormBodyCannotBeParsed(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Body: `not multipart`,
+		Headers: map[string][]string{
+			"Content-Type": {"multipart/form-data"},
+		},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{})
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(1))
+
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body).To(HaveLen(1))
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Matcher).To(Equal("exact"))
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Value).To(Equal(`not multipart`))
+}
+
 func Test_Hoverfly_Save_CanAddPairStatefully(t *testing.T) {
 	RegisterTestingT(t)
 
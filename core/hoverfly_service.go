@@ -4,17 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"github.com/SpectoLabs/hoverfly/core/delay"
+	"net/http"
 	"regexp"
 
 	"strings"
 
 	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/importers"
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/lint"
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/metrics"
 	"github.com/SpectoLabs/hoverfly/core/middleware"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/openapi"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
@@ -80,6 +85,10 @@ func (this *Hoverfly) SetModeWithArguments(modeView v2.ModeView) error {
 		}
 	}
 
+	if _, err := modes.NewDiffRules(modeView.Arguments.DiffRules); err != nil {
+		return err
+	}
+
 	matchingStrategy := modeView.Arguments.MatchingStrategy
 	if modeView.Mode == modes.Simulate {
 		if matchingStrategy == nil {
@@ -93,7 +102,11 @@ func (this *Hoverfly) SetModeWithArguments(modeView v2.ModeView) error {
 	if this.Cfg.GetMode() == "capture" {
 		this.CacheMatcher.FlushCache()
 	} else if this.Cfg.GetMode() == "simulate" || this.Cfg.GetMode() == "spy" {
-		this.CacheMatcher.PreloadCache(*this.Simulation)
+		// Preload the lowest priority first, so that higher priority pairs replace them
+		simulations := this.SimulationSets.GetByPriority(this.Simulation)
+		for i := len(simulations) - 1; i >= 0; i-- {
+			this.CacheMatcher.PreloadCache(*simulations[i])
+		}
 	}
 
 	modeArguments := modes.ModeArguments{
@@ -101,6 +114,8 @@ func (this *Hoverfly) SetModeWithArguments(modeView v2.ModeView) error {
 		MatchingStrategy: 	matchingStrategy,
 		Stateful:         	modeView.Arguments.Stateful,
 		OverwriteDuplicate:	modeView.Arguments.OverwriteDuplicate,
+		CaptureMisses:		modeView.Arguments.CaptureMisses,
+		DiffRules:		modeView.Arguments.DiffRules,
 	}
 
 	this.modeMap[this.Cfg.GetMode()].SetArguments(modeArguments)
@@ -181,11 +196,31 @@ func (hf Hoverfly) FlushCache() error {
 }
 
 func (hf *Hoverfly) SetResponseDelays(payloadView v1.ResponseDelayPayloadView) error {
-	err := models.ValidateResponseDelayPayload(payloadView)
+	responseDelays, err := buildResponseDelays(payloadView)
+	if err != nil {
+		return err
+	}
+
+	hf.Simulation.SetResponseDelays(responseDelays)
+	return nil
+}
+
+func (hf *Hoverfly) SetResponseDelaysLogNormal(payloadView v1.ResponseDelayLogNormalPayloadView) error {
+	responseDelaysLogNormal, err := buildResponseDelaysLogNormal(payloadView)
 	if err != nil {
 		return err
 	}
 
+	hf.Simulation.SetResponseDelaysLogNormal(responseDelaysLogNormal)
+	return nil
+}
+
+func buildResponseDelays(payloadView v1.ResponseDelayPayloadView) (*models.ResponseDelayList, error) {
+	err := models.ValidateResponseDelayPayload(payloadView)
+	if err != nil {
+		return nil, err
+	}
+
 	var responseDelays models.ResponseDelayList
 
 	for _, responseDelayView := range payloadView.Data {
@@ -196,14 +231,13 @@ func (hf *Hoverfly) SetResponseDelays(payloadView v1.ResponseDelayPayloadView) e
 		})
 	}
 
-	hf.Simulation.ResponseDelays = &responseDelays
-	return nil
+	return &responseDelays, nil
 }
 
-func (hf *Hoverfly) SetResponseDelaysLogNormal(payloadView v1.ResponseDelayLogNormalPayloadView) error {
+func buildResponseDelaysLogNormal(payloadView v1.ResponseDelayLogNormalPayloadView) (*models.ResponseDelayLogNormalList, error) {
 	err := models.ValidateResponseDelayLogNormalPayload(payloadView)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var responseDelaysLogNormal models.ResponseDelayLogNormalList
@@ -225,16 +259,15 @@ func (hf *Hoverfly) SetResponseDelaysLogNormal(payloadView v1.ResponseDelayLogNo
 		})
 	}
 
-	hf.Simulation.ResponseDelaysLogNormal = &responseDelaysLogNormal
-	return nil
+	return &responseDelaysLogNormal, nil
 }
 
 func (hf *Hoverfly) DeleteResponseDelays() {
-	hf.Simulation.ResponseDelays = &models.ResponseDelayList{}
+	hf.Simulation.SetResponseDelays(&models.ResponseDelayList{})
 }
 
 func (hf *Hoverfly) DeleteResponseDelaysLogNormal() {
-	hf.Simulation.ResponseDelaysLogNormal = &models.ResponseDelayLogNormalList{}
+	hf.Simulation.SetResponseDelaysLogNormal(&models.ResponseDelayLogNormalList{})
 }
 
 func (hf Hoverfly) GetStats() metrics.Stats {
@@ -242,16 +275,38 @@ func (hf Hoverfly) GetStats() metrics.Stats {
 }
 
 func (hf Hoverfly) GetSimulation() (v2.SimulationViewV5, error) {
+	return hf.buildSimulationView(hf.Simulation), nil
+}
+
+func (hf *Hoverfly) buildSimulationView(simulation *models.Simulation) v2.SimulationViewV5 {
 	pairViews := make([]v2.RequestMatcherResponsePairViewV5, 0)
 
-	for _, v := range hf.Simulation.GetMatchingPairs() {
+	for _, v := range simulation.GetMatchingPairs() {
 		pairViews = append(pairViews, v.BuildView())
 	}
 
-	return v2.BuildSimulationView(pairViews,
-		hf.Simulation.ResponseDelays.ConvertToResponseDelayPayloadView(),
-		hf.Simulation.ResponseDelaysLogNormal.ConvertToResponseDelayLogNormalPayloadView(),
-		hf.version), nil
+	simulationView := v2.BuildSimulationView(pairViews,
+		simulation.GetResponseDelays().ConvertToResponseDelayPayloadView(),
+		simulation.GetResponseDelaysLogNormal().ConvertToResponseDelayLogNormalPayloadView(),
+		hf.version)
+
+	for _, webSocket := range simulation.GetWebSockets() {
+		simulationView.WebSockets = append(simulationView.WebSockets, webSocket.BuildView())
+	}
+	simulationView.SchemaVersion = simulationView.RequiredSchemaVersion()
+
+	return simulationView
+}
+
+// LintSimulation finds the problems in a simulation that would not stop it being imported,
+// but would stop some of it from working as intended
+func (hf *Hoverfly) LintSimulation(simulationView v2.SimulationViewV5) v2.SimulationLintView {
+	pairs := []models.RequestMatcherResponsePair{}
+	for _, pairView := range simulationView.RequestResponsePairs {
+		pairs = append(pairs, *models.NewRequestMatcherResponsePairFromView(&pairView))
+	}
+
+	return lint.Pairs(pairs, hf.Cfg.Webserver)
 }
 
 func (hf Hoverfly) GetFilteredSimulation(urlPattern string) (v2.SimulationViewV5, error) {
@@ -278,13 +333,57 @@ func (hf Hoverfly) GetFilteredSimulation(urlPattern string) (v2.SimulationViewV5
 	}
 
 	return v2.BuildSimulationView(pairViews,
-		hf.Simulation.ResponseDelays.ConvertToResponseDelayPayloadView(),
-		hf.Simulation.ResponseDelaysLogNormal.ConvertToResponseDelayLogNormalPayloadView(),
+		hf.Simulation.GetResponseDelays().ConvertToResponseDelayPayloadView(),
+		hf.Simulation.GetResponseDelaysLogNormal().ConvertToResponseDelayLogNormalPayloadView(),
+		hf.version), nil
+}
+
+// GetSimulationFromOpenAPISpec builds a simulation with a request response pair
+// for each operation of an OpenAPI 2 or 3 spec
+func (hf *Hoverfly) GetSimulationFromOpenAPISpec(spec []byte) (v2.SimulationViewV5, error) {
+	pairViews, err := openapi.ConvertToPairs(spec)
+	if err != nil {
+		return v2.SimulationViewV5{}, err
+	}
+
+	return v2.BuildSimulationView(pairViews,
+		v1.ResponseDelayPayloadView{},
+		v1.ResponseDelayLogNormalPayloadView{},
 		hf.version), nil
 }
 
+// GetSimulationFromWireMockMappings builds a simulation from WireMock stub mappings,
+// returning warnings for anything that could not be converted
+func (hf *Hoverfly) GetSimulationFromWireMockMappings(mappings []byte) (v2.SimulationViewV5, v2.SimulationImportResult, error) {
+	dataView, result, err := importers.ImportWireMock(mappings)
+	if err != nil {
+		return v2.SimulationViewV5{}, result, err
+	}
+
+	return hf.buildConvertedSimulationView(dataView), result, nil
+}
+
+// GetSimulationFromPostmanCollection builds a simulation from the example responses
+// of a Postman collection, returning warnings for anything that could not be converted
+func (hf *Hoverfly) GetSimulationFromPostmanCollection(collection []byte) (v2.SimulationViewV5, v2.SimulationImportResult, error) {
+	dataView, result, err := importers.ImportPostman(collection)
+	if err != nil {
+		return v2.SimulationViewV5{}, result, err
+	}
+
+	return hf.buildConvertedSimulationView(dataView), result, nil
+}
+
+func (hf *Hoverfly) buildConvertedSimulationView(dataView v2.DataViewV5) v2.SimulationViewV5 {
+	return v2.BuildSimulationView(dataView.RequestResponsePairs,
+		v1.ResponseDelayPayloadView{Data: dataView.GlobalActions.Delays},
+		v1.ResponseDelayLogNormalPayloadView{Data: dataView.GlobalActions.DelaysLogNormal},
+		hf.version)
+}
+
 func (this *Hoverfly) PutSimulation(simulationView v2.SimulationViewV5) v2.SimulationImportResult {
 	result := this.importRequestResponsePairViews(simulationView.DataViewV5.RequestResponsePairs)
+	importWebSocketViews(this.Simulation, simulationView.WebSockets, &result)
 
 	result.AddError(this.SetResponseDelays(v1.ResponseDelayPayloadView{Data: simulationView.GlobalActions.Delays}))
 	result.AddError(this.SetResponseDelaysLogNormal(v1.ResponseDelayLogNormalPayloadView{Data: simulationView.GlobalActions.DelaysLogNormal}))
@@ -294,11 +393,161 @@ func (this *Hoverfly) PutSimulation(simulationView v2.SimulationViewV5) v2.Simul
 
 func (this *Hoverfly) DeleteSimulation() {
 	this.Simulation.DeleteMatchingPairs()
+	this.Simulation.DeleteWebSockets()
 	this.DeleteResponseDelays()
 	this.DeleteResponseDelaysLogNormal()
 	this.FlushCache()
 }
 
+func (this *Hoverfly) GetSimulationSets() v2.SimulationSetsView {
+	setViews := []v2.SimulationSetView{}
+	for _, set := range this.SimulationSets.GetAll() {
+		setViews = append(setViews, v2.SimulationSetView{
+			Name:     set.Name,
+			Enabled:  set.Enabled,
+			Priority: set.Priority,
+			Pairs:    len(set.Simulation.GetMatchingPairs()),
+		})
+	}
+
+	return v2.SimulationSetsView{Simulations: setViews}
+}
+
+func (this *Hoverfly) GetSimulationSet(name string) (v2.SimulationViewV5, error) {
+	set := this.SimulationSets.Get(name)
+	if set == nil {
+		return v2.SimulationViewV5{}, simulationSetNotFoundError(name)
+	}
+
+	return this.buildSimulationView(set.Simulation), nil
+}
+
+// PutSimulationSet adds the simulation to the named set, creating the set if it does
+// not exist, and then applies any of the settings that are given
+func (this *Hoverfly) PutSimulationSet(name string, settings v2.SimulationSetSettingsView, simulationView v2.SimulationViewV5, overrideExisting bool) v2.SimulationImportResult {
+	simulation := this.SimulationSets.GetOrCreate(name)
+	if overrideExisting {
+		simulation.DeleteMatchingPairs()
+		simulation.DeleteWebSockets()
+	}
+	this.SimulationSets.Update(name, settings.Enabled, settings.Priority)
+
+	result := this.importSimulationInto(simulation, this.state, simulationView)
+
+	this.FlushCache()
+	return result
+}
+
+// importSimulationInto adds the pairs and global delays of the simulation view to a simulation
+// other than the default simulation, using the state to initialise any sequences it has
+func (this *Hoverfly) importSimulationInto(simulation *models.Simulation, simulationState *state.State, simulationView v2.SimulationViewV5) v2.SimulationImportResult {
+	result := this.importRequestResponsePairViewsIntoSimulation(simulation, simulationState, simulationView.DataViewV5.RequestResponsePairs)
+	importWebSocketViews(simulation, simulationView.WebSockets, &result)
+
+	responseDelays, err := buildResponseDelays(v1.ResponseDelayPayloadView{Data: simulationView.GlobalActions.Delays})
+	result.AddError(err)
+	if err == nil {
+		simulation.SetResponseDelays(responseDelays)
+	}
+
+	responseDelaysLogNormal, err := buildResponseDelaysLogNormal(v1.ResponseDelayLogNormalPayloadView{Data: simulationView.GlobalActions.DelaysLogNormal})
+	result.AddError(err)
+	if err == nil {
+		simulation.SetResponseDelaysLogNormal(responseDelaysLogNormal)
+	}
+
+	return result
+}
+
+func (this *Hoverfly) UpdateSimulationSet(name string, settings v2.SimulationSetSettingsView) error {
+	if !this.SimulationSets.Update(name, settings.Enabled, settings.Priority) {
+		return simulationSetNotFoundError(name)
+	}
+
+	this.FlushCache()
+	return nil
+}
+
+func (this *Hoverfly) DeleteSimulationSet(name string) error {
+	if !this.SimulationSets.Delete(name) {
+		return simulationSetNotFoundError(name)
+	}
+
+	this.FlushCache()
+	return nil
+}
+
+func simulationSetNotFoundError(name string) error {
+	return fmt.Errorf("Simulation %s not found", name)
+}
+
+func (this *Hoverfly) GetSessions() v2.SessionsView {
+	sessionViews := []v2.SessionView{}
+	for _, session := range this.Sessions.GetAll() {
+		sessionViews = append(sessionViews, v2.SessionView{
+			Name:    session.Name,
+			Created: session.Created.Format(journal.RFC3339Milli),
+			Pairs:   len(session.Simulation.GetMatchingPairs()),
+		})
+	}
+
+	return v2.SessionsView{Sessions: sessionViews}
+}
+
+func (this *Hoverfly) CreateSession(name string) {
+	this.Sessions.GetOrCreate(name)
+}
+
+func (this *Hoverfly) GetSessionSimulation(name string) (v2.SimulationViewV5, error) {
+	session := this.Sessions.Get(name)
+	if session == nil {
+		return v2.SimulationViewV5{}, sessionNotFoundError(name)
+	}
+
+	return this.buildSimulationView(session.Simulation), nil
+}
+
+// PutSessionSimulation adds the simulation to the session, creating the session if it does not exist
+func (this *Hoverfly) PutSessionSimulation(name string, simulationView v2.SimulationViewV5, overrideExisting bool) v2.SimulationImportResult {
+	session := this.Sessions.GetOrCreate(name)
+	if overrideExisting {
+		session.Simulation.DeleteMatchingPairs()
+		session.Simulation.DeleteWebSockets()
+	}
+
+	return this.importSimulationInto(session.Simulation, session.State, simulationView)
+}
+
+func (this *Hoverfly) GetSessionState(name string) (v2.HoverflyState, error) {
+	session := this.Sessions.Get(name)
+	if session == nil {
+		return nil, sessionNotFoundError(name)
+	}
+
+	return session, nil
+}
+
+func (this *Hoverfly) GetSessionJournal(name string) (v2.HoverflyJournal, error) {
+	session := this.Sessions.Get(name)
+	if session == nil {
+		return nil, sessionNotFoundError(name)
+	}
+
+	return session.Journal, nil
+}
+
+func (this *Hoverfly) DeleteSession(name string) error {
+	if !this.Sessions.Delete(name) {
+		return sessionNotFoundError(name)
+	}
+
+	return nil
+}
+
+func sessionNotFoundError(name string) error {
+	return fmt.Errorf("Session %s not found", name)
+}
+
 func (this Hoverfly) GetVersion() string {
 	return this.version
 }
@@ -312,6 +561,15 @@ func (this Hoverfly) IsWebServer() bool {
 	return this.Cfg.Webserver
 }
 
+// GetImportWatch returns the files and directories the simulation is imported from when they change, or nil
+func (this *Hoverfly) GetImportWatch() *v2.ImportWatchView {
+	if this.importWatcher == nil {
+		return nil
+	}
+
+	return this.importWatcher.getView()
+}
+
 func (this Hoverfly) IsMiddlewareSet() bool {
 	return this.Cfg.Middleware.IsSet()
 }
@@ -331,7 +589,7 @@ func (this Hoverfly) GetCORS() v2.CORSView {
 
 
 func (this *Hoverfly) GetState() map[string]string {
-	return this.state.State
+	return this.state.GetAllState()
 }
 
 func (this *Hoverfly) SetState(state map[string]string) {
@@ -342,8 +600,11 @@ func (this *Hoverfly) PatchState(toPatch map[string]string) {
 	this.state.PatchState(toPatch)
 }
 
+// ClearState empties the state in place, as requests and the import watcher may be using it
 func (this *Hoverfly) ClearState() {
-	this.state = state.NewState()
+	this.state.SetState(map[string]string{})
+	this.Simulation.ResetResponseSequences()
+	this.SimulationSets.ResetResponseSequences()
 }
 
 func (this *Hoverfly) GetDiff() map[v2.SimpleRequestDefinitionView][]v2.DiffReport {
@@ -361,6 +622,35 @@ func (this *Hoverfly) AddDiff(requestView v2.SimpleRequestDefinitionView, diffRe
 	}
 }
 
+func (this *Hoverfly) GetUnmatchedRequests() v2.UnmatchedRequestsView {
+	unmatchedViews := []v2.UnmatchedRequestView{}
+
+	for _, unmatched := range this.unmatchedRequests.GetAll() {
+		suggestedPair := newRequestMatcherResponsePair(&unmatched.Request, &models.ResponseDetails{
+			Status: http.StatusOK,
+		}, &modes.ModeArguments{})
+
+		unmatchedView := v2.UnmatchedRequestView{
+			Request:       unmatched.Request.ConvertToRequestDetailsView(),
+			Count:         unmatched.Count,
+			FirstSeen:     unmatched.FirstSeen.Format(journal.RFC3339Milli),
+			LastSeen:      unmatched.LastSeen.Format(journal.RFC3339Milli),
+			SuggestedPair: suggestedPair.BuildView(),
+		}
+		if unmatched.ClosestMiss != nil {
+			unmatchedView.ClosestMiss = unmatched.ClosestMiss.BuildView()
+		}
+
+		unmatchedViews = append(unmatchedViews, unmatchedView)
+	}
+
+	return v2.UnmatchedRequestsView{Unmatched: unmatchedViews}
+}
+
+func (this *Hoverfly) DeleteUnmatchedRequests() {
+	this.unmatchedRequests.DeleteAll()
+}
+
 func (this *Hoverfly) GetPACFile() []byte {
 	return this.Cfg.PACFile
 }
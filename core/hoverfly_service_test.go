@@ -169,6 +169,60 @@ func Test_Hoverfly_GetSimulation_ReturnsASingleRequestResponsePair(t *testing.T)
 	Expect(nil).To(BeNil())
 }
 
+func Test_Hoverfly_LintSimulation_LintsThePairsOfTheSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/one",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+		},
+	})
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Glob,
+					Value:   "/o*",
+				},
+				{
+					Matcher: matchers.Glob,
+					Value:   "*e",
+				},
+			},
+			RequiresState: map[string]string{
+				"page": "basket",
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+		},
+	})
+
+	simulation, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+
+	lintView := unit.LintSimulation(simulation)
+	Expect(lintView.Problems).To(HaveLen(1))
+	Expect(lintView.Problems[0].Field).To(Equal("requiresState.page"))
+
+	simulation.RequestResponsePairs[1].RequestMatcher.RequiresState = nil
+
+	lintView = unit.LintSimulation(simulation)
+	Expect(lintView.Problems).To(HaveLen(1))
+	Expect(lintView.Problems[0].Pair).To(Equal(0))
+	Expect(lintView.Problems[0].Message).To(ContainSubstring("pair 1"))
+}
+
 func Test_Hoverfly_GetSimulation_ReturnsMultipleRequestResponsePairs(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -444,6 +498,73 @@ func Test_Hoverfly_GetFilteredSimulationReturnError_OnInvalidRegexQuery(t *testi
 	Expect(err).NotTo(BeNil())
 }
 
+func Test_Hoverfly_GetSimulationFromOpenAPISpec_ReturnsAPairForEachOperation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	simulation, err := unit.GetSimulationFromOpenAPISpec([]byte(`
+swagger: "2.0"
+paths:
+  /pets/{name}:
+    get:
+      responses:
+        200:
+          description: A pet
+    delete:
+      responses:
+        204:
+          description: Deleted
+`))
+	Expect(err).To(BeNil())
+
+	Expect(simulation.SchemaVersion).To(Equal("v5"))
+	Expect(simulation.RequestResponsePairs).To(HaveLen(2))
+	Expect(simulation.RequestResponsePairs[0].RequestMatcher.Path[0].Matcher).To(Equal("regex"))
+	Expect(simulation.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("^/pets/[^/]+$"))
+	Expect(simulation.RequestResponsePairs[0].Response.Status).To(Equal(200))
+	Expect(simulation.RequestResponsePairs[1].RequestMatcher.Method[0].Value).To(Equal("DELETE"))
+	Expect(simulation.RequestResponsePairs[1].Response.Status).To(Equal(204))
+}
+
+func Test_Hoverfly_GetSimulationFromOpenAPISpec_ReturnsErrorForInvalidSpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_, err := unit.GetSimulationFromOpenAPISpec([]byte(`{"data": {"pairs": []}}`))
+	Expect(err).To(MatchError("Unable to parse OpenAPI spec: missing swagger or openapi version"))
+}
+
+func Test_Hoverfly_GetSimulationFromWireMockMappings_KeepsFixedDelays(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	simulation, result, err := unit.GetSimulationFromWireMockMappings([]byte(`{
+		"request": {"method": "GET", "urlPath": "/slow"},
+		"response": {"status": 200, "fixedDelayMilliseconds": 100}
+	}`))
+	Expect(err).To(BeNil())
+	Expect(result.WarningMessages).To(BeEmpty())
+
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulation.GlobalActions.Delays).To(HaveLen(1))
+	Expect(simulation.GlobalActions.Delays[0].Delay).To(Equal(100))
+
+	Expect(unit.PutSimulation(simulation).GetError()).To(BeNil())
+	Expect(unit.Simulation.ResponseDelays.ConvertToResponseDelayPayloadView().Data).To(HaveLen(1))
+}
+
+func Test_Hoverfly_GetSimulationFromPostmanCollection_ReturnsErrorForInvalidCollection(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_, _, err := unit.GetSimulationFromPostmanCollection([]byte(`{"item": []}`))
+	Expect(err).To(MatchError("Unable to parse Postman collection: missing info, only collection format v2 is supported"))
+}
+
 func Test_Hoverfly_GetFilteredSimulation_WithUrlQueryContainingPath(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -923,6 +1044,41 @@ func Test_Hoverfly_SetModeWithArguments_Stateful(t *testing.T) {
 	Expect(storedMode.Arguments.Stateful).To(BeTrue())
 }
 
+func Test_Hoverfly_SetModeWithArguments_SpyCanCaptureMisses(t *testing.T) {
+	RegisterTestingT(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("from the real service"))
+	}))
+	defer server.Close()
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.SetModeWithArguments(v2.ModeView{
+		Mode: "spy",
+		Arguments: v2.ModeArgumentsView{
+			CaptureMisses: true,
+		},
+	})).To(Succeed())
+
+	Expect(unit.modeMap[modes.Spy].View().Arguments.CaptureMisses).To(BeTrue())
+
+	for i := 0; i < 2; i++ {
+		request, _ := http.NewRequest(http.MethodGet, server.URL+"/missed", nil)
+		response := unit.processRequest(request)
+
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("from the real service"))
+	}
+
+	Expect(calls).To(Equal(1))
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(1))
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Path[0].Value).To(Equal("/missed"))
+}
+
 func Test_Hoverfly_SetModeWithArguments_AsteriskCanOnlyBeValidAsTheOnlyHeader(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -964,6 +1120,44 @@ func Test_Hoverfly_SetModeWithArguments_OverwriteDuplicate(t *testing.T) {
 	Expect(storedMode.Arguments.OverwriteDuplicate).To(BeTrue())
 }
 
+func Test_Hoverfly_SetModeWithArguments_DiffRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.SetModeWithArguments(v2.ModeView{
+		Mode: "diff",
+		Arguments: v2.ModeArgumentsView{
+			DiffRules: &v2.DiffRulesView{
+				Ignore:           []string{"$.generatedAt"},
+				IgnoreArrayOrder: true,
+			},
+		},
+	})).To(Succeed())
+
+	storedMode := unit.modeMap[modes.Diff].View()
+	Expect(storedMode.Arguments.DiffRules.Ignore).To(Equal([]string{"$.generatedAt"}))
+	Expect(storedMode.Arguments.DiffRules.IgnoreArrayOrder).To(BeTrue())
+}
+
+func Test_Hoverfly_SetModeWithArguments_InvalidDiffRulesAreRejected(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.SetModeWithArguments(v2.ModeView{
+		Mode: "diff",
+		Arguments: v2.ModeArgumentsView{
+			DiffRules: &v2.DiffRulesView{
+				Normalise: []v2.DiffNormaliseView{{Pattern: "[a-"}},
+			},
+		},
+	})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("Invalid regex [a-"))
+	Expect(unit.Cfg.GetMode()).ToNot(Equal("diff"))
+}
+
 func Test_Hoverfly_AddDiff_AddEntry(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -1048,6 +1242,268 @@ func Test_Hoverfly_AddDiff_DoesntAddDiffReport_NoEntries(t *testing.T) {
 	Expect(unit.responsesDiff).To(HaveLen(0))
 }
 
+func Test_Hoverfly_GetUnmatchedRequests_SuggestsAPairForEachUnmatchedRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/home",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+			Body:   "home",
+		},
+	})
+
+	request := models.RequestDetails{
+		Method:      "GET",
+		Scheme:      "http",
+		Destination: "test.com",
+		Path:        "/about",
+		Query:       map[string][]string{"page": {"1"}},
+	}
+	unit.GetResponse(request)
+	unit.GetResponse(request)
+
+	unmatchedView := unit.GetUnmatchedRequests()
+	Expect(unmatchedView.Unmatched).To(HaveLen(1))
+
+	unmatched := unmatchedView.Unmatched[0]
+	Expect(*unmatched.Request.Path).To(Equal("/about"))
+	Expect(unmatched.Count).To(Equal(2))
+	Expect(unmatched.ClosestMiss.MissedFields).To(Equal([]string{"path"}))
+	Expect(unmatched.ClosestMiss.Response.Body).To(Equal("home"))
+
+	Expect(unmatched.SuggestedPair.RequestMatcher.Method).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "GET")}))
+	Expect(unmatched.SuggestedPair.RequestMatcher.Destination).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "test.com")}))
+	Expect(unmatched.SuggestedPair.RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/about")}))
+	Expect(*unmatched.SuggestedPair.RequestMatcher.Query).To(Equal(v2.QueryMatcherViewV5{
+		"page": []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "1")},
+	}))
+	Expect(unmatched.SuggestedPair.Response.Status).To(Equal(http.StatusOK))
+}
+
+func Test_Hoverfly_DeleteUnmatchedRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.GetResponse(models.RequestDetails{Path: "/"})
+	Expect(unit.GetUnmatchedRequests().Unmatched).To(HaveLen(1))
+
+	unit.DeleteUnmatchedRequests()
+
+	Expect(unit.GetUnmatchedRequests().Unmatched).To(BeEmpty())
+}
+
+func newSimulationSetView(path, body string) v2.SimulationViewV5 {
+	return v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Path: []v2.MatcherViewV5{
+							v2.NewMatcherView(matchers.Glob, path),
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   body,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v5",
+		},
+	}
+}
+
+func Test_Hoverfly_PutSimulationSet_AddsANamedSimulationAlongsideTheDefaultSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulation(newSimulationSetView("/default", "default"))
+
+	priority := 5
+	result := unit.PutSimulationSet("users", v2.SimulationSetSettingsView{Priority: &priority}, newSimulationSetView("/users", "users"), false)
+	Expect(result.GetError()).To(BeNil())
+
+	Expect(unit.GetSimulationSets()).To(Equal(v2.SimulationSetsView{
+		Simulations: []v2.SimulationSetView{
+			{Name: "users", Enabled: true, Priority: 5, Pairs: 1},
+		},
+	}))
+
+	simulationView, err := unit.GetSimulationSet("users")
+	Expect(err).To(BeNil())
+	Expect(simulationView.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulationView.RequestResponsePairs[0].Response.Body).To(Equal("users"))
+
+	defaultView, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(defaultView.RequestResponsePairs).To(HaveLen(1))
+	Expect(defaultView.RequestResponsePairs[0].Response.Body).To(Equal("default"))
+}
+
+func Test_Hoverfly_PutSimulationSet_ReplacesOrAppendsToTheSet(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulationSet("users", v2.SimulationSetSettingsView{}, newSimulationSetView("/one", "one"), false)
+	unit.PutSimulationSet("users", v2.SimulationSetSettingsView{}, newSimulationSetView("/two", "two"), false)
+
+	simulationView, _ := unit.GetSimulationSet("users")
+	Expect(simulationView.RequestResponsePairs).To(HaveLen(2))
+
+	unit.PutSimulationSet("users", v2.SimulationSetSettingsView{}, newSimulationSetView("/three", "three"), true)
+
+	simulationView, _ = unit.GetSimulationSet("users")
+	Expect(simulationView.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulationView.RequestResponsePairs[0].Response.Body).To(Equal("three"))
+}
+
+func Test_Hoverfly_GetResponse_MatchesSimulationSetsByPriority(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulation(newSimulationSetView("/users", "default"))
+
+	priority := 1
+	unit.PutSimulationSet("catch-all", v2.SimulationSetSettingsView{Priority: &priority}, newSimulationSetView("*", "catch-all"), false)
+
+	response, err := unit.GetResponse(models.RequestDetails{Path: "/users"})
+	Expect(err).To(BeNil())
+	Expect(response.Body).To(Equal("catch-all"))
+
+	priority = -1
+	Expect(unit.UpdateSimulationSet("catch-all", v2.SimulationSetSettingsView{Priority: &priority})).To(Succeed())
+
+	response, err = unit.GetResponse(models.RequestDetails{Path: "/users"})
+	Expect(err).To(BeNil())
+	Expect(response.Body).To(Equal("default"))
+
+	response, err = unit.GetResponse(models.RequestDetails{Path: "/other"})
+	Expect(err).To(BeNil())
+	Expect(response.Body).To(Equal("catch-all"))
+
+	enabled := false
+	Expect(unit.UpdateSimulationSet("catch-all", v2.SimulationSetSettingsView{Enabled: &enabled})).To(Succeed())
+
+	_, err = unit.GetResponse(models.RequestDetails{Path: "/other"})
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_Hoverfly_DeleteSimulationSet(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulationSet("users", v2.SimulationSetSettingsView{}, newSimulationSetView("/users", "users"), false)
+
+	Expect(unit.DeleteSimulationSet("users")).To(Succeed())
+	Expect(unit.GetSimulationSets().Simulations).To(BeEmpty())
+
+	_, err := unit.GetResponse(models.RequestDetails{Path: "/users"})
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_Hoverfly_SimulationSets_ReturnErrorsForSetsThatDoNotExist(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_, err := unit.GetSimulationSet("users")
+	Expect(err).To(MatchError("Simulation users not found"))
+
+	Expect(unit.UpdateSimulationSet("users", v2.SimulationSetSettingsView{})).To(MatchError("Simulation users not found"))
+	Expect(unit.DeleteSimulationSet("users")).To(MatchError("Simulation users not found"))
+}
+
+func Test_Hoverfly_PutSessionSimulation_CreatesTheSessionWithoutChangingTheDefaultSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	result := unit.PutSessionSimulation("first", newSimulationSetView("/first", "first"), true)
+	Expect(result.GetError()).To(BeNil())
+
+	sessions := unit.GetSessions()
+	Expect(sessions.Sessions).To(HaveLen(1))
+	Expect(sessions.Sessions[0].Name).To(Equal("first"))
+	Expect(sessions.Sessions[0].Pairs).To(Equal(1))
+	Expect(sessions.Sessions[0].Created).ToNot(BeEmpty())
+
+	simulationView, err := unit.GetSessionSimulation("first")
+	Expect(err).To(BeNil())
+	Expect(simulationView.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulationView.RequestResponsePairs[0].Response.Body).To(Equal("first"))
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(BeEmpty())
+}
+
+func Test_Hoverfly_PutSessionSimulation_ReplacesOrAppendsToTheSessionSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	unit.PutSessionSimulation("first", newSimulationSetView("/first", "first"), true)
+	unit.PutSessionSimulation("first", newSimulationSetView("/second", "second"), false)
+
+	Expect(unit.Sessions.Get("first").Simulation.GetMatchingPairs()).To(HaveLen(2))
+
+	unit.PutSessionSimulation("first", newSimulationSetView("/third", "third"), true)
+
+	Expect(unit.Sessions.Get("first").Simulation.GetMatchingPairs()).To(HaveLen(1))
+}
+
+func Test_Hoverfly_GetSessionState_ReturnsTheStateOfTheSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.CreateSession("first")
+
+	sessionState, err := unit.GetSessionState("first")
+	Expect(err).To(BeNil())
+
+	sessionState.SetState(map[string]string{"page": "home"})
+
+	Expect(unit.Sessions.Get("first").GetState()).To(Equal(map[string]string{"page": "home"}))
+	Expect(unit.GetState()).To(BeEmpty())
+}
+
+func Test_Hoverfly_DeleteSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.CreateSession("first")
+
+	Expect(unit.DeleteSession("first")).To(BeNil())
+
+	Expect(unit.GetSessions().Sessions).To(BeEmpty())
+}
+
+func Test_Hoverfly_Sessions_ReturnErrorsForSessionsThatDoNotExist(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_, err := unit.GetSessionSimulation("first")
+	Expect(err).To(MatchError("Session first not found"))
+
+	_, err = unit.GetSessionState("first")
+	Expect(err).To(MatchError("Session first not found"))
+
+	_, err = unit.GetSessionJournal("first")
+	Expect(err).To(MatchError("Session first not found"))
+
+	Expect(unit.DeleteSession("first")).To(MatchError("Session first not found"))
+}
+
 func Test_Hoverfly_GetPACFile_GetsPACFile(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -9,11 +9,13 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/SpectoLabs/hoverfly/core/authentication/backends"
 	"github.com/SpectoLabs/hoverfly/core/cache"
 	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	. "github.com/onsi/gomega"
 )
@@ -352,6 +354,46 @@ func Test_Hoverfly_processRequest_DelayAppliedToSuccessfulSimulateRequest(t *tes
 	Expect(stubLogNormal.gotDelays, Equal(1))
 }
 
+func Test_Hoverfly_processRequest_GlobalDelayNotAppliedToResponseWithItsOwnDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "somehost.com",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status:     http.StatusOK,
+			FixedDelay: 100,
+		},
+	})
+
+	r, err := http.NewRequest("GET", "http://somehost.com", nil)
+	Expect(err).To(BeNil())
+
+	unit.Cfg.SetMode("simulate")
+
+	stub := ResponseDelayListStub{}
+	unit.Simulation.ResponseDelays = &stub
+	stubLogNormal := ResponseDelayLogNormalListStub{}
+	unit.Simulation.ResponseDelaysLogNormal = &stubLogNormal
+
+	start := time.Now()
+	newResp := unit.processRequest(r)
+
+	Expect(newResp.StatusCode).To(Equal(http.StatusOK))
+	Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+
+	Expect(stub.gotDelays).To(Equal(0))
+	Expect(stubLogNormal.gotDelays).To(Equal(0))
+}
+
 func Test_Hoverfly_processRequest_DelayNotAppliedToFailedSimulateRequest(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -761,3 +803,18 @@ func Test_Hoverfly_StartProxy_StartProxyWOPort(t *testing.T) {
 	err := unit.StartProxy()
 	Expect(err).ToNot(BeNil())
 }
+
+func Test_Hoverfly_countSimulationPairs_CountsThePairsOfEnabledSimulationSetsAndSessions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Simulation.AddPair(newPathPair("/default", "default"))
+	unit.SimulationSets.GetOrCreate("enabled").AddPair(newPathPair("/enabled", "enabled"))
+	unit.SimulationSets.GetOrCreate("disabled").AddPair(newPathPair("/disabled", "disabled"))
+	unit.Sessions.GetOrCreate("first").Simulation.AddPair(newPathPair("/first", "first"))
+
+	disabled := false
+	unit.SimulationSets.Update("disabled", &disabled, nil)
+
+	Expect(unit.countSimulationPairs()).To(Equal(float64(3)))
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/jackwakefield/gopac"
+	"golang.org/x/net/http2"
 )
 
 func GetDefaultHoverflyHTTPClient(tlsVerification bool, upstreamProxy string) *http.Client {
@@ -123,3 +125,72 @@ func parsePACFileResult(result string, tlsVerification bool) *http.Client {
 	}
 	return nil
 }
+
+// h2cTransport sends requests over cleartext HTTP/2 connections. It does not go through
+// an upstream proxy, as proxies do not forward cleartext HTTP/2.
+var h2cTransport = &http2.Transport{
+	AllowHTTP: true,
+	DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+		return net.Dial(network, addr)
+	},
+}
+
+// http2RoundTripper sends https requests with its own transport and http requests over
+// cleartext HTTP/2
+type http2RoundTripper struct {
+	https http.RoundTripper
+}
+
+func (this http2RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.URL.Scheme == "http" {
+		return h2cTransport.RoundTrip(request)
+	}
+	return this.https.RoundTrip(request)
+}
+
+// NewHttp2Client returns a client that sends requests the same way as the given client,
+// but only over HTTP/2, as gRPC needs. Its connections are closed after each request
+// unless it keeps them alive. Clients whose transport cannot be configured are returned
+// as they are.
+func NewHttp2Client(client *http.Client, keepAlives bool) *http.Client {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	tlsConfig := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+
+	httpsTransport := &http.Transport{
+		Proxy:             transport.Proxy,
+		TLSClientConfig:   tlsConfig,
+		DisableKeepAlives: !keepAlives,
+	}
+	if err := http2.ConfigureTransport(httpsTransport); err != nil {
+		log.Warnf("Could not configure HTTP/2 transport: %s", err.Error())
+		return client
+	}
+
+	return &http.Client{
+		CheckRedirect: client.CheckRedirect,
+		Transport:     http2RoundTripper{https: httpsTransport},
+	}
+}
+
+// getHttp2Client converts the client to HTTP/2. The conversion of the default client is
+// kept, so that its connections are reused.
+func (hf *Hoverfly) getHttp2Client(client *http.Client) *http.Client {
+	if client != hf.HTTP {
+		return NewHttp2Client(client, false)
+	}
+
+	hf.http2Mutex.Lock()
+	defer hf.http2Mutex.Unlock()
+	if hf.http2ClientFor != client {
+		hf.http2Client = NewHttp2Client(client, true)
+		hf.http2ClientFor = client
+	}
+	return hf.http2Client
+}
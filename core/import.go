@@ -1,12 +1,12 @@
 package hoverfly
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"net/url"
 	"os"
-	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,6 +16,8 @@ import (
 
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/openapi"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -33,9 +35,8 @@ func (hf *Hoverfly) Import(uri string) error {
 		return hf.ImportFromURL(uri)
 	}
 	// assuming file URI is disk location
-	ext := path.Ext(uri)
-	if ext != ".json" {
-		return fmt.Errorf("Failed to import payloads, only JSON files are acceppted. Given file: %s", uri)
+	if !isImportFile(uri) {
+		return fmt.Errorf("Failed to import payloads, only JSON, HAR files or YAML OpenAPI specs are acceppted. Given file: %s", uri)
 	}
 	// checking whether it exists
 	exists, err := exists(uri)
@@ -92,19 +93,12 @@ func (hf *Hoverfly) ImportFromDisk(path string) error {
 		return fmt.Errorf("Got error while opening payloads file, error %s", err.Error())
 	}
 
-	var simulation v2.SimulationViewV5
-
 	body, err := ioutil.ReadAll(pairsFile)
 	if err != nil {
 		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
 	}
 
-	err = json.Unmarshal(body, &simulation)
-	if err != nil {
-		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
-	}
-
-	return hf.PutSimulation(simulation).GetError()
+	return hf.importPayload(body)
 }
 
 // ImportFromURL - takes one string value and tries connect to a remote server, then parse response body into
@@ -117,23 +111,130 @@ func (hf *Hoverfly) ImportFromURL(url string) error {
 		return fmt.Errorf("Failed to fetch given URL, error %s", err.Error())
 	}
 
-	var simulation v2.SimulationViewV5
-
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
 	}
 
-	err = json.Unmarshal(body, &simulation)
-	if err != nil {
+	return hf.importPayload(body)
+}
+
+// importPayload imports either a simulation, an HTTP Archive or an OpenAPI spec, which
+// is turned into a simulation with a request response pair for each operation
+func (hf *Hoverfly) importPayload(body []byte) error {
+	if hf.state == nil {
+		hf.state = state.NewState()
+	}
+	return hf.importPayloadInto(hf.Simulation, hf.state, body)
+}
+
+// importPayloadInto imports the payload into the given simulation rather than the default simulation
+func (hf *Hoverfly) importPayloadInto(simulation *models.Simulation, simulationState *state.State, body []byte) error {
+	var simulationView v2.SimulationViewV5
+
+	var har v2.HARView
+	if err := json.Unmarshal(body, &har); err == nil && har.Log != nil {
+		return importHARInto(simulation, simulationState, har)
+	}
+
+	if openapi.IsSpec(body) {
+		var err error
+		simulationView, err = hf.GetSimulationFromOpenAPISpec(body)
+		if err != nil {
+			return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
+		}
+	} else if err := json.Unmarshal(body, &simulationView); err != nil {
 		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
 	}
 
-	return hf.PutSimulation(simulation).GetError()
+	return hf.importSimulationInto(simulation, simulationState, simulationView).GetError()
+}
+
+// importHARInto saves each entry of an HTTP Archive the same way as a request captured
+// in capture mode, so that it is matched exactly. The archive holds decoded response
+// bodies, so the headers describing the encoding and length of the original body are dropped.
+func importHARInto(simulation *models.Simulation, simulationState *state.State, har v2.HARView) error {
+	for i, entry := range har.Log.Entries {
+		var requestBody string
+		if postData := entry.Request.PostData; postData != nil {
+			requestBody = postData.Text
+			if requestBody == "" && len(postData.Params) > 0 {
+				form := url.Values{}
+				for _, param := range postData.Params {
+					form.Add(param.Name, param.Value)
+				}
+				requestBody = form.Encode()
+			}
+		}
+
+		request, err := http.NewRequest(entry.Request.Method, entry.Request.URL, strings.NewReader(requestBody))
+		if err != nil {
+			return fmt.Errorf("Got error while parsing HAR entry %d, error %s", i, err.Error())
+		}
+		for _, header := range entry.Request.Headers {
+			if !strings.HasPrefix(header.Name, ":") {
+				request.Header.Add(header.Name, header.Value)
+			}
+		}
+
+		requestDetails, err := models.NewRequestDetailsFromHttpRequest(request)
+		if err != nil {
+			return fmt.Errorf("Got error while parsing HAR entry %d, error %s", i, err.Error())
+		}
+
+		responseBody := []byte(entry.Response.Content.Text)
+		if entry.Response.Content.Encoding == "base64" {
+			if responseBody, err = base64.StdEncoding.DecodeString(entry.Response.Content.Text); err != nil {
+				return fmt.Errorf("Got error while parsing HAR entry %d, error %s", i, err.Error())
+			}
+		}
+
+		responseHeaders := http.Header{}
+		for _, header := range entry.Response.Headers {
+			if strings.HasPrefix(header.Name, ":") || strings.EqualFold(header.Name, "Content-Encoding") || strings.EqualFold(header.Name, "Content-Length") {
+				continue
+			}
+			responseHeaders.Add(header.Name, header.Value)
+		}
+
+		err = saveInSimulation(simulation, simulationState, &requestDetails, &models.ResponseDetails{
+			Status:  entry.Response.Status,
+			Body:    string(responseBody),
+			Headers: responseHeaders,
+		}, &modes.ModeArguments{})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"total": len(har.Log.Entries),
+	}).Info("HAR entries imported")
+
+	return nil
+}
+
+// importWebSocketViews adds the WebSockets to the simulation, warning about those that are
+// not added as the simulation already has a WebSocket with the same request matcher
+func importWebSocketViews(simulation *models.Simulation, webSocketViews []v2.WebSocketViewV5, importResult *v2.SimulationImportResult) {
+	for i, webSocketView := range webSocketViews {
+		if !simulation.AddWebSocket(models.NewWebSocketFromView(&webSocketView)) {
+			importResult.AddWebSocketIgnoredWarning(i)
+		}
+	}
 }
 
 // importRequestResponsePairViews - a function to save given pairs into the database.
 func (hf *Hoverfly) importRequestResponsePairViews(pairViews []v2.RequestMatcherResponsePairViewV5) v2.SimulationImportResult {
+	if hf.state == nil {
+		hf.state = state.NewState()
+	}
+	return hf.importRequestResponsePairViewsIntoSimulation(hf.Simulation, hf.state, pairViews)
+}
+
+// importRequestResponsePairViewsIntoSimulation saves the pairs into the given simulation, which is the
+// default simulation, the simulation of a named set or that of a session, along with the state it uses
+func (hf *Hoverfly) importRequestResponsePairViewsIntoSimulation(simulation *models.Simulation, simulationState *state.State, pairViews []v2.RequestMatcherResponsePairViewV5) v2.SimulationImportResult {
 	importResult := v2.SimulationImportResult{}
 	initialStates := map[string]string{}
 	if len(pairViews) > 0 {
@@ -145,10 +246,10 @@ func (hf *Hoverfly) importRequestResponsePairViews(pairViews []v2.RequestMatcher
 
 			var isPairAdded bool
 			if hf.Cfg.NoImportCheck {
-				hf.Simulation.AddPairWithoutCheck(pair)
+				simulation.AddPairWithoutCheck(pair)
 				isPairAdded = true
 			} else {
-				isPairAdded = hf.Simulation.AddPair(pair)
+				isPairAdded = simulation.AddPair(pair)
 			}
 
 			if isPairAdded {
@@ -178,10 +279,7 @@ func (hf *Hoverfly) importRequestResponsePairViews(pairViews []v2.RequestMatcher
 			continue
 		}
 
-		if hf.state == nil {
-			hf.state = state.NewState()
-		}
-		hf.state.InitializeSequences(initialStates)
+		simulationState.InitializeSequences(initialStates)
 
 		log.WithFields(log.Fields{
 			"total":      len(pairViews),
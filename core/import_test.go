@@ -90,6 +90,107 @@ func TestImportFromDisk(t *testing.T) {
 	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(2))
 }
 
+func TestImportFromDisk_ImportsOpenAPISpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.Import("openapi/testdata/petstore-v2.yaml")
+	Expect(err).To(BeNil())
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(4))
+	Expect(unit.Simulation.GetMatchingPairs()[3].RequestMatcher.Path[0].Value).To(Equal("/v1/pets"))
+}
+
+const harPayload = `{
+	"log": {
+		"version": "1.2",
+		"creator": {"name": "test", "version": "1.0"},
+		"entries": [
+			{
+				"startedDateTime": "2018-01-01T10:00:00.000Z",
+				"time": 10,
+				"request": {
+					"method": "POST",
+					"url": "https://test.com/users?b=2&a=1",
+					"httpVersion": "HTTP/2.0",
+					"headers": [
+						{"name": ":authority", "value": "test.com"},
+						{"name": "Content-Type", "value": "application/x-www-form-urlencoded"}
+					],
+					"queryString": [],
+					"postData": {
+						"mimeType": "application/x-www-form-urlencoded",
+						"params": [{"name": "name", "value": "Ben"}]
+					}
+				},
+				"response": {
+					"status": 201,
+					"statusText": "Created",
+					"headers": [
+						{"name": "Content-Encoding", "value": "gzip"},
+						{"name": "Content-Type", "value": "text/plain"}
+					],
+					"content": {"size": 7, "mimeType": "text/plain", "text": "Y3JlYXRlZA==", "encoding": "base64"}
+				}
+			},
+			{
+				"startedDateTime": "2018-01-01T10:00:01.000Z",
+				"time": 10,
+				"request": {
+					"method": "GET",
+					"url": "http://test.com/users",
+					"httpVersion": "HTTP/1.1",
+					"headers": [],
+					"queryString": []
+				},
+				"response": {
+					"status": 200,
+					"statusText": "OK",
+					"headers": [],
+					"content": {"size": 5, "mimeType": "text/plain", "text": "users"}
+				}
+			}
+		]
+	}
+}`
+
+func TestImportPayload_ImportsHARAsExactMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.importPayload([]byte(harPayload))
+	Expect(err).To(BeNil())
+
+	pairs := unit.Simulation.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(2))
+
+	Expect(pairs[0].RequestMatcher.Method[0].Value).To(Equal("POST"))
+	Expect(pairs[0].RequestMatcher.Scheme[0].Value).To(Equal("https"))
+	Expect(pairs[0].RequestMatcher.Destination[0].Value).To(Equal("test.com"))
+	Expect(pairs[0].RequestMatcher.Path[0].Value).To(Equal("/users"))
+	Expect((*pairs[0].RequestMatcher.Query)["a"][0].Value).To(Equal("1"))
+	Expect(pairs[0].RequestMatcher.Body[0].Matcher).To(Equal(matchers.Form))
+	Expect(pairs[0].RequestMatcher.Headers).To(BeNil())
+	Expect(pairs[0].Response.Status).To(Equal(201))
+	Expect(pairs[0].Response.Body).To(Equal("created"))
+	Expect(pairs[0].Response.Headers).To(Equal(map[string][]string{"Content-Type": {"text/plain"}}))
+
+	Expect(pairs[1].RequestMatcher.Method[0].Value).To(Equal("GET"))
+	Expect(pairs[1].RequestMatcher.Body[0].Value).To(Equal(""))
+	Expect(pairs[1].Response.Body).To(Equal("users"))
+}
+
+func TestImport_RejectsUnknownFileExtension(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.Import("simulation.txt")
+	Expect(err).To(MatchError("Failed to import payloads, only JSON, HAR files or YAML OpenAPI specs are acceppted. Given file: simulation.txt"))
+}
+
 func TestImportFromDiskBlankPath(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -0,0 +1,266 @@
+package hoverfly
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// ImportWatchDelay is how long the import watcher waits after a change before importing the
+// simulation again, so that an editor writing a file in several steps only causes one import
+var ImportWatchDelay = 100 * time.Millisecond
+
+// importWatcher imports the simulation from files, and the simulation files in directories,
+// each time one of them changes
+type importWatcher struct {
+	hoverfly *Hoverfly
+	files    []string
+	dirs     []string
+	watcher  *fsnotify.Watcher
+
+	mutex      sync.Mutex
+	timer      *time.Timer
+	stopped    bool
+	lastReload time.Time
+	err        error
+}
+
+// WatchImports imports the simulation from the given files, and the simulation files in the given directories,
+// then imports it again each time they change. Each import replaces the whole of the simulation at once, while
+// the state and journal are kept. An import that fails is logged and reported by GetImportWatch, and the
+// simulation is left as it was.
+func (hf *Hoverfly) WatchImports(paths []string) error {
+	imports := &importWatcher{hoverfly: hf}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("Failed to watch %s for simulations, error %s", path, err.Error())
+		}
+		if info.IsDir() {
+			imports.dirs = append(imports.dirs, filepath.Clean(path))
+		} else {
+			imports.files = append(imports.files, filepath.Clean(path))
+		}
+	}
+
+	if err := imports.reload(); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("Failed to watch for simulations, error %s", err.Error())
+	}
+
+	// Files are watched through their directory, as editors often save a file by replacing it
+	watched := map[string]bool{}
+	for _, dir := range imports.dirs {
+		watched[dir] = true
+	}
+	for _, file := range imports.files {
+		watched[filepath.Dir(file)] = true
+	}
+	for dir := range watched {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("Failed to watch %s for simulations, error %s", dir, err.Error())
+		}
+	}
+
+	imports.watcher = watcher
+	hf.importWatcher = imports
+
+	go imports.watch()
+
+	return nil
+}
+
+// StopWatchingImports stops importing the simulation when the watched files change, including
+// an import that is waiting for the files to stop changing
+func (hf *Hoverfly) StopWatchingImports() {
+	if hf.importWatcher != nil {
+		hf.importWatcher.stop()
+		hf.importWatcher = nil
+	}
+}
+
+func (this *importWatcher) stop() {
+	this.watcher.Close()
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.stopped = true
+	if this.timer != nil {
+		this.timer.Stop()
+	}
+}
+
+func (this *importWatcher) watch() {
+	for {
+		select {
+		case event, ok := <-this.watcher.Events:
+			if !ok {
+				return
+			}
+			if !this.isWatched(event.Name) {
+				continue
+			}
+			this.scheduleReload()
+		case err, ok := <-this.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to watch for simulations")
+		}
+	}
+}
+
+// scheduleReload imports the simulation again once there have been no changes for ImportWatchDelay
+func (this *importWatcher) scheduleReload() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.stopped {
+		return
+	}
+	if this.timer != nil {
+		this.timer.Stop()
+	}
+	this.timer = time.AfterFunc(ImportWatchDelay, func() {
+		this.reload()
+	})
+}
+
+// isWatched returns whether a change to the file changes the simulation
+func (this *importWatcher) isWatched(file string) bool {
+	file = filepath.Clean(file)
+	for _, watchedFile := range this.files {
+		if file == watchedFile {
+			return true
+		}
+	}
+
+	for _, dir := range this.dirs {
+		if filepath.Dir(file) == dir && isImportFile(file) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reload imports the watched files into a new simulation, which replaces the simulation of Hoverfly
+// only once every file has been imported
+func (this *importWatcher) reload() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.stopped {
+		return nil
+	}
+
+	simulation := models.NewSimulation()
+	simulationState := state.NewState()
+
+	files, err := this.getFiles()
+	if err == nil {
+		err = this.importFiles(files, simulation, simulationState)
+	}
+
+	if err != nil {
+		this.err = err
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Error("Failed to import watched simulations")
+		return err
+	}
+
+	this.hoverfly.Simulation.Replace(simulation)
+
+	// The state is kept, other than the sequences of the new simulation that it does not have yet
+	this.hoverfly.state.PatchMissingState(simulationState.State)
+
+	this.hoverfly.CacheMatcher.FlushCache()
+
+	this.lastReload = time.Now()
+	this.err = nil
+	log.WithFields(log.Fields{
+		"files": files,
+		"pairs": len(simulation.GetMatchingPairs()),
+	}).Info("Imported watched simulations")
+
+	return nil
+}
+
+func (this *importWatcher) importFiles(files []string, simulation *models.Simulation, simulationState *state.State) error {
+	for _, file := range files {
+		body, err := ioutil.ReadFile(file)
+		if err == nil {
+			err = this.hoverfly.importPayloadInto(simulation, simulationState, body)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to import %s, error %s", file, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// getFiles returns the watched files and the simulation files in the watched directories, in order
+func (this *importWatcher) getFiles() ([]string, error) {
+	files := append([]string{}, this.files...)
+
+	for _, dir := range this.dirs {
+		fileInfos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read %s, error %s", dir, err.Error())
+		}
+
+		for _, fileInfo := range fileInfos {
+			if !fileInfo.IsDir() && isImportFile(fileInfo.Name()) {
+				files = append(files, filepath.Join(dir, fileInfo.Name()))
+			}
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (this *importWatcher) getView() *v2.ImportWatchView {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	view := &v2.ImportWatchView{
+		Paths: append(append([]string{}, this.files...), this.dirs...),
+	}
+	if !this.lastReload.IsZero() {
+		view.LastReload = this.lastReload.Format(time.RFC3339)
+	}
+	if this.err != nil {
+		view.Error = this.err.Error()
+	}
+
+	return view
+}
+
+// isImportFile returns whether the file has the extension of a file that Import accepts
+func isImportFile(file string) bool {
+	ext := filepath.Ext(file)
+	return ext == ".json" || ext == ".har" || ext == ".yaml" || ext == ".yml"
+}
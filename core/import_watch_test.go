@@ -0,0 +1,191 @@
+package hoverfly
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/util"
+	. "github.com/onsi/gomega"
+)
+
+func writeWatchedSimulation(path, requestPath, body string) {
+	ioutil.WriteFile(path, []byte(fmt.Sprintf(`{
+		"data": {
+			"pairs": [{
+				"request": {
+					"path": [{"matcher": "exact", "value": "%s"}]
+				},
+				"response": {
+					"status": 200,
+					"body": "%s"
+				}
+			}]
+		},
+		"meta": {
+			"schemaVersion": "v5"
+		}
+	}`, requestPath, body)), 0644)
+}
+
+func Test_Hoverfly_WatchImports_ImportsTheFilesInADirectoryInOrder(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "import-watch")
+	defer os.RemoveAll(directory)
+
+	writeWatchedSimulation(filepath.Join(directory, "b.json"), "/b", "b")
+	writeWatchedSimulation(filepath.Join(directory, "a.json"), "/a", "a")
+	ioutil.WriteFile(filepath.Join(directory, "notes.txt"), []byte("not a simulation"), 0644)
+
+	unit := NewHoverflyWithConfiguration(InitSettings())
+	defer unit.StopWatchingImports()
+
+	Expect(unit.WatchImports([]string{directory})).To(Succeed())
+
+	pairs := unit.Simulation.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(2))
+	Expect(pairs[0].Response.Body).To(Equal("a"))
+	Expect(pairs[1].Response.Body).To(Equal("b"))
+
+	importWatch := unit.GetImportWatch()
+	Expect(importWatch.Paths).To(ConsistOf(directory))
+	Expect(importWatch.LastReload).ToNot(BeEmpty())
+	Expect(importWatch.Error).To(BeEmpty())
+}
+
+func Test_Hoverfly_WatchImports_ReturnsAnErrorWhenAFileCannotBeImported(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "import-watch")
+	defer os.RemoveAll(directory)
+
+	ioutil.WriteFile(filepath.Join(directory, "broken.json"), []byte("{"), 0644)
+
+	unit := NewHoverflyWithConfiguration(InitSettings())
+
+	err := unit.WatchImports([]string{filepath.Join(directory, "broken.json")})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("broken.json"))
+
+	err = unit.WatchImports([]string{filepath.Join(directory, "missing.json")})
+	Expect(err).ToNot(BeNil())
+
+	Expect(unit.GetImportWatch()).To(BeNil())
+}
+
+func Test_Hoverfly_WatchImports_ReplacesTheSimulationWhenAFileChanges(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "import-watch")
+	defer os.RemoveAll(directory)
+
+	file := filepath.Join(directory, "simulation.json")
+	writeWatchedSimulation(file, "/path", "before")
+
+	unit := NewHoverflyWithConfiguration(InitSettings())
+	unit.Cfg.SetMode("simulate")
+	defer unit.StopWatchingImports()
+
+	Expect(unit.WatchImports([]string{file})).To(Succeed())
+
+	response := unit.processRequest(newSessionRequest("/path", ""))
+	Expect(util.GetResponseBody(response)).To(Equal("before"))
+
+	unit.SetState(map[string]string{"page": "checkout"})
+	unit.recordRequest(newSessionRequest("/path", ""), response, time.Now())
+
+	writeWatchedSimulation(file, "/path", "after")
+
+	Eventually(func() string {
+		body, _ := util.GetResponseBody(unit.processRequest(newSessionRequest("/path", "")))
+		return body
+	}, time.Second*5).Should(Equal("after"))
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(1))
+	Expect(unit.GetState()).To(Equal(map[string]string{"page": "checkout"}))
+
+	journalView, err := unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+}
+
+func Test_Hoverfly_WatchImports_KeepsTheSimulationWhenAChangedFileCannotBeImported(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "import-watch")
+	defer os.RemoveAll(directory)
+
+	file := filepath.Join(directory, "simulation.json")
+	writeWatchedSimulation(file, "/path", "before")
+
+	unit := NewHoverflyWithConfiguration(InitSettings())
+	unit.Cfg.SetMode("simulate")
+	defer unit.StopWatchingImports()
+
+	Expect(unit.WatchImports([]string{directory})).To(Succeed())
+
+	ioutil.WriteFile(file, []byte(`{"data": `), 0644)
+
+	Eventually(func() string {
+		return unit.GetImportWatch().Error
+	}, time.Second*5).Should(ContainSubstring("simulation.json"))
+
+	response := unit.processRequest(newSessionRequest("/path", ""))
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(util.GetResponseBody(response)).To(Equal("before"))
+
+	writeWatchedSimulation(file, "/path", "after")
+
+	Eventually(func() string {
+		return unit.GetImportWatch().Error
+	}, time.Second*5).Should(BeEmpty())
+
+	Expect(util.GetResponseBody(unit.processRequest(newSessionRequest("/path", "")))).To(Equal("after"))
+}
+
+func Test_Hoverfly_StopWatchingImports_CancelsAPendingImport(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "import-watch")
+	defer os.RemoveAll(directory)
+
+	file := filepath.Join(directory, "simulation.json")
+	writeWatchedSimulation(file, "/path", "before")
+
+	unit := NewHoverflyWithConfiguration(InitSettings())
+	unit.Cfg.SetMode("simulate")
+
+	Expect(unit.WatchImports([]string{file})).To(Succeed())
+	imports := unit.importWatcher
+
+	imports.scheduleReload()
+	unit.StopWatchingImports()
+	writeWatchedSimulation(file, "/path", "after")
+	imports.scheduleReload()
+
+	Consistently(func() string {
+		body, _ := util.GetResponseBody(unit.processRequest(newSessionRequest("/path", "")))
+		return body
+	}, 3*ImportWatchDelay).Should(Equal("before"))
+}
+
+func Test_importWatcher_isWatched(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &importWatcher{
+		files: []string{"/simulations/one.json"},
+		dirs:  []string{"/watched"},
+	}
+
+	Expect(unit.isWatched("/simulations/one.json")).To(BeTrue())
+	Expect(unit.isWatched("/simulations/two.json")).To(BeFalse())
+	Expect(unit.isWatched("/watched/two.json")).To(BeTrue())
+	Expect(unit.isWatched("/watched/two.yml")).To(BeTrue())
+	Expect(unit.isWatched("/watched/two.json.swp")).To(BeFalse())
+	Expect(unit.isWatched("/watched/nested/two.json")).To(BeFalse())
+}
@@ -0,0 +1,383 @@
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+)
+
+var postmanVariableRegex = regexp.MustCompile(`{{\s*([^}\s]+)\s*}}`)
+
+type postmanCollection struct {
+	Info     map[string]interface{} `json:"info"`
+	Items    []postmanItem          `json:"item"`
+	Variable []postmanKeyValue      `json:"variable"`
+}
+
+// postmanItem is either a request with its example responses, or a folder of items
+type postmanItem struct {
+	Name      string            `json:"name"`
+	Items     []postmanItem     `json:"item"`
+	Request   *postmanRequest   `json:"request"`
+	Responses []postmanResponse `json:"response"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	URL    postmanURL        `json:"url"`
+	Header []postmanKeyValue `json:"header"`
+	Body   *postmanBody      `json:"body"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanKeyValue `json:"query"`
+}
+
+// UnmarshalJSON reads a URL that can either be a string or an object
+func (this *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		this.Raw = raw
+		return nil
+	}
+
+	type plainURL postmanURL
+	var parsed plainURL
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+
+	var host struct {
+		Host interface{} `json:"host"`
+		Path interface{} `json:"path"`
+	}
+	json.Unmarshal(data, &host)
+	parsed.Host = stringOrList(host.Host, ".")
+	parsed.Path = stringOrList(host.Path, "/")
+
+	*this = postmanURL(parsed)
+	return nil
+}
+
+type postmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+type postmanBody struct {
+	Mode       string                 `json:"mode"`
+	Raw        string                 `json:"raw"`
+	URLEncoded []postmanKeyValue      `json:"urlencoded"`
+	FormData   []postmanKeyValue      `json:"formdata"`
+	Options    map[string]interface{} `json:"options"`
+}
+
+type postmanResponse struct {
+	Name            string            `json:"name"`
+	OriginalRequest *postmanRequest   `json:"originalRequest"`
+	Code            int               `json:"code"`
+	Header          []postmanKeyValue `json:"header"`
+	Body            string            `json:"body"`
+}
+
+// ImportPostman translates the example responses saved in a Postman collection into
+// request response pairs, using the request each example was saved for. Collection
+// variables are replaced with their values. Path variables, and any variables without
+// a value, are matched with a glob.
+func ImportPostman(data []byte) (v2.DataViewV5, v2.SimulationImportResult, error) {
+	result := v2.SimulationImportResult{}
+	dataView := v2.DataViewV5{
+		RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{},
+		GlobalActions: v2.GlobalActionsView{
+			Delays:          []v1.ResponseDelayView{},
+			DelaysLogNormal: []v1.ResponseDelayLogNormalView{},
+		},
+	}
+
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return dataView, result, fmt.Errorf("Unable to parse Postman collection: %s", err.Error())
+	}
+
+	if collection.Info == nil {
+		return dataView, result, fmt.Errorf("Unable to parse Postman collection: missing info, only collection format v2 is supported")
+	}
+
+	variables := map[string]string{}
+	for _, variable := range collection.Variable {
+		variables[variable.Key] = variable.Value
+	}
+
+	importer := postmanImporter{
+		variables: variables,
+		result:    &result,
+	}
+	importer.importItems(collection.Items, "item", &dataView)
+
+	return dataView, result, nil
+}
+
+type postmanImporter struct {
+	variables map[string]string
+	result    *v2.SimulationImportResult
+}
+
+func (this *postmanImporter) importItems(items []postmanItem, location string, dataView *v2.DataViewV5) {
+	for i, item := range items {
+		itemLocation := fmt.Sprintf("%s[%d]", location, i)
+
+		if item.Items != nil {
+			this.importItems(item.Items, itemLocation+".item", dataView)
+			continue
+		}
+
+		if len(item.Responses) == 0 {
+			this.result.AddNotImportedWarning(itemLocation, fmt.Sprintf("'%s' has no example responses", item.Name))
+			continue
+		}
+
+		for j, response := range item.Responses {
+			request := response.OriginalRequest
+			if request == nil {
+				request = item.Request
+			}
+			if request == nil {
+				this.result.AddNotImportedWarning(fmt.Sprintf("%s.response[%d]", itemLocation, j), "it has no request")
+				continue
+			}
+
+			dataView.RequestResponsePairs = append(dataView.RequestResponsePairs, v2.RequestMatcherResponsePairViewV5{
+				RequestMatcher: this.translateRequest(*request, fmt.Sprintf("%s.response[%d].originalRequest", itemLocation, j)),
+				Response:       this.translateResponse(response),
+			})
+		}
+	}
+}
+
+func (this *postmanImporter) translateRequest(request postmanRequest, location string) v2.RequestMatcherViewV5 {
+	requestMatcher := v2.RequestMatcherViewV5{}
+
+	method := request.Method
+	if method == "" {
+		method = "GET"
+	}
+	requestMatcher.Method = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, strings.ToUpper(method))}
+
+	scheme, destination, path, query := this.splitURL(request.URL)
+
+	if scheme != "" && !postmanVariableRegex.MatchString(scheme) {
+		requestMatcher.Scheme = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, scheme)}
+	}
+
+	if destination != "" {
+		requestMatcher.Destination = []v2.MatcherViewV5{this.matcherForValue(destination)}
+	}
+
+	if path != "" {
+		requestMatcher.Path = []v2.MatcherViewV5{this.matcherForPath(path)}
+	}
+
+	for _, parameter := range query {
+		if parameter.Disabled {
+			continue
+		}
+		if requestMatcher.Query == nil {
+			requestMatcher.Query = &v2.QueryMatcherViewV5{}
+		}
+		(*requestMatcher.Query)[parameter.Key] = []v2.MatcherViewV5{this.matcherForValue(parameter.Value)}
+	}
+
+	if request.Body != nil {
+		requestMatcher.Body = this.translateBody(*request.Body, request.Header, location+".body")
+	}
+
+	return requestMatcher
+}
+
+// splitURL breaks a Postman URL into its parts, using the parsed parts of the URL when
+// Postman has saved them and the raw URL otherwise
+func (this *postmanImporter) splitURL(postmanURL postmanURL) (string, string, string, []postmanKeyValue) {
+	if len(postmanURL.Host) > 0 || len(postmanURL.Path) > 0 {
+		path := ""
+		if len(postmanURL.Path) > 0 {
+			path = "/" + strings.Join(postmanURL.Path, "/")
+		}
+		return postmanURL.Protocol, strings.Join(postmanURL.Host, "."), path, postmanURL.Query
+	}
+
+	raw := this.replaceVariables(postmanURL.Raw)
+
+	scheme := ""
+	if index := strings.Index(raw, "://"); index >= 0 {
+		scheme = raw[:index]
+		raw = raw[index+3:]
+	}
+
+	rawQuery := ""
+	if index := strings.Index(raw, "?"); index >= 0 {
+		rawQuery = raw[index+1:]
+		raw = raw[:index]
+	}
+
+	destination := raw
+	path := ""
+	if index := strings.Index(raw, "/"); index >= 0 {
+		destination = raw[:index]
+		path = raw[index:]
+	}
+
+	query := []postmanKeyValue{}
+	values, _ := url.ParseQuery(rawQuery)
+	for key, value := range values {
+		query = append(query, postmanKeyValue{Key: key, Value: strings.Join(value, ";")})
+	}
+
+	return scheme, destination, path, query
+}
+
+// matcherForPath matches a path exactly, unless it has path variables such as :id
+// or variables without a value, which are matched with a glob
+func (this *postmanImporter) matcherForPath(path string) v2.MatcherViewV5 {
+	path = this.replaceVariables(path)
+
+	segments := strings.Split(path, "/")
+	hasGlob := false
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "*"
+			hasGlob = true
+		}
+	}
+
+	if hasGlob || postmanVariableRegex.MatchString(path) {
+		return v2.NewMatcherView(matchers.Glob, postmanVariableRegex.ReplaceAllString(strings.Join(segments, "/"), "*"))
+	}
+
+	return v2.NewMatcherView(matchers.Exact, path)
+}
+
+func (this *postmanImporter) matcherForValue(value string) v2.MatcherViewV5 {
+	value = this.replaceVariables(value)
+
+	if postmanVariableRegex.MatchString(value) {
+		return v2.NewMatcherView(matchers.Glob, postmanVariableRegex.ReplaceAllString(value, "*"))
+	}
+
+	return v2.NewMatcherView(matchers.Exact, value)
+}
+
+func (this *postmanImporter) translateBody(body postmanBody, headers []postmanKeyValue, location string) []v2.MatcherViewV5 {
+	switch body.Mode {
+	case "raw":
+		raw := this.replaceVariables(body.Raw)
+		if raw == "" {
+			return nil
+		}
+		if isPostmanJSON(body, headers) {
+			return []v2.MatcherViewV5{v2.NewMatcherView(matchers.Json, raw)}
+		}
+		return []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, raw)}
+	case "urlencoded", "formdata":
+		fields := body.URLEncoded
+		if body.Mode == "formdata" {
+			fields = body.FormData
+		}
+
+		form := map[string]interface{}{}
+		for _, field := range fields {
+			if field.Disabled {
+				continue
+			}
+			form[field.Key] = []interface{}{
+				map[string]interface{}{
+					"matcher": matchers.Exact,
+					"value":   this.replaceVariables(field.Value),
+				},
+			}
+		}
+		if len(form) == 0 {
+			return nil
+		}
+		return []v2.MatcherViewV5{v2.NewMatcherView(matchers.Form, form)}
+	case "", "none":
+		return nil
+	}
+
+	this.result.AddNotImportedWarning(location, fmt.Sprintf("the %s body mode is not supported", body.Mode))
+	return nil
+}
+
+func (this *postmanImporter) translateResponse(response postmanResponse) v2.ResponseDetailsViewV5 {
+	responseView := v2.ResponseDetailsViewV5{
+		Status: response.Code,
+		Body:   response.Body,
+	}
+	if responseView.Status == 0 {
+		responseView.Status = 200
+	}
+
+	for _, header := range response.Header {
+		if header.Disabled {
+			continue
+		}
+		// Postman saves the decoded body of a response
+		if strings.EqualFold(header.Key, "Content-Encoding") || strings.EqualFold(header.Key, "Content-Length") {
+			continue
+		}
+		if responseView.Headers == nil {
+			responseView.Headers = map[string][]string{}
+		}
+		responseView.Headers[header.Key] = append(responseView.Headers[header.Key], header.Value)
+	}
+
+	return responseView
+}
+
+func (this *postmanImporter) replaceVariables(value string) string {
+	return postmanVariableRegex.ReplaceAllStringFunc(value, func(variable string) string {
+		name := postmanVariableRegex.FindStringSubmatch(variable)[1]
+		if replacement, ok := this.variables[name]; ok {
+			return replacement
+		}
+		return variable
+	})
+}
+
+func isPostmanJSON(body postmanBody, headers []postmanKeyValue) bool {
+	if raw, ok := body.Options["raw"].(map[string]interface{}); ok && raw["language"] == "json" {
+		return true
+	}
+
+	for _, header := range headers {
+		if strings.EqualFold(header.Key, "Content-Type") && strings.Contains(header.Value, "json") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringOrList(value interface{}, separator string) []string {
+	switch typed := value.(type) {
+	case string:
+		return strings.Split(strings.Trim(typed, separator), separator)
+	case []interface{}:
+		list := []string{}
+		for _, item := range typed {
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	}
+	return nil
+}
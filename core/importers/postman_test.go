@@ -0,0 +1,159 @@
+package importers_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/importers"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+const postmanCollection = `{
+	"info": {
+		"name": "Pets",
+		"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	},
+	"variable": [
+		{"key": "baseUrl", "value": "https://api.example.com"}
+	],
+	"item": [
+		{
+			"name": "pets",
+			"item": [
+				{
+					"name": "Get pet",
+					"request": {
+						"method": "GET",
+						"url": "{{baseUrl}}/pets/:id"
+					},
+					"response": [
+						{
+							"name": "Found",
+							"originalRequest": {
+								"method": "GET",
+								"url": {
+									"raw": "https://api.example.com/pets/:id?expand={{expand}}",
+									"protocol": "https",
+									"host": ["api", "example", "com"],
+									"path": ["pets", ":id"],
+									"query": [
+										{"key": "expand", "value": "{{expand}}"},
+										{"key": "debug", "value": "true", "disabled": true}
+									]
+								}
+							},
+							"code": 200,
+							"header": [
+								{"key": "Content-Type", "value": "application/json"},
+								{"key": "Content-Length", "value": "11"}
+							],
+							"body": "{\"id\": 1}"
+						}
+					]
+				}
+			]
+		},
+		{
+			"name": "Create pet",
+			"request": {
+				"method": "POST",
+				"url": "{{baseUrl}}/pets",
+				"header": [{"key": "Content-Type", "value": "application/json"}],
+				"body": {"mode": "raw", "raw": "{\"name\": \"Rex\"}"}
+			},
+			"response": [
+				{"name": "Created", "code": 201, "body": ""}
+			]
+		},
+		{
+			"name": "Login",
+			"request": {
+				"method": "POST",
+				"url": "{{baseUrl}}/login",
+				"body": {"mode": "urlencoded", "urlencoded": [{"key": "user", "value": "rex"}]}
+			},
+			"response": [
+				{"name": "Logged in", "code": 204}
+			]
+		},
+		{
+			"name": "Health",
+			"request": {"method": "GET", "url": "{{baseUrl}}/health"},
+			"response": []
+		}
+	]
+}`
+
+func Test_ImportPostman_TranslatesExampleResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, _, err := importers.ImportPostman([]byte(postmanCollection))
+	Expect(err).To(BeNil())
+	Expect(dataView.RequestResponsePairs).To(HaveLen(3))
+
+	pair := dataView.RequestResponsePairs[0]
+	Expect(pair.RequestMatcher.Method).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "GET")}))
+	Expect(pair.RequestMatcher.Scheme).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "https")}))
+	Expect(pair.RequestMatcher.Destination).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "api.example.com")}))
+	Expect(pair.RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, "/pets/*")}))
+	Expect(*pair.RequestMatcher.Query).To(Equal(v2.QueryMatcherViewV5{
+		"expand": []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, "*")},
+	}))
+
+	Expect(pair.Response.Status).To(Equal(200))
+	Expect(pair.Response.Body).To(Equal(`{"id": 1}`))
+	Expect(pair.Response.Headers).To(Equal(map[string][]string{"Content-Type": {"application/json"}}))
+}
+
+func Test_ImportPostman_ReplacesCollectionVariablesInRawURLs(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, _, err := importers.ImportPostman([]byte(postmanCollection))
+	Expect(err).To(BeNil())
+
+	pair := dataView.RequestResponsePairs[1]
+	Expect(pair.RequestMatcher.Method).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "POST")}))
+	Expect(pair.RequestMatcher.Scheme).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "https")}))
+	Expect(pair.RequestMatcher.Destination).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "api.example.com")}))
+	Expect(pair.RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/pets")}))
+	Expect(pair.Response.Status).To(Equal(201))
+}
+
+func Test_ImportPostman_TranslatesRequestBodies(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, _, err := importers.ImportPostman([]byte(postmanCollection))
+	Expect(err).To(BeNil())
+
+	Expect(dataView.RequestResponsePairs[1].RequestMatcher.Body).To(Equal([]v2.MatcherViewV5{
+		v2.NewMatcherView(matchers.Json, `{"name": "Rex"}`),
+	}))
+	Expect(dataView.RequestResponsePairs[2].RequestMatcher.Body).To(Equal([]v2.MatcherViewV5{
+		v2.NewMatcherView(matchers.Form, map[string]interface{}{
+			"user": []interface{}{
+				map[string]interface{}{"matcher": matchers.Exact, "value": "rex"},
+			},
+		}),
+	}))
+}
+
+func Test_ImportPostman_ReportsRequestsWithoutExamples(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, result, err := importers.ImportPostman([]byte(postmanCollection))
+	Expect(err).To(BeNil())
+
+	Expect(result.WarningMessages).To(HaveLen(1))
+	Expect(result.WarningMessages[0].Message).To(Equal("WARNING: item[3] could not be imported, 'Health' has no example responses"))
+}
+
+func Test_ImportPostman_ReturnsErrorForAnythingButACollection(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, _, err := importers.ImportPostman([]byte(`not json`))
+	Expect(err).ToNot(BeNil())
+
+	_, _, err = importers.ImportPostman([]byte(`{"mappings": []}`))
+	Expect(err).To(MatchError("Unable to parse Postman collection: missing info, only collection format v2 is supported"))
+}
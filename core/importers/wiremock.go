@@ -0,0 +1,416 @@
+package importers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+)
+
+// WireMock starts every scenario in this state
+const wireMockStartedState = "Started"
+
+// wireMockDefaultPriority is the priority WireMock gives mappings without one
+const wireMockDefaultPriority = 5
+
+type wireMockMappings struct {
+	Mappings []wireMockMapping `json:"mappings"`
+}
+
+type wireMockMapping struct {
+	Priority              int              `json:"priority"`
+	Request               wireMockRequest  `json:"request"`
+	Response              wireMockResponse `json:"response"`
+	ScenarioName          string           `json:"scenarioName"`
+	RequiredScenarioState string           `json:"requiredScenarioState"`
+	NewScenarioState      string           `json:"newScenarioState"`
+}
+
+type wireMockRequest struct {
+	Method          string                            `json:"method"`
+	URL             string                            `json:"url"`
+	URLPath         string                            `json:"urlPath"`
+	URLPattern      string                            `json:"urlPattern"`
+	URLPathPattern  string                            `json:"urlPathPattern"`
+	QueryParameters map[string]wireMockContentPattern `json:"queryParameters"`
+	Headers         map[string]wireMockContentPattern `json:"headers"`
+	Cookies         map[string]wireMockContentPattern `json:"cookies"`
+	BodyPatterns    []wireMockContentPattern          `json:"bodyPatterns"`
+	BasicAuth       map[string]interface{}            `json:"basicAuthCredentials"`
+	MultipartParts  []map[string]interface{}          `json:"multipartPatterns"`
+}
+
+// wireMockContentPattern is a WireMock matcher such as {"equalTo": "value"}, along
+// with the options that can be given next to it
+type wireMockContentPattern map[string]interface{}
+
+type wireMockResponse struct {
+	Status                 int                    `json:"status"`
+	Body                   string                 `json:"body"`
+	JSONBody               interface{}            `json:"jsonBody"`
+	Base64Body             string                 `json:"base64Body"`
+	BodyFileName           string                 `json:"bodyFileName"`
+	Headers                map[string]interface{} `json:"headers"`
+	FixedDelayMilliseconds int                    `json:"fixedDelayMilliseconds"`
+	DelayDistribution      map[string]interface{} `json:"delayDistribution"`
+	ChunkedDribbleDelay    map[string]interface{} `json:"chunkedDribbleDelay"`
+	Fault                  string                 `json:"fault"`
+	Transformers           []string               `json:"transformers"`
+	ProxyBaseURL           string                 `json:"proxyBaseUrl"`
+}
+
+// ImportWireMock translates WireMock stub mappings into request response pairs. It takes
+// either a single mapping or an object with a list of mappings, as found in the files of
+// a WireMock mappings directory. Scenarios become state requirements and transitions, and
+// fixed delays become delays for the URL of the mapping. Anything without an equivalent in
+// Hoverfly is left out and reported as a warning.
+func ImportWireMock(data []byte) (v2.DataViewV5, v2.SimulationImportResult, error) {
+	result := v2.SimulationImportResult{}
+	dataView := v2.DataViewV5{
+		RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{},
+		GlobalActions: v2.GlobalActionsView{
+			Delays:          []v1.ResponseDelayView{},
+			DelaysLogNormal: []v1.ResponseDelayLogNormalView{},
+		},
+	}
+
+	var mappings wireMockMappings
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return dataView, result, fmt.Errorf("Unable to parse WireMock mappings: %s", err.Error())
+	}
+
+	if mappings.Mappings == nil {
+		var mapping wireMockMapping
+		if err := json.Unmarshal(data, &mapping); err != nil {
+			return dataView, result, fmt.Errorf("Unable to parse WireMock mappings: %s", err.Error())
+		}
+		mappings.Mappings = []wireMockMapping{mapping}
+	}
+
+	// WireMock uses the mapping with the highest priority, which is the lowest number, and
+	// the one added last when they have the same priority. Hoverfly uses the last of the
+	// pairs that match equally well, so the highest priority mappings go last.
+	order := []int{}
+	for i := range mappings.Mappings {
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return priority(mappings.Mappings[order[i]]) > priority(mappings.Mappings[order[j]])
+	})
+
+	for _, i := range order {
+		mapping := mappings.Mappings[i]
+		location := fmt.Sprintf("mappings[%d]", i)
+
+		requestMatcher, delayURLPattern := translateWireMockRequest(mapping.Request, location, &result)
+
+		response := translateWireMockResponse(mapping.Response, location+".response", &result)
+
+		if mapping.ScenarioName != "" {
+			// Hoverfly has no initial state, so the first state of a scenario is the one without any state set
+			if mapping.RequiredScenarioState != "" && mapping.RequiredScenarioState != wireMockStartedState {
+				requestMatcher.RequiresState = map[string]string{mapping.ScenarioName: mapping.RequiredScenarioState}
+			}
+			if mapping.NewScenarioState != "" {
+				response.TransitionsState = map[string]string{mapping.ScenarioName: mapping.NewScenarioState}
+			}
+		}
+
+		if mapping.Response.FixedDelayMilliseconds > 0 {
+			delay := v1.ResponseDelayView{
+				UrlPattern: delayURLPattern,
+				Delay:      mapping.Response.FixedDelayMilliseconds,
+			}
+			if len(requestMatcher.Method) > 0 {
+				delay.HttpMethod = requestMatcher.Method[0].Value.(string)
+			}
+			dataView.GlobalActions.Delays = append(dataView.GlobalActions.Delays, delay)
+		}
+
+		dataView.RequestResponsePairs = append(dataView.RequestResponsePairs, v2.RequestMatcherResponsePairViewV5{
+			RequestMatcher: requestMatcher,
+			Response:       response,
+		})
+	}
+
+	return dataView, result, nil
+}
+
+func priority(mapping wireMockMapping) int {
+	if mapping.Priority == 0 {
+		return wireMockDefaultPriority
+	}
+	return mapping.Priority
+}
+
+// translateWireMockRequest returns the request matcher for a mapping, along with
+// a pattern for the delays of its URL
+func translateWireMockRequest(request wireMockRequest, location string, result *v2.SimulationImportResult) (v2.RequestMatcherViewV5, string) {
+	requestMatcher := v2.RequestMatcherViewV5{}
+
+	if request.Method != "" && request.Method != "ANY" {
+		requestMatcher.Method = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, strings.ToUpper(request.Method))}
+	}
+
+	// Delays are matched against the destination and path of a request
+	delayURLPattern := "."
+
+	switch {
+	case request.URL != "":
+		parsed, err := url.Parse(request.URL)
+		if err != nil {
+			result.AddNotImportedWarning(location+".request.url", "it is not a valid URL")
+			break
+		}
+		requestMatcher.Path = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, parsed.Path)}
+		delayURLPattern = "^[^/]*" + regexp.QuoteMeta(parsed.Path) + "$"
+
+		if parsed.RawQuery != "" {
+			query := v2.QueryMatcherViewV5{}
+			for key, values := range parsed.Query() {
+				query[key] = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, strings.Join(values, ";"))}
+			}
+			requestMatcher.Query = &query
+		}
+	case request.URLPath != "":
+		requestMatcher.Path = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, request.URLPath)}
+		delayURLPattern = "^[^/]*" + regexp.QuoteMeta(request.URLPath) + "$"
+	case request.URLPattern != "":
+		pathPattern := request.URLPattern
+		if index := strings.Index(pathPattern, `\?`); index >= 0 {
+			pathPattern = pathPattern[:index]
+			result.AddNotImportedWarning(location+".request.urlPattern", "only the path is matched, use queryParameters to match the query")
+		}
+		requestMatcher.Path = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Regex, fullMatch(pathPattern))}
+		delayURLPattern = "^[^/]*" + group(pathPattern) + "$"
+	case request.URLPathPattern != "":
+		requestMatcher.Path = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Regex, fullMatch(request.URLPathPattern))}
+		delayURLPattern = "^[^/]*" + group(request.URLPathPattern) + "$"
+	}
+
+	for _, name := range sortedPatternKeys(request.QueryParameters) {
+		matcher, ok := translateWireMockPattern(request.QueryParameters[name], fmt.Sprintf("%s.request.queryParameters.%s", location, name), result)
+		if !ok {
+			continue
+		}
+		if requestMatcher.Query == nil {
+			requestMatcher.Query = &v2.QueryMatcherViewV5{}
+		}
+		(*requestMatcher.Query)[name] = []v2.MatcherViewV5{matcher}
+	}
+
+	for _, name := range sortedPatternKeys(request.Headers) {
+		matcher, ok := translateWireMockPattern(request.Headers[name], fmt.Sprintf("%s.request.headers.%s", location, name), result)
+		if !ok {
+			continue
+		}
+		if requestMatcher.Headers == nil {
+			requestMatcher.Headers = map[string][]v2.MatcherViewV5{}
+		}
+		requestMatcher.Headers[name] = []v2.MatcherViewV5{matcher}
+	}
+
+	for i, pattern := range request.BodyPatterns {
+		matcher, ok := translateWireMockPattern(pattern, fmt.Sprintf("%s.request.bodyPatterns[%d]", location, i), result)
+		if ok {
+			requestMatcher.Body = append(requestMatcher.Body, matcher)
+		}
+	}
+
+	if len(request.Cookies) > 0 {
+		result.AddNotImportedWarning(location+".request.cookies", "cookie matching is not supported")
+	}
+	if request.BasicAuth != nil {
+		result.AddNotImportedWarning(location+".request.basicAuthCredentials", "basic auth matching is not supported")
+	}
+	if len(request.MultipartParts) > 0 {
+		result.AddNotImportedWarning(location+".request.multipartPatterns", "multipart matching is not supported, use the form matcher instead")
+	}
+
+	return requestMatcher, delayURLPattern
+}
+
+// translateWireMockPattern turns a WireMock matcher into a Hoverfly matcher
+func translateWireMockPattern(pattern wireMockContentPattern, location string, result *v2.SimulationImportResult) (v2.MatcherViewV5, bool) {
+	var matcher v2.MatcherViewV5
+
+	switch {
+	case pattern["equalTo"] != nil:
+		matcher = v2.NewMatcherView(matchers.Exact, fmt.Sprint(pattern["equalTo"]))
+		if caseInsensitive, _ := pattern["caseInsensitive"].(bool); caseInsensitive {
+			matcher.Config = map[string]interface{}{matchers.IgnoreCaseConfig: true}
+		}
+	case pattern["contains"] != nil:
+		matcher = v2.NewMatcherView(matchers.Regex, regexp.QuoteMeta(fmt.Sprint(pattern["contains"])))
+	case pattern["matches"] != nil:
+		matcher = v2.NewMatcherView(matchers.Regex, fullMatch(fmt.Sprint(pattern["matches"])))
+	case pattern["doesNotMatch"] != nil:
+		matcher = v2.NewMatcherView(matchers.Regex, fullMatch(fmt.Sprint(pattern["doesNotMatch"])))
+		matcher.Config = map[string]interface{}{matchers.NegateConfig: true}
+	case pattern["equalToJson"] != nil:
+		value := pattern["equalToJson"]
+		if _, ok := value.(string); !ok {
+			encoded, _ := json.Marshal(value)
+			value = string(encoded)
+		}
+		matcher = v2.NewMatcherView(matchers.Json, value)
+		if ignoreExtraElements, _ := pattern["ignoreExtraElements"].(bool); ignoreExtraElements {
+			matcher.Matcher = matchers.JsonPartial
+		}
+		if ignoreArrayOrder, _ := pattern["ignoreArrayOrder"].(bool); ignoreArrayOrder {
+			result.AddNotImportedWarning(location+".ignoreArrayOrder", "arrays are always matched in order")
+		}
+	case pattern["matchesJsonPath"] != nil:
+		expression, ok := pattern["matchesJsonPath"].(string)
+		if !ok {
+			result.AddNotImportedWarning(location, "matchesJsonPath with a sub matcher is not supported")
+			return matcher, false
+		}
+		matcher = v2.NewMatcherView(matchers.JsonPath, expression)
+	case pattern["equalToXml"] != nil:
+		matcher = v2.NewMatcherView(matchers.Xml, fmt.Sprint(pattern["equalToXml"]))
+	case pattern["matchesXPath"] != nil:
+		expression, ok := pattern["matchesXPath"].(string)
+		if !ok {
+			result.AddNotImportedWarning(location, "matchesXPath with a sub matcher is not supported")
+			return matcher, false
+		}
+		matcher = v2.NewMatcherView(matchers.Xpath, expression)
+	default:
+		names := []string{}
+		for name := range pattern {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result.AddNotImportedWarning(location, fmt.Sprintf("the %s matcher is not supported", strings.Join(names, ", ")))
+		return matcher, false
+	}
+
+	return matcher, true
+}
+
+func translateWireMockResponse(response wireMockResponse, location string, result *v2.SimulationImportResult) v2.ResponseDetailsViewV5 {
+	responseView := v2.ResponseDetailsViewV5{
+		Status: response.Status,
+		Body:   response.Body,
+	}
+	if responseView.Status == 0 {
+		responseView.Status = 200
+	}
+
+	if response.JSONBody != nil {
+		body, _ := json.Marshal(response.JSONBody)
+		responseView.Body = string(body)
+	} else if response.Base64Body != "" {
+		if _, err := base64.StdEncoding.DecodeString(response.Base64Body); err != nil {
+			result.AddNotImportedWarning(location+".base64Body", "it is not valid base64")
+		} else {
+			responseView.Body = response.Base64Body
+			responseView.EncodedBody = true
+		}
+	}
+
+	if response.BodyFileName != "" {
+		result.AddNotImportedWarning(location+".bodyFileName", "body files are not supported, copy the file contents into body")
+	}
+
+	if len(response.Headers) > 0 {
+		responseView.Headers = map[string][]string{}
+		for name, value := range response.Headers {
+			switch typed := value.(type) {
+			case []interface{}:
+				for _, item := range typed {
+					responseView.Headers[name] = append(responseView.Headers[name], fmt.Sprint(item))
+				}
+			default:
+				responseView.Headers[name] = []string{fmt.Sprint(typed)}
+			}
+		}
+	}
+
+	for _, transformer := range response.Transformers {
+		if transformer == "response-template" {
+			responseView.Templated = true
+			result.AddNotImportedWarning(location+".transformers", "templating is enabled, but WireMock template helpers need to be replaced with Hoverfly ones")
+		}
+	}
+
+	translateWireMockDelayDistribution(response.DelayDistribution, &responseView, location+".delayDistribution", result)
+
+	switch response.Fault {
+	case "":
+	case "CONNECTION_RESET_BY_PEER":
+		responseView.Fault = &v2.ResponseFaultView{ConnectionReset: true}
+	case "EMPTY_RESPONSE":
+		responseView.Fault = &v2.ResponseFaultView{EmptyResponse: true}
+	case "MALFORMED_RESPONSE_CHUNK":
+		responseView.Fault = &v2.ResponseFaultView{MalformedChunked: true}
+	default:
+		result.AddNotImportedWarning(location+".fault", fmt.Sprintf("the %s fault is not supported", response.Fault))
+	}
+
+	if response.ChunkedDribbleDelay != nil {
+		result.AddNotImportedWarning(location+".chunkedDribbleDelay", "use a bandwidth fault to slow down the response instead")
+	}
+	if response.ProxyBaseURL != "" {
+		result.AddNotImportedWarning(location+".proxyBaseUrl", "proxying from a single mapping is not supported")
+	}
+
+	return responseView
+}
+
+// translateWireMockDelayDistribution turns a random delay into a delay on the response.
+// WireMock describes a log normal delay with its median and sigma, the mean of which
+// is the median multiplied by e^(sigma^2 / 2).
+func translateWireMockDelayDistribution(distribution map[string]interface{}, responseView *v2.ResponseDetailsViewV5, location string, result *v2.SimulationImportResult) {
+	if distribution == nil {
+		return
+	}
+
+	number := func(name string) float64 {
+		value, _ := distribution[name].(float64)
+		return value
+	}
+
+	switch distribution["type"] {
+	case "uniform":
+		responseView.UniformDelay = &v2.UniformDelayView{
+			Min: int(number("lower")),
+			Max: int(number("upper")),
+		}
+	case "lognormal":
+		median := number("median")
+		responseView.LogNormalDelay = &v2.LogNormalDelayView{
+			Median: int(median),
+			Mean:   int(median * math.Exp(math.Pow(number("sigma"), 2)/2)),
+		}
+	default:
+		result.AddNotImportedWarning(location, fmt.Sprintf("the %v delay distribution is not supported", distribution["type"]))
+	}
+}
+
+func sortedPatternKeys(patterns map[string]wireMockContentPattern) []string {
+	keys := []string{}
+	for key := range patterns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fullMatch anchors a regex, as WireMock requires a regex to match the whole value
+func fullMatch(pattern string) string {
+	return "^" + group(pattern) + "$"
+}
+
+func group(pattern string) string {
+	return "(?:" + strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$") + ")"
+}
@@ -0,0 +1,248 @@
+package importers_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/importers"
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	. "github.com/onsi/gomega"
+)
+
+func Test_ImportWireMock_TranslatesASingleMapping(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, result, err := importers.ImportWireMock([]byte(`{
+		"request": {
+			"method": "GET",
+			"url": "/api/users?page=2"
+		},
+		"response": {
+			"status": 200,
+			"body": "users",
+			"headers": {
+				"Content-Type": "text/plain",
+				"Set-Cookie": ["a=1", "b=2"]
+			}
+		}
+	}`))
+	Expect(err).To(BeNil())
+	Expect(result.WarningMessages).To(BeEmpty())
+
+	Expect(dataView.RequestResponsePairs).To(HaveLen(1))
+	pair := dataView.RequestResponsePairs[0]
+	Expect(pair.RequestMatcher.Method).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "GET")}))
+	Expect(pair.RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/api/users")}))
+	Expect(*pair.RequestMatcher.Query).To(Equal(v2.QueryMatcherViewV5{
+		"page": []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "2")},
+	}))
+
+	Expect(pair.Response.Status).To(Equal(200))
+	Expect(pair.Response.Body).To(Equal("users"))
+	Expect(pair.Response.Headers).To(Equal(map[string][]string{
+		"Content-Type": {"text/plain"},
+		"Set-Cookie":   {"a=1", "b=2"},
+	}))
+}
+
+func Test_ImportWireMock_TranslatesURLPatterns(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, result, err := importers.ImportWireMock([]byte(`{
+		"mappings": [
+			{"request": {"method": "ANY", "urlPath": "/exact"}, "response": {}},
+			{"request": {"urlPathPattern": "/users/[0-9]+"}, "response": {}},
+			{"request": {"urlPattern": "/search\\?q=.*"}, "response": {}}
+		]
+	}`))
+	Expect(err).To(BeNil())
+
+	Expect(dataView.RequestResponsePairs).To(HaveLen(3))
+	Expect(dataView.RequestResponsePairs[0].RequestMatcher.Method).To(BeNil())
+	Expect(dataView.RequestResponsePairs[0].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/exact")}))
+	Expect(dataView.RequestResponsePairs[0].Response.Status).To(Equal(200))
+	Expect(dataView.RequestResponsePairs[1].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Regex, "^(?:/users/[0-9]+)$")}))
+	Expect(dataView.RequestResponsePairs[2].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Regex, "^(?:/search)$")}))
+
+	Expect(result.WarningMessages).To(HaveLen(1))
+	Expect(result.WarningMessages[0].Message).To(Equal("WARNING: mappings[2].request.urlPattern could not be imported, only the path is matched, use queryParameters to match the query"))
+}
+
+func Test_ImportWireMock_TranslatesContentPatterns(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, result, err := importers.ImportWireMock([]byte(`{
+		"request": {
+			"method": "POST",
+			"urlPath": "/orders",
+			"queryParameters": {
+				"type": {"equalTo": "express", "caseInsensitive": true},
+				"debug": {"doesNotMatch": "true|yes"}
+			},
+			"headers": {
+				"Content-Type": {"contains": "json"}
+			},
+			"bodyPatterns": [
+				{"equalToJson": {"id": 1}, "ignoreExtraElements": true},
+				{"matchesJsonPath": "$.items[?(@.count > 1)]"},
+				{"equalToJson": "{\"id\": 1}"}
+			]
+		},
+		"response": {"status": 201}
+	}`))
+	Expect(err).To(BeNil())
+	Expect(result.WarningMessages).To(BeEmpty())
+
+	requestMatcher := dataView.RequestResponsePairs[0].RequestMatcher
+	Expect(*requestMatcher.Query).To(Equal(v2.QueryMatcherViewV5{
+		"type": []v2.MatcherViewV5{{
+			Matcher: matchers.Exact,
+			Value:   "express",
+			Config:  map[string]interface{}{matchers.IgnoreCaseConfig: true},
+		}},
+		"debug": []v2.MatcherViewV5{{
+			Matcher: matchers.Regex,
+			Value:   "^(?:true|yes)$",
+			Config:  map[string]interface{}{matchers.NegateConfig: true},
+		}},
+	}))
+	Expect(requestMatcher.Headers).To(Equal(map[string][]v2.MatcherViewV5{
+		"Content-Type": {v2.NewMatcherView(matchers.Regex, "json")},
+	}))
+	Expect(requestMatcher.Body).To(Equal([]v2.MatcherViewV5{
+		v2.NewMatcherView(matchers.JsonPartial, `{"id":1}`),
+		v2.NewMatcherView(matchers.JsonPath, "$.items[?(@.count > 1)]"),
+		v2.NewMatcherView(matchers.Json, `{"id": 1}`),
+	}))
+}
+
+func Test_ImportWireMock_TranslatesScenariosIntoState(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, _, err := importers.ImportWireMock([]byte(`{
+		"mappings": [
+			{
+				"scenarioName": "cart",
+				"requiredScenarioState": "Started",
+				"newScenarioState": "item added",
+				"request": {"method": "POST", "url": "/cart"},
+				"response": {"status": 201}
+			},
+			{
+				"scenarioName": "cart",
+				"requiredScenarioState": "item added",
+				"request": {"method": "GET", "url": "/cart"},
+				"response": {"body": "1 item"}
+			}
+		]
+	}`))
+	Expect(err).To(BeNil())
+
+	Expect(dataView.RequestResponsePairs[0].RequestMatcher.RequiresState).To(BeNil())
+	Expect(dataView.RequestResponsePairs[0].Response.TransitionsState).To(Equal(map[string]string{"cart": "item added"}))
+	Expect(dataView.RequestResponsePairs[1].RequestMatcher.RequiresState).To(Equal(map[string]string{"cart": "item added"}))
+	Expect(dataView.RequestResponsePairs[1].Response.TransitionsState).To(BeNil())
+}
+
+func Test_ImportWireMock_OrdersMappingsByPriority(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, result, err := importers.ImportWireMock([]byte(`{
+		"mappings": [
+			{"request": {"urlPathPattern": "/.*"}, "response": {"body": "fallback"}},
+			{"priority": 1, "request": {"urlPath": "/specific", "cookies": {"session": {"equalTo": "1"}}}, "response": {"body": "specific"}}
+		]
+	}`))
+	Expect(err).To(BeNil())
+
+	Expect(dataView.RequestResponsePairs[0].Response.Body).To(Equal("fallback"))
+	Expect(dataView.RequestResponsePairs[1].Response.Body).To(Equal("specific"))
+
+	Expect(result.WarningMessages).To(HaveLen(1))
+	Expect(result.WarningMessages[0].Message).To(Equal("WARNING: mappings[1].request.cookies could not be imported, cookie matching is not supported"))
+}
+
+func Test_ImportWireMock_MatchesTheHighestPriorityMappingWhenMoreThanOneMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, _, err := importers.ImportWireMock([]byte(`{
+		"mappings": [
+			{"priority": 1, "request": {"urlPathPattern": "/things/.*"}, "response": {"body": "high"}},
+			{"request": {"urlPathPattern": "/things/[0-9]+"}, "response": {"body": "default"}},
+			{"priority": 10, "request": {"urlPathPattern": "/things/1"}, "response": {"body": "low"}},
+			{"request": {"urlPathPattern": "/other/.*"}, "response": {"body": "first"}},
+			{"request": {"urlPathPattern": "/other/[0-9]+"}, "response": {"body": "last"}}
+		]
+	}`))
+	Expect(err).To(BeNil())
+
+	simulation := models.NewSimulation()
+	for _, pair := range dataView.RequestResponsePairs {
+		simulation.AddPair(models.NewRequestMatcherResponsePairFromView(&pair))
+	}
+
+	match := func(path string) string {
+		result := matching.Match("strongest", models.RequestDetails{Method: "GET", Path: path}, false, simulation, state.NewState())
+		Expect(result.Error).To(BeNil())
+		return result.Pair.Response.Body
+	}
+
+	Expect(match("/things/1")).To(Equal("high"))
+	Expect(match("/other/1")).To(Equal("last"))
+}
+
+func Test_ImportWireMock_TranslatesDelaysAndFaults(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, result, err := importers.ImportWireMock([]byte(`{
+		"mappings": [
+			{"request": {"method": "GET", "url": "/slow"}, "response": {"fixedDelayMilliseconds": 500}},
+			{"request": {"url": "/random"}, "response": {"delayDistribution": {"type": "uniform", "lower": 100, "upper": 200}}},
+			{"request": {"url": "/reset"}, "response": {"fault": "CONNECTION_RESET_BY_PEER"}},
+			{"request": {"url": "/garbage"}, "response": {"fault": "RANDOM_DATA_THEN_CLOSE"}}
+		]
+	}`))
+	Expect(err).To(BeNil())
+
+	Expect(dataView.GlobalActions.Delays).To(Equal([]v1.ResponseDelayView{{
+		UrlPattern: `^[^/]*/slow$`,
+		Delay:      500,
+		HttpMethod: "GET",
+	}}))
+	Expect(dataView.RequestResponsePairs[1].Response.UniformDelay).To(Equal(&v2.UniformDelayView{Min: 100, Max: 200}))
+	Expect(dataView.RequestResponsePairs[2].Response.Fault).To(Equal(&v2.ResponseFaultView{ConnectionReset: true}))
+	Expect(dataView.RequestResponsePairs[3].Response.Fault).To(BeNil())
+
+	Expect(result.WarningMessages).To(HaveLen(1))
+	Expect(result.WarningMessages[0].Message).To(Equal("WARNING: mappings[3].response.fault could not be imported, the RANDOM_DATA_THEN_CLOSE fault is not supported"))
+}
+
+func Test_ImportWireMock_ReportsUnsupportedMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataView, result, err := importers.ImportWireMock([]byte(`{
+		"request": {
+			"urlPath": "/",
+			"headers": {"Accept": {"absent": true}}
+		},
+		"response": {"bodyFileName": "body.json"}
+	}`))
+	Expect(err).To(BeNil())
+
+	Expect(dataView.RequestResponsePairs[0].RequestMatcher.Headers).To(BeNil())
+	Expect(result.WarningMessages).To(HaveLen(2))
+	Expect(result.WarningMessages[0].Message).To(Equal("WARNING: mappings[0].request.headers.Accept could not be imported, the absent matcher is not supported"))
+	Expect(result.WarningMessages[1].Message).To(Equal("WARNING: mappings[0].response.bodyFileName could not be imported, body files are not supported, copy the file contents into body"))
+}
+
+func Test_ImportWireMock_ReturnsErrorForInvalidJSON(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, _, err := importers.ImportWireMock([]byte(`not json`))
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(HavePrefix("Unable to parse WireMock mappings"))
+}
@@ -0,0 +1,289 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	entriesBucket          = []byte("journal_entries")
+	metaBucket             = []byte("journal_meta")
+	destinationIndexBucket = []byte("journal_index_destination")
+	pathIndexBucket        = []byte("journal_index_path")
+	statusIndexBucket      = []byte("journal_index_status")
+
+	countKey = []byte("count")
+	sizeKey  = []byte("size")
+)
+
+// BoltStorage keeps journal entries in a BoltDB database so that they survive restarts.
+// Entries are keyed by a sequence number, so they are kept in the order they were
+// added, and are indexed by destination, path and status. An index key is the indexed
+// value followed by the sequence number of the entry.
+type BoltStorage struct {
+	DB *bolt.DB
+}
+
+func NewBoltStorage(db *bolt.DB) (*BoltStorage, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		return createBuckets(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltStorage{DB: db}, nil
+}
+
+// OpenBoltStorage opens, or creates, a BoltDB database at the given path for a journal
+func OpenBoltStorage(path string) (*BoltStorage, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open journal database %s: %s", path, err.Error())
+	}
+
+	storage, err := NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return storage, nil
+}
+
+// Append adds the entry and removes the oldest entries in the same transaction
+func (this *BoltStorage) Append(entry JournalEntry, limits Limits) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return this.DB.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+
+		sequence, err := entries.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := sequenceKey(sequence)
+
+		if err := entries.Put(key, value); err != nil {
+			return err
+		}
+
+		for _, index := range indexValues(entry) {
+			if err := tx.Bucket(index.bucket).Put(indexKey(index.value, key), []byte{}); err != nil {
+				return err
+			}
+		}
+
+		return rotate(tx, getCount(tx)+1, getMeta(tx, sizeKey)+EntrySize(entry), limits)
+	})
+}
+
+func (this *BoltStorage) Count() (int, error) {
+	var count int
+	err := this.DB.View(func(tx *bolt.Tx) error {
+		count = getCount(tx)
+		return nil
+	})
+	return count, err
+}
+
+// Range moves a cursor over the skipped entries without decoding them
+func (this *BoltStorage) Range(offset int, reverse bool, visit func(JournalEntry) bool) error {
+	return this.DB.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(entriesBucket).Cursor()
+		first, next := cursor.First, cursor.Next
+		if reverse {
+			first, next = cursor.Last, cursor.Prev
+		}
+
+		key, value := first()
+		for i := 0; i < offset && key != nil; i++ {
+			key, value = next()
+		}
+
+		for ; key != nil; key, value = next() {
+			entry, err := decodeEntry(value)
+			if err != nil {
+				return err
+			}
+			if !visit(entry) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// Find looks up the entries in the index of the first field that is set, then
+// checks the other fields of the query against each of those entries. Index keys
+// for the same value are sorted by sequence number, so entries stay in order.
+func (this *BoltStorage) Find(query IndexQuery) ([]JournalEntry, error) {
+	found := []JournalEntry{}
+
+	if query.IsEmpty() {
+		err := this.Range(0, false, func(entry JournalEntry) bool {
+			found = append(found, entry)
+			return true
+		})
+		return found, err
+	}
+
+	bucket, value := pathIndexBucket, query.Path
+	if query.Destination != "" {
+		bucket, value = destinationIndexBucket, query.Destination
+	} else if query.Path == "" {
+		bucket, value = statusIndexBucket, strconv.Itoa(query.Status)
+	}
+
+	err := this.DB.View(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+
+		keys := [][]byte{}
+		prefix := indexKey(value, nil)
+		cursor := tx.Bucket(bucket).Cursor()
+		for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			keys = append(keys, append([]byte{}, key[len(prefix):]...))
+		}
+
+		for _, key := range keys {
+			value := entries.Get(key)
+			if value == nil {
+				continue
+			}
+			entry, err := decodeEntry(value)
+			if err != nil {
+				return err
+			}
+			if query.Matches(entry) {
+				found = append(found, entry)
+			}
+		}
+		return nil
+	})
+
+	return found, err
+}
+
+// rotate removes the oldest entries, and their index keys, until the entries are within the limits,
+// then keeps how many entries are left and their size
+func rotate(tx *bolt.Tx, count int, size int64, limits Limits) error {
+	cursor := tx.Bucket(entriesBucket).Cursor()
+
+	for key, value := cursor.First(); key != nil; key, value = cursor.First() {
+		entry, err := decodeEntry(value)
+		if err != nil {
+			return err
+		}
+		if !limits.exceeded(count, size, entry) {
+			break
+		}
+
+		for _, index := range indexValues(entry) {
+			if err := tx.Bucket(index.bucket).Delete(indexKey(index.value, key)); err != nil {
+				return err
+			}
+		}
+		if err := cursor.Delete(); err != nil {
+			return err
+		}
+		count--
+		size -= EntrySize(entry)
+	}
+
+	// Databases written before the size was kept do not know the size of their older entries
+	if size < 0 || count == 0 {
+		size = 0
+	}
+	if err := setMeta(tx, sizeKey, size); err != nil {
+		return err
+	}
+	return setCount(tx, count)
+}
+
+func (this *BoltStorage) DeleteAll() error {
+	return this.DB.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{entriesBucket, metaBucket, destinationIndexBucket, pathIndexBucket, statusIndexBucket} {
+			if err := tx.DeleteBucket(bucket); err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+		}
+		return createBuckets(tx)
+	})
+}
+
+func (this *BoltStorage) Close() error {
+	return this.DB.Close()
+}
+
+func createBuckets(tx *bolt.Tx) error {
+	for _, bucket := range [][]byte{entriesBucket, metaBucket, destinationIndexBucket, pathIndexBucket, statusIndexBucket} {
+		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type indexValue struct {
+	bucket []byte
+	value  string
+}
+
+func indexValues(entry JournalEntry) []indexValue {
+	return []indexValue{
+		{bucket: destinationIndexBucket, value: entry.Request.Destination},
+		{bucket: pathIndexBucket, value: entry.Request.Path},
+		{bucket: statusIndexBucket, value: strconv.Itoa(entry.Response.Status)},
+	}
+}
+
+func decodeEntry(value []byte) (JournalEntry, error) {
+	var entry JournalEntry
+	if err := json.Unmarshal(value, &entry); err != nil {
+		return entry, fmt.Errorf("Unable to read journal entry: %s", err.Error())
+	}
+	return entry, nil
+}
+
+// getCount returns the number of entries, which is kept rather than counted
+// as counting the keys of a bucket reads all of them
+func getCount(tx *bolt.Tx) int {
+	return int(getMeta(tx, countKey))
+}
+
+func setCount(tx *bolt.Tx, count int) error {
+	return setMeta(tx, countKey, int64(count))
+}
+
+func getMeta(tx *bolt.Tx, key []byte) int64 {
+	value := tx.Bucket(metaBucket).Get(key)
+	if value == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(value))
+}
+
+func setMeta(tx *bolt.Tx, key []byte, value int64) error {
+	return tx.Bucket(metaBucket).Put(key, sequenceKey(uint64(value)))
+}
+
+func sequenceKey(sequence uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+	return key
+}
+
+// indexKey separates the indexed value from the entry key with a zero byte,
+// so that looking up one value does not find values that start with it
+func indexKey(value string, key []byte) []byte {
+	return append(append([]byte(value), 0), key...)
+}
@@ -0,0 +1,163 @@
+package journal_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	. "github.com/onsi/gomega"
+)
+
+func openTestBoltStorage(directory string) *journal.BoltStorage {
+	storage, err := journal.OpenBoltStorage(filepath.Join(directory, "journal.db"))
+	Expect(err).To(BeNil())
+	return storage
+}
+
+func Test_BoltStorage_Append_KeepsEntriesInOrderAcrossRestarts(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(directory)
+
+	unit := openTestBoltStorage(directory)
+	started := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	Expect(unit.Append(newStorageEntry("one", "/a", 200, started), noLimits)).To(Succeed())
+	Expect(unit.Append(newStorageEntry("two", "/b", 404, started), noLimits)).To(Succeed())
+	Expect(unit.Close()).To(Succeed())
+
+	unit = openTestBoltStorage(directory)
+	defer unit.Close()
+
+	Expect(unit.Count()).To(Equal(2))
+	entries := allEntries(unit)
+	Expect(destinations(entries)).To(Equal([]string{"one", "two"}))
+	Expect(entries[1].Request.Path).To(Equal("/b"))
+	Expect(entries[1].Response.Status).To(Equal(404))
+	Expect(entries[1].TimeStarted.Equal(started)).To(BeTrue())
+}
+
+func Test_BoltStorage_Find_UsesIndexes(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(directory)
+
+	unit := openTestBoltStorage(directory)
+	defer unit.Close()
+
+	unit.Append(newStorageEntry("hoverfly.io", "/a", 200, time.Now()), noLimits)
+	unit.Append(newStorageEntry("hoverfly.io.uk", "/a", 500, time.Now()), noLimits)
+	unit.Append(newStorageEntry("hoverfly.io", "/b", 500, time.Now()), noLimits)
+	unit.Append(newStorageEntry("specto.io", "/a", 200, time.Now()), noLimits)
+
+	entries, err := unit.Find(journal.IndexQuery{Destination: "hoverfly.io"})
+	Expect(err).To(BeNil())
+	Expect(entries).To(HaveLen(2))
+	Expect(entries[0].Request.Path).To(Equal("/a"))
+	Expect(entries[1].Request.Path).To(Equal("/b"))
+
+	entries, _ = unit.Find(journal.IndexQuery{Path: "/a", Status: 200})
+	Expect(destinations(entries)).To(Equal([]string{"hoverfly.io", "specto.io"}))
+
+	entries, _ = unit.Find(journal.IndexQuery{Status: 500})
+	Expect(destinations(entries)).To(Equal([]string{"hoverfly.io.uk", "hoverfly.io"}))
+}
+
+func Test_BoltStorage_Append_RemovesOldEntriesAndTheirIndexes(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(directory)
+
+	unit := openTestBoltStorage(directory)
+	defer unit.Close()
+
+	now := time.Now()
+	unit.Append(newStorageEntry("old", "/a", 200, now.Add(-time.Hour)), noLimits)
+	unit.Append(newStorageEntry("one", "/a", 200, now), noLimits)
+	unit.Append(newStorageEntry("two", "/a", 200, now), journal.Limits{MaxEntries: 10, Cutoff: now.Add(-time.Minute)})
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"one", "two"}))
+
+	unit.Append(newStorageEntry("three", "/a", 200, now), journal.Limits{MaxEntries: 2})
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"two", "three"}))
+	Expect(unit.Count()).To(Equal(2))
+
+	entries, _ := unit.Find(journal.IndexQuery{Path: "/a"})
+	Expect(destinations(entries)).To(Equal([]string{"two", "three"}))
+
+	entries, _ = unit.Find(journal.IndexQuery{Destination: "old"})
+	Expect(entries).To(BeEmpty())
+}
+
+func Test_BoltStorage_Append_RemovesOldestEntriesOverTheByteLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(directory)
+
+	unit := openTestBoltStorage(directory)
+	defer unit.Close()
+
+	entry := newStorageEntry("one", "/", 200, time.Now())
+	limits := journal.Limits{MaxEntries: 10, MaxBytes: 2*journal.EntrySize(entry) + 1}
+	for _, destination := range []string{"one", "two", "six"} {
+		unit.Append(newStorageEntry(destination, "/", 200, time.Now()), limits)
+	}
+
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"two", "six"}))
+}
+
+func Test_BoltStorage_Range_SkipsToTheOffsetInEitherDirection(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(directory)
+
+	unit := openTestBoltStorage(directory)
+	defer unit.Close()
+
+	for _, destination := range []string{"one", "two", "three", "four"} {
+		unit.Append(newStorageEntry(destination, "/", 200, time.Now()), noLimits)
+	}
+
+	entries := []journal.JournalEntry{}
+	Expect(unit.Range(1, false, func(entry journal.JournalEntry) bool {
+		entries = append(entries, entry)
+		return len(entries) < 2
+	})).To(Succeed())
+	Expect(destinations(entries)).To(Equal([]string{"two", "three"}))
+
+	entries = []journal.JournalEntry{}
+	unit.Range(1, true, func(entry journal.JournalEntry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	Expect(destinations(entries)).To(Equal([]string{"three", "two", "one"}))
+}
+
+func Test_BoltStorage_DeleteAll_RemovesEverything(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(directory)
+
+	unit := openTestBoltStorage(directory)
+	defer unit.Close()
+
+	unit.Append(newStorageEntry("one", "/a", 200, time.Now()), noLimits)
+	Expect(unit.DeleteAll()).To(Succeed())
+
+	Expect(allEntries(unit)).To(BeEmpty())
+
+	entries, _ := unit.Find(journal.IndexQuery{Path: "/a"})
+	Expect(entries).To(BeEmpty())
+
+	unit.Append(newStorageEntry("two", "/a", 200, time.Now()), noLimits)
+	unit.Append(newStorageEntry("three", "/a", 200, time.Now()), journal.Limits{MaxEntries: 1})
+
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"three"}))
+}
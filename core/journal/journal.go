@@ -10,6 +10,7 @@ import (
 
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/util"
 )
@@ -22,21 +23,38 @@ type JournalEntry struct {
 	Mode        string
 	TimeStarted time.Time
 	Latency     time.Duration
+	Match       *v2.JournalMatchView
 }
 
 type Journal struct {
-	entries    []JournalEntry
-	EntryLimit int
+	storage     Storage
+	subscribers *subscribers
+	EntryLimit  int
+	// MaxBytes is how many bytes the entries can take up, or no limit when it is zero
+	MaxBytes int64
+	// MaxAge is how long entries are kept for, or forever when it is zero
+	MaxAge time.Duration
 }
 
 func NewJournal() *Journal {
+	return NewJournalWithStorage(NewMemoryStorage())
+}
+
+// NewJournalWithStorage returns a journal that keeps its entries in the given storage
+func NewJournalWithStorage(storage Storage) *Journal {
 	return &Journal{
-		entries:    []JournalEntry{},
-		EntryLimit: 1000,
+		storage:     storage,
+		subscribers: newSubscribers(),
+		EntryLimit:  1000,
 	}
 }
 
 func (this *Journal) NewEntry(request *http.Request, response *http.Response, mode string, started time.Time) error {
+	return this.NewEntryWithMatch(request, response, nil, mode, started)
+}
+
+// NewEntryWithMatch adds an entry along with how its request was matched against the simulation
+func (this *Journal) NewEntryWithMatch(request *http.Request, response *http.Response, match *v2.JournalMatchView, mode string, started time.Time) error {
 	if this.EntryLimit == 0 {
 		return fmt.Errorf("Journal disabled")
 	}
@@ -51,21 +69,37 @@ func (this *Journal) NewEntry(request *http.Request, response *http.Response, mo
 		Headers: response.Header,
 	}
 
-	if len(this.entries) >= this.EntryLimit {
-		this.entries = append(this.entries[:0], this.entries[1:]...)
-	}
-
-	this.entries = append(this.entries, JournalEntry{
+	entry := JournalEntry{
 		Request:     &payloadRequest,
 		Response:    payloadResponse,
 		Mode:        mode,
 		TimeStarted: started,
 		Latency:     time.Since(started),
-	})
+		Match:       match,
+	}
+
+	if err := this.storage.Append(entry, this.limits()); err != nil {
+		return err
+	}
+
+	this.subscribers.publish(entry)
 
 	return nil
 }
 
+// limits makes the storage remove the oldest entries once there are more than the entry limit
+// or they take up more than the maximum bytes, along with any entries older than the maximum age
+func (this *Journal) limits() Limits {
+	limits := Limits{
+		MaxEntries: this.EntryLimit,
+		MaxBytes:   this.MaxBytes,
+	}
+	if this.MaxAge > 0 {
+		limits.Cutoff = time.Now().Add(-this.MaxAge)
+	}
+	return limits
+}
+
 func (this Journal) GetEntries(offset int, limit int, from *time.Time, to *time.Time, sort string) (v2.JournalView, error) {
 	journalView := v2.JournalView{
 		Journal: []v2.JournalEntryView{},
@@ -84,56 +118,99 @@ func (this Journal) GetEntries(offset int, limit int, from *time.Time, to *time.
 		return journalView, err
 	}
 
-	selectedEntries := []JournalEntry{}
+	if offset < 0 {
+		offset = 0
+	}
 
-	// Filtering
-	if from != nil || to != nil {
-		for _, entry := range this.entries {
-			if from != nil && entry.TimeStarted.Before(*from) {
-				continue
-			}
-			if to != nil && entry.TimeStarted.After(*to) {
-				continue
-			}
-			selectedEntries = append(selectedEntries, entry)
-		}
+	inWindow := func(entry JournalEntry) bool {
+		return (from == nil || !entry.TimeStarted.Before(*from)) && (to == nil || !entry.TimeStarted.After(*to))
+	}
+
+	// Entries are kept in the order they were added, so only sorting by latency needs every entry.
+	// Otherwise the storage skips to the page, unless entries have to be counted to filter them by time.
+	var page []JournalEntry
+	var totalElements int
+	if sortKey == "latency" {
+		page, totalElements, err = this.getEntriesByLatency(offset, limit, inWindow, sortOrder)
+	} else if from == nil && to == nil {
+		page, totalElements, err = this.getPage(offset, limit, sortOrder == "desc")
 	} else {
-		selectedEntries = append(selectedEntries, this.entries...)
+		page, totalElements, err = this.getFilteredPage(offset, limit, inWindow, sortOrder == "desc")
 	}
 
-	// Sorting
-	if sortKey == "timestarted" && sortOrder == "desc" {
-		sorting.Slice(selectedEntries, func(i, j int) bool {
-			return selectedEntries[i].TimeStarted.After(selectedEntries[j].TimeStarted)
-		})
-	} else if sortKey == "latency" {
-		sorting.Slice(selectedEntries, func(i, j int) bool {
-			if sortOrder == "desc" {
-				return selectedEntries[i].Latency > selectedEntries[j].Latency
-			} else {
-				return selectedEntries[i].Latency < selectedEntries[j].Latency
-			}
-		})
+	if err != nil || offset >= totalElements {
+		return journalView, err
 	}
 
-	totalElements := len(selectedEntries)
+	journalView.Journal = convertJournalEntries(page)
+	journalView.Offset = offset
+	journalView.Limit = limit
+	journalView.Total = totalElements
+	return journalView, nil
+}
 
-	if offset < 0 {
-		offset = 0
-	} else if offset >= totalElements {
-		return journalView, nil
+// getPage reads the entries of a page, and counts all of the entries
+func (this Journal) getPage(offset, limit int, reverse bool) ([]JournalEntry, int, error) {
+	total, err := this.storage.Count()
+	if err != nil || offset >= total || limit <= 0 {
+		return nil, total, err
+	}
+
+	page := []JournalEntry{}
+	err = this.storage.Range(offset, reverse, func(entry JournalEntry) bool {
+		page = append(page, entry)
+		return len(page) < limit
+	})
+	return page, total, err
+}
+
+// getFilteredPage reads every entry to count those that are selected, keeping only those of the page
+func (this Journal) getFilteredPage(offset, limit int, selected func(JournalEntry) bool, reverse bool) ([]JournalEntry, int, error) {
+	page := []JournalEntry{}
+	total := 0
+	err := this.storage.Range(0, reverse, func(entry JournalEntry) bool {
+		if selected(entry) {
+			if total >= offset && len(page) < limit {
+				page = append(page, entry)
+			}
+			total++
+		}
+		return true
+	})
+	return page, total, err
+}
+
+func (this Journal) getEntriesByLatency(offset, limit int, selected func(JournalEntry) bool, sortOrder string) ([]JournalEntry, int, error) {
+	selectedEntries := []JournalEntry{}
+	err := this.storage.Range(0, false, func(entry JournalEntry) bool {
+		if selected(entry) {
+			selectedEntries = append(selectedEntries, entry)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
+	sorting.SliceStable(selectedEntries, func(i, j int) bool {
+		if sortOrder == "desc" {
+			return selectedEntries[i].Latency > selectedEntries[j].Latency
+		}
+		return selectedEntries[i].Latency < selectedEntries[j].Latency
+	})
+
+	totalElements := len(selectedEntries)
+	if offset >= totalElements {
+		return nil, totalElements, nil
+	}
 	endIndex := offset + limit
 	if endIndex > totalElements {
 		endIndex = totalElements
 	}
-
-	journalView.Journal = convertJournalEntries(selectedEntries[offset:endIndex])
-	journalView.Offset = offset
-	journalView.Limit = limit
-	journalView.Total = totalElements
-	return journalView, nil
+	if endIndex < offset {
+		endIndex = offset
+	}
+	return selectedEntries[offset:endIndex], totalElements, nil
 }
 
 func (this Journal) GetFilteredEntries(journalEntryFilterView v2.JournalEntryFilterView) ([]v2.JournalEntryView, error) {
@@ -142,51 +219,20 @@ func (this Journal) GetFilteredEntries(journalEntryFilterView v2.JournalEntryFil
 		return filteredEntries, fmt.Errorf("Journal disabled")
 	}
 
-	requestMatcher := models.RequestMatcher{
-		Path:            models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Path),
-		Method:          models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Method),
-		Destination:     models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Destination),
-		Scheme:          models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Scheme),
-		DeprecatedQuery: models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.DeprecatedQuery),
-		Body:            models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Body),
-		Query:           models.NewQueryRequestFieldMatchersFromMapView(journalEntryFilterView.Request.Query),
-		Headers:         models.NewRequestFieldMatchersFromMapView(journalEntryFilterView.Request.Headers),
+	filter := newEntryFilter(journalEntryFilterView)
+	if filter.isEmpty() {
+		return filteredEntries, nil
 	}
 
-	allEntries := convertJournalEntries(this.entries)
+	entries, err := this.storage.Find(filter.indexQuery)
+	if err != nil {
+		return filteredEntries, err
+	}
 
-	for _, entry := range allEntries {
-		if requestMatcher.Body == nil && requestMatcher.Destination == nil &&
-			requestMatcher.Headers == nil && requestMatcher.Method == nil &&
-			requestMatcher.Path == nil && requestMatcher.DeprecatedQuery == nil &&
-			requestMatcher.Scheme == nil && requestMatcher.Query == nil {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Body, *entry.Request.Body).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Destination, *entry.Request.Destination).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Method, *entry.Request.Method).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Path, *entry.Request.Path).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.DeprecatedQuery, *entry.Request.Query).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Scheme, *entry.Request.Scheme).Matched {
-			continue
+	for _, entry := range convertJournalEntries(entries) {
+		if filter.matches(entry) {
+			filteredEntries = append(filteredEntries, entry)
 		}
-		if !matching.QueryMatching(requestMatcher, entry.Request.QueryMap).Matched {
-			continue
-		}
-		if !matching.HeaderMatching(requestMatcher, entry.Request.Headers).Matched {
-			continue
-		}
-		filteredEntries = append(filteredEntries, entry)
 	}
 
 	return filteredEntries, nil
@@ -197,9 +243,112 @@ func (this *Journal) DeleteEntries() error {
 		return fmt.Errorf("Journal disabled")
 	}
 
-	this.entries = []JournalEntry{}
+	return this.storage.DeleteAll()
+}
+
+// entryFilter matches journal entries against the fields of a JournalEntryFilterView
+type entryFilter struct {
+	requestMatcher models.RequestMatcher
+	indexQuery     IndexQuery
+	unmatched      bool
+}
 
-	return nil
+func newEntryFilter(journalEntryFilterView v2.JournalEntryFilterView) entryFilter {
+	filter := entryFilter{
+		indexQuery: IndexQuery{Status: journalEntryFilterView.Status},
+		unmatched:  journalEntryFilterView.Unmatched,
+	}
+
+	if journalEntryFilterView.Request != nil {
+		filter.requestMatcher = models.RequestMatcher{
+			Path:            models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Path),
+			Method:          models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Method),
+			Destination:     models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Destination),
+			Scheme:          models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Scheme),
+			DeprecatedQuery: models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.DeprecatedQuery),
+			Body:            models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Body),
+			Query:           models.NewQueryRequestFieldMatchersFromMapView(journalEntryFilterView.Request.Query),
+			Headers:         models.NewRequestFieldMatchersFromMapView(journalEntryFilterView.Request.Headers),
+		}
+
+		// Exact matches on indexed fields narrow down the entries before matching
+		filter.indexQuery.Destination = exactMatcherValue(journalEntryFilterView.Request.Destination)
+		filter.indexQuery.Path = exactMatcherValue(journalEntryFilterView.Request.Path)
+	}
+
+	return filter
+}
+
+func (this entryFilter) isEmpty() bool {
+	requestMatcher := this.requestMatcher
+	return requestMatcher.Body == nil && requestMatcher.Destination == nil &&
+		requestMatcher.Headers == nil && requestMatcher.Method == nil &&
+		requestMatcher.Path == nil && requestMatcher.DeprecatedQuery == nil &&
+		requestMatcher.Scheme == nil && requestMatcher.Query == nil && this.indexQuery.Status == 0 && !this.unmatched
+}
+
+// entryMatch is the result of checking a filter against a journal entry
+type entryMatch struct {
+	missedFields []string
+	missReasons  []string
+	score        int
+}
+
+func (this *entryMatch) add(fieldMatch *matching.FieldMatch, field string) {
+	if !fieldMatch.Matched {
+		this.missedFields = append(this.missedFields, field)
+		for _, missReason := range fieldMatch.MissReasons {
+			this.missReasons = append(this.missReasons, field+": "+missReason)
+		}
+	}
+	this.score += fieldMatch.Score
+}
+
+// match checks every field of the filter against an entry, scoring it the same way
+// as matching a request against a simulation so that near misses can be found
+func (this entryFilter) match(entry v2.JournalEntryView) entryMatch {
+	requestMatcher := this.requestMatcher
+	result := entryMatch{missedFields: []string{}}
+
+	contentType := http.Header(entry.Request.Headers).Get("Content-Type")
+	result.add(matching.BodyMatcher(requestMatcher.Body, *entry.Request.Body, contentType), "body")
+	result.add(matching.FieldMatcher(requestMatcher.Destination, *entry.Request.Destination), "destination")
+	result.add(matching.FieldMatcher(requestMatcher.Path, *entry.Request.Path), "path")
+	result.add(matching.FieldMatcher(requestMatcher.DeprecatedQuery, *entry.Request.Query), "query")
+	result.add(matching.FieldMatcher(requestMatcher.Method, *entry.Request.Method), "method")
+	result.add(matching.FieldMatcher(requestMatcher.Scheme, *entry.Request.Scheme), "scheme")
+	result.add(matching.HeaderMatching(requestMatcher, entry.Request.Headers), "headers")
+	result.add(matching.QueryMatching(requestMatcher, entry.Request.QueryMap), "queries")
+
+	if this.indexQuery.Status != 0 {
+		result.add(&matching.FieldMatch{
+			Matched: entry.Response.Status == this.indexQuery.Status,
+			Score:   1,
+		}, "status")
+	}
+
+	// Only requests that were matched against the simulation and missed are unmatched,
+	// requests handled in other ways, such as in capture mode, are neither
+	if this.unmatched {
+		result.add(&matching.FieldMatch{
+			Matched: entry.Match != nil && !entry.Match.Matched,
+			Score:   1,
+		}, "unmatched")
+	}
+
+	return result
+}
+
+func (this entryFilter) matches(entry v2.JournalEntryView) bool {
+	return len(this.match(entry).missedFields) == 0
+}
+
+func exactMatcherValue(fieldMatchers []v2.MatcherViewV5) string {
+	if len(fieldMatchers) != 1 || fieldMatchers[0].Matcher != matchers.Exact || fieldMatchers[0].Config != nil {
+		return ""
+	}
+	value, _ := fieldMatchers[0].Value.(string)
+	return value
 }
 
 func convertJournalEntries(entries []JournalEntry) []v2.JournalEntryView {
@@ -213,6 +362,7 @@ func convertJournalEntries(entries []JournalEntry) []v2.JournalEntryView {
 			Mode:        journalEntry.Mode,
 			TimeStarted: journalEntry.TimeStarted.Format(RFC3339Milli),
 			Latency:     journalEntry.Latency.Seconds() * 1e3,
+			Match:       journalEntry.Match,
 		})
 	}
 
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -761,3 +763,141 @@ func Test_Journal_GetFilteredEntries_WillReturnEmptyIfRequestMatcherIsEmpty(t *t
 		Request: &v2.RequestMatcherViewV5{},
 	})).To(HaveLen(0))
 }
+
+func Test_Journal_GetFilteredEntries_WillFilterOnResponseStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+
+	for _, status := range []int{200, 404, 200} {
+		request, _ := http.NewRequest("GET", "http://hoverfly.io/path/"+strconv.Itoa(status), nil)
+		unit.NewEntry(request, &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("test body")),
+		}, "test-mode", time.Now())
+	}
+
+	Expect(unit.GetFilteredEntries(v2.JournalEntryFilterView{Status: 200})).To(HaveLen(2))
+
+	entries, err := unit.GetFilteredEntries(v2.JournalEntryFilterView{
+		Status: 404,
+		Request: &v2.RequestMatcherViewV5{
+			Destination: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "hoverfly.io")},
+		},
+	})
+	Expect(err).To(BeNil())
+	Expect(entries).To(HaveLen(1))
+	Expect(*entries[0].Request.Path).To(Equal("/path/404"))
+}
+
+func Test_Journal_NewEntry_RemovesEntriesOlderThanMaxAge(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	unit.MaxAge = time.Minute
+
+	request, _ := http.NewRequest("GET", "http://hoverfly.io", nil)
+
+	unit.NewEntry(request, &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("old")),
+	}, "test-mode", time.Now().Add(-time.Hour))
+
+	unit.NewEntry(request, &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("new")),
+	}, "test-mode", time.Now())
+
+	journalView, err := unit.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+	Expect(journalView.Journal[0].Response.Body).To(Equal("new"))
+}
+
+func Test_Journal_NewEntry_RemovesOldestEntriesOverMaxBytes(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	unit.MaxBytes = 1000
+
+	request, _ := http.NewRequest("GET", "http://hoverfly.io", nil)
+
+	for i := 0; i < 5; i++ {
+		unit.NewEntry(request, &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(strconv.Itoa(i) + strings.Repeat("-", 399))),
+		}, "test-mode", time.Now())
+	}
+
+	journalView, err := unit.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(2))
+	Expect(journalView.Journal[0].Response.Body).To(HavePrefix("3"))
+	Expect(journalView.Journal[1].Response.Body).To(HavePrefix("4"))
+}
+
+func Test_Journal_WithBoltStorage_KeepsEntriesAcrossRestarts(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(directory)
+
+	storage := openTestBoltStorage(directory)
+	unit := journal.NewJournalWithStorage(storage)
+
+	request, _ := http.NewRequest("GET", "http://hoverfly.io/path", nil)
+	unit.NewEntry(request, &http.Response{
+		StatusCode: 201,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("test body")),
+	}, "test-mode", time.Now())
+	storage.Close()
+
+	unit = journal.NewJournalWithStorage(openTestBoltStorage(directory))
+
+	journalView, err := unit.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+	Expect(journalView.Journal[0].Response.Status).To(Equal(201))
+	Expect(journalView.Journal[0].Response.Body).To(Equal("test body"))
+}
+
+func Test_Journal_NewEntryWithMatch_KeepsTheMatch(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+
+	request, _ := http.NewRequest("GET", "http://hoverfly.io", nil)
+	unit.NewEntryWithMatch(request, &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("test body")),
+	}, &v2.JournalMatchView{Matched: true, CacheHit: true}, "simulate", time.Now())
+
+	journalView, err := unit.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal[0].Match).To(Equal(&v2.JournalMatchView{Matched: true, CacheHit: true}))
+}
+
+func Test_Journal_GetFilteredEntries_WillFilterOnUnmatchedRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+
+	matches := []*v2.JournalMatchView{
+		{Matched: true},
+		{Matched: false, ClosestMiss: &v2.ClosestMissView{MissedFields: []string{"path"}}},
+		nil,
+	}
+	for i, match := range matches {
+		request, _ := http.NewRequest("GET", "http://hoverfly.io/"+strconv.Itoa(i), nil)
+		unit.NewEntryWithMatch(request, &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("test body")),
+		}, match, "simulate", time.Now())
+	}
+
+	entries, err := unit.GetFilteredEntries(v2.JournalEntryFilterView{Unmatched: true})
+	Expect(err).To(BeNil())
+	Expect(entries).To(HaveLen(1))
+	Expect(*entries[0].Request.Path).To(Equal("/1"))
+	Expect(entries[0].Match.ClosestMiss.MissedFields).To(Equal([]string{"path"}))
+}
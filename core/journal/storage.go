@@ -0,0 +1,169 @@
+package journal
+
+import (
+	"sync"
+	"time"
+)
+
+// Storage is where a journal keeps its entries, oldest first
+type Storage interface {
+	// Append adds an entry, then removes the oldest entries until the storage is within the limits
+	Append(entry JournalEntry, limits Limits) error
+	Count() (int, error)
+	// Range calls visit with each entry after the first offset entries, from the oldest, or from
+	// the newest when reverse is true, until visit returns false. Skipped entries are not read.
+	Range(offset int, reverse bool, visit func(JournalEntry) bool) error
+	// Find returns the entries with the destination, path and status of the query,
+	// ignoring any of them that are not set
+	Find(query IndexQuery) ([]JournalEntry, error)
+	DeleteAll() error
+	Close() error
+}
+
+// Limits are how many entries a storage keeps. The byte limit and the cutoff are ignored when they are zero.
+type Limits struct {
+	MaxEntries int
+	// MaxBytes is the most bytes the entries can take up, as counted by EntrySize
+	MaxBytes int64
+	// Cutoff is the earliest time the entries that are kept can have started at
+	Cutoff time.Time
+}
+
+// exceeded returns whether a storage with the given number and size of entries, and the
+// given oldest entry, has to remove its oldest entry to be within the limits
+func (this Limits) exceeded(count int, size int64, oldest JournalEntry) bool {
+	return count > this.MaxEntries ||
+		(this.MaxBytes > 0 && size > this.MaxBytes) ||
+		(!this.Cutoff.IsZero() && oldest.TimeStarted.Before(this.Cutoff))
+}
+
+// EntrySize is how many bytes an entry takes up, counting its URL, headers and bodies
+func EntrySize(entry JournalEntry) int64 {
+	size := len(entry.Request.Method) + len(entry.Request.Scheme) + len(entry.Request.Destination) +
+		len(entry.Request.Path) + len(entry.Request.Body) + len(entry.Response.Body)
+	for _, fields := range []map[string][]string{entry.Request.Query, entry.Request.Headers, entry.Response.Headers} {
+		for name, values := range fields {
+			size += len(name)
+			for _, value := range values {
+				size += len(value)
+			}
+		}
+	}
+	return int64(size)
+}
+
+// IndexQuery looks up journal entries by the fields that storage indexes
+type IndexQuery struct {
+	Destination string
+	Path        string
+	Status      int
+}
+
+func (this IndexQuery) IsEmpty() bool {
+	return this.Destination == "" && this.Path == "" && this.Status == 0
+}
+
+func (this IndexQuery) Matches(entry JournalEntry) bool {
+	if this.Destination != "" && entry.Request.Destination != this.Destination {
+		return false
+	}
+	if this.Path != "" && entry.Request.Path != this.Path {
+		return false
+	}
+	if this.Status != 0 && entry.Response.Status != this.Status {
+		return false
+	}
+	return true
+}
+
+// MemoryStorage keeps journal entries in memory. Removing the oldest entries moves the
+// start of the slice forward, and the slice is only copied once half of it is unused.
+type MemoryStorage struct {
+	entries []JournalEntry
+	sizes   []int64
+	size    int64
+	start   int
+	mutex   sync.RWMutex
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		entries: []JournalEntry{},
+	}
+}
+
+func (this *MemoryStorage) Append(entry JournalEntry, limits Limits) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	size := EntrySize(entry)
+	this.entries = append(this.entries, entry)
+	this.sizes = append(this.sizes, size)
+	this.size += size
+
+	for this.start < len(this.entries) && limits.exceeded(len(this.entries)-this.start, this.size, this.entries[this.start]) {
+		this.entries[this.start] = JournalEntry{}
+		this.size -= this.sizes[this.start]
+		this.start++
+	}
+
+	if this.start > len(this.entries)/2 {
+		this.entries = append([]JournalEntry{}, this.entries[this.start:]...)
+		this.sizes = append([]int64{}, this.sizes[this.start:]...)
+		this.start = 0
+	}
+
+	return nil
+}
+
+func (this *MemoryStorage) Count() (int, error) {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	return len(this.entries) - this.start, nil
+}
+
+func (this *MemoryStorage) Range(offset int, reverse bool, visit func(JournalEntry) bool) error {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	entries := this.entries[this.start:]
+	for i := offset; i < len(entries); i++ {
+		index := i
+		if reverse {
+			index = len(entries) - 1 - i
+		}
+		if !visit(entries[index]) {
+			break
+		}
+	}
+	return nil
+}
+
+func (this *MemoryStorage) Find(query IndexQuery) ([]JournalEntry, error) {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	found := []JournalEntry{}
+	for _, entry := range this.entries[this.start:] {
+		if query.Matches(entry) {
+			found = append(found, entry)
+		}
+	}
+	return found, nil
+}
+
+func (this *MemoryStorage) DeleteAll() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.entries = []JournalEntry{}
+	this.sizes = nil
+	this.size = 0
+	this.start = 0
+	return nil
+}
+
+func (this *MemoryStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package journal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func newStorageEntry(destination, path string, status int, started time.Time) journal.JournalEntry {
+	return journal.JournalEntry{
+		Request: &models.RequestDetails{
+			Method:      "GET",
+			Destination: destination,
+			Path:        path,
+		},
+		Response: &models.ResponseDetails{
+			Status: status,
+		},
+		TimeStarted: started,
+	}
+}
+
+var noLimits = journal.Limits{MaxEntries: 1000}
+
+func allEntries(storage journal.Storage) []journal.JournalEntry {
+	entries := []journal.JournalEntry{}
+	Expect(storage.Range(0, false, func(entry journal.JournalEntry) bool {
+		entries = append(entries, entry)
+		return true
+	})).To(Succeed())
+	return entries
+}
+
+func destinations(entries []journal.JournalEntry) []string {
+	result := []string{}
+	for _, entry := range entries {
+		result = append(result, entry.Request.Destination)
+	}
+	return result
+}
+
+func Test_MemoryStorage_Append_RemovesOldestEntriesOverTheLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewMemoryStorage()
+	for _, destination := range []string{"one", "two", "three", "four"} {
+		Expect(unit.Append(newStorageEntry(destination, "/", 200, time.Now()), journal.Limits{MaxEntries: 2})).To(Succeed())
+	}
+
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"three", "four"}))
+
+	unit.Append(newStorageEntry("five", "/", 200, time.Now()), journal.Limits{MaxEntries: 2})
+
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"four", "five"}))
+	Expect(unit.Count()).To(Equal(2))
+}
+
+func Test_MemoryStorage_Append_RemovesEntriesOlderThanCutoff(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Now()
+	unit := journal.NewMemoryStorage()
+	unit.Append(newStorageEntry("old", "/", 200, now.Add(-time.Hour)), noLimits)
+	unit.Append(newStorageEntry("new", "/", 200, now), journal.Limits{MaxEntries: 10, Cutoff: now.Add(-time.Minute)})
+
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"new"}))
+}
+
+func Test_MemoryStorage_Append_RemovesOldestEntriesOverTheByteLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	entry := newStorageEntry("one", "/", 200, time.Now())
+	limits := journal.Limits{MaxEntries: 10, MaxBytes: 2*journal.EntrySize(entry) + 1}
+
+	unit := journal.NewMemoryStorage()
+	for _, destination := range []string{"one", "two", "six"} {
+		unit.Append(newStorageEntry(destination, "/", 200, time.Now()), limits)
+	}
+
+	Expect(destinations(allEntries(unit))).To(Equal([]string{"two", "six"}))
+}
+
+func Test_MemoryStorage_Range_SkipsToTheOffsetInEitherDirection(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewMemoryStorage()
+	for _, destination := range []string{"one", "two", "three", "four"} {
+		unit.Append(newStorageEntry(destination, "/", 200, time.Now()), noLimits)
+	}
+
+	entries := []journal.JournalEntry{}
+	Expect(unit.Range(1, false, func(entry journal.JournalEntry) bool {
+		entries = append(entries, entry)
+		return len(entries) < 2
+	})).To(Succeed())
+	Expect(destinations(entries)).To(Equal([]string{"two", "three"}))
+
+	entries = []journal.JournalEntry{}
+	unit.Range(1, true, func(entry journal.JournalEntry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	Expect(destinations(entries)).To(Equal([]string{"three", "two", "one"}))
+}
+
+func Test_MemoryStorage_Find_ReturnsEntriesMatchingTheQuery(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewMemoryStorage()
+	unit.Append(newStorageEntry("one", "/a", 200, time.Now()), noLimits)
+	unit.Append(newStorageEntry("two", "/a", 500, time.Now()), noLimits)
+	unit.Append(newStorageEntry("three", "/b", 500, time.Now()), noLimits)
+
+	entries, err := unit.Find(journal.IndexQuery{Path: "/a", Status: 500})
+	Expect(err).To(BeNil())
+	Expect(destinations(entries)).To(Equal([]string{"two"}))
+
+	entries, _ = unit.Find(journal.IndexQuery{})
+	Expect(entries).To(HaveLen(3))
+}
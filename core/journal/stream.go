@@ -0,0 +1,81 @@
+package journal
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// SubscriberBufferSize is how many entries a subscriber can fall behind by. Any
+// further entries are dropped for that subscriber rather than slowing down requests.
+const SubscriberBufferSize = 100
+
+type subscriber struct {
+	filter  entryFilter
+	entries chan v2.JournalEntryView
+}
+
+type subscribers struct {
+	mutex sync.Mutex
+	all   map[*subscriber]bool
+}
+
+func newSubscribers() *subscribers {
+	return &subscribers{
+		all: map[*subscriber]bool{},
+	}
+}
+
+// Subscribe returns a channel which receives each new journal entry that matches the
+// filter, or every new entry when the filter is empty. The returned function stops
+// the subscription and closes the channel.
+func (this *Journal) Subscribe(journalEntryFilterView v2.JournalEntryFilterView) (<-chan v2.JournalEntryView, func(), error) {
+	if this.EntryLimit == 0 {
+		return nil, nil, fmt.Errorf("Journal disabled")
+	}
+
+	subscriber := &subscriber{
+		filter:  newEntryFilter(journalEntryFilterView),
+		entries: make(chan v2.JournalEntryView, SubscriberBufferSize),
+	}
+
+	this.subscribers.mutex.Lock()
+	this.subscribers.all[subscriber] = true
+	this.subscribers.mutex.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			this.subscribers.mutex.Lock()
+			delete(this.subscribers.all, subscriber)
+			this.subscribers.mutex.Unlock()
+
+			close(subscriber.entries)
+		})
+	}
+
+	return subscriber.entries, unsubscribe, nil
+}
+
+func (this *subscribers) publish(entry JournalEntry) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if len(this.all) == 0 {
+		return
+	}
+
+	entryView := convertJournalEntries([]JournalEntry{entry})[0]
+
+	for subscriber := range this.all {
+		if !subscriber.filter.matches(entryView) {
+			continue
+		}
+
+		select {
+		case subscriber.entries <- entryView:
+		default:
+		}
+	}
+}
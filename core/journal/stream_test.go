@@ -0,0 +1,112 @@
+package journal_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func addJournalEntry(unit *journal.Journal, url string, status int) {
+	request, _ := http.NewRequest("GET", url, nil)
+
+	unit.NewEntry(request, &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("test body")),
+	}, "simulate", time.Now())
+}
+
+func Test_Journal_Subscribe_ReceivesNewEntries(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	addJournalEntry(unit, "http://hoverfly.io/before", 200)
+
+	entries, unsubscribe, err := unit.Subscribe(v2.JournalEntryFilterView{})
+	Expect(err).To(BeNil())
+	defer unsubscribe()
+
+	addJournalEntry(unit, "http://hoverfly.io/after", 201)
+
+	var entry v2.JournalEntryView
+	Eventually(entries).Should(Receive(&entry))
+	Expect(*entry.Request.Path).To(Equal("/after"))
+	Expect(entry.Response.Status).To(Equal(201))
+	Expect(entry.Response.Body).To(Equal("test body"))
+	Expect(entry.Mode).To(Equal("simulate"))
+
+	Consistently(entries).ShouldNot(Receive())
+}
+
+func Test_Journal_Subscribe_OnlyReceivesEntriesMatchingTheFilter(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+
+	entries, unsubscribe, err := unit.Subscribe(v2.JournalEntryFilterView{
+		Status: 500,
+		Request: &v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, "/api/*")},
+		},
+	})
+	Expect(err).To(BeNil())
+	defer unsubscribe()
+
+	addJournalEntry(unit, "http://hoverfly.io/api/one", 200)
+	addJournalEntry(unit, "http://hoverfly.io/other", 500)
+	addJournalEntry(unit, "http://hoverfly.io/api/two", 500)
+
+	var entry v2.JournalEntryView
+	Eventually(entries).Should(Receive(&entry))
+	Expect(*entry.Request.Path).To(Equal("/api/two"))
+
+	Consistently(entries).ShouldNot(Receive())
+}
+
+func Test_Journal_Subscribe_UnsubscribeClosesTheChannel(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+
+	entries, unsubscribe, err := unit.Subscribe(v2.JournalEntryFilterView{})
+	Expect(err).To(BeNil())
+
+	unsubscribe()
+	unsubscribe()
+
+	Eventually(entries).Should(BeClosed())
+
+	addJournalEntry(unit, "http://hoverfly.io", 200)
+}
+
+func Test_Journal_Subscribe_DropsEntriesWhenSubscriberFallsBehind(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+
+	entries, unsubscribe, err := unit.Subscribe(v2.JournalEntryFilterView{})
+	Expect(err).To(BeNil())
+	defer unsubscribe()
+
+	for i := 0; i < journal.SubscriberBufferSize+10; i++ {
+		addJournalEntry(unit, "http://hoverfly.io", 200)
+	}
+
+	Expect(entries).To(HaveLen(journal.SubscriberBufferSize))
+}
+
+func Test_Journal_Subscribe_WhenDisabledReturnsError(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	unit.EntryLimit = 0
+
+	_, _, err := unit.Subscribe(v2.JournalEntryFilterView{})
+	Expect(err).To(MatchError("Journal disabled"))
+}
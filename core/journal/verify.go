@@ -0,0 +1,226 @@
+package journal
+
+import (
+	"fmt"
+	sorting "sort"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// ClosestEntriesLimit is how many of the closest non-matching entries a failed verification returns
+const ClosestEntriesLimit = 3
+
+// Verify checks how many journal entries match a filter, or the order of the entries that
+// match a list of filters. When it fails, the entries that came closest to matching are
+// returned, or the unexpected matching entries when there were too many.
+func (this Journal) Verify(journalVerifyView v2.JournalVerifyView) (v2.JournalVerificationView, error) {
+	verificationView := v2.JournalVerificationView{}
+	if this.EntryLimit == 0 {
+		return verificationView, fmt.Errorf("Journal disabled")
+	}
+
+	if len(journalVerifyView.InOrder) > 0 {
+		return this.verifyInOrder(journalVerifyView.InOrder)
+	}
+
+	return this.verifyCount(journalVerifyView)
+}
+
+// rangeEntryViews reads the entries one at a time, so that they are not all kept in memory
+func (this Journal) rangeEntryViews(offset int, visit func(v2.JournalEntryView) bool) error {
+	return this.storage.Range(offset, false, func(entry JournalEntry) bool {
+		return visit(convertJournalEntries([]JournalEntry{entry})[0])
+	})
+}
+
+func (this Journal) verifyCount(journalVerifyView v2.JournalVerifyView) (v2.JournalVerificationView, error) {
+	filter := newEntryFilter(journalVerifyView.JournalEntryFilterView)
+	atLeast, atMost := expectedCount(journalVerifyView)
+
+	matched := []v2.JournalEntryView{}
+	closest := newClosestEntries(filter)
+	err := this.rangeEntryViews(0, func(entry v2.JournalEntryView) bool {
+		if closest.add(entry) {
+			matched = append(matched, entry)
+		}
+		return true
+	})
+	if err != nil {
+		return v2.JournalVerificationView{}, err
+	}
+
+	count := len(matched)
+	verificationView := v2.JournalVerificationView{
+		Passed:  count >= atLeast && (atMost < 0 || count <= atMost),
+		Message: fmt.Sprintf("Found %d matching %s, expected %s", count, pluralRequests(count), describeCount(atLeast, atMost)),
+		Count:   count,
+	}
+
+	if !verificationView.Passed {
+		if count < atLeast {
+			verificationView.Closest = closest.views()
+		} else {
+			verificationView.Matched = matched
+		}
+	}
+
+	return verificationView, nil
+}
+
+// expectedCount returns the least and most matching entries expected, where
+// the most is negative when there is no limit
+func expectedCount(journalVerifyView v2.JournalVerifyView) (int, int) {
+	switch {
+	case journalVerifyView.Never:
+		return 0, 0
+	case journalVerifyView.Times != nil:
+		return *journalVerifyView.Times, *journalVerifyView.Times
+	case journalVerifyView.AtLeast == nil && journalVerifyView.AtMost == nil:
+		return 1, -1
+	}
+
+	atLeast, atMost := 0, -1
+	if journalVerifyView.AtLeast != nil {
+		atLeast = *journalVerifyView.AtLeast
+	}
+	if journalVerifyView.AtMost != nil {
+		atMost = *journalVerifyView.AtMost
+	}
+	return atLeast, atMost
+}
+
+func describeCount(atLeast, atMost int) string {
+	switch {
+	case atLeast == atMost:
+		return fmt.Sprintf("exactly %d", atLeast)
+	case atMost < 0:
+		return fmt.Sprintf("at least %d", atLeast)
+	case atLeast == 0:
+		return fmt.Sprintf("at most %d", atMost)
+	default:
+		return fmt.Sprintf("between %d and %d", atLeast, atMost)
+	}
+}
+
+func pluralRequests(count int) string {
+	if count == 1 {
+		return "request"
+	}
+	return "requests"
+}
+
+// verifyInOrder looks for an entry matching each filter after the entry that matched the
+// filter before it, so other requests can come in between. The entries are only read again
+// to explain a failure.
+func (this Journal) verifyInOrder(filterViews []v2.JournalEntryFilterView) (v2.JournalVerificationView, error) {
+	filters := []entryFilter{}
+	for _, filterView := range filterViews {
+		filters = append(filters, newEntryFilter(filterView))
+	}
+
+	found, next, index := 0, 0, 0
+	err := this.rangeEntryViews(0, func(entry v2.JournalEntryView) bool {
+		index++
+		if filters[found].matches(entry) {
+			found++
+			next = index
+		}
+		return found < len(filters)
+	})
+	if err != nil {
+		return v2.JournalVerificationView{}, err
+	}
+
+	if found == len(filters) {
+		return v2.JournalVerificationView{
+			Passed:  true,
+			Message: fmt.Sprintf("Found %d %s in order", len(filterViews), pluralRequests(len(filterViews))),
+			Count:   len(filterViews),
+		}, nil
+	}
+
+	filter := filters[found]
+	message := fmt.Sprintf("Expected request %d of %d, but it was not found", found+1, len(filterViews))
+	if found > 0 {
+		message = fmt.Sprintf("Expected request %d of %d after request %d, but it was not found", found+1, len(filterViews), found)
+		index = 0
+		err = this.rangeEntryViews(0, func(entry v2.JournalEntryView) bool {
+			index++
+			if filter.matches(entry) {
+				message = fmt.Sprintf("Expected request %d of %d after request %d, but it was only found before it", found+1, len(filterViews), found)
+				return false
+			}
+			return index < next
+		})
+		if err != nil {
+			return v2.JournalVerificationView{}, err
+		}
+	}
+
+	closest := newClosestEntries(filter)
+	err = this.rangeEntryViews(next, func(entry v2.JournalEntryView) bool {
+		closest.add(entry)
+		return true
+	})
+
+	return v2.JournalVerificationView{
+		Passed:  false,
+		Message: message,
+		Count:   found,
+		Closest: closest.views(),
+	}, err
+}
+
+type scoredMiss struct {
+	miss  v2.JournalVerificationMissView
+	score int
+}
+
+// closestEntries keeps the entries that did not match the filter with the highest
+// matching scores, keeping entries with the same score in the order they were received
+type closestEntries struct {
+	filter entryFilter
+	misses []scoredMiss
+}
+
+func newClosestEntries(filter entryFilter) *closestEntries {
+	return &closestEntries{filter: filter}
+}
+
+// add returns whether the entry matches the filter, keeping it when it is one of the closest that do not
+func (this *closestEntries) add(entry v2.JournalEntryView) bool {
+	match := this.filter.match(entry)
+	if len(match.missedFields) == 0 {
+		return true
+	}
+
+	i := sorting.Search(len(this.misses), func(i int) bool {
+		return this.misses[i].score < match.score
+	})
+	if i >= ClosestEntriesLimit {
+		return false
+	}
+
+	this.misses = append(this.misses, scoredMiss{})
+	copy(this.misses[i+1:], this.misses[i:])
+	this.misses[i] = scoredMiss{
+		miss: v2.JournalVerificationMissView{
+			Entry:        entry,
+			MissedFields: match.missedFields,
+			MissReasons:  match.missReasons,
+		},
+		score: match.score,
+	}
+	if len(this.misses) > ClosestEntriesLimit {
+		this.misses = this.misses[:ClosestEntriesLimit]
+	}
+	return false
+}
+
+func (this *closestEntries) views() []v2.JournalVerificationMissView {
+	closest := []v2.JournalVerificationMissView{}
+	for _, miss := range this.misses {
+		closest = append(closest, miss.miss)
+	}
+	return closest
+}
@@ -0,0 +1,160 @@
+package journal_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func pathFilter(path string) v2.JournalEntryFilterView {
+	return v2.JournalEntryFilterView{
+		Request: &v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, path)},
+		},
+	}
+}
+
+func newVerifyJournal() *journal.Journal {
+	unit := journal.NewJournal()
+	addJournalEntry(unit, "http://hoverfly.io/login", 200)
+	addJournalEntry(unit, "http://hoverfly.io/basket", 200)
+	addJournalEntry(unit, "http://hoverfly.io/basket", 500)
+	addJournalEntry(unit, "http://specto.io/checkout", 200)
+	return unit
+}
+
+func Test_Journal_Verify_ExpectsAtLeastOneMatchByDefault(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerifyJournal()
+
+	result, err := unit.Verify(v2.JournalVerifyView{JournalEntryFilterView: pathFilter("/basket")})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeTrue())
+	Expect(result.Count).To(Equal(2))
+	Expect(result.Message).To(Equal("Found 2 matching requests, expected at least 1"))
+	Expect(result.Closest).To(BeEmpty())
+}
+
+func Test_Journal_Verify_ChecksTimes(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerifyJournal()
+
+	verifyView := v2.JournalVerifyView{JournalEntryFilterView: pathFilter("/basket"), Times: intPointer(2)}
+	verifyView.Status = 500
+
+	result, err := unit.Verify(verifyView)
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Count).To(Equal(1))
+	Expect(result.Message).To(Equal("Found 1 matching request, expected exactly 2"))
+}
+
+func Test_Journal_Verify_ChecksAtLeastAndAtMost(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerifyJournal()
+
+	result, _ := unit.Verify(v2.JournalVerifyView{JournalEntryFilterView: pathFilter("/basket"), AtLeast: intPointer(1), AtMost: intPointer(2)})
+	Expect(result.Passed).To(BeTrue())
+	Expect(result.Message).To(Equal("Found 2 matching requests, expected between 1 and 2"))
+
+	result, _ = unit.Verify(v2.JournalVerifyView{JournalEntryFilterView: pathFilter("/basket"), AtMost: intPointer(1)})
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Message).To(Equal("Found 2 matching requests, expected at most 1"))
+	Expect(result.Matched).To(HaveLen(2))
+	Expect(result.Closest).To(BeEmpty())
+}
+
+func Test_Journal_Verify_ChecksNever(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerifyJournal()
+
+	result, _ := unit.Verify(v2.JournalVerifyView{JournalEntryFilterView: pathFilter("/logout"), Never: true})
+	Expect(result.Passed).To(BeTrue())
+	Expect(result.Message).To(Equal("Found 0 matching requests, expected exactly 0"))
+
+	result, _ = unit.Verify(v2.JournalVerifyView{JournalEntryFilterView: pathFilter("/login"), Never: true})
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Matched).To(HaveLen(1))
+}
+
+func Test_Journal_Verify_ReturnsClosestEntriesWhenThereAreTooFewMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerifyJournal()
+
+	result, err := unit.Verify(v2.JournalVerifyView{
+		JournalEntryFilterView: v2.JournalEntryFilterView{
+			Request: &v2.RequestMatcherViewV5{
+				Destination: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "specto.io")},
+				Path:        []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/basket")},
+			},
+		},
+	})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Message).To(Equal("Found 0 matching requests, expected at least 1"))
+
+	Expect(result.Closest).To(HaveLen(3))
+	Expect(*result.Closest[0].Entry.Request.Path).To(Equal("/basket"))
+	Expect(result.Closest[0].MissedFields).To(Equal([]string{"destination"}))
+	Expect(*result.Closest[1].Entry.Request.Path).To(Equal("/basket"))
+	Expect(*result.Closest[2].Entry.Request.Path).To(Equal("/checkout"))
+	Expect(result.Closest[2].MissedFields).To(Equal([]string{"path"}))
+}
+
+func Test_Journal_Verify_ChecksOrder(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerifyJournal()
+
+	result, err := unit.Verify(v2.JournalVerifyView{
+		InOrder: []v2.JournalEntryFilterView{pathFilter("/login"), pathFilter("/checkout")},
+	})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeTrue())
+	Expect(result.Count).To(Equal(2))
+	Expect(result.Message).To(Equal("Found 2 requests in order"))
+}
+
+func Test_Journal_Verify_ReportsRequestsOutOfOrder(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerifyJournal()
+
+	result, err := unit.Verify(v2.JournalVerifyView{
+		InOrder: []v2.JournalEntryFilterView{pathFilter("/basket"), pathFilter("/checkout"), pathFilter("/login")},
+	})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Count).To(Equal(2))
+	Expect(result.Message).To(Equal("Expected request 3 of 3 after request 2, but it was only found before it"))
+	Expect(result.Closest).To(BeEmpty())
+
+	result, _ = unit.Verify(v2.JournalVerifyView{
+		InOrder: []v2.JournalEntryFilterView{pathFilter("/logout")},
+	})
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Message).To(Equal("Expected request 1 of 1, but it was not found"))
+	Expect(result.Closest).To(HaveLen(3))
+}
+
+func Test_Journal_Verify_WhenDisabledReturnsError(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	unit.EntryLimit = 0
+
+	_, err := unit.Verify(v2.JournalVerifyView{JournalEntryFilterView: pathFilter("/")})
+	Expect(err).To(MatchError("Journal disabled"))
+}
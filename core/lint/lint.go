@@ -0,0 +1,353 @@
+package lint
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/aymerick/raymond"
+)
+
+// Pairs finds the problems in the pairs of a simulation that would not stop it being imported:
+// pairs that are never matched as another pair always matches the same requests with a higher
+// score, matcher values that can never match, templates that do not parse, bodies that are
+// replaced by chunks, and state that is required but is never set. Destinations are ignored when Hoverfly is a webserver, as they are
+// when matching.
+func Pairs(pairs []models.RequestMatcherResponsePair, webserver bool) v2.SimulationLintView {
+	problems := []v2.SimulationLintProblemView{}
+
+	transitionedState := map[string]bool{}
+	for _, pair := range pairs {
+		for _, response := range getResponses(pair) {
+			for key := range response.details.TransitionsState {
+				transitionedState[key] = true
+			}
+		}
+	}
+
+	for i, pair := range pairs {
+		if shadowingPair := findShadowingPair(pairs, i, webserver); shadowingPair >= 0 {
+			problems = append(problems, v2.SimulationLintProblemView{
+				Pair:    i,
+				Message: fmt.Sprintf("Pair is never matched, as pair %d matches the same requests with a score that is at least as high", shadowingPair),
+			})
+		}
+
+		for _, field := range getFields(pair.RequestMatcher) {
+			for _, matcher := range field.matchers {
+				if err := validateMatcher(matcher); err != nil {
+					problems = append(problems, v2.SimulationLintProblemView{
+						Pair:    i,
+						Field:   field.name,
+						Message: err.Error(),
+					})
+				}
+			}
+		}
+
+		for _, key := range sortedKeys(pair.RequestMatcher.RequiresState) {
+			// Sequence state is set by Hoverfly as it moves through the sequence
+			if !transitionedState[key] && !strings.HasPrefix(key, "sequence:") {
+				problems = append(problems, v2.SimulationLintProblemView{
+					Pair:    i,
+					Field:   "requiresState." + key,
+					Message: fmt.Sprintf("State %s is required but no response sets it", key),
+				})
+			}
+		}
+
+		for _, response := range getResponses(pair) {
+			if len(response.details.Chunks) > 0 && response.details.Body != "" {
+				problems = append(problems, v2.SimulationLintProblemView{
+					Pair:    i,
+					Field:   response.name + ".body",
+					Message: "Body is never sent, as the response has chunks",
+				})
+			}
+
+			if !response.details.Templated {
+				continue
+			}
+			if _, err := raymond.Parse(response.details.Body); err != nil {
+				problems = append(problems, v2.SimulationLintProblemView{
+					Pair:    i,
+					Field:   response.name + ".body",
+					Message: "Template does not parse: " + err.Error(),
+				})
+			}
+			for j, chunk := range response.details.Chunks {
+				if _, err := raymond.Parse(chunk.Body); err != nil {
+					problems = append(problems, v2.SimulationLintProblemView{
+						Pair:    i,
+						Field:   fmt.Sprintf("%s.chunks[%d].body", response.name, j),
+						Message: "Template does not parse: " + err.Error(),
+					})
+				}
+			}
+		}
+	}
+
+	return v2.SimulationLintView{Problems: problems}
+}
+
+// findShadowingPair returns the index of a pair which is always matched instead of the pair at the
+// given index, or -1. A pair is matched instead when it matches every request the other pair matches,
+// and either has a higher score or the same score and comes later, as the last of the strongest wins.
+func findShadowingPair(pairs []models.RequestMatcherResponsePair, index int, webserver bool) int {
+	pair := pairs[index].RequestMatcher
+	score := getMatchedScore(pair, webserver)
+
+	for i, other := range pairs {
+		if i == index || !matchesEveryRequestOf(other.RequestMatcher, pair, webserver) {
+			continue
+		}
+
+		otherScore := getMatchedScore(other.RequestMatcher, webserver)
+		if otherScore > score || (otherScore == score && i > index) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// matchesEveryRequestOf returns whether the request matcher matches every request the other request
+// matcher matches. It only ever returns true when it is sure, so it misses some that do.
+func matchesEveryRequestOf(requestMatcher, other models.RequestMatcher, webserver bool) bool {
+	if !webserver && !fieldMatchesEveryValueOf(requestMatcher.Destination, other.Destination) {
+		return false
+	}
+
+	if !fieldMatchesEveryValueOf(requestMatcher.Body, other.Body) ||
+		!fieldMatchesEveryValueOf(requestMatcher.Path, other.Path) ||
+		!fieldMatchesEveryValueOf(requestMatcher.DeprecatedQuery, other.DeprecatedQuery) ||
+		!fieldMatchesEveryValueOf(requestMatcher.Method, other.Method) {
+		return false
+	}
+
+	if !mapMatchesEveryValueOf(requestMatcher.Headers, other.Headers) {
+		return false
+	}
+
+	if requestMatcher.Query != nil {
+		if other.Query == nil || (len(*requestMatcher.Query) == 0) != (len(*other.Query) == 0) {
+			return false
+		}
+		if !mapMatchesEveryValueOf(*requestMatcher.Query, *other.Query) {
+			return false
+		}
+	}
+
+	for key, value := range requestMatcher.RequiresState {
+		if otherValue, ok := other.RequiresState[key]; !ok || otherValue != value {
+			return false
+		}
+	}
+
+	if requestMatcher.Grpc != nil {
+		if other.Grpc == nil ||
+			!fieldMatchesEveryValueOf(requestMatcher.Grpc.Service, other.Grpc.Service) ||
+			!fieldMatchesEveryValueOf(requestMatcher.Grpc.Method, other.Grpc.Method) ||
+			!fieldMatchesEveryValueOf(requestMatcher.Grpc.Message, other.Grpc.Message) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mapMatchesEveryValueOf does the same as fieldMatchesEveryValueOf for headers and queries,
+// where every key of the matchers must also be in the other matchers
+func mapMatchesEveryValueOf(fields, other map[string][]models.RequestFieldMatchers) bool {
+	for key, keyFields := range fields {
+		found := false
+		for otherKey, otherKeyFields := range other {
+			if strings.EqualFold(key, otherKey) && fieldMatchesEveryValueOf(keyFields, otherKeyFields) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fieldMatchesEveryValueOf returns whether every value the other matchers match is also matched by
+// the matchers. This is the case when each matcher is one of the other matchers, or the other matchers
+// only match one value, which the matcher matches.
+func fieldMatchesEveryValueOf(fields, other []models.RequestFieldMatchers) bool {
+	exactValue, hasExactValue := getExactValue(other)
+
+	for _, field := range fields {
+		found := false
+		for _, otherField := range other {
+			if reflect.DeepEqual(field, otherField) {
+				found = true
+				break
+			}
+		}
+
+		if !found && hasExactValue && canEvaluate(field) {
+			found = matching.FieldMatcher([]models.RequestFieldMatchers{field}, exactValue).Matched
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getExactValue returns the only value the matchers match, if they include a plain exact matcher
+func getExactValue(fields []models.RequestFieldMatchers) (string, bool) {
+	for _, field := range fields {
+		value, ok := field.Value.(string)
+		if ok && strings.ToLower(field.Matcher) == matchers.Exact && !field.HasConfig() {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// canEvaluate returns whether the matcher can be run against a value while linting, which is
+// limited to matchers that do not log when the value is not in the format they expect
+func canEvaluate(field models.RequestFieldMatchers) bool {
+	switch strings.ToLower(field.Matcher) {
+	case "", matchers.Exact, matchers.Glob, matchers.Regex:
+		return field.DoMatch == nil
+	}
+
+	return false
+}
+
+// getMatchedScore returns the score of the request matcher for any request it matches
+func getMatchedScore(requestMatcher models.RequestMatcher, webserver bool) int {
+	score := matching.MatchedScore(requestMatcher.Body) +
+		matching.MatchedScore(requestMatcher.Path) +
+		matching.MatchedScore(requestMatcher.DeprecatedQuery) +
+		matching.MatchedScore(requestMatcher.Method) +
+		len(requestMatcher.RequiresState)
+
+	if !webserver {
+		score += matching.MatchedScore(requestMatcher.Destination)
+	}
+
+	for _, fields := range requestMatcher.Headers {
+		score += matching.MatchedScore(fields)
+	}
+
+	if requestMatcher.Query == nil || len(*requestMatcher.Query) == 0 {
+		score++
+	} else {
+		for _, fields := range *requestMatcher.Query {
+			score += matching.MatchedScore(fields)
+		}
+	}
+
+	if requestMatcher.Grpc != nil {
+		score += matching.MatchedScore(requestMatcher.Grpc.Service) +
+			matching.MatchedScore(requestMatcher.Grpc.Method) +
+			matching.MatchedScore(requestMatcher.Grpc.Message)
+	}
+
+	return score
+}
+
+// validateMatcher returns an error when the matcher, or one chained to it, can never match
+func validateMatcher(field models.RequestFieldMatchers) error {
+	matcherType := strings.ToLower(field.Matcher)
+	if _, found := matchers.Matchers[matcherType]; !found {
+		return fmt.Errorf("%s is not a matcher", field.Matcher)
+	}
+
+	if validator, ok := matchers.MatchValueValidators[matcherType]; ok {
+		if err := validator(field.Value); err != nil {
+			return fmt.Errorf("Invalid %s %v: %s", matcherType, field.Value, err.Error())
+		}
+	}
+
+	if field.DoMatch != nil {
+		return validateMatcher(*field.DoMatch)
+	}
+
+	return nil
+}
+
+type namedFieldMatchers struct {
+	name     string
+	matchers []models.RequestFieldMatchers
+}
+
+func getFields(requestMatcher models.RequestMatcher) []namedFieldMatchers {
+	fields := []namedFieldMatchers{
+		{"destination", requestMatcher.Destination},
+		{"path", requestMatcher.Path},
+		{"method", requestMatcher.Method},
+		{"scheme", requestMatcher.Scheme},
+		{"query", requestMatcher.DeprecatedQuery},
+		{"body", requestMatcher.Body},
+	}
+
+	for _, key := range sortedFieldKeys(requestMatcher.Headers) {
+		fields = append(fields, namedFieldMatchers{"headers." + key, requestMatcher.Headers[key]})
+	}
+
+	if requestMatcher.Query != nil {
+		for _, key := range sortedFieldKeys(*requestMatcher.Query) {
+			fields = append(fields, namedFieldMatchers{"queries." + key, (*requestMatcher.Query)[key]})
+		}
+	}
+
+	if requestMatcher.Grpc != nil {
+		fields = append(fields,
+			namedFieldMatchers{"grpc.service", requestMatcher.Grpc.Service},
+			namedFieldMatchers{"grpc.method", requestMatcher.Grpc.Method},
+			namedFieldMatchers{"grpc.message", requestMatcher.Grpc.Message},
+		)
+	}
+
+	return fields
+}
+
+type namedResponse struct {
+	name    string
+	details models.ResponseDetails
+}
+
+func getResponses(pair models.RequestMatcherResponsePair) []namedResponse {
+	responses := []namedResponse{{"response", pair.Response}}
+	if pair.Responses != nil {
+		for i, response := range pair.Responses.Responses {
+			responses = append(responses, namedResponse{fmt.Sprintf("responses[%d]", i), response})
+		}
+	}
+	return responses
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFieldKeys(fields map[string][]models.RequestFieldMatchers) []string {
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
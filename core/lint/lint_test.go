@@ -0,0 +1,209 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/lint"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func newPair(path []models.RequestFieldMatchers) models.RequestMatcherResponsePair {
+	return models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: path,
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+		},
+	}
+}
+
+func Test_Pairs_FindsNoProblemsWithPairsThatCanAllMatch(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{
+		newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}}),
+		newPair([]models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "/*"}}),
+	}, false)
+
+	Expect(unit.Problems).To(BeEmpty())
+}
+
+func Test_Pairs_FindsPairsWithTheSameMatchersAsALaterPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{
+		newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}}),
+		newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}}),
+	}, false)
+
+	Expect(unit.Problems).To(HaveLen(1))
+	Expect(unit.Problems[0].Pair).To(Equal(0))
+	Expect(unit.Problems[0].Message).To(ContainSubstring("pair 1"))
+}
+
+func Test_Pairs_FindsPairsThatAnotherPairMatchesWithAHigherScore(t *testing.T) {
+	RegisterTestingT(t)
+
+	weaker := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+
+	stronger := newPair([]models.RequestFieldMatchers{
+		{Matcher: matchers.Glob, Value: "/o*"},
+		{Matcher: matchers.Regex, Value: "^/one$"},
+		{Matcher: matchers.Glob, Value: "*e"},
+	})
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{stronger, weaker}, false)
+
+	Expect(unit.Problems).To(ConsistOf(v2.SimulationLintProblemView{
+		Pair:    1,
+		Message: "Pair is never matched, as pair 0 matches the same requests with a score that is at least as high",
+	}))
+}
+
+func Test_Pairs_DoesNotFindPairsThatMatchOnMoreFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	stricter := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+	stricter.RequestMatcher.Method = []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "GET"}}
+	stricter.RequestMatcher.Headers = map[string][]models.RequestFieldMatchers{
+		"Accept": {{Matcher: matchers.Exact, Value: "application/json"}},
+	}
+
+	looser := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+	looser.RequestMatcher.Headers = map[string][]models.RequestFieldMatchers{
+		"accept": {{Matcher: matchers.Glob, Value: "application/*"}},
+	}
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{stricter, looser}, false)
+
+	Expect(unit.Problems).To(BeEmpty())
+}
+
+func Test_Pairs_IgnoresDestinationsWhenHoverflyIsAWebserver(t *testing.T) {
+	RegisterTestingT(t)
+
+	first := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+	first.RequestMatcher.Destination = []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "one.com"}}
+
+	second := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+	second.RequestMatcher.Destination = []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "two.com"}}
+
+	pairs := []models.RequestMatcherResponsePair{first, second}
+
+	Expect(lint.Pairs(pairs, false).Problems).To(BeEmpty())
+	Expect(lint.Pairs(pairs, true).Problems).To(HaveLen(1))
+}
+
+func Test_Pairs_FindsInvalidMatcherValues(t *testing.T) {
+	RegisterTestingT(t)
+
+	pair := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Regex, Value: "/one["}})
+	pair.RequestMatcher.Body = []models.RequestFieldMatchers{
+		{
+			Matcher: matchers.Xpath,
+			Value:   "/list",
+			DoMatch: &models.RequestFieldMatchers{
+				Matcher: matchers.JsonPath,
+				Value:   "{$.id",
+			},
+		},
+		{
+			Matcher: "unknown",
+			Value:   "test",
+		},
+	}
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{pair}, false)
+
+	Expect(unit.Problems).To(HaveLen(3))
+	Expect(unit.Problems[0].Field).To(Equal("path"))
+	Expect(unit.Problems[0].Message).To(ContainSubstring("Invalid regex /one["))
+	Expect(unit.Problems[1].Field).To(Equal("body"))
+	Expect(unit.Problems[1].Message).To(ContainSubstring("Invalid jsonpath {$.id"))
+	Expect(unit.Problems[2].Field).To(Equal("body"))
+	Expect(unit.Problems[2].Message).To(Equal("unknown is not a matcher"))
+}
+
+func Test_Pairs_ChecksGrpcMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	grpcPair := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/shop.Items/GetItem"}})
+	grpcPair.RequestMatcher.Grpc = &models.GrpcRequestMatcher{
+		Message: []models.RequestFieldMatchers{{Matcher: matchers.Regex, Value: "{["}},
+	}
+
+	pathPair := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/shop.Items/GetItem"}})
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{grpcPair, pathPair}, false)
+
+	Expect(unit.Problems).To(HaveLen(1))
+	Expect(unit.Problems[0].Field).To(Equal("grpc.message"))
+	Expect(unit.Problems[0].Message).To(ContainSubstring("Invalid regex {["))
+}
+
+func Test_Pairs_FindsTemplatesThatDoNotParse(t *testing.T) {
+	RegisterTestingT(t)
+
+	pair := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+	pair.Response.Body = "{{ Request.Path.[0] }"
+	pair.Response.Templated = true
+	pair.Responses = &models.ResponseSequence{
+		Responses: []models.ResponseDetails{
+			{Body: "{{ Request.Path.[0] }}", Templated: true},
+			{Body: "{{#if}}", Templated: true},
+			{Body: "{{ not templated", Templated: false},
+		},
+	}
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{pair}, false)
+
+	Expect(unit.Problems).To(HaveLen(2))
+	Expect(unit.Problems[0].Field).To(Equal("response.body"))
+	Expect(unit.Problems[0].Message).To(HavePrefix("Template does not parse: "))
+	Expect(unit.Problems[1].Field).To(Equal("responses[1].body"))
+}
+
+func Test_Pairs_FindsChunksThatDoNotParseOrReplaceABody(t *testing.T) {
+	RegisterTestingT(t)
+
+	pair := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+	pair.Response.Body = "body"
+	pair.Response.Templated = true
+	pair.Response.Chunks = []models.ResponseChunk{
+		{Body: "data: {{ Request.Path.[0] }}\n\n"},
+		{Body: "data: {{#if}}\n\n"},
+	}
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{pair}, false)
+
+	Expect(unit.Problems).To(HaveLen(2))
+	Expect(unit.Problems[0]).To(Equal(v2.SimulationLintProblemView{
+		Pair:    0,
+		Field:   "response.body",
+		Message: "Body is never sent, as the response has chunks",
+	}))
+	Expect(unit.Problems[1].Field).To(Equal("response.chunks[1].body"))
+	Expect(unit.Problems[1].Message).To(HavePrefix("Template does not parse: "))
+}
+
+func Test_Pairs_FindsStateThatIsRequiredButNeverSet(t *testing.T) {
+	RegisterTestingT(t)
+
+	first := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/one"}})
+	first.RequestMatcher.RequiresState = map[string]string{"page": "basket", "user": "signed-in", "sequence:1": "1"}
+
+	second := newPair([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/two"}})
+	second.Response.TransitionsState = map[string]string{"page": "basket"}
+
+	unit := lint.Pairs([]models.RequestMatcherResponsePair{first, second}, false)
+
+	Expect(unit.Problems).To(ConsistOf(v2.SimulationLintProblemView{
+		Pair:    0,
+		Field:   "requiresState.user",
+		Message: "State user is required but no response sets it",
+	}))
+}
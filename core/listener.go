@@ -3,6 +3,7 @@ package hoverfly
 import (
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -11,7 +12,9 @@ import (
 // StoppableListener - wrapper for tcp listener that can stop
 type StoppableListener struct {
 	*net.TCPListener
-	stop chan int
+	stop       chan int
+	conns      map[string]net.Conn
+	connsMutex sync.Mutex
 }
 
 // NewStoppableListener returns new StoppableListener listener
@@ -25,6 +28,7 @@ func NewStoppableListener(l net.Listener) (*StoppableListener, error) {
 	sl := &StoppableListener{}
 	sl.TCPListener = tcpListener
 	sl.stop = make(chan int)
+	sl.conns = make(map[string]net.Conn)
 
 	return sl, nil
 }
@@ -49,13 +53,57 @@ func (sl *StoppableListener) Accept() (net.Conn, error) {
 			if ok && netErr.Timeout() && netErr.Temporary() {
 				continue
 			}
+			return newConn, err
 		}
 
-		return newConn, err
+		return sl.track(newConn), nil
 	}
 }
 
+// GetConn returns the open client connection with the given remote address
+func (sl *StoppableListener) GetConn(remoteAddr string) net.Conn {
+	sl.connsMutex.Lock()
+	defer sl.connsMutex.Unlock()
+
+	return sl.conns[remoteAddr]
+}
+
+func (sl *StoppableListener) track(conn net.Conn) net.Conn {
+	tracked := &trackedConn{Conn: conn, listener: sl}
+
+	sl.connsMutex.Lock()
+	sl.conns[conn.RemoteAddr().String()] = tracked
+	sl.connsMutex.Unlock()
+
+	return tracked
+}
+
+// trackedConn removes itself from the listener's open connections once closed
+type trackedConn struct {
+	net.Conn
+	listener  *StoppableListener
+	closeOnce sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.listener.connsMutex.Lock()
+		delete(c.listener.conns, c.RemoteAddr().String())
+		c.listener.connsMutex.Unlock()
+	})
+
+	return c.Conn.Close()
+}
+
 // Stop - stops listener
 func (sl *StoppableListener) Stop() {
 	close(sl.stop)
 }
+
+// CloseWrite keeps half closing the connection available to the http server
+func (c *trackedConn) CloseWrite() error {
+	if tcpConn, ok := c.Conn.(*net.TCPConn); ok {
+		return tcpConn.CloseWrite()
+	}
+	return nil
+}
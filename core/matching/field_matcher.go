@@ -1,12 +1,19 @@
 package matching
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
-	"strings"
 )
 
 func FieldMatcher(fields []models.RequestFieldMatchers, toMatch string) *FieldMatch {
+	return BodyMatcher(fields, toMatch, "")
+}
+
+// BodyMatcher matches a body like FieldMatcher, giving its Content-Type to the matchers that parse it
+func BodyMatcher(fields []models.RequestFieldMatchers, toMatch, contentType string) *FieldMatch {
 
 	fieldMatch := &FieldMatch{Matched: true}
 
@@ -15,21 +22,156 @@ func FieldMatcher(fields []models.RequestFieldMatchers, toMatch string) *FieldMa
 	}
 
 	for _, field := range fields {
-		if matchers.Matchers[strings.ToLower(field.Matcher)](field.Value, toMatch) {
-			if field.Matcher == matchers.Exact {
-				fieldMatch.Score = fieldMatch.Score + 2
-			} else {
-				fieldMatch.Score = fieldMatch.Score + 1
-			}
+		if matched, score, missReason := matchField(field, toMatch, contentType); matched {
+			fieldMatch.Score = fieldMatch.Score + score
 		} else {
 			fieldMatch.Matched = false
+			if missReason != "" {
+				fieldMatch.MissReasons = append(fieldMatch.MissReasons, missReason)
+			}
 		}
 	}
 
 	return fieldMatch
 }
 
+// matchField runs a single matcher, applying its config and any chained doMatch
+// matchers. Exact matches score double, and each matcher in a chain adds to the
+// score, as every link narrows down what is matched. Negated matches only ever
+// score one, as they say little about what the value actually is. On a miss,
+// the reason is returned if the matcher is able to describe it.
+func matchField(field models.RequestFieldMatchers, toMatch, contentType string) (bool, int, string) {
+	matcherType := strings.ToLower(field.Matcher)
+	matcherFunc, found := matchers.Matchers[matcherType]
+	if !found {
+		return false, 0, fmt.Sprintf("%s is not a matcher", field.Matcher)
+	}
+
+	value := field.Value
+	doMatch := field.DoMatch
+	if getBoolConfig(field.Config, matchers.IgnoreCaseConfig) {
+		if isExpressionMatcher(matcherType) {
+			doMatch = ignoreCaseOfChainedMatcher(doMatch)
+		} else {
+			value, toMatch = ignoreCase(matcherType, value, toMatch)
+		}
+	}
+
+	var matched bool
+	if contentTypeMatcherFunc, ok := matchers.ContentTypeMatchers[matcherType]; ok && contentType != "" {
+		matched = contentTypeMatcherFunc(value, toMatch, contentType)
+	} else {
+		matched = matcherFunc(value, toMatch)
+	}
+
+	var missReason string
+	if describer, ok := matchers.MatchFailureDescribers[matcherType]; ok && !matched {
+		missReason = describer(value, toMatch)
+	}
+
+	score := 1
+	if field.Matcher == matchers.Exact {
+		score = 2
+	}
+
+	if matched && doMatch != nil {
+		chainedToMatch := toMatch
+		if generator, ok := matchers.MatchValueGenerators[matcherType]; ok {
+			chainedToMatch = generator(value, toMatch)
+		}
+
+		var chainedScore int
+		// The chained matcher is given part of the body, which the Content-Type does not describe
+		matched, chainedScore, missReason = matchField(*doMatch, chainedToMatch, "")
+		score += chainedScore
+	}
+
+	if getBoolConfig(field.Config, matchers.NegateConfig) {
+		if matched {
+			return false, 1, fmt.Sprintf("%s matched %v, but negate was set", getMatcherName(matcherType), field.Value)
+		}
+		return true, 1, ""
+	}
+
+	return matched, score, missReason
+}
+
+func getMatcherName(matcherType string) string {
+	if matcherType == "" {
+		return matchers.Exact
+	}
+	return matcherType
+}
+
+// MatchedScore is the score FieldMatcher gives the matchers when they all match,
+// which is the same whatever the value they match
+func MatchedScore(fields []models.RequestFieldMatchers) int {
+	score := 0
+	for _, field := range fields {
+		score += matchedFieldScore(field)
+	}
+	return score
+}
+
+func matchedFieldScore(field models.RequestFieldMatchers) int {
+	if getBoolConfig(field.Config, matchers.NegateConfig) {
+		return 1
+	}
+
+	score := 1
+	if field.Matcher == matchers.Exact {
+		score = 2
+	}
+
+	if field.DoMatch != nil {
+		score += matchedFieldScore(*field.DoMatch)
+	}
+
+	return score
+}
+
+// isExpressionMatcher returns whether the matcher value is an expression that selects part of the value
+// to match by name, which changes when it is lowercased
+func isExpressionMatcher(matcherType string) bool {
+	return matcherType == matchers.JsonPath || matcherType == matchers.Xpath
+}
+
+// ignoreCaseOfChainedMatcher returns a copy of the chained matcher that ignores case, as that is the matcher
+// that compares the part selected by an expression
+func ignoreCaseOfChainedMatcher(doMatch *models.RequestFieldMatchers) *models.RequestFieldMatchers {
+	if doMatch == nil {
+		return nil
+	}
+
+	chained := *doMatch
+	chained.Config = map[string]interface{}{matchers.IgnoreCaseConfig: true}
+	for key, value := range doMatch.Config {
+		chained.Config[key] = value
+	}
+	return &chained
+}
+
+func ignoreCase(matcherType string, value interface{}, toMatch string) (interface{}, string) {
+	valueString, ok := value.(string)
+	if !ok {
+		return value, toMatch
+	}
+
+	// Lowercasing a regex could change the meaning of its character classes
+	if matcherType == matchers.Regex {
+		return "(?i)" + valueString, toMatch
+	}
+
+	return strings.ToLower(valueString), strings.ToLower(toMatch)
+}
+
+func getBoolConfig(config map[string]interface{}, key string) bool {
+	value, ok := config[key].(bool)
+	return ok && value
+}
+
 type FieldMatch struct {
-	Matched bool
-	Score   int
+	Matched     bool
+	Score       int
+	MissReasons []string
 }
@@ -155,6 +155,174 @@ var fieldMatcherTests = []fieldMatcherTest{
 		toMatch: `<document></document>`,
 		equals:  BeTrue(),
 	},
+	{
+		name: "UnknownMatcher_MatchesFalse",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: "unknown",
+				Value:   "test",
+			},
+		},
+		toMatch: "test",
+		equals:  BeFalse(),
+	},
+	{
+		name: "WithNegateConfig_MatchesFalseWhenMatcherMatches",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "test",
+				Config:  map[string]interface{}{"negate": true},
+			},
+		},
+		toMatch: "test",
+		equals:  BeFalse(),
+	},
+	{
+		name: "WithNegateConfig_MatchesTrueWhenMatcherDoesNotMatch_AndScoresOne",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "test",
+				Config:  map[string]interface{}{"negate": true},
+			},
+		},
+		toMatch:     "nottest",
+		equals:      BeTrue(),
+		scoreEquals: Equal(1),
+	},
+	{
+		name: "WithIgnoreCaseConfig_MatchesTrueWithDifferentCase",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "TEST",
+				Config:  map[string]interface{}{"ignoreCase": true},
+			},
+		},
+		toMatch:     "test",
+		equals:      BeTrue(),
+		scoreEquals: Equal(2),
+	},
+	{
+		name: "WithIgnoreCaseConfig_MatchesTrueWithRegex",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Regex,
+				Value:   `^T\S+$`,
+				Config:  map[string]interface{}{"ignoreCase": true},
+			},
+		},
+		toMatch: "test",
+		equals:  BeTrue(),
+	},
+	{
+		name: "WithIgnoreCaseConfig_KeepsTheCaseOfJsonPathExpressions",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.JsonPath,
+				Value:   "$.userId",
+				Config:  map[string]interface{}{"ignoreCase": true},
+			},
+		},
+		toMatch: `{"userId": "ABC"}`,
+		equals:  BeTrue(),
+	},
+	{
+		name: "WithIgnoreCaseConfig_IgnoresTheCaseOfTheValueSelectedByXpath",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Xpath,
+				Value:   "/user/userId",
+				Config:  map[string]interface{}{"ignoreCase": true},
+				DoMatch: &models.RequestFieldMatchers{
+					Matcher: matchers.Exact,
+					Value:   "abc",
+				},
+			},
+		},
+		toMatch:     `<user><userId>ABC</userId></user>`,
+		equals:      BeTrue(),
+		scoreEquals: Equal(3),
+	},
+	{
+		name: "WithoutIgnoreCaseConfig_MatchesFalseWithDifferentCase",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "TEST",
+			},
+		},
+		toMatch: "test",
+		equals:  BeFalse(),
+	},
+	{
+		name: "WithDoMatch_MatchesOnExtractedValue_AndScoresEachMatcher",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.JsonPath,
+				Value:   "$.order.id",
+				DoMatch: &models.RequestFieldMatchers{
+					Matcher: matchers.Regex,
+					Value:   "^ORD-[0-9]+$",
+				},
+			},
+		},
+		toMatch:     `{"order": {"id": "ORD-123", "note": "not ORD-abc"}}`,
+		equals:      BeTrue(),
+		scoreEquals: Equal(2),
+	},
+	{
+		name: "WithDoMatch_MatchesFalseWhenChainedMatcherFails",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.JsonPath,
+				Value:   "$.order.id",
+				DoMatch: &models.RequestFieldMatchers{
+					Matcher: matchers.Regex,
+					Value:   "^ORD-[0-9]+$",
+				},
+			},
+		},
+		toMatch: `{"order": {"id": "ORD-abc", "note": "ORD-123"}}`,
+		equals:  BeFalse(),
+	},
+	{
+		name: "WithDoMatch_CanChainMoreThanTwoMatchers",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.JsonPath,
+				Value:   "$.order.id",
+				DoMatch: &models.RequestFieldMatchers{
+					Matcher: matchers.Regex,
+					Value:   "[0-9]+",
+					DoMatch: &models.RequestFieldMatchers{
+						Matcher: matchers.Exact,
+						Value:   "123",
+					},
+				},
+			},
+		},
+		toMatch:     `{"order": {"id": "ORD-123"}}`,
+		equals:      BeTrue(),
+		scoreEquals: Equal(4),
+	},
+	{
+		name: "WithDoMatchAndNegate_NegatesWholeChain",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.JsonPath,
+				Value:   "$.order.id",
+				Config:  map[string]interface{}{"negate": true},
+				DoMatch: &models.RequestFieldMatchers{
+					Matcher: matchers.Exact,
+					Value:   "ORD-123",
+				},
+			},
+		},
+		toMatch: `{"order": {"id": "ORD-456"}}`,
+		equals:  BeTrue(),
+	},
 }
 
 func Test_FieldMatcher(t *testing.T) {
@@ -171,3 +339,71 @@ func Test_FieldMatcher(t *testing.T) {
 	}
 
 }
+
+func Test_FieldMatcher_GivesTheReasonANegatedMatcherMissed(t *testing.T) {
+	RegisterTestingT(t)
+
+	fields := []models.RequestFieldMatchers{
+		{
+			Matcher: matchers.Exact,
+			Value:   "/admin",
+			Config:  map[string]interface{}{matchers.NegateConfig: true},
+		},
+	}
+
+	result := matching.FieldMatcher(fields, "/admin")
+	Expect(result.Matched).To(BeFalse())
+	Expect(result.MissReasons).To(ConsistOf("exact matched /admin, but negate was set"))
+
+	Expect(matching.FieldMatcher(fields, "/home").MissReasons).To(BeEmpty())
+}
+
+func Test_MatchedScore_IsTheScoreOfTheMatchersWhenTheyMatch(t *testing.T) {
+	RegisterTestingT(t)
+
+	fields := []models.RequestFieldMatchers{
+		{
+			Matcher: matchers.Exact,
+			Value:   "test",
+		},
+		{
+			Matcher: matchers.Glob,
+			Value:   "t*",
+			DoMatch: &models.RequestFieldMatchers{
+				Matcher: matchers.Exact,
+				Value:   "test",
+			},
+		},
+		{
+			Matcher: matchers.Exact,
+			Value:   "other",
+			Config:  map[string]interface{}{matchers.NegateConfig: true},
+		},
+	}
+
+	Expect(matching.MatchedScore(fields)).To(Equal(6))
+	Expect(matching.FieldMatcher(fields, "test").Score).To(Equal(6))
+}
+
+func Test_BodyMatcher_ParsesMultipartBodiesWithTheirContentType(t *testing.T) {
+	RegisterTestingT(t)
+
+	fields := []models.RequestFieldMatchers{
+		{
+			Matcher: matchers.Form,
+			Value: map[string]interface{}{
+				"username": []interface{}{
+					map[string]interface{}{"matcher": "exact", "value": "hoverfly"},
+				},
+			},
+		},
+	}
+
+	body := "--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"username\"\r\n\r\nhoverfly\r\n" +
+		"--boundary--\r\n"
+
+	Expect(matching.BodyMatcher(fields, body, "multipart/form-data; boundary=boundary").Matched).To(BeTrue())
+	Expect(matching.BodyMatcher(fields, "username=hoverfly", "application/x-www-form-urlencoded").Matched).To(BeTrue())
+	Expect(matching.FieldMatcher(fields, body).Matched).To(BeFalse())
+}
@@ -0,0 +1,35 @@
+package matching
+
+import (
+	"github.com/SpectoLabs/hoverfly/core/models"
+)
+
+// GrpcMatching matches the service, method and message of a gRPC call. A request matcher
+// without gRPC matchers matches any request, and one with them only matches gRPC calls.
+func GrpcMatching(requestMatcher models.RequestMatcher, toMatch *models.GrpcRequestDetails) *FieldMatch {
+	if requestMatcher.Grpc == nil {
+		return &FieldMatch{
+			Matched: true,
+		}
+	}
+
+	if toMatch == nil {
+		return &FieldMatch{
+			Matched:     false,
+			MissReasons: []string{"request is not a gRPC call"},
+		}
+	}
+
+	fieldMatch := &FieldMatch{Matched: true}
+	for _, match := range []*FieldMatch{
+		FieldMatcher(requestMatcher.Grpc.Service, toMatch.Service),
+		FieldMatcher(requestMatcher.Grpc.Method, toMatch.Method),
+		FieldMatcher(requestMatcher.Grpc.Message, toMatch.Message),
+	} {
+		fieldMatch.Matched = fieldMatch.Matched && match.Matched
+		fieldMatch.Score += match.Score
+		fieldMatch.MissReasons = append(fieldMatch.MissReasons, match.MissReasons...)
+	}
+
+	return fieldMatch
+}
@@ -0,0 +1,51 @@
+package matching_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+var grpcRequest = &models.GrpcRequestDetails{
+	Service: "shop.Items",
+	Method:  "GetItem",
+	Message: `{"id":"1"}`,
+}
+
+func Test_GrpcMatching_MatchesAnyRequestWithoutGrpcMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matching.GrpcMatching(models.RequestMatcher{}, nil).Matched).To(BeTrue())
+	Expect(matching.GrpcMatching(models.RequestMatcher{}, grpcRequest).Matched).To(BeTrue())
+}
+
+func Test_GrpcMatching_DoesNotMatchRequestsThatAreNotGrpcCalls(t *testing.T) {
+	RegisterTestingT(t)
+
+	match := matching.GrpcMatching(models.RequestMatcher{Grpc: &models.GrpcRequestMatcher{}}, nil)
+
+	Expect(match.Matched).To(BeFalse())
+	Expect(match.MissReasons).To(ConsistOf("request is not a gRPC call"))
+}
+
+func Test_GrpcMatching_MatchesServiceMethodAndMessage(t *testing.T) {
+	RegisterTestingT(t)
+
+	requestMatcher := models.RequestMatcher{
+		Grpc: &models.GrpcRequestMatcher{
+			Service: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "shop.Items"}},
+			Method:  []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "GetItem"}},
+			Message: []models.RequestFieldMatchers{{Matcher: matchers.JsonPartial, Value: `{"id":"1"}`}},
+		},
+	}
+
+	match := matching.GrpcMatching(requestMatcher, grpcRequest)
+	Expect(match.Matched).To(BeTrue())
+	Expect(match.Score).To(Equal(5))
+
+	requestMatcher.Grpc.Method = []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "DeleteItem"}}
+	Expect(matching.GrpcMatching(requestMatcher, grpcRequest).Matched).To(BeFalse())
+}
@@ -16,6 +16,7 @@ func HeaderMatching(requestMatcher models.RequestMatcher, toMatch map[string][]s
 
 	matched := true
 	var score int
+	var missReasons []string
 
 	requestMatcherHeadersWithMatchers := requestMatcher.Headers
 
@@ -36,6 +37,7 @@ func HeaderMatching(requestMatcher models.RequestMatcher, toMatch map[string][]s
 		fieldMatch := FieldMatcher(matcherHeaderValue, strings.Join(toMatchHeaderValues, ";"))
 		matcherHeaderValueMatched = fieldMatch.Matched
 		score += fieldMatch.Score
+		missReasons = append(missReasons, fieldMatch.MissReasons...)
 
 		if !matcherHeaderValueMatched {
 			matched = false
@@ -43,7 +45,8 @@ func HeaderMatching(requestMatcher models.RequestMatcher, toMatch map[string][]s
 	}
 
 	return &FieldMatch{
-		Matched: matched,
-		Score:   score,
+		Matched:     matched,
+		Score:       score,
+		MissReasons: missReasons,
 	}
 }
@@ -15,6 +15,42 @@ func Match(strongestMatch string, req models.RequestDetails, webserver bool, sim
 	}
 }
 
+// MatchSimulations matches the request against each simulation in turn, stopping at the
+// first that matches. If none of them match, the miss closest to matching is returned. The
+// result is only cachable if the result of every simulation that was tried is cachable.
+func MatchSimulations(strongestMatch string, req models.RequestDetails, webserver bool, simulations []*models.Simulation, state *state.State) *MatchingResult {
+	var closestMiss *MatchingResult
+	cachable := true
+
+	for _, simulation := range simulations {
+		result := Match(strongestMatch, req, webserver, simulation, state)
+		cachable = cachable && result.Cachable
+
+		if result.Error == nil {
+			result.Cachable = cachable
+			return result
+		}
+
+		if closestMiss == nil || isCloserMiss(result.Error.ClosestMiss, closestMiss.Error.ClosestMiss) {
+			closestMiss = result
+		}
+	}
+
+	if closestMiss == nil {
+		return Match(strongestMatch, req, webserver, models.NewSimulation(), state)
+	}
+
+	closestMiss.Cachable = cachable
+	return closestMiss
+}
+
+func isCloserMiss(miss, than *models.ClosestMiss) bool {
+	if miss == nil {
+		return false
+	}
+	return than == nil || len(miss.MissedFields) < len(than.MissedFields)
+}
+
 type MatchingResult struct {
 	Pair     *models.RequestMatcherResponsePair
 	Error    *models.MatchError
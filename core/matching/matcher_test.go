@@ -0,0 +1,116 @@
+package matching_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	. "github.com/onsi/gomega"
+)
+
+func newSimulationWithPair(path, method, body string) *models.Simulation {
+	simulation := models.NewSimulation()
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Glob,
+					Value:   path,
+				},
+			},
+			Method: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   method,
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+			Body:   body,
+		},
+	})
+	return simulation
+}
+
+func Test_MatchSimulations_ReturnsTheMatchFromTheFirstSimulationThatMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulations := []*models.Simulation{
+		newSimulationWithPair("/other", "GET", "other"),
+		newSimulationWithPair("/*", "GET", "first"),
+		newSimulationWithPair("/users", "GET", "second"),
+	}
+
+	result := matching.MatchSimulations("strongest", models.RequestDetails{
+		Method: "GET",
+		Path:   "/users",
+	}, false, simulations, state.NewState())
+
+	Expect(result.Error).To(BeNil())
+	Expect(result.Pair.Response.Body).To(Equal("first"))
+}
+
+func Test_MatchSimulations_ReturnsTheClosestMissOfAllTheSimulations(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulations := []*models.Simulation{
+		newSimulationWithPair("/other", "POST", "far"),
+		newSimulationWithPair("/users", "POST", "close"),
+	}
+
+	result := matching.MatchSimulations("strongest", models.RequestDetails{
+		Method: "GET",
+		Path:   "/users",
+	}, false, simulations, state.NewState())
+
+	Expect(result.Pair).To(BeNil())
+	Expect(result.Error.ClosestMiss.Response.Body).To(Equal("close"))
+	Expect(result.Error.ClosestMiss.MissedFields).To(Equal([]string{"method"}))
+}
+
+func Test_MatchSimulations_IsNotCachableIfAnEarlierSimulationWasNot(t *testing.T) {
+	RegisterTestingT(t)
+
+	notCachable := models.NewSimulation()
+	notCachable.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/users",
+				},
+			},
+			Headers: map[string][]models.RequestFieldMatchers{
+				"Authorization": {
+					{
+						Matcher: matchers.Exact,
+						Value:   "secret",
+					},
+				},
+			},
+		},
+	})
+
+	result := matching.MatchSimulations("strongest", models.RequestDetails{
+		Method: "GET",
+		Path:   "/users",
+	}, false, []*models.Simulation{notCachable, newSimulationWithPair("/users", "GET", "second")}, state.NewState())
+
+	Expect(result.Error).To(BeNil())
+	Expect(result.Pair.Response.Body).To(Equal("second"))
+	Expect(result.Cachable).To(BeFalse())
+}
+
+func Test_MatchSimulations_MissesWithoutSimulations(t *testing.T) {
+	RegisterTestingT(t)
+
+	result := matching.MatchSimulations("first", models.RequestDetails{
+		Path: "/users",
+	}, false, []*models.Simulation{}, state.NewState())
+
+	Expect(result.Pair).To(BeNil())
+	Expect(result.Error).ToNot(BeNil())
+}
@@ -0,0 +1,83 @@
+package matchers
+
+import (
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/util"
+)
+
+var Form = "form"
+
+const urlEncodedContentType = "application/x-www-form-urlencoded"
+
+// Registered here rather than in the Matchers map literal, as FormMatch
+// delegates each field to the other matchers
+func init() {
+	Matchers[Form] = FormMatch
+	ContentTypeMatchers[Form] = FormMatchWithContentType
+}
+
+// FormMatch matches a url-encoded body, as a multipart body cannot be parsed without the boundary
+// in its Content-Type
+func FormMatch(match interface{}, toMatch string) bool {
+	return FormMatchWithContentType(match, toMatch, urlEncodedContentType)
+}
+
+// FormMatchWithContentType matches a url-encoded or multipart body, parsing it as its Content-Type says
+func FormMatchWithContentType(match interface{}, toMatch, contentType string) bool {
+	fieldMatchers, ok := match.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	fields, err := util.ParseFormBody(toMatch, contentType)
+	if err != nil {
+		return false
+	}
+
+	for fieldName, fieldMatcherValues := range fieldMatchers {
+		fieldValues, found := fields[fieldName]
+		if !found {
+			return false
+		}
+
+		if !matchFormField(fieldMatcherValues, fieldValues) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchFormField matches when each of the field matchers matches one of the values of the field,
+// so that a field sent more than once can be matched value by value
+func matchFormField(fieldMatcherValues interface{}, fieldValues []string) bool {
+	fieldMatchers, ok := fieldMatcherValues.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, fieldMatcherValue := range fieldMatchers {
+		fieldMatcher, ok := fieldMatcherValue.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		matcherName, _ := fieldMatcher["matcher"].(string)
+		matcherFunc, found := Matchers[strings.ToLower(matcherName)]
+		if !found || !matchAnyValue(matcherFunc, fieldMatcher["value"], fieldValues) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchAnyValue(matcherFunc MatcherFunc, value interface{}, fieldValues []string) bool {
+	for _, fieldValue := range fieldValues {
+		if matcherFunc(value, fieldValue) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package matchers_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func formMatcherValue(fields map[string][]map[string]interface{}) map[string]interface{} {
+	value := map[string]interface{}{}
+	for field, fieldMatchers := range fields {
+		views := []interface{}{}
+		for _, fieldMatcher := range fieldMatchers {
+			views = append(views, fieldMatcher)
+		}
+		value[field] = views
+	}
+	return value
+}
+
+func Test_FormMatch_IsRegistered(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.Matchers).To(HaveKey("form"))
+}
+
+func Test_FormMatch_MatchesFalseWithIncorrectDataType(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.FormMatch("a=1", "a=1")).To(BeFalse())
+}
+
+func Test_FormMatch_MatchesTrueWithUrlEncodedBodyInAnyOrder(t *testing.T) {
+	RegisterTestingT(t)
+
+	value := formMatcherValue(map[string][]map[string]interface{}{
+		"username": {{"matcher": "exact", "value": "hoverfly"}},
+		"password": {{"matcher": "regex", "value": "^s.*t$"}},
+	})
+
+	Expect(matchers.FormMatch(value, "password=secret&username=hoverfly")).To(BeTrue())
+	Expect(matchers.FormMatch(value, "username=hoverfly&extra=1&password=secret")).To(BeTrue())
+}
+
+func Test_FormMatch_MatchesFalseWhenFieldIsMissing(t *testing.T) {
+	RegisterTestingT(t)
+
+	value := formMatcherValue(map[string][]map[string]interface{}{
+		"username": {{"matcher": "exact", "value": "hoverfly"}},
+	})
+
+	Expect(matchers.FormMatch(value, "password=secret")).To(BeFalse())
+}
+
+func Test_FormMatch_MatchesFalseWhenFieldMatcherFails(t *testing.T) {
+	RegisterTestingT(t)
+
+	value := formMatcherValue(map[string][]map[string]interface{}{
+		"username": {
+			{"matcher": "glob", "value": "hover*"},
+			{"matcher": "exact", "value": "hoverfly"},
+		},
+	})
+
+	Expect(matchers.FormMatch(value, "username=hovercraft")).To(BeFalse())
+}
+
+func Test_FormMatch_MatchesFalseWithUnknownFieldMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	value := formMatcherValue(map[string][]map[string]interface{}{
+		"username": {{"matcher": "unknown", "value": "hoverfly"}},
+	})
+
+	Expect(matchers.FormMatch(value, "username=hoverfly")).To(BeFalse())
+}
+
+func Test_FormMatch_MatchesEachValueOfAFieldSentMoreThanOnce(t *testing.T) {
+	RegisterTestingT(t)
+
+	value := formMatcherValue(map[string][]map[string]interface{}{
+		"tags": {
+			{"matcher": "exact", "value": "a"},
+			{"matcher": "glob", "value": "b*"},
+		},
+	})
+
+	Expect(matchers.FormMatch(value, "tags=a&tags=bc")).To(BeTrue())
+	Expect(matchers.FormMatch(value, "tags=bc&tags=d&tags=a")).To(BeTrue())
+	Expect(matchers.FormMatch(value, "tags=a&tags=c")).To(BeFalse())
+	Expect(matchers.FormMatch(value, "tags=a;bc")).To(BeFalse())
+}
+
+func Test_FormMatch_ParsesBodiesThatStartWithDashesAsUrlEncoded(t *testing.T) {
+	RegisterTestingT(t)
+
+	value := formMatcherValue(map[string][]map[string]interface{}{
+		"--name": {{"matcher": "exact", "value": "hoverfly"}},
+	})
+
+	Expect(matchers.FormMatch(value, "--name=hoverfly")).To(BeTrue())
+}
+
+func Test_FormMatchWithContentType_MatchesTrueWithMultipartBodyRegardlessOfBoundary(t *testing.T) {
+	RegisterTestingT(t)
+
+	value := formMatcherValue(map[string][]map[string]interface{}{
+		"username": {{"matcher": "exact", "value": "hoverfly"}},
+		"document": {{"matcher": "json", "value": `{"id": 1}`}},
+	})
+
+	firstBody := "--boundary-one\r\n" +
+		"Content-Disposition: form-data; name=\"username\"\r\n\r\nhoverfly\r\n" +
+		"--boundary-one\r\n" +
+		"Content-Disposition: form-data; name=\"document\"; filename=\"doc.json\"\r\n" +
+		"Content-Type: application/json\r\n\r\n{\"id\":1}\r\n" +
+		"--boundary-one--\r\n"
+
+	secondBody := "--another-boundary\r\n" +
+		"Content-Disposition: form-data; name=\"document\"\r\n\r\n{\"id\": 1}\r\n" +
+		"--another-boundary\r\n" +
+		"Content-Disposition: form-data; name=\"username\"\r\n\r\nhoverfly\r\n" +
+		"--another-boundary--\r\n"
+
+	Expect(matchers.FormMatchWithContentType(value, firstBody, `multipart/form-data; boundary=boundary-one`)).To(BeTrue())
+	Expect(matchers.FormMatchWithContentType(value, secondBody, `multipart/form-data; boundary="another-boundary"`)).To(BeTrue())
+	Expect(matchers.FormMatchWithContentType(value, firstBody, `multipart/form-data; boundary=another-boundary`)).To(BeFalse())
+	Expect(matchers.FormMatch(value, firstBody)).To(BeFalse())
+}
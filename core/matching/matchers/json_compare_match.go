@@ -0,0 +1,213 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+var JsonCompare = "jsoncompare"
+
+func JsonCompareMatch(match interface{}, toMatch string) bool {
+	matched, _ := jsonCompare(match, toMatch)
+	return matched
+}
+
+func JsonCompareMatchFailureDescriber(match interface{}, toMatch string) string {
+	_, reason := jsonCompare(match, toMatch)
+	return reason
+}
+
+// jsonCompare finds every value at the path and passes if any one of them
+// satisfies the comparison. When it fails, it also returns the reason why
+func jsonCompare(match interface{}, toMatch string) (bool, string) {
+	matchMap, ok := match.(map[string]interface{})
+	if !ok {
+		return false, "jsoncompare value must be an object with a path, operator and value"
+	}
+
+	path, _ := matchMap["path"].(string)
+	operator, _ := matchMap["operator"].(string)
+	expected := matchMap["value"]
+	if path == "" || operator == "" {
+		return false, "jsoncompare value must have a path and an operator"
+	}
+
+	compare, err := getJsonComparison(operator, expected)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	actualValues, err := findJsonPathValues(path, toMatch)
+	if err != nil {
+		return false, fmt.Sprintf("%s could not be evaluated: %s", path, err.Error())
+	}
+	if len(actualValues) == 0 {
+		return false, fmt.Sprintf("%s did not match anything", path)
+	}
+
+	for _, actual := range actualValues {
+		if compare(actual) {
+			return true, ""
+		}
+	}
+
+	actualJson, _ := json.Marshal(actualValues)
+	expectedJson, _ := json.Marshal(expected)
+	return false, fmt.Sprintf("%s %s %s failed, found %s", path, operator, expectedJson, actualJson)
+}
+
+func findJsonPathValues(path, toMatch string) ([]interface{}, error) {
+	jsonPath := jsonpath.New("")
+	if err := jsonPath.Parse(prepareJsonPathQuery(path)); err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(toMatch), &data); err != nil {
+		return nil, err
+	}
+
+	results, err := jsonPath.FindResults(data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{}
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+
+	return values, nil
+}
+
+func getJsonComparison(operator string, expected interface{}) (func(actual interface{}) bool, error) {
+	lowerOperator := strings.ToLower(operator)
+
+	if strings.HasPrefix(lowerOperator, "length") {
+		compareLength, err := getNumericComparison(strings.TrimSpace(strings.TrimPrefix(lowerOperator, "length")), expected)
+		if err != nil {
+			return nil, fmt.Errorf("%s operator %s", operator, err.Error())
+		}
+
+		return func(actual interface{}) bool {
+			length := reflect.ValueOf(actual)
+			switch length.Kind() {
+			case reflect.Slice, reflect.Map, reflect.String:
+				return compareLength(float64(length.Len()))
+			}
+			return false
+		}, nil
+	}
+
+	switch lowerOperator {
+	case "contains":
+		return func(actual interface{}) bool {
+			actualArray, ok := actual.([]interface{})
+			return ok && jsonArrayContains(actualArray, expected)
+		}, nil
+	case "anyof":
+		expectedArray, ok := expected.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s operator needs an array value", operator)
+		}
+
+		return func(actual interface{}) bool {
+			return jsonArrayContains(expectedArray, actual)
+		}, nil
+	case "==":
+		return func(actual interface{}) bool {
+			return jsonValuesEqual(expected, actual)
+		}, nil
+	case "!=":
+		return func(actual interface{}) bool {
+			return !jsonValuesEqual(expected, actual)
+		}, nil
+	}
+
+	compareNumber, err := getNumericComparison(lowerOperator, expected)
+	if err != nil {
+		return nil, fmt.Errorf("%s operator %s", operator, err.Error())
+	}
+
+	return func(actual interface{}) bool {
+		actualNumber, ok := toFloat(actual)
+		return ok && compareNumber(actualNumber)
+	}, nil
+}
+
+func getNumericComparison(operator string, expected interface{}) (func(actual float64) bool, error) {
+	if operator == "between" {
+		bounds, ok := expected.([]interface{})
+		if !ok || len(bounds) != 2 {
+			return nil, fmt.Errorf("needs an array value of [min, max]")
+		}
+
+		min, minOk := toFloat(bounds[0])
+		max, maxOk := toFloat(bounds[1])
+		if !minOk || !maxOk {
+			return nil, fmt.Errorf("needs numeric bounds")
+		}
+
+		return func(actual float64) bool {
+			return actual >= min && actual <= max
+		}, nil
+	}
+
+	expectedNumber, ok := toFloat(expected)
+	if !ok {
+		return nil, fmt.Errorf("needs a numeric value")
+	}
+
+	switch operator {
+	case ">":
+		return func(actual float64) bool { return actual > expectedNumber }, nil
+	case ">=":
+		return func(actual float64) bool { return actual >= expectedNumber }, nil
+	case "<":
+		return func(actual float64) bool { return actual < expectedNumber }, nil
+	case "<=":
+		return func(actual float64) bool { return actual <= expectedNumber }, nil
+	case "==":
+		return func(actual float64) bool { return actual == expectedNumber }, nil
+	case "!=":
+		return func(actual float64) bool { return actual != expectedNumber }, nil
+	}
+
+	return nil, fmt.Errorf("is not supported")
+}
+
+func jsonArrayContains(array []interface{}, value interface{}) bool {
+	for _, item := range array {
+		if jsonValuesEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonValuesEqual(expected, actual interface{}) bool {
+	expectedNumber, expectedOk := toFloat(expected)
+	actualNumber, actualOk := toFloat(actual)
+	if expectedOk && actualOk {
+		return expectedNumber == actualNumber
+	}
+
+	return reflect.DeepEqual(expected, actual)
+}
+
+// toFloat converts numbers, but not strings that hold a number, so that "1" does not equal 1
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case int:
+		return float64(number), true
+	}
+	return 0, false
+}
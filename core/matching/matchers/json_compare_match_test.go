@@ -0,0 +1,115 @@
+package matchers_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func jsonCompareValue(path, operator string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"path":     path,
+		"operator": operator,
+		"value":    value,
+	}
+}
+
+const ordersJson = `{
+	"orders": [
+		{"id": "a", "quantity": 5, "tags": ["new", "gift"]},
+		{"id": "b", "quantity": 150, "tags": []}
+	],
+	"items": [1, 2, 3],
+	"status": "open"
+}`
+
+func Test_JsonCompareMatch_MatchesFalseWithIncorrectDataType(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch("$.items", ordersJson)).To(BeFalse())
+}
+
+func Test_JsonCompareMatch_MatchesFalseWithInvalidJson(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.items", "length==", 3), "not json")).To(BeFalse())
+}
+
+func Test_JsonCompareMatch_MatchesFalseWithUnknownOperator(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", "~", 100), ordersJson)).To(BeFalse())
+}
+
+func Test_JsonCompareMatch_NumericComparisons(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", ">", 100.0), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", ">", 150.0), ordersJson)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", ">=", 150.0), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", "<", 5.0), ordersJson)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", "<=", 5.0), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[0].quantity", "==", 5), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[0].quantity", "!=", 5), ordersJson)).To(BeFalse())
+}
+
+func Test_JsonCompareMatch_DoesNotCompareStringsAsNumbers(t *testing.T) {
+	RegisterTestingT(t)
+
+	body := `{"id": "1", "count": 1}`
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.id", "==", 1.0), body)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.count", "==", "1"), body)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.id", "==", "1"), body)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.id", ">", 0.0), body)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.count", ">", "0"), body)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.count", "anyOf", []interface{}{"1", 2.0}), body)).To(BeFalse())
+}
+
+func Test_JsonCompareMatch_Between(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", "between", []interface{}{100.0, 200.0}), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", "between", []interface{}{6.0, 149.0}), ordersJson)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].quantity", "between", 100.0), ordersJson)).To(BeFalse())
+}
+
+func Test_JsonCompareMatch_Contains(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.items", "contains", 2.0), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.items", "contains", 4.0), ordersJson)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders[*].tags", "contains", "gift"), ordersJson)).To(BeTrue())
+}
+
+func Test_JsonCompareMatch_AnyOf(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.status", "anyOf", []interface{}{"open", "pending"}), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.status", "anyOf", []interface{}{"closed", "pending"}), ordersJson)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.status", "anyOf", "open"), ordersJson)).To(BeFalse())
+}
+
+func Test_JsonCompareMatch_Length(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.items", "length==", 3.0), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.items", "length ==", 3.0), ordersJson)).To(BeTrue())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.items", "length>", 3.0), ordersJson)).To(BeFalse())
+	Expect(matchers.JsonCompareMatch(jsonCompareValue("$.orders", "length between", []interface{}{1.0, 2.0}), ordersJson)).To(BeTrue())
+}
+
+func Test_JsonCompareMatchFailureDescriber_DescribesFailedComparison(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatchFailureDescriber(jsonCompareValue("$.orders[*].quantity", ">", 200.0), ordersJson)).
+		To(Equal(`$.orders[*].quantity > 200 failed, found [5,150]`))
+}
+
+func Test_JsonCompareMatchFailureDescriber_DescribesMissingPath(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonCompareMatchFailureDescriber(jsonCompareValue("$.missing", ">", 200.0), ordersJson)).
+		To(ContainSubstring(`$.missing could not be evaluated`))
+}
@@ -3,6 +3,7 @@ package matchers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -26,6 +27,20 @@ func JsonPathMatch(match interface{}, toMatch string) bool {
 	return true
 }
 
+func JsonPathMatchValueGenerator(match interface{}, toMatch string) string {
+	matchString, ok := match.(string)
+	if !ok {
+		return ""
+	}
+
+	returnedString, err := JsonPathExecution(prepareJsonPathQuery(matchString), toMatch)
+	if err != nil {
+		return ""
+	}
+
+	return returnedString
+}
+
 func JsonPathExecution(matchString, toMatch string) (string, error) {
 	jsonPath := jsonpath.New("")
 
@@ -59,3 +74,12 @@ func prepareJsonPathQuery(query string) string {
 
 	return query
 }
+
+func JsonPathMatchValueValidator(match interface{}) error {
+	matchString, ok := match.(string)
+	if !ok || matchString == "" {
+		return errors.New("json path must be a non-empty string")
+	}
+
+	return jsonpath.New("").Parse(prepareJsonPathQuery(matchString))
+}
@@ -66,3 +66,12 @@ func Test_JsonPathMatch_MatchesFalseWithIncorrectJsonMatch_WithExpression(t *tes
 //
 //	Expect(matchers.JsonPathMatch("$.test[?(@.field == \"test\")]", `{"test": {"field": "test"}}`)).To(BeTrue())
 //}
+
+func Test_JsonPathMatchValueValidator(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.JsonPathMatchValueValidator("$.test")).To(BeNil())
+	Expect(matchers.JsonPathMatchValueValidator("{$.test")).ToNot(BeNil())
+	Expect(matchers.JsonPathMatchValueValidator("")).ToNot(BeNil())
+	Expect(matchers.JsonPathMatchValueValidator(1)).ToNot(BeNil())
+}
@@ -2,6 +2,28 @@ package matchers
 
 type MatcherFunc func(data interface{}, toMatch string) bool
 
+// ContentTypeMatcherFunc is a matcher of bodies which needs their Content-Type to parse them
+type ContentTypeMatcherFunc func(data interface{}, toMatch, contentType string) bool
+
+// MatchValueGenerator returns the part of toMatch which a matcher matched
+// on, so that it can be passed on to a chained matcher
+type MatchValueGenerator func(data interface{}, toMatch string) string
+
+// MatchFailureDescriber explains why a matcher did not match, for
+// matchers where that is not obvious from the matcher value alone
+type MatchFailureDescriber func(data interface{}, toMatch string) string
+
+// MatchValueValidator returns an error when a matcher value can never match,
+// such as a regex that does not compile
+type MatchValueValidator func(data interface{}) error
+
+// Keys which can be set in the config of a matcher
+var (
+	NegateConfig     = "negate"
+	IgnoreCaseConfig = "ignoreCase"
+	DoMatchConfig    = "doMatch"
+)
+
 var Matchers = map[string]MatcherFunc{
 	// Default matcher
 	"": ExactMatch,
@@ -11,7 +33,29 @@ var Matchers = map[string]MatcherFunc{
 	Json:        JsonMatch,
 	JsonPath:    JsonPathMatch,
 	JsonPartial: JsonPartialMatch,
+	JsonCompare: JsonCompareMatch,
 	Regex:       RegexMatch,
 	Xml:         XmlMatch,
 	Xpath:       XpathMatch,
 }
+
+// Matchers which are given the Content-Type of the body they match, when there is one
+var ContentTypeMatchers = map[string]ContentTypeMatcherFunc{}
+
+// Matchers without a value generator pass the whole of toMatch on
+var MatchValueGenerators = map[string]MatchValueGenerator{
+	JsonPath: JsonPathMatchValueGenerator,
+	Regex:    RegexMatchValueGenerator,
+	Xpath:    XpathMatchValueGenerator,
+}
+
+// Matchers without a value validator accept any value
+var MatchValueValidators = map[string]MatchValueValidator{
+	JsonPath: JsonPathMatchValueValidator,
+	Regex:    RegexMatchValueValidator,
+	Xpath:    XpathMatchValueValidator,
+}
+
+var MatchFailureDescribers = map[string]MatchFailureDescriber{
+	JsonCompare: JsonCompareMatchFailureDescriber,
+}
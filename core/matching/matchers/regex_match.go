@@ -1,6 +1,9 @@
 package matchers
 
-import "regexp"
+import (
+	"errors"
+	"regexp"
+)
 
 var Regex = "regex"
 
@@ -17,3 +20,27 @@ func RegexMatch(match interface{}, toMatch string) bool {
 
 	return result
 }
+
+func RegexMatchValueGenerator(match interface{}, toMatch string) string {
+	matchString, ok := match.(string)
+	if !ok {
+		return ""
+	}
+
+	regex, err := regexp.Compile(matchString)
+	if err != nil {
+		return ""
+	}
+
+	return regex.FindString(toMatch)
+}
+
+func RegexMatchValueValidator(match interface{}) error {
+	matchString, ok := match.(string)
+	if !ok {
+		return errors.New("regex must be a string")
+	}
+
+	_, err := regexp.Compile(matchString)
+	return err
+}
@@ -23,3 +23,11 @@ func Test_RegexMatch_MatchesFalseWithIncorrectRegexMatch(t *testing.T) {
 
 	Expect(matchers.RegexMatch("t[o|a]st", `test`)).To(BeFalse())
 }
+
+func Test_RegexMatchValueValidator(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.RegexMatchValueValidator("t[o|a]st")).To(BeNil())
+	Expect(matchers.RegexMatchValueValidator("t[o|a")).ToNot(BeNil())
+	Expect(matchers.RegexMatchValueValidator(1)).ToNot(BeNil())
+}
@@ -2,6 +2,7 @@ package matchers
 
 import (
 	"bytes"
+	"errors"
 
 	"github.com/ChrisTrenkamp/goxpath"
 	"github.com/ChrisTrenkamp/goxpath/tree"
@@ -25,6 +26,20 @@ func XpathMatch(match interface{}, toMatch string) bool {
 	return len(results) > 0
 }
 
+func XpathMatchValueGenerator(match interface{}, toMatch string) string {
+	matchString, ok := match.(string)
+	if !ok {
+		return ""
+	}
+
+	results, err := XpathExecution(matchString, toMatch)
+	if err != nil {
+		return ""
+	}
+
+	return results.String()
+}
+
 func XpathExecution(matchString, toMatch string) (tree.NodeSet, error) {
 	xpathRule, err := goxpath.Parse(matchString)
 	if err != nil {
@@ -49,3 +64,13 @@ func XpathExecution(matchString, toMatch string) (tree.NodeSet, error) {
 
 	return results, nil
 }
+
+func XpathMatchValueValidator(match interface{}) error {
+	matchString, ok := match.(string)
+	if !ok {
+		return errors.New("xpath must be a string")
+	}
+
+	_, err := goxpath.Parse(matchString)
+	return err
+}
@@ -61,3 +61,11 @@ func Test_XpathMatch_MatchesTrue_WithoutHeader(t *testing.T) {
 
 	Expect(matchers.XpathMatch("/list/item/field", "<list><item><field></field></item></list>")).To(BeTrue())
 }
+
+func Test_XpathMatchValueValidator(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.XpathMatchValueValidator("/list/item")).To(BeNil())
+	Expect(matchers.XpathMatchValueValidator("/list/[")).ToNot(BeNil())
+	Expect(matchers.XpathMatchValueValidator(1)).ToNot(BeNil())
+}
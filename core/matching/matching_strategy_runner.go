@@ -1,6 +1,8 @@
 package matching
 
 import (
+	"net/http"
+
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/util"
@@ -21,7 +23,7 @@ func MatchingStrategyRunner(req models.RequestDetails, webserver bool, simulatio
 		requestMatcher := matchingPair.RequestMatcher
 		strategy.PreMatching()
 
-		strategy.Matching(FieldMatcher(requestMatcher.Body, req.Body), "body")
+		strategy.Matching(BodyMatcher(requestMatcher.Body, req.Body, getContentType(req.Headers)), "body")
 
 		if !webserver {
 			strategy.Matching(FieldMatcher(requestMatcher.Destination, req.Destination), "destination")
@@ -39,6 +41,8 @@ func MatchingStrategyRunner(req models.RequestDetails, webserver bool, simulatio
 
 		strategy.Matching(StateMatcher(copyState, requestMatcher.RequiresState), "state")
 
+		strategy.Matching(GrpcMatching(requestMatcher, req.Grpc), "grpc")
+
 		if result := strategy.PostMatching(req, requestMatcher, matchingPair, copyState); result != nil {
 			return result
 		}
@@ -46,3 +50,8 @@ func MatchingStrategyRunner(req models.RequestDetails, webserver bool, simulatio
 
 	return strategy.Result()
 }
+
+// getContentType returns the Content-Type of a request, or "" if it has none
+func getContentType(headers map[string][]string) string {
+	return http.Header(headers).Get("Content-Type")
+}
@@ -10,6 +10,7 @@ func QueryMatching(requestMatcher models.RequestMatcher, toMatch map[string][]st
 
 	matched := true
 	var score int
+	var missReasons []string
 
 	if requestMatcher.Query == nil {
 		return &FieldMatch{
@@ -48,6 +49,7 @@ func QueryMatching(requestMatcher models.RequestMatcher, toMatch map[string][]st
 		fieldMatch := FieldMatcher(matcherQueryValue, strings.Join(toMatchQueryValues, ";"))
 		matcherHeaderValueMatched = fieldMatch.Matched
 		score += fieldMatch.Score
+		missReasons = append(missReasons, fieldMatch.MissReasons...)
 
 		if !matcherHeaderValueMatched {
 			matched = false
@@ -55,7 +57,8 @@ func QueryMatching(requestMatcher models.RequestMatcher, toMatch map[string][]st
 	}
 
 	return &FieldMatch{
-		Matched: matched,
-		Score:   score,
+		Matched:     matched,
+		Score:       score,
+		MissReasons: missReasons,
 	}
 }
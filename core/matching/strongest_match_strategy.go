@@ -15,12 +15,14 @@ type StrongestMatchStrategy struct {
 	closestMissScore                  int
 	closestMiss                       *models.ClosestMiss
 	missedFields                      []string
+	missReasons                       []string
 	requestMatch                      *models.RequestMatcherResponsePair
 }
 
 func (s *StrongestMatchStrategy) PreMatching() {
 	s.matched = true
 	s.missedFields = make([]string, 0)
+	s.missReasons = nil
 	s.matchedOnAllButHeaders = true
 	s.matchedOnAllButState = true
 	s.score = 0
@@ -36,6 +38,9 @@ func (s *StrongestMatchStrategy) Matching(fieldMatch *FieldMatch, field string)
 		}
 		s.matched = false
 		s.missedFields = append(s.missedFields, field)
+		for _, missReason := range fieldMatch.MissReasons {
+			s.missReasons = append(s.missReasons, field+": "+missReason)
+		}
 	}
 	s.score += fieldMatch.Score
 }
@@ -55,6 +60,7 @@ func (s *StrongestMatchStrategy) PostMatching(req models.RequestDetails, request
 		s.requestMatch = &models.RequestMatcherResponsePair{
 			RequestMatcher: requestMatcher,
 			Response:       matchingPair.Response,
+			Responses:      matchingPair.Responses,
 		}
 		s.strongestMatchScore = s.score
 		s.closestMiss = nil
@@ -66,6 +72,7 @@ func (s *StrongestMatchStrategy) PostMatching(req models.RequestDetails, request
 			RequestMatcher: view.RequestMatcher,
 			Response:       view.Response,
 			MissedFields:   s.missedFields,
+			MissReasons:    s.missReasons,
 			State:          state,
 		}
 	}
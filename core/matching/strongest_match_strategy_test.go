@@ -2069,3 +2069,112 @@ func Test_StrongestMatch__ShouldBeCachableIfMatchedOnEverythingApartFromStateZer
 	Expect(result.Error).ToNot(BeNil())
 	Expect(result.Cachable).To(BeTrue())
 }
+
+func Test_StrongestMatch_ChainedMatcherScoresHigherThanSingleMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Body: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.JsonPath,
+					Value:   "$.id",
+					DoMatch: &models.RequestFieldMatchers{
+						Matcher: matchers.Exact,
+						Value:   "123",
+					},
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Body: "chained",
+		},
+	})
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Body: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.JsonPath,
+					Value:   "$.id",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Body: "single",
+		},
+	})
+
+	r := models.RequestDetails{
+		Body: `{"id": 123}`,
+	}
+	result := matching.MatchingStrategyRunner(r, false, simulation, &state.State{State: map[string]string{}}, &matching.StrongestMatchStrategy{})
+
+	Expect(result.Error).To(BeNil())
+	Expect(result.Pair.Response.Body).To(Equal("chained"))
+}
+
+func Test_StrongestMatch_ClosestMissIncludesMatcherConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Method: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "GET",
+					Config: map[string]interface{}{
+						"negate": true,
+					},
+				},
+			},
+		},
+		Response: testResponse,
+	})
+
+	r := models.RequestDetails{
+		Method: "GET",
+	}
+	result := matching.MatchingStrategyRunner(r, false, simulation, &state.State{State: map[string]string{}}, &matching.StrongestMatchStrategy{})
+
+	Expect(result.Pair).To(BeNil())
+	Expect(result.Error.ClosestMiss).ToNot(BeNil())
+	Expect(result.Error.ClosestMiss.MissedFields).To(ConsistOf("method"))
+	Expect(result.Error.ClosestMiss.RequestMatcher.Method[0].Config).To(HaveKeyWithValue("negate", true))
+}
+
+func Test_StrongestMatch_ClosestMissIncludesReasonForFailedComparison(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Body: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.JsonCompare,
+					Value: map[string]interface{}{
+						"path":     "$.quantity",
+						"operator": ">",
+						"value":    100.0,
+					},
+				},
+			},
+		},
+		Response: testResponse,
+	})
+
+	r := models.RequestDetails{
+		Body: `{"quantity": 10}`,
+	}
+	result := matching.MatchingStrategyRunner(r, false, simulation, &state.State{State: map[string]string{}}, &matching.StrongestMatchStrategy{})
+
+	Expect(result.Pair).To(BeNil())
+	Expect(result.Error.ClosestMiss.MissedFields).To(ConsistOf("body"))
+	Expect(result.Error.ClosestMiss.MissReasons).To(ConsistOf("body: $.quantity > 100 failed, found [10]"))
+	Expect(result.Error.ClosestMiss.GetMessage()).To(ContainSubstring("body: $.quantity > 100 failed, found [10]"))
+}
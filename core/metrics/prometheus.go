@@ -0,0 +1,295 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Match outcomes counted by hoverfly_matches_total
+const (
+	MatchHit      = "hit"
+	MatchMiss     = "miss"
+	MatchCacheHit = "cache_hit"
+)
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the duration histograms
+var DefaultDurationBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics holds the metrics Hoverfly exposes in the Prometheus text format
+type PrometheusMetrics struct {
+	Requests           *CounterVec
+	Matches            *CounterVec
+	RequestDuration    *HistogramVec
+	MiddlewareDuration *HistogramVec
+	MiddlewareFailures *CounterVec
+	SimulationPairs    *GaugeFunc
+}
+
+// NewPrometheusMetrics creates the Hoverfly metrics, using pairCount to read
+// the number of pairs being simulated when the metrics are scraped
+func NewPrometheusMetrics(pairCount func() float64) *PrometheusMetrics {
+	return &PrometheusMetrics{
+		Requests: NewCounterVec("hoverfly_requests_total",
+			"Requests handled by Hoverfly.", "mode", "destination", "method", "status"),
+		Matches: NewCounterVec("hoverfly_matches_total",
+			"Outcomes of matching requests against the simulation.", "outcome"),
+		RequestDuration: NewHistogramVec("hoverfly_request_duration_seconds",
+			"Time taken to handle a request, until it is added to the journal.", DefaultDurationBuckets, "mode"),
+		MiddlewareDuration: NewHistogramVec("hoverfly_middleware_duration_seconds",
+			"Time taken to execute middleware.", DefaultDurationBuckets),
+		MiddlewareFailures: NewCounterVec("hoverfly_middleware_failures_total",
+			"Middleware executions that returned an error."),
+		SimulationPairs: &GaugeFunc{
+			Name:  "hoverfly_simulation_pairs",
+			Help:  "Request response pairs in the simulation, its enabled simulation sets and its sessions.",
+			Value: pairCount,
+		},
+	}
+}
+
+// CountRequest counts a handled request and records how long it took
+func (this *PrometheusMetrics) CountRequest(mode, destination, method string, status int, latency time.Duration) {
+	this.Requests.Inc(mode, destination, method, strconv.Itoa(status))
+	this.RequestDuration.Observe(latency.Seconds(), mode)
+}
+
+// CountMatch counts the outcome of matching a request
+func (this *PrometheusMetrics) CountMatch(outcome string) {
+	this.Matches.Inc(outcome)
+}
+
+// ObserveMiddleware records how long middleware took to execute and whether it failed
+func (this *PrometheusMetrics) ObserveMiddleware(duration time.Duration, err error) {
+	this.MiddlewareDuration.Observe(duration.Seconds())
+	if err != nil {
+		this.MiddlewareFailures.Inc()
+	}
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format
+func (this *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	buffer := &bytes.Buffer{}
+
+	this.Requests.write(buffer)
+	this.Matches.write(buffer)
+	this.RequestDuration.write(buffer)
+	this.MiddlewareDuration.write(buffer)
+	this.MiddlewareFailures.write(buffer)
+	this.SimulationPairs.write(buffer)
+
+	return buffer.WriteTo(w)
+}
+
+// CounterVec is a counter with a value for each combination of label values
+type CounterVec struct {
+	Name   string
+	Help   string
+	Labels []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		Name:   name,
+		Help:   help,
+		Labels: labels,
+		series: map[string]*counterSeries{},
+	}
+}
+
+// Inc adds one to the counter for the given label values, which are in the order of the labels
+func (this *CounterVec) Inc(labelValues ...string) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	series, ok := this.series[key]
+	if !ok {
+		series = &counterSeries{labelValues: labelValues}
+		this.series[key] = series
+	}
+	series.value++
+}
+
+// Value returns the counter for the given label values
+func (this *CounterVec) Value(labelValues ...string) float64 {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if series, ok := this.series[strings.Join(labelValues, "\xff")]; ok {
+		return series.value
+	}
+	return 0
+}
+
+func (this *CounterVec) write(buffer *bytes.Buffer) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	writeHeader(buffer, this.Name, this.Help, "counter")
+	for _, key := range sortedSeriesKeys(this.series) {
+		series := this.series[key]
+		fmt.Fprintf(buffer, "%s%s %s\n", this.Name, formatLabels(this.Labels, series.labelValues), formatValue(series.value))
+	}
+}
+
+// HistogramVec is a histogram with a set of buckets for each combination of label values
+type HistogramVec struct {
+	Name    string
+	Help    string
+	Labels  []string
+	Buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues  []string
+	bucketCounts []uint64
+	count        uint64
+	sum          float64
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{
+		Name:    name,
+		Help:    help,
+		Labels:  labels,
+		Buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+}
+
+// Observe adds a value to the histogram for the given label values
+func (this *HistogramVec) Observe(value float64, labelValues ...string) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	series, ok := this.series[key]
+	if !ok {
+		series = &histogramSeries{
+			labelValues:  labelValues,
+			bucketCounts: make([]uint64, len(this.Buckets)),
+		}
+		this.series[key] = series
+	}
+
+	for i, upperBound := range this.Buckets {
+		if value <= upperBound {
+			series.bucketCounts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// Count returns the number of values observed for the given label values
+func (this *HistogramVec) Count(labelValues ...string) uint64 {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if series, ok := this.series[strings.Join(labelValues, "\xff")]; ok {
+		return series.count
+	}
+	return 0
+}
+
+func (this *HistogramVec) write(buffer *bytes.Buffer) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	writeHeader(buffer, this.Name, this.Help, "histogram")
+	bucketLabels := append(append([]string{}, this.Labels...), "le")
+
+	for _, key := range sortedSeriesKeys(this.series) {
+		series := this.series[key]
+
+		for i, upperBound := range this.Buckets {
+			bucketValues := append(append([]string{}, series.labelValues...), formatValue(upperBound))
+			fmt.Fprintf(buffer, "%s_bucket%s %d\n", this.Name, formatLabels(bucketLabels, bucketValues), series.bucketCounts[i])
+		}
+		infValues := append(append([]string{}, series.labelValues...), "+Inf")
+		fmt.Fprintf(buffer, "%s_bucket%s %d\n", this.Name, formatLabels(bucketLabels, infValues), series.count)
+
+		labels := formatLabels(this.Labels, series.labelValues)
+		fmt.Fprintf(buffer, "%s_sum%s %s\n", this.Name, labels, formatValue(series.sum))
+		fmt.Fprintf(buffer, "%s_count%s %d\n", this.Name, labels, series.count)
+	}
+}
+
+// GaugeFunc is a gauge whose value is read when the metrics are written
+type GaugeFunc struct {
+	Name  string
+	Help  string
+	Value func() float64
+}
+
+func (this *GaugeFunc) write(buffer *bytes.Buffer) {
+	writeHeader(buffer, this.Name, this.Help, "gauge")
+	fmt.Fprintf(buffer, "%s %s\n", this.Name, formatValue(this.Value()))
+}
+
+func writeHeader(buffer *bytes.Buffer, name, help, metricType string) {
+	fmt.Fprintf(buffer, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buffer, "# TYPE %s %s\n", name, metricType)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := []string{}
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, labelValueEscaper.Replace(value)))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedSeriesKeys(series interface{}) []string {
+	keys := []string{}
+	switch typed := series.(type) {
+	case map[string]*counterSeries:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]*histogramSeries:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/metrics"
+	. "github.com/onsi/gomega"
+)
+
+func Test_PrometheusMetrics_WriteTo_WritesCountersWithLabels(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewPrometheusMetrics(func() float64 { return 2 })
+	unit.CountRequest("simulate", "test.com", "GET", 200, time.Millisecond)
+	unit.CountRequest("simulate", "test.com", "GET", 200, time.Millisecond)
+	unit.CountRequest("capture", "test.com", "POST", 502, time.Millisecond)
+	unit.CountMatch(metrics.MatchHit)
+	unit.CountMatch(metrics.MatchCacheHit)
+
+	buffer := &bytes.Buffer{}
+	_, err := unit.WriteTo(buffer)
+	Expect(err).To(BeNil())
+
+	Expect(buffer.String()).To(ContainSubstring(`# HELP hoverfly_requests_total Requests handled by Hoverfly.
+# TYPE hoverfly_requests_total counter
+hoverfly_requests_total{mode="capture",destination="test.com",method="POST",status="502"} 1
+hoverfly_requests_total{mode="simulate",destination="test.com",method="GET",status="200"} 2
+`))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_matches_total{outcome="cache_hit"} 1
+hoverfly_matches_total{outcome="hit"} 1
+`))
+	Expect(buffer.String()).To(ContainSubstring(`# TYPE hoverfly_simulation_pairs gauge
+hoverfly_simulation_pairs 2
+`))
+}
+
+func Test_PrometheusMetrics_WriteTo_WritesCumulativeHistogramBuckets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewPrometheusMetrics(func() float64 { return 0 })
+	unit.ObserveMiddleware(20*time.Millisecond, nil)
+	unit.ObserveMiddleware(3*time.Second, errors.New("middleware failed"))
+
+	buffer := &bytes.Buffer{}
+	unit.WriteTo(buffer)
+
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_middleware_duration_seconds_bucket{le="0.01"} 0
+hoverfly_middleware_duration_seconds_bucket{le="0.025"} 1
+`))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_middleware_duration_seconds_bucket{le="2.5"} 1
+hoverfly_middleware_duration_seconds_bucket{le="5"} 2
+hoverfly_middleware_duration_seconds_bucket{le="10"} 2
+hoverfly_middleware_duration_seconds_bucket{le="+Inf"} 2
+hoverfly_middleware_duration_seconds_sum 3.02
+hoverfly_middleware_duration_seconds_count 2
+`))
+	Expect(buffer.String()).To(ContainSubstring("hoverfly_middleware_failures_total 1\n"))
+}
+
+func Test_PrometheusMetrics_WriteTo_EscapesLabelValues(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewPrometheusMetrics(func() float64 { return 0 })
+	unit.CountRequest("simulate", "a\"quoted\"\\host\n", "GET", 200, time.Millisecond)
+
+	buffer := &bytes.Buffer{}
+	unit.WriteTo(buffer)
+
+	Expect(buffer.String()).To(ContainSubstring(`destination="a\"quoted\"\\host\n"`))
+}
+
+func Test_CounterVec_Value_ReturnsCountForLabelValues(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewCounterVec("test_total", "Test.", "name")
+	unit.Inc("one")
+	unit.Inc("one")
+
+	Expect(unit.Value("one")).To(Equal(float64(2)))
+	Expect(unit.Value("two")).To(Equal(float64(0)))
+}
@@ -13,6 +13,7 @@ type ClosestMiss struct {
 	Response       v2.ResponseDetailsViewV5
 	RequestMatcher v2.RequestMatcherViewV5
 	MissedFields   []string
+	MissReasons    []string
 	State          map[string]string
 }
 
@@ -23,6 +24,11 @@ func (this *ClosestMiss) GetMessage() string {
 	responseBytes, _ := json.MarshalIndent(this.Response, "", "    ")
 	currentState, _ := json.MarshalIndent(this.State, "", "    ")
 
+	var missReasons string
+	if len(this.MissReasons) > 0 {
+		missReasons = "\n\nBecause:\n\n" + strings.Join(this.MissReasons, "\n")
+	}
+
 	return "\n\nThe following request was made, but was not matched by Hoverfly:\n\n" +
 		string(requestBytes) +
 		"\n\nWhilst Hoverfly has the following state:\n\n" +
@@ -31,6 +37,7 @@ func (this *ClosestMiss) GetMessage() string {
 		string(matcherBytes) +
 		"\n\nBut it did not match on the following fields:\n\n" +
 		fmt.Sprint("["+strings.Join(this.MissedFields, ", ")+"]") +
+		missReasons +
 		"\n\nWhich if hit would have given the following response:\n\n" +
 		string(responseBytes)
 }
@@ -40,5 +47,6 @@ func (this *ClosestMiss) BuildView() *v2.ClosestMissView {
 		Response:       this.Response,
 		RequestMatcher: this.RequestMatcher,
 		MissedFields:   this.MissedFields,
+		MissReasons:    this.MissReasons,
 	}
 }
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/base64"
+
+	"github.com/SpectoLabs/hoverfly/core/grpc"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// GrpcRequestDetails is what is read from a gRPC call: the service and method it calls,
+// and the message it sends. The message is in JSON when the method has a registered
+// descriptor set, otherwise it is base64 encoded. Only unary calls, which send exactly
+// one message, have a message.
+type GrpcRequestDetails struct {
+	Service string
+	Method  string
+	Message string
+}
+
+// NewGrpcRequestDetails reads the gRPC call of a request, returning nil if the request is
+// not a gRPC call. The descriptors may be nil.
+func NewGrpcRequestDetails(request RequestDetails, descriptors *grpc.Descriptors) *GrpcRequestDetails {
+	if !grpc.IsGrpc(request.Headers) {
+		return nil
+	}
+
+	service, method, err := grpc.ParseMethodPath(request.Path)
+	if err != nil {
+		return nil
+	}
+
+	details := &GrpcRequestDetails{
+		Service: service,
+		Method:  method,
+	}
+
+	messages, err := grpc.ReadMessages([]byte(request.Body), request.Headers)
+	if err != nil || len(messages) != 1 {
+		return details
+	}
+
+	details.Message = base64.StdEncoding.EncodeToString(messages[0])
+	if descriptors != nil {
+		if decoded, err := descriptors.DecodeRequest(service, method, messages[0]); err == nil {
+			details.Message = decoded
+		}
+	}
+
+	return details
+}
+
+func (this *GrpcRequestDetails) BuildView() *v2.GrpcRequestView {
+	if this == nil {
+		return nil
+	}
+
+	return &v2.GrpcRequestView{
+		Service: this.Service,
+		Method:  this.Method,
+		Message: this.Message,
+	}
+}
+
+// GrpcRequestMatcher matches the service, method and message of a gRPC call
+type GrpcRequestMatcher struct {
+	Service []RequestFieldMatchers
+	Method  []RequestFieldMatchers
+	Message []RequestFieldMatchers
+}
+
+func NewGrpcRequestMatcherFromView(view *v2.GrpcRequestMatcherViewV5) *GrpcRequestMatcher {
+	if view == nil {
+		return nil
+	}
+
+	return &GrpcRequestMatcher{
+		Service: NewRequestFieldMatchersFromView(view.Service),
+		Method:  NewRequestFieldMatchersFromView(view.Method),
+		Message: NewRequestFieldMatchersFromView(view.Message),
+	}
+}
+
+func (this *GrpcRequestMatcher) BuildView() *v2.GrpcRequestMatcherViewV5 {
+	if this == nil {
+		return nil
+	}
+
+	return &v2.GrpcRequestMatcherViewV5{
+		Service: buildFieldMatcherViews(this.Service),
+		Method:  buildFieldMatcherViews(this.Method),
+		Message: buildFieldMatcherViews(this.Message),
+	}
+}
+
+func buildFieldMatcherViews(fieldMatchers []RequestFieldMatchers) []v2.MatcherViewV5 {
+	var views []v2.MatcherViewV5
+	for _, matcher := range fieldMatchers {
+		views = append(views, matcher.BuildView())
+	}
+	return views
+}
@@ -0,0 +1,63 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/grpc"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func Test_NewGrpcRequestDetails_ReturnsNilForRequestsThatAreNotGrpcCalls(t *testing.T) {
+	RegisterTestingT(t)
+
+	request := models.RequestDetails{
+		Path:    "/shop.Items/GetItem",
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+	}
+
+	Expect(models.NewGrpcRequestDetails(request, grpc.NewDescriptors())).To(BeNil())
+}
+
+func Test_NewGrpcRequestDetails_EncodesMessagesWithoutDescriptorsInBase64(t *testing.T) {
+	RegisterTestingT(t)
+
+	request := models.RequestDetails{
+		Path:    "/shop.Items/GetItem",
+		Body:    string(grpc.WriteMessage([]byte{0x0a, 0x01, '1'})),
+		Headers: map[string][]string{"Content-Type": {"application/grpc"}},
+	}
+
+	Expect(models.NewGrpcRequestDetails(request, grpc.NewDescriptors())).To(Equal(&models.GrpcRequestDetails{
+		Service: "shop.Items",
+		Method:  "GetItem",
+		Message: "CgEx",
+	}))
+}
+
+func Test_NewGrpcRequestDetails_LeavesMessageEmptyForStreamingCalls(t *testing.T) {
+	RegisterTestingT(t)
+
+	request := models.RequestDetails{
+		Path:    "/shop.Items/GetItems",
+		Body:    string(append(grpc.WriteMessage([]byte{0x0a, 0x01, '1'}), grpc.WriteMessage([]byte{0x0a, 0x01, '2'})...)),
+		Headers: map[string][]string{"Content-Type": {"application/grpc"}},
+	}
+
+	Expect(models.NewGrpcRequestDetails(request, nil).Message).To(Equal(""))
+}
+
+func Test_GrpcRequestMatcher_BuildView_ConvertsBackToView(t *testing.T) {
+	RegisterTestingT(t)
+
+	view := &v2.GrpcRequestMatcherViewV5{
+		Service: []v2.MatcherViewV5{{Matcher: matchers.Exact, Value: "shop.Items"}},
+		Method:  []v2.MatcherViewV5{{Matcher: matchers.Glob, Value: "Get*"}},
+		Message: []v2.MatcherViewV5{{Matcher: matchers.Json, Value: `{"id":"1"}`}},
+	}
+
+	Expect(models.NewGrpcRequestMatcherFromView(view).BuildView()).To(Equal(view))
+	Expect(models.NewGrpcRequestMatcherFromView(nil)).To(BeNil())
+}
@@ -62,6 +62,7 @@ type RequestDetails struct {
 	Query       map[string][]string
 	Body        string
 	Headers     map[string][]string
+	Grpc        *GrpcRequestDetails `json:",omitempty"`
 	rawQuery    string
 }
 
@@ -126,6 +127,7 @@ func (this *RequestDetails) ConvertToRequestDetailsView() v2.RequestDetailsView
 		QueryMap:    this.Query,
 		Body:        &this.Body,
 		Headers:     this.Headers,
+		Grpc:        this.Grpc.BuildView(),
 	}
 }
 
@@ -193,6 +195,13 @@ type ResponseDetails struct {
 	Templated        bool
 	TransitionsState map[string]string
 	RemovesState     []string
+	Fault            *ResponseFault
+	FixedDelay       int
+	LogNormalDelay   *LogNormalDelay
+	UniformDelay     *UniformDelay
+	Chunks           []ResponseChunk
+	// Match is how the response was found when matching a request, it is not part of the simulation
+	Match *ResponseMatch `json:"-"`
 }
 
 func NewResponseDetailsFromResponse(data interfaces.Response) ResponseDetails {
@@ -280,6 +289,11 @@ func (r *ResponseDetails) ConvertToResponseDetailsViewV5() v2.ResponseDetailsVie
 		Templated:        r.Templated,
 		RemovesState:     r.RemovesState,
 		TransitionsState: r.TransitionsState,
+		Fault:            r.Fault.BuildView(),
+		FixedDelay:       r.FixedDelay,
+		LogNormalDelay:   r.LogNormalDelay.BuildView(),
+		UniformDelay:     r.UniformDelay.BuildView(),
+		Chunks:           BuildResponseChunkViews(r.Chunks),
 	}
 }
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"net/url"
 
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
@@ -11,6 +12,8 @@ import (
 type RequestFieldMatchers struct {
 	Matcher string
 	Value   interface{}
+	Config  map[string]interface{}
+	DoMatch *RequestFieldMatchers
 }
 
 func NewRequestFieldMatchersFromView(matchers []v2.MatcherViewV5) []RequestFieldMatchers {
@@ -19,14 +22,50 @@ func NewRequestFieldMatchersFromView(matchers []v2.MatcherViewV5) []RequestField
 	}
 	convertedMatchers := []RequestFieldMatchers{}
 	for _, matcher := range matchers {
-		convertedMatchers = append(convertedMatchers, RequestFieldMatchers{
-			Matcher: matcher.Matcher,
-			Value:   matcher.Value,
-		})
+		convertedMatchers = append(convertedMatchers, newRequestFieldMatcherFromView(matcher))
 	}
 	return convertedMatchers
 }
 
+// The doMatch config is held as its own RequestFieldMatchers, so that it
+// does not need converting every time a request is matched
+func newRequestFieldMatcherFromView(view v2.MatcherViewV5) RequestFieldMatchers {
+	fieldMatcher := RequestFieldMatchers{
+		Matcher: view.Matcher,
+		Value:   view.Value,
+	}
+
+	for key, value := range view.Config {
+		if key == matchers.DoMatchConfig {
+			doMatchView, err := newMatcherViewFromConfigValue(value)
+			if err == nil {
+				doMatch := newRequestFieldMatcherFromView(doMatchView)
+				fieldMatcher.DoMatch = &doMatch
+			}
+			continue
+		}
+
+		if fieldMatcher.Config == nil {
+			fieldMatcher.Config = map[string]interface{}{}
+		}
+		fieldMatcher.Config[key] = value
+	}
+
+	return fieldMatcher
+}
+
+func newMatcherViewFromConfigValue(value interface{}) (v2.MatcherViewV5, error) {
+	var view v2.MatcherViewV5
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return view, err
+	}
+
+	err = json.Unmarshal(bytes, &view)
+	return view, err
+}
+
 func NewRequestFieldMatchersFromMapView(mapMatchers map[string][]v2.MatcherViewV5) map[string][]RequestFieldMatchers {
 	var matchers map[string][]RequestFieldMatchers
 	for key, view := range mapMatchers {
@@ -54,95 +93,169 @@ func NewQueryRequestFieldMatchersFromMapView(mapMatchers *v2.QueryMatcherViewV5)
 }
 
 func (this RequestFieldMatchers) BuildView() v2.MatcherViewV5 {
+	var config map[string]interface{}
+	for key, value := range this.Config {
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		config[key] = value
+	}
+
+	if this.DoMatch != nil {
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		config[matchers.DoMatchConfig] = this.DoMatch.BuildView()
+	}
+
 	return v2.MatcherViewV5{
 		Matcher: this.Matcher,
 		Value:   this.Value,
+		Config:  config,
 	}
 }
 
+// HasConfig returns true if the matcher behaves differently
+// to its plain matcher type
+func (this RequestFieldMatchers) HasConfig() bool {
+	return len(this.Config) > 0 || this.DoMatch != nil
+}
+
 type RequestMatcherResponsePair struct {
 	RequestMatcher RequestMatcher
 	Response       ResponseDetails
+	Responses      *ResponseSequence
 }
 
 func NewRequestMatcherResponsePairFromView(view *v2.RequestMatcherResponsePairViewV5) *RequestMatcherResponsePair {
-	for i, matcher := range view.RequestMatcher.DeprecatedQuery {
+	response := newResponseDetailsFromView(view.Response)
+
+	var responses *ResponseSequence
+	if len(view.Responses) > 0 {
+		details := []ResponseDetails{}
+		weights := []int{}
+		for _, responseView := range view.Responses {
+			details = append(details, newResponseDetailsFromView(responseView))
+			weights = append(weights, responseView.Weight)
+		}
+		responses = NewResponseSequence(view.ResponseStrategy, details, weights)
+		response = details[0]
+	}
+
+	return &RequestMatcherResponsePair{
+		RequestMatcher: NewRequestMatcherFromView(&view.RequestMatcher),
+		Response:       response,
+		Responses:      responses,
+	}
+}
+
+func NewRequestMatcherFromView(view *v2.RequestMatcherViewV5) RequestMatcher {
+	for i, matcher := range view.DeprecatedQuery {
 		if matcher.Matcher == matchers.Exact {
 			sortedQuery := util.SortQueryString(matcher.Value.(string))
-			view.RequestMatcher.DeprecatedQuery[i].Value = sortedQuery
+			view.DeprecatedQuery[i].Value = sortedQuery
 		}
 	}
 
-	return &RequestMatcherResponsePair{
-		RequestMatcher: RequestMatcher{
-			Path:            NewRequestFieldMatchersFromView(view.RequestMatcher.Path),
-			Method:          NewRequestFieldMatchersFromView(view.RequestMatcher.Method),
-			Destination:     NewRequestFieldMatchersFromView(view.RequestMatcher.Destination),
-			Scheme:          NewRequestFieldMatchersFromView(view.RequestMatcher.Scheme),
-			DeprecatedQuery: NewRequestFieldMatchersFromView(view.RequestMatcher.DeprecatedQuery),
-			Body:            NewRequestFieldMatchersFromView(view.RequestMatcher.Body),
-			Headers:         NewRequestFieldMatchersFromMapView(view.RequestMatcher.Headers),
-			Query:           NewQueryRequestFieldMatchersFromMapView(view.RequestMatcher.Query),
-			RequiresState:   view.RequestMatcher.RequiresState,
-		},
-		Response: NewResponseDetailsFromResponse(view.Response),
+	return RequestMatcher{
+		Path:            NewRequestFieldMatchersFromView(view.Path),
+		Method:          NewRequestFieldMatchersFromView(view.Method),
+		Destination:     NewRequestFieldMatchersFromView(view.Destination),
+		Scheme:          NewRequestFieldMatchersFromView(view.Scheme),
+		DeprecatedQuery: NewRequestFieldMatchersFromView(view.DeprecatedQuery),
+		Body:            NewRequestFieldMatchersFromView(view.Body),
+		Headers:         NewRequestFieldMatchersFromMapView(view.Headers),
+		Query:           NewQueryRequestFieldMatchersFromMapView(view.Query),
+		RequiresState:   view.RequiresState,
+		Grpc:            NewGrpcRequestMatcherFromView(view.Grpc),
 	}
 }
 
+func newResponseDetailsFromView(view v2.ResponseDetailsViewV5) ResponseDetails {
+	response := NewResponseDetailsFromResponse(view)
+	response.Fault = NewResponseFaultFromView(view.Fault)
+	response.FixedDelay = view.FixedDelay
+	response.LogNormalDelay = NewLogNormalDelayFromView(view.LogNormalDelay)
+	response.UniformDelay = NewUniformDelayFromView(view.UniformDelay)
+	response.Chunks = NewResponseChunksFromView(view.Chunks)
+	return response
+}
+
 func (this *RequestMatcherResponsePair) BuildView() v2.RequestMatcherResponsePairViewV5 {
+	var responses []v2.ResponseDetailsViewV5
+	var responseStrategy string
+	if this.Responses != nil {
+		for i, response := range this.Responses.Responses {
+			responseView := response.ConvertToResponseDetailsViewV5()
+			if this.Responses.Strategy == ResponseStrategyWeighted {
+				responseView.Weight = this.Responses.GetWeight(i)
+			}
+			responses = append(responses, responseView)
+		}
+		responseStrategy = this.Responses.Strategy
+	}
+
+	return v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher:   this.RequestMatcher.BuildView(),
+		Response:         this.Response.ConvertToResponseDetailsViewV5(),
+		Responses:        responses,
+		ResponseStrategy: responseStrategy,
+	}
+}
 
+func (this RequestMatcher) BuildView() v2.RequestMatcherViewV5 {
 	var path, method, destination, scheme, query, body []v2.MatcherViewV5
 
-	if this.RequestMatcher.Path != nil && len(this.RequestMatcher.Path) != 0 {
+	if this.Path != nil && len(this.Path) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Path {
+		for _, matcher := range this.Path {
 			views = append(views, matcher.BuildView())
 		}
 		path = views
 	}
 
-	if this.RequestMatcher.Method != nil && len(this.RequestMatcher.Method) != 0 {
+	if this.Method != nil && len(this.Method) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Method {
+		for _, matcher := range this.Method {
 			views = append(views, matcher.BuildView())
 		}
 		method = views
 	}
 
-	if this.RequestMatcher.Destination != nil && len(this.RequestMatcher.Destination) != 0 {
+	if this.Destination != nil && len(this.Destination) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Destination {
+		for _, matcher := range this.Destination {
 			views = append(views, matcher.BuildView())
 		}
 		destination = views
 	}
 
-	if this.RequestMatcher.Scheme != nil && len(this.RequestMatcher.Scheme) != 0 {
+	if this.Scheme != nil && len(this.Scheme) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Scheme {
+		for _, matcher := range this.Scheme {
 			views = append(views, matcher.BuildView())
 		}
 		scheme = views
 	}
 
-	if this.RequestMatcher.Body != nil && len(this.RequestMatcher.Body) != 0 {
+	if this.Body != nil && len(this.Body) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Body {
+		for _, matcher := range this.Body {
 			views = append(views, matcher.BuildView())
 		}
 		body = views
 	}
 
-	if this.RequestMatcher.DeprecatedQuery != nil && len(this.RequestMatcher.DeprecatedQuery) != 0 {
+	if this.DeprecatedQuery != nil && len(this.DeprecatedQuery) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.DeprecatedQuery {
+		for _, matcher := range this.DeprecatedQuery {
 			views = append(views, matcher.BuildView())
 		}
 		query = views
 	}
 
 	headersWithMatchers := map[string][]v2.MatcherViewV5{}
-	for key, matchers := range this.RequestMatcher.Headers {
+	for key, matchers := range this.Headers {
 		views := []v2.MatcherViewV5{}
 		for _, matcher := range matchers {
 			views = append(views, matcher.BuildView())
@@ -151,9 +264,9 @@ func (this *RequestMatcherResponsePair) BuildView() v2.RequestMatcherResponsePai
 	}
 
 	var queriesWithMatchers *v2.QueryMatcherViewV5
-	if this.RequestMatcher.Query != nil {
+	if this.Query != nil {
 		queriesWithMatchers = &v2.QueryMatcherViewV5{}
-		for key, matchers := range *this.RequestMatcher.Query {
+		for key, matchers := range *this.Query {
 			views := []v2.MatcherViewV5{}
 			for _, matcher := range matchers {
 				views = append(views, matcher.BuildView())
@@ -162,19 +275,17 @@ func (this *RequestMatcherResponsePair) BuildView() v2.RequestMatcherResponsePai
 		}
 	}
 
-	return v2.RequestMatcherResponsePairViewV5{
-		RequestMatcher: v2.RequestMatcherViewV5{
-			Path:            path,
-			Method:          method,
-			Destination:     destination,
-			Scheme:          scheme,
-			DeprecatedQuery: query,
-			Body:            body,
-			Headers:         headersWithMatchers,
-			Query:           queriesWithMatchers,
-			RequiresState:   this.RequestMatcher.RequiresState,
-		},
-		Response: this.Response.ConvertToResponseDetailsViewV5(),
+	return v2.RequestMatcherViewV5{
+		Path:            path,
+		Method:          method,
+		Destination:     destination,
+		Scheme:          scheme,
+		DeprecatedQuery: query,
+		Body:            body,
+		Headers:         headersWithMatchers,
+		Query:           queriesWithMatchers,
+		RequiresState:   this.RequiresState,
+		Grpc:            this.Grpc.BuildView(),
 	}
 }
 
@@ -188,6 +299,7 @@ type RequestMatcher struct {
 	Headers         map[string][]RequestFieldMatchers
 	Query           *QueryRequestFieldMatchers
 	RequiresState   map[string]string
+	Grpc            *GrpcRequestMatcher
 }
 
 type QueryRequestFieldMatchers map[string][]RequestFieldMatchers
@@ -205,12 +317,12 @@ func (this RequestMatcher) IncludesStateMatching() bool {
 }
 
 func (this RequestMatcher) ToEagerlyCachable() *RequestDetails {
-	if this.Body == nil || len(this.Body) != 1 || this.Body[0].Matcher != matchers.Exact ||
-		this.Destination == nil || len(this.Destination) != 1 || this.Destination[0].Matcher != matchers.Exact ||
-		this.Method == nil || len(this.Method) != 1 || this.Method[0].Matcher != matchers.Exact ||
-		this.Path == nil || len(this.Path) != 1 || this.Path[0].Matcher != matchers.Exact ||
-		this.DeprecatedQuery == nil || len(this.DeprecatedQuery) != 1 || this.DeprecatedQuery[0].Matcher != matchers.Exact ||
-		this.Scheme == nil || len(this.Scheme) != 1 || this.Scheme[0].Matcher != matchers.Exact {
+	if !isSingleExactMatcher(this.Body) ||
+		!isSingleExactMatcher(this.Destination) ||
+		!isSingleExactMatcher(this.Method) ||
+		!isSingleExactMatcher(this.Path) ||
+		!isSingleExactMatcher(this.DeprecatedQuery) ||
+		!isSingleExactMatcher(this.Scheme) {
 		return nil
 	}
 
@@ -222,6 +334,10 @@ func (this RequestMatcher) ToEagerlyCachable() *RequestDetails {
 		return nil
 	}
 
+	if this.Grpc != nil {
+		return nil
+	}
+
 	query, _ := url.ParseQuery(this.DeprecatedQuery[0].Value.(string))
 
 	return &RequestDetails{
@@ -234,6 +350,10 @@ func (this RequestMatcher) ToEagerlyCachable() *RequestDetails {
 	}
 }
 
+func isSingleExactMatcher(fieldMatchers []RequestFieldMatchers) bool {
+	return len(fieldMatchers) == 1 && fieldMatchers[0].Matcher == matchers.Exact && !fieldMatchers[0].HasConfig()
+}
+
 type MatchError struct {
 	ClosestMiss *ClosestMiss
 	error       string
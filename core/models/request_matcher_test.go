@@ -46,6 +46,60 @@ func Test_NewRequestFieldMatchers_BuildView(t *testing.T) {
 	Expect(view.Value).To(Equal("exactly"))
 }
 
+func Test_NewRequestFieldMatchersFromView_ConvertsConfigAndDoMatch(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewRequestFieldMatchersFromView([]v2.MatcherViewV5{
+		{
+			Matcher: matchers.JsonPath,
+			Value:   "$.id",
+			Config: map[string]interface{}{
+				"negate": true,
+				"doMatch": map[string]interface{}{
+					"matcher": "regex",
+					"value":   "^[0-9]+$",
+					"config": map[string]interface{}{
+						"ignoreCase": true,
+					},
+				},
+			},
+		},
+	})
+
+	Expect(unit).To(HaveLen(1))
+	Expect(unit[0].Config).To(Equal(map[string]interface{}{"negate": true}))
+	Expect(unit[0].DoMatch).ToNot(BeNil())
+	Expect(unit[0].DoMatch.Matcher).To(Equal("regex"))
+	Expect(unit[0].DoMatch.Value).To(Equal("^[0-9]+$"))
+	Expect(unit[0].DoMatch.Config).To(Equal(map[string]interface{}{"ignoreCase": true}))
+	Expect(unit[0].DoMatch.DoMatch).To(BeNil())
+}
+
+func Test_NewRequestFieldMatchers_BuildView_IncludesConfigAndDoMatch(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.RequestFieldMatchers{
+		Matcher: matchers.JsonPath,
+		Value:   "$.id",
+		Config: map[string]interface{}{
+			"negate": true,
+		},
+		DoMatch: &models.RequestFieldMatchers{
+			Matcher: matchers.Regex,
+			Value:   "^[0-9]+$",
+		},
+	}
+
+	view := unit.BuildView()
+	Expect(view.Matcher).To(Equal("jsonpath"))
+	Expect(view.Config).To(HaveKeyWithValue("negate", true))
+	Expect(view.Config).To(HaveKeyWithValue("doMatch", v2.MatcherViewV5{
+		Matcher: "regex",
+		Value:   "^[0-9]+$",
+	}))
+	Expect(unit.Config).ToNot(HaveKey("doMatch"))
+}
+
 func Test_NewRequestMatcherResponsePairFromView_BuildsPair(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -168,6 +222,123 @@ func Test_NewRequestMatcherResponsePairFromView_StoresTemplated(t *testing.T) {
 	Expect(unit.Response.Templated).To(BeTrue())
 }
 
+func Test_NewRequestMatcherResponsePairFromView_BuildsResponseSequence(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewRequestMatcherResponsePairFromView(&v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{},
+		Responses: []v2.ResponseDetailsViewV5{
+			{
+				Status: 200,
+				Body:   "ok",
+				Weight: 4,
+			},
+			{
+				Status: 503,
+				Body:   "unavailable",
+			},
+		},
+		ResponseStrategy: "weighted",
+	})
+
+	Expect(unit.Responses).ToNot(BeNil())
+	Expect(unit.Responses.Strategy).To(Equal(models.ResponseStrategyWeighted))
+	Expect(unit.Responses.Responses).To(HaveLen(2))
+	Expect(unit.Responses.Weights).To(Equal([]int{4, 0}))
+	Expect(unit.Response.Body).To(Equal("ok"))
+}
+
+func Test_RequestMatcherResponsePair_BuildView_IncludesResponseSequence(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.RequestMatcherResponsePair{
+		Response: models.ResponseDetails{Status: 200, Body: "ok"},
+		Responses: models.NewResponseSequence(models.ResponseStrategyWeighted, []models.ResponseDetails{
+			{Status: 200, Body: "ok"},
+			{Status: 503, Body: "unavailable"},
+		}, []int{4}),
+	}
+
+	view := unit.BuildView()
+
+	Expect(view.ResponseStrategy).To(Equal("weighted"))
+	Expect(view.Responses).To(HaveLen(2))
+	Expect(view.Responses[0].Weight).To(Equal(4))
+	Expect(view.Responses[1].Weight).To(Equal(1))
+	Expect(view.Responses[1].Status).To(Equal(503))
+}
+
+func Test_NewRequestMatcherResponsePairFromView_StoresFault(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewRequestMatcherResponsePairFromView(&v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{},
+		Response: v2.ResponseDetailsViewV5{
+			Body: "body",
+			Fault: &v2.ResponseFaultView{
+				TruncateBodyAt:       2,
+				BandwidthBytesPerSec: 100,
+			},
+		},
+	})
+
+	Expect(unit.Response.Fault).To(Equal(&models.ResponseFault{
+		TruncateBodyAt:       2,
+		BandwidthBytesPerSec: 100,
+	}))
+
+	Expect(unit.BuildView().Response.Fault).To(Equal(&v2.ResponseFaultView{
+		TruncateBodyAt:       2,
+		BandwidthBytesPerSec: 100,
+	}))
+}
+
+func Test_NewRequestMatcherResponsePairFromView_StoresChunks(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewRequestMatcherResponsePairFromView(&v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{},
+		Response: v2.ResponseDetailsViewV5{
+			Chunks: []v2.ResponseChunkView{
+				{Body: "data: 1\n\n"},
+				{Body: "data: 2\n\n", Delay: 500},
+			},
+		},
+	})
+
+	Expect(unit.Response.Chunks).To(Equal([]models.ResponseChunk{
+		{Body: "data: 1\n\n"},
+		{Body: "data: 2\n\n", Delay: 500},
+	}))
+
+	Expect(unit.BuildView().Response.Chunks).To(Equal([]v2.ResponseChunkView{
+		{Body: "data: 1\n\n"},
+		{Body: "data: 2\n\n", Delay: 500},
+	}))
+}
+
+func Test_NewRequestMatcherResponsePairFromView_StoresDelays(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewRequestMatcherResponsePairFromView(&v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{},
+		Response: v2.ResponseDetailsViewV5{
+			FixedDelay:     100,
+			LogNormalDelay: &v2.LogNormalDelayView{Min: 10, Max: 500, Mean: 200, Median: 150},
+			UniformDelay:   &v2.UniformDelayView{Min: 10, Max: 20},
+		},
+	})
+
+	Expect(unit.Response.FixedDelay).To(Equal(100))
+	Expect(unit.Response.LogNormalDelay).To(Equal(&models.LogNormalDelay{Min: 10, Max: 500, Mean: 200, Median: 150}))
+	Expect(unit.Response.UniformDelay).To(Equal(&models.UniformDelay{Min: 10, Max: 20}))
+
+	view := unit.BuildView()
+	Expect(view.Response.FixedDelay).To(Equal(100))
+	Expect(view.Response.LogNormalDelay).To(Equal(&v2.LogNormalDelayView{Min: 10, Max: 500, Mean: 200, Median: 150}))
+	Expect(view.Response.UniformDelay).To(Equal(&v2.UniformDelayView{Min: 10, Max: 20}))
+}
+
 func Test_RequestMatcher_BuildRequestDetailsFromExactMatches_GeneratesARequestDetails(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -267,3 +438,51 @@ func Test_RequestMatcher_BuildRequestDetailsFromExactMatches_ReturnsNilIfMissing
 
 	Expect(unit.ToEagerlyCachable()).To(BeNil())
 }
+
+func Test_RequestMatcher_BuildRequestDetailsFromExactMatches_ReturnsNilIfAnExactMatchHasConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.RequestMatcher{
+		Body: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "body",
+			},
+		},
+		Destination: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "destination",
+			},
+		},
+		Method: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "method",
+				Config: map[string]interface{}{
+					"negate": true,
+				},
+			},
+		},
+		Path: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "path",
+			},
+		},
+		DeprecatedQuery: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "query",
+			},
+		},
+		Scheme: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   "scheme",
+			},
+		},
+	}
+
+	Expect(unit.ToEagerlyCachable()).To(BeNil())
+}
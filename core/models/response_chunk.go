@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// ResponseChunk is a part of a streamed response body. A response with chunks sends
+// them in place of its body, each once its delay, in milliseconds, has passed.
+type ResponseChunk struct {
+	Body  string
+	Delay int
+}
+
+func NewResponseChunksFromView(views []v2.ResponseChunkView) []ResponseChunk {
+	var chunks []ResponseChunk
+	for _, view := range views {
+		chunks = append(chunks, ResponseChunk{
+			Body:  view.Body,
+			Delay: view.Delay,
+		})
+	}
+	return chunks
+}
+
+func BuildResponseChunkViews(chunks []ResponseChunk) []v2.ResponseChunkView {
+	var views []v2.ResponseChunkView
+	for _, chunk := range chunks {
+		views = append(views, v2.ResponseChunkView{
+			Body:  chunk.Body,
+			Delay: chunk.Delay,
+		})
+	}
+	return views
+}
+
+// JoinResponseChunks returns the whole body that the chunks send
+func JoinResponseChunks(chunks []ResponseChunk) string {
+	var body string
+	for _, chunk := range chunks {
+		body += chunk.Body
+	}
+	return body
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"github.com/SpectoLabs/hoverfly/core/delay"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// LogNormalDelay is a response delay following a log-normal distribution
+type LogNormalDelay struct {
+	Min    int
+	Max    int
+	Mean   int
+	Median int
+}
+
+// UniformDelay is a response delay picked evenly between Min and Max
+type UniformDelay struct {
+	Min int
+	Max int
+}
+
+func NewLogNormalDelayFromView(view *v2.LogNormalDelayView) *LogNormalDelay {
+	if view == nil {
+		return nil
+	}
+
+	return &LogNormalDelay{
+		Min:    view.Min,
+		Max:    view.Max,
+		Mean:   view.Mean,
+		Median: view.Median,
+	}
+}
+
+func (this *LogNormalDelay) BuildView() *v2.LogNormalDelayView {
+	if this == nil {
+		return nil
+	}
+
+	return &v2.LogNormalDelayView{
+		Min:    this.Min,
+		Max:    this.Max,
+		Mean:   this.Mean,
+		Median: this.Median,
+	}
+}
+
+func NewUniformDelayFromView(view *v2.UniformDelayView) *UniformDelay {
+	if view == nil {
+		return nil
+	}
+
+	return &UniformDelay{
+		Min: view.Min,
+		Max: view.Max,
+	}
+}
+
+func (this *UniformDelay) BuildView() *v2.UniformDelayView {
+	if this == nil {
+		return nil
+	}
+
+	return &v2.UniformDelayView{
+		Min: this.Min,
+		Max: this.Max,
+	}
+}
+
+// HasDelay returns true if the response has a delay of its own,
+// in which case global delays do not apply to it
+func (this ResponseDetails) HasDelay() bool {
+	return this.FixedDelay > 0 || this.LogNormalDelay != nil || this.UniformDelay != nil
+}
+
+// GenerateDelay returns how long to wait before sending the response in milliseconds,
+// adding up every delay the response has
+func (this ResponseDetails) GenerateDelay() int {
+	responseDelay := this.FixedDelay
+
+	if this.LogNormalDelay != nil {
+		responseDelay += delay.NewLogNormalGenerator(
+			this.LogNormalDelay.Min,
+			this.LogNormalDelay.Max,
+			this.LogNormalDelay.Mean,
+			this.LogNormalDelay.Median,
+		).GenerateDelay()
+	}
+
+	if this.UniformDelay != nil {
+		responseDelay += delay.NewUniformGenerator(this.UniformDelay.Min, this.UniformDelay.Max).GenerateDelay()
+	}
+
+	return responseDelay
+}
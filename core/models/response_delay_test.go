@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func Test_ResponseDetails_HasDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(models.ResponseDetails{}.HasDelay()).To(BeFalse())
+	Expect(models.ResponseDetails{FixedDelay: 10}.HasDelay()).To(BeTrue())
+	Expect(models.ResponseDetails{LogNormalDelay: &models.LogNormalDelay{Mean: 20, Median: 10}}.HasDelay()).To(BeTrue())
+	Expect(models.ResponseDetails{UniformDelay: &models.UniformDelay{Min: 10, Max: 20}}.HasDelay()).To(BeTrue())
+}
+
+func Test_ResponseDetails_GenerateDelay_ReturnsFixedDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.ResponseDetails{FixedDelay: 150}
+
+	Expect(unit.GenerateDelay()).To(Equal(150))
+}
+
+func Test_ResponseDetails_GenerateDelay_ReturnsUniformDelayWithinRange(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.ResponseDetails{UniformDelay: &models.UniformDelay{Min: 100, Max: 200}}
+
+	for i := 0; i < 100; i++ {
+		Expect(unit.GenerateDelay()).To(BeNumerically(">=", 100))
+		Expect(unit.GenerateDelay()).To(BeNumerically("<=", 200))
+	}
+}
+
+func Test_ResponseDetails_GenerateDelay_AddsUpDelays(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.ResponseDetails{
+		FixedDelay:     1000,
+		LogNormalDelay: &models.LogNormalDelay{Min: 100, Max: 100, Mean: 100, Median: 100},
+		UniformDelay:   &models.UniformDelay{Min: 10, Max: 10},
+	}
+
+	Expect(unit.GenerateDelay()).To(Equal(1110))
+}
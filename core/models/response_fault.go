@@ -0,0 +1,49 @@
+package models
+
+import (
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// ResponseFault describes how a simulated response should misbehave
+// when it is written to the client
+type ResponseFault struct {
+	ConnectionReset      bool
+	EmptyResponse        bool
+	MalformedChunked     bool
+	TruncateBodyAt       int
+	BandwidthBytesPerSec int
+}
+
+func NewResponseFaultFromView(view *v2.ResponseFaultView) *ResponseFault {
+	if view == nil {
+		return nil
+	}
+
+	return &ResponseFault{
+		ConnectionReset:      view.ConnectionReset,
+		EmptyResponse:        view.EmptyResponse,
+		MalformedChunked:     view.MalformedChunked,
+		TruncateBodyAt:       view.TruncateBodyAt,
+		BandwidthBytesPerSec: view.BandwidthBytesPerSec,
+	}
+}
+
+func (this *ResponseFault) BuildView() *v2.ResponseFaultView {
+	if this == nil {
+		return nil
+	}
+
+	return &v2.ResponseFaultView{
+		ConnectionReset:      this.ConnectionReset,
+		EmptyResponse:        this.EmptyResponse,
+		MalformedChunked:     this.MalformedChunked,
+		TruncateBodyAt:       this.TruncateBodyAt,
+		BandwidthBytesPerSec: this.BandwidthBytesPerSec,
+	}
+}
+
+// ClosesConnection returns true if the fault drops the connection
+// before anything is written to the client
+func (this *ResponseFault) ClosesConnection() bool {
+	return this.ConnectionReset || this.EmptyResponse
+}
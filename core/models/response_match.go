@@ -0,0 +1,29 @@
+package models
+
+import (
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// ResponseMatch is the pair a request was matched with, and whether
+// the match was found in the cache
+type ResponseMatch struct {
+	Pair     *RequestMatcherResponsePair
+	CacheHit bool
+}
+
+func (this *ResponseMatch) BuildView() *v2.JournalMatchView {
+	if this == nil {
+		return nil
+	}
+
+	matchView := &v2.JournalMatchView{
+		Matched:  true,
+		CacheHit: this.CacheHit,
+	}
+	if this.Pair != nil {
+		pairView := this.Pair.BuildView()
+		matchView.Pair = &pairView
+	}
+
+	return matchView
+}
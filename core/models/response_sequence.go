@@ -0,0 +1,98 @@
+package models
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	ResponseStrategySequential = "sequential"
+	ResponseStrategyCycle      = "cycle"
+	ResponseStrategyRandom     = "random"
+	ResponseStrategyWeighted   = "weighted"
+)
+
+// ResponseSequence holds the responses of a pair that can return
+// a different response each time it is matched. The position is
+// shared between every copy of the pair, so it is kept behind a pointer.
+type ResponseSequence struct {
+	Strategy  string
+	Responses []ResponseDetails
+	Weights   []int
+	position  int
+	random    *rand.Rand
+	mutex     sync.Mutex
+}
+
+func NewResponseSequence(strategy string, responses []ResponseDetails, weights []int) *ResponseSequence {
+	if strategy == "" {
+		strategy = ResponseStrategySequential
+	}
+
+	return &ResponseSequence{
+		Strategy:  strategy,
+		Responses: responses,
+		Weights:   weights,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Next returns the response to serve for the current request and moves
+// the sequence along according to its strategy. A sequential sequence
+// keeps returning its last response once exhausted, a cycle sequence
+// starts again from the first one.
+func (this *ResponseSequence) Next() ResponseDetails {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	switch this.Strategy {
+	case ResponseStrategyRandom:
+		return this.Responses[this.random.Intn(len(this.Responses))]
+	case ResponseStrategyWeighted:
+		return this.Responses[this.weightedIndex()]
+	case ResponseStrategyCycle:
+		response := this.Responses[this.position]
+		this.position = (this.position + 1) % len(this.Responses)
+		return response
+	default:
+		response := this.Responses[this.position]
+		if this.position < len(this.Responses)-1 {
+			this.position++
+		}
+		return response
+	}
+}
+
+// Reset moves the sequence back to its first response
+func (this *ResponseSequence) Reset() {
+	this.mutex.Lock()
+	this.position = 0
+	this.mutex.Unlock()
+}
+
+// GetWeight returns the weight of the response at the given index,
+// responses without a weight count as 1
+func (this *ResponseSequence) GetWeight(index int) int {
+	if index < len(this.Weights) && this.Weights[index] > 0 {
+		return this.Weights[index]
+	}
+	return 1
+}
+
+func (this *ResponseSequence) weightedIndex() int {
+	total := 0
+	for i := range this.Responses {
+		total += this.GetWeight(i)
+	}
+
+	pick := this.random.Intn(total)
+	for i := range this.Responses {
+		pick -= this.GetWeight(i)
+		if pick < 0 {
+			return i
+		}
+	}
+
+	return len(this.Responses) - 1
+}
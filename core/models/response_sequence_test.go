@@ -0,0 +1,89 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+var sequenceResponses = []models.ResponseDetails{
+	{Status: 200, Body: "first"},
+	{Status: 200, Body: "second"},
+	{Status: 503, Body: "third"},
+}
+
+func Test_ResponseSequence_Next_ReturnsResponsesInOrderAndStaysOnTheLastOne(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewResponseSequence(models.ResponseStrategySequential, sequenceResponses, nil)
+
+	Expect(unit.Next().Body).To(Equal("first"))
+	Expect(unit.Next().Body).To(Equal("second"))
+	Expect(unit.Next().Body).To(Equal("third"))
+	Expect(unit.Next().Body).To(Equal("third"))
+}
+
+func Test_ResponseSequence_Next_DefaultsToSequential(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewResponseSequence("", sequenceResponses, nil)
+
+	Expect(unit.Strategy).To(Equal(models.ResponseStrategySequential))
+}
+
+func Test_ResponseSequence_Next_CycleStartsAgainFromTheFirstResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewResponseSequence(models.ResponseStrategyCycle, sequenceResponses, nil)
+
+	Expect(unit.Next().Body).To(Equal("first"))
+	Expect(unit.Next().Body).To(Equal("second"))
+	Expect(unit.Next().Body).To(Equal("third"))
+	Expect(unit.Next().Body).To(Equal("first"))
+}
+
+func Test_ResponseSequence_Next_RandomReturnsOneOfTheResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewResponseSequence(models.ResponseStrategyRandom, sequenceResponses, nil)
+
+	for i := 0; i < 20; i++ {
+		Expect([]string{"first", "second", "third"}).To(ContainElement(unit.Next().Body))
+	}
+}
+
+func Test_ResponseSequence_Next_WeightedFavoursHeavierResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewResponseSequence(models.ResponseStrategyWeighted, sequenceResponses[:2], []int{1000, 1})
+
+	counts := map[string]int{}
+	for i := 0; i < 100; i++ {
+		counts[unit.Next().Body]++
+	}
+
+	Expect(counts["first"]).To(BeNumerically(">", 90))
+}
+
+func Test_ResponseSequence_GetWeight_DefaultsToOne(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewResponseSequence(models.ResponseStrategyWeighted, sequenceResponses, []int{3})
+
+	Expect(unit.GetWeight(0)).To(Equal(3))
+	Expect(unit.GetWeight(1)).To(Equal(1))
+	Expect(unit.GetWeight(2)).To(Equal(1))
+}
+
+func Test_ResponseSequence_Reset_StartsFromTheFirstResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewResponseSequence(models.ResponseStrategySequential, sequenceResponses, nil)
+
+	unit.Next()
+	unit.Next()
+	unit.Reset()
+
+	Expect(unit.Next().Body).To(Equal("first"))
+}
@@ -11,9 +11,12 @@ import (
 
 type Simulation struct {
 	matchingPairs           []RequestMatcherResponsePair
+	webSockets              []WebSocket
 	ResponseDelays          ResponseDelays
 	ResponseDelaysLogNormal ResponseDelaysLogNormal
 	RWMutex                 sync.RWMutex
+	// version changes whenever the pairs or WebSockets change
+	version uint64
 }
 
 func NewSimulation() *Simulation {
@@ -37,6 +40,7 @@ func (this *Simulation) AddPair(pair *RequestMatcherResponsePair) bool {
 	}
 	if !duplicate {
 		this.matchingPairs = append(this.matchingPairs, *pair)
+		this.version++
 	}
 	this.RWMutex.Unlock()
 	return !duplicate
@@ -56,6 +60,7 @@ func (this *Simulation) AddPairWithOverwritingDuplicate(pair *RequestMatcherResp
 	if !duplicate {
 		this.matchingPairs = append(this.matchingPairs, *pair)
 	}
+	this.version++
 	this.RWMutex.Unlock()
 	return !duplicate
 }
@@ -63,6 +68,7 @@ func (this *Simulation) AddPairWithOverwritingDuplicate(pair *RequestMatcherResp
 func (this *Simulation) AddPairWithoutCheck(pair *RequestMatcherResponsePair) {
 	this.RWMutex.Lock()
 	this.matchingPairs = append(this.matchingPairs, *pair)
+	this.version++
 	this.RWMutex.Unlock()
 }
 
@@ -132,6 +138,7 @@ func (this *Simulation) AddPairInSequence(pair *RequestMatcherResponsePair, stat
 	}
 
 	this.matchingPairs = append(this.matchingPairs, *pair)
+	this.version++
 	this.RWMutex.Unlock()
 }
 
@@ -146,5 +153,102 @@ func (this *Simulation) DeleteMatchingPairs() {
 	var pairs []RequestMatcherResponsePair
 	this.RWMutex.Lock()
 	this.matchingPairs = pairs
+	this.version++
 	this.RWMutex.Unlock()
 }
+
+// AddWebSocket returns whether the WebSocket is added, which it is not when
+// there is already a WebSocket with the same request matcher
+func (this *Simulation) AddWebSocket(webSocket *WebSocket) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	for _, savedWebSocket := range this.webSockets {
+		if reflect.DeepEqual(webSocket.RequestMatcher, savedWebSocket.RequestMatcher) {
+			return false
+		}
+	}
+	this.webSockets = append(this.webSockets, *webSocket)
+	this.version++
+	return true
+}
+
+func (this *Simulation) AddWebSocketWithoutCheck(webSocket *WebSocket) {
+	this.RWMutex.Lock()
+	this.webSockets = append(this.webSockets, *webSocket)
+	this.version++
+	this.RWMutex.Unlock()
+}
+
+func (this *Simulation) GetWebSockets() []WebSocket {
+	this.RWMutex.RLock()
+	webSockets := this.webSockets
+	this.RWMutex.RUnlock()
+	return webSockets
+}
+
+func (this *Simulation) DeleteWebSockets() {
+	this.RWMutex.Lock()
+	this.webSockets = nil
+	this.version++
+	this.RWMutex.Unlock()
+}
+
+// Version changes whenever the pairs or WebSockets of the simulation change
+func (this *Simulation) Version() uint64 {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.version
+}
+
+func (this *Simulation) GetResponseDelays() ResponseDelays {
+	this.RWMutex.RLock()
+	responseDelays := this.ResponseDelays
+	this.RWMutex.RUnlock()
+	return responseDelays
+}
+
+func (this *Simulation) SetResponseDelays(responseDelays ResponseDelays) {
+	this.RWMutex.Lock()
+	this.ResponseDelays = responseDelays
+	this.RWMutex.Unlock()
+}
+
+func (this *Simulation) GetResponseDelaysLogNormal() ResponseDelaysLogNormal {
+	this.RWMutex.RLock()
+	responseDelaysLogNormal := this.ResponseDelaysLogNormal
+	this.RWMutex.RUnlock()
+	return responseDelaysLogNormal
+}
+
+func (this *Simulation) SetResponseDelaysLogNormal(responseDelaysLogNormal ResponseDelaysLogNormal) {
+	this.RWMutex.Lock()
+	this.ResponseDelaysLogNormal = responseDelaysLogNormal
+	this.RWMutex.Unlock()
+}
+
+// Replace swaps the pairs, WebSockets and global delays of the simulation for those of
+// another in one step, so that requests are never matched against a partial simulation
+func (this *Simulation) Replace(simulation *Simulation) {
+	pairs := simulation.GetMatchingPairs()
+	webSockets := simulation.GetWebSockets()
+	responseDelays := simulation.GetResponseDelays()
+	responseDelaysLogNormal := simulation.GetResponseDelaysLogNormal()
+	this.RWMutex.Lock()
+	this.matchingPairs = pairs
+	this.webSockets = webSockets
+	this.version++
+	this.ResponseDelays = responseDelays
+	this.ResponseDelaysLogNormal = responseDelaysLogNormal
+	this.RWMutex.Unlock()
+}
+
+// ResetResponseSequences moves every response sequence back to its first response
+func (this *Simulation) ResetResponseSequences() {
+	this.RWMutex.RLock()
+	for _, pair := range this.matchingPairs {
+		if pair.Responses != nil {
+			pair.Responses.Reset()
+		}
+	}
+	this.RWMutex.RUnlock()
+}
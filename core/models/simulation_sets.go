@@ -0,0 +1,235 @@
+package models
+
+import (
+	"sort"
+	"sync"
+)
+
+// SimulationSet is a named simulation that can be enabled, disabled and deleted without
+// affecting any other simulation. Sets with a higher priority are matched first.
+type SimulationSet struct {
+	Name       string
+	Enabled    bool
+	Priority   int
+	Simulation *Simulation
+}
+
+// SimulationSets holds the named simulations that are matched alongside the default simulation
+type SimulationSets struct {
+	sets  map[string]*SimulationSet
+	mutex sync.RWMutex
+	// version changes whenever a set is created, updated or deleted
+	version uint64
+	merged  *mergedSimulations
+}
+
+// mergedSimulations keeps the result of GetMatchingSimulations, along with what it was built
+// from, so that it is only built again once the sets or the simulations combined in it change
+type mergedSimulations struct {
+	setsVersion       uint64
+	defaultSimulation *Simulation
+	combined          []*Simulation
+	combinedVersions  []uint64
+	simulations       []*Simulation
+}
+
+func (this *mergedSimulations) isCurrent(setsVersion uint64, defaultSimulation *Simulation) bool {
+	if this == nil || this.setsVersion != setsVersion || this.defaultSimulation != defaultSimulation {
+		return false
+	}
+	for i, simulation := range this.combined {
+		if simulation.Version() != this.combinedVersions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func NewSimulationSets() *SimulationSets {
+	return &SimulationSets{
+		sets: map[string]*SimulationSet{},
+	}
+}
+
+// Get returns a copy of the set with the given name, or nil if there is no such set
+func (this *SimulationSets) Get(name string) *SimulationSet {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	set, ok := this.sets[name]
+	if !ok {
+		return nil
+	}
+	setCopy := *set
+	return &setCopy
+}
+
+// GetOrCreate returns the simulation of the set with the given name, creating
+// an enabled set with a priority of 0 if there is no such set
+func (this *SimulationSets) GetOrCreate(name string) *Simulation {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	set, ok := this.sets[name]
+	if !ok {
+		set = &SimulationSet{
+			Name:       name,
+			Enabled:    true,
+			Simulation: NewSimulation(),
+		}
+		this.sets[name] = set
+		this.version++
+	}
+	return set.Simulation
+}
+
+// GetAll returns a copy of each set, ordered by name
+func (this *SimulationSets) GetAll() []SimulationSet {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	sets := []SimulationSet{}
+	for _, set := range this.sets {
+		sets = append(sets, *set)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		return sets[i].Name < sets[j].Name
+	})
+	return sets
+}
+
+// Update changes whether the set with the given name is enabled and its priority, leaving
+// either unchanged when it is nil. It returns false if there is no such set.
+func (this *SimulationSets) Update(name string, enabled *bool, priority *int) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	set, ok := this.sets[name]
+	if !ok {
+		return false
+	}
+	if enabled != nil {
+		set.Enabled = *enabled
+	}
+	if priority != nil {
+		set.Priority = *priority
+	}
+	this.version++
+	return true
+}
+
+// Delete removes the set with the given name, returning false if there is no such set
+func (this *SimulationSets) Delete(name string) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if _, ok := this.sets[name]; !ok {
+		return false
+	}
+	delete(this.sets, name)
+	this.version++
+	return true
+}
+
+// ResetResponseSequences moves every response sequence of every set back to its first response
+func (this *SimulationSets) ResetResponseSequences() {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	for _, set := range this.sets {
+		set.Simulation.ResetResponseSequences()
+	}
+}
+
+// GetByPriority returns the default simulation and the simulation of each enabled
+// set, highest priority first. The default simulation has a priority of 0 and comes
+// before sets with the same priority, which are ordered by name.
+func (this *SimulationSets) GetByPriority(defaultSimulation *Simulation) []*Simulation {
+	simulations := []*Simulation{}
+	for _, group := range this.groupByPriority(defaultSimulation) {
+		simulations = append(simulations, group...)
+	}
+	return simulations
+}
+
+// GetMatchingSimulations returns a simulation for each priority, highest first. Sets
+// with the same priority are combined into one simulation, so that the matching
+// strategy chooses between their pairs and WebSockets as if they were in the same
+// simulation. The combined simulations are kept until a set or one of the simulations
+// combined in them changes, and must not be modified.
+func (this *SimulationSets) GetMatchingSimulations(defaultSimulation *Simulation) []*Simulation {
+	this.mutex.RLock()
+	setsVersion := this.version
+	merged := this.merged
+	this.mutex.RUnlock()
+
+	if merged.isCurrent(setsVersion, defaultSimulation) {
+		return merged.simulations
+	}
+
+	merged = this.merge(setsVersion, defaultSimulation)
+
+	this.mutex.Lock()
+	if this.version == setsVersion {
+		this.merged = merged
+	}
+	this.mutex.Unlock()
+
+	return merged.simulations
+}
+
+func (this *SimulationSets) merge(setsVersion uint64, defaultSimulation *Simulation) *mergedSimulations {
+	merged := &mergedSimulations{
+		setsVersion:       setsVersion,
+		defaultSimulation: defaultSimulation,
+		simulations:       []*Simulation{},
+	}
+
+	for _, group := range this.groupByPriority(defaultSimulation) {
+		if len(group) == 1 {
+			merged.simulations = append(merged.simulations, group[0])
+			continue
+		}
+
+		combined := NewSimulation()
+		for _, simulation := range group {
+			// The version is read first, so that a change made while copying builds it again
+			merged.combined = append(merged.combined, simulation)
+			merged.combinedVersions = append(merged.combinedVersions, simulation.Version())
+
+			for _, pair := range simulation.GetMatchingPairs() {
+				combined.AddPairWithoutCheck(&pair)
+			}
+			for _, webSocket := range simulation.GetWebSockets() {
+				combined.AddWebSocketWithoutCheck(&webSocket)
+			}
+		}
+		merged.simulations = append(merged.simulations, combined)
+	}
+
+	return merged
+}
+
+func (this *SimulationSets) groupByPriority(defaultSimulation *Simulation) [][]*Simulation {
+	enabled := []SimulationSet{{Simulation: defaultSimulation}}
+	for _, set := range this.GetAll() {
+		if set.Enabled {
+			enabled = append(enabled, set)
+		}
+	}
+
+	// The default simulation has no name, so it sorts before sets with the same priority
+	sort.SliceStable(enabled, func(i, j int) bool {
+		return enabled[i].Priority > enabled[j].Priority
+	})
+
+	groups := [][]*Simulation{}
+	for i, set := range enabled {
+		if i > 0 && set.Priority == enabled[i-1].Priority {
+			groups[len(groups)-1] = append(groups[len(groups)-1], set.Simulation)
+		} else {
+			groups = append(groups, []*Simulation{set.Simulation})
+		}
+	}
+	return groups
+}
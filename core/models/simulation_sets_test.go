@@ -0,0 +1,210 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func newSimulationWithPath(path string) *models.Simulation {
+	simulation := models.NewSimulation()
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   path,
+				},
+			},
+		},
+	})
+	return simulation
+}
+
+func addSimulationSet(unit *models.SimulationSets, name, path string, priority int) {
+	unit.GetOrCreate(name).AddPair(&newSimulationWithPath(path).GetMatchingPairs()[0])
+	unit.Update(name, nil, &priority)
+}
+
+func paths(simulations []*models.Simulation) [][]string {
+	simulationPaths := [][]string{}
+	for _, simulation := range simulations {
+		pairPaths := []string{}
+		for _, pair := range simulation.GetMatchingPairs() {
+			pairPaths = append(pairPaths, pair.RequestMatcher.Path[0].Value.(string))
+		}
+		simulationPaths = append(simulationPaths, pairPaths)
+	}
+	return simulationPaths
+}
+
+func Test_SimulationSets_GetOrCreate_CreatesAnEnabledSetOnce(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+
+	simulation := unit.GetOrCreate("payments")
+	Expect(unit.GetOrCreate("payments")).To(BeIdenticalTo(simulation))
+
+	set := unit.Get("payments")
+	Expect(set.Name).To(Equal("payments"))
+	Expect(set.Enabled).To(BeTrue())
+	Expect(set.Priority).To(Equal(0))
+	Expect(set.Simulation).To(BeIdenticalTo(simulation))
+
+	Expect(unit.Get("users")).To(BeNil())
+}
+
+func Test_SimulationSets_Update_ChangesOnlyTheSettingsThatAreGiven(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	unit.GetOrCreate("payments")
+
+	enabled := false
+	Expect(unit.Update("payments", &enabled, nil)).To(BeTrue())
+	Expect(unit.Get("payments").Enabled).To(BeFalse())
+	Expect(unit.Get("payments").Priority).To(Equal(0))
+
+	priority := 5
+	Expect(unit.Update("payments", nil, &priority)).To(BeTrue())
+	Expect(unit.Get("payments").Enabled).To(BeFalse())
+	Expect(unit.Get("payments").Priority).To(Equal(5))
+
+	Expect(unit.Update("users", &enabled, &priority)).To(BeFalse())
+}
+
+func Test_SimulationSets_Delete(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	unit.GetOrCreate("payments")
+
+	Expect(unit.Delete("payments")).To(BeTrue())
+	Expect(unit.Get("payments")).To(BeNil())
+	Expect(unit.Delete("payments")).To(BeFalse())
+}
+
+func Test_SimulationSets_GetAll_OrdersSetsByName(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	unit.GetOrCreate("users")
+	unit.GetOrCreate("payments")
+
+	sets := unit.GetAll()
+	Expect(sets).To(HaveLen(2))
+	Expect(sets[0].Name).To(Equal("payments"))
+	Expect(sets[1].Name).To(Equal("users"))
+}
+
+func Test_SimulationSets_GetByPriority_OrdersEnabledSetsByPriority(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	addSimulationSet(unit, "low", "/low", -1)
+	addSimulationSet(unit, "b-high", "/b-high", 10)
+	addSimulationSet(unit, "a-high", "/a-high", 10)
+	addSimulationSet(unit, "same", "/same", 0)
+	addSimulationSet(unit, "disabled", "/disabled", 20)
+
+	enabled := false
+	unit.Update("disabled", &enabled, nil)
+
+	simulations := unit.GetByPriority(newSimulationWithPath("/default"))
+
+	Expect(paths(simulations)).To(Equal([][]string{
+		{"/a-high"},
+		{"/b-high"},
+		{"/default"},
+		{"/same"},
+		{"/low"},
+	}))
+}
+
+func Test_SimulationSets_GetMatchingSimulations_CombinesSetsWithTheSamePriority(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	addSimulationSet(unit, "low", "/low", -1)
+	addSimulationSet(unit, "b-high", "/b-high", 10)
+	addSimulationSet(unit, "a-high", "/a-high", 10)
+	addSimulationSet(unit, "same", "/same", 0)
+
+	defaultSimulation := newSimulationWithPath("/default")
+	simulations := unit.GetMatchingSimulations(defaultSimulation)
+
+	Expect(paths(simulations)).To(Equal([][]string{
+		{"/a-high", "/b-high"},
+		{"/default", "/same"},
+		{"/low"},
+	}))
+}
+
+func Test_SimulationSets_GetMatchingSimulations_CombinesTheWebSocketsOfSetsWithTheSamePriority(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	addSimulationSet(unit, "same", "/same", 0)
+
+	webSocket := models.WebSocket{
+		RequestMatcher: newSimulationWithPath("/socket").GetMatchingPairs()[0].RequestMatcher,
+	}
+	unit.GetOrCreate("same").AddWebSocket(&webSocket)
+
+	simulations := unit.GetMatchingSimulations(newSimulationWithPath("/default"))
+
+	Expect(simulations).To(HaveLen(1))
+	Expect(simulations[0].GetWebSockets()).To(ConsistOf(webSocket))
+}
+
+func Test_SimulationSets_GetMatchingSimulations_ReturnsTheDefaultSimulationWithoutEnabledSets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	addSimulationSet(unit, "disabled", "/disabled", 0)
+
+	enabled := false
+	unit.Update("disabled", &enabled, nil)
+
+	defaultSimulation := newSimulationWithPath("/default")
+	simulations := unit.GetMatchingSimulations(defaultSimulation)
+
+	Expect(simulations).To(HaveLen(1))
+	Expect(simulations[0]).To(BeIdenticalTo(defaultSimulation))
+}
+
+func Test_SimulationSets_GetMatchingSimulations_KeepsCombinedSimulationsUntilTheyChange(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulationSets()
+	addSimulationSet(unit, "same", "/same", 0)
+	addSimulationSet(unit, "high", "/high", 10)
+
+	defaultSimulation := newSimulationWithPath("/default")
+	combined := unit.GetMatchingSimulations(defaultSimulation)[1]
+	Expect(unit.GetMatchingSimulations(defaultSimulation)[1]).To(BeIdenticalTo(combined))
+
+	defaultSimulation.AddPair(&newSimulationWithPath("/added").GetMatchingPairs()[0])
+	simulations := unit.GetMatchingSimulations(defaultSimulation)
+	Expect(simulations[1]).ToNot(BeIdenticalTo(combined))
+	Expect(paths(simulations)).To(Equal([][]string{
+		{"/high"},
+		{"/default", "/added", "/same"},
+	}))
+
+	priority := 10
+	unit.Update("same", nil, &priority)
+	Expect(paths(unit.GetMatchingSimulations(defaultSimulation))).To(Equal([][]string{
+		{"/high", "/same"},
+		{"/default", "/added"},
+	}))
+
+	unit.Delete("high")
+	Expect(paths(unit.GetMatchingSimulations(defaultSimulation))).To(Equal([][]string{
+		{"/same"},
+		{"/default", "/added"},
+	}))
+}
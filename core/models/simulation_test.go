@@ -15,7 +15,7 @@ func Test_Simulation_AddPair_CanAddAPairToTheArray(t *testing.T) {
 	unit := models.NewSimulation()
 
 	unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -23,7 +23,7 @@ func Test_Simulation_AddPair_CanAddAPairToTheArray(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{},
+		Response: models.ResponseDetails{},
 	})
 
 	Expect(unit.GetMatchingPairs()).To(HaveLen(1))
@@ -37,7 +37,7 @@ func Test_Simulation_AddPair_CanAddAFullPairToTheArray(t *testing.T) {
 	unit := models.NewSimulation()
 
 	unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Body: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -81,7 +81,7 @@ func Test_Simulation_AddPair_CanAddAFullPairToTheArray(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "testresponsebody",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -120,7 +120,7 @@ func Test_Simulation_AddPairInSequence_CanAddAFullPairToTheArray(t *testing.T) {
 	unit := models.NewSimulation()
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Body: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -164,7 +164,7 @@ func Test_Simulation_AddPairInSequence_CanAddAFullPairToTheArray(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "testresponsebody",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -203,7 +203,7 @@ func Test_Simulation_AddPairInSequence_CanSequence(t *testing.T) {
 	unit := models.NewSimulation()
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -211,7 +211,7 @@ func Test_Simulation_AddPairInSequence_CanSequence(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "1",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -219,7 +219,7 @@ func Test_Simulation_AddPairInSequence_CanSequence(t *testing.T) {
 	}, &state.State{State: map[string]string{}})
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -227,7 +227,7 @@ func Test_Simulation_AddPairInSequence_CanSequence(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "2",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -235,7 +235,7 @@ func Test_Simulation_AddPairInSequence_CanSequence(t *testing.T) {
 	}, &state.State{State: map[string]string{}})
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -243,7 +243,7 @@ func Test_Simulation_AddPairInSequence_CanSequence(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "3",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -280,7 +280,7 @@ func Test_Simulation_AddPairInSequence_CanBeUsedWithAddPair(t *testing.T) {
 	unit := models.NewSimulation()
 
 	unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -288,7 +288,7 @@ func Test_Simulation_AddPairInSequence_CanBeUsedWithAddPair(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "1",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -296,7 +296,7 @@ func Test_Simulation_AddPairInSequence_CanBeUsedWithAddPair(t *testing.T) {
 	})
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -304,7 +304,7 @@ func Test_Simulation_AddPairInSequence_CanBeUsedWithAddPair(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "2",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -335,7 +335,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 	state := state.NewState()
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -343,7 +343,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "1",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -351,7 +351,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -359,7 +359,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "2",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -367,7 +367,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -375,7 +375,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "different1",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -383,7 +383,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -391,7 +391,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceTwoDifferentSequences(t *testi
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "different2",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -435,7 +435,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 	state := state.NewState()
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -443,7 +443,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "1",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -451,7 +451,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -459,7 +459,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "2",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -467,7 +467,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -475,7 +475,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "different1",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -483,7 +483,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -491,7 +491,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "different2",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -499,7 +499,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -507,7 +507,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "third1",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -515,7 +515,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 	}, state)
 
 	unit.AddPairInSequence(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -523,7 +523,7 @@ func Test_Simulation_AddPairInSequence_CanSequenceThreeDifferentSequences(t *tes
 				},
 			},
 		},
-		models.ResponseDetails{
+		Response: models.ResponseDetails{
 			Body:    "third2",
 			Headers: map[string][]string{"testheader": {"testvalue"}},
 			Status:  200,
@@ -578,7 +578,7 @@ func Test_Simulation_AddPair_WillNotSaveDuplicates(t *testing.T) {
 	unit := models.NewSimulation()
 
 	isAdded := unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -586,13 +586,13 @@ func Test_Simulation_AddPair_WillNotSaveDuplicates(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{},
+		Response: models.ResponseDetails{},
 	})
 
 	Expect(isAdded).To(BeTrue())
 
 	isAdded = unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -600,7 +600,7 @@ func Test_Simulation_AddPair_WillNotSaveDuplicates(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{},
+		Response: models.ResponseDetails{},
 	})
 
 	Expect(isAdded).To(BeFalse())
@@ -613,7 +613,7 @@ func Test_Simulation_AddPair_WillSaveTwoWhenNotDuplicates(t *testing.T) {
 	unit := models.NewSimulation()
 
 	isAdded := unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -621,12 +621,12 @@ func Test_Simulation_AddPair_WillSaveTwoWhenNotDuplicates(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{},
+		Response: models.ResponseDetails{},
 	})
 	Expect(isAdded).To(BeTrue())
 
 	isAdded = unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -634,7 +634,7 @@ func Test_Simulation_AddPair_WillSaveTwoWhenNotDuplicates(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{},
+		Response: models.ResponseDetails{},
 	})
 	Expect(isAdded).To(BeTrue())
 
@@ -649,7 +649,7 @@ func Test_Simulation_GetMatchingPairs(t *testing.T) {
 	unit := models.NewSimulation()
 
 	unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -657,7 +657,7 @@ func Test_Simulation_GetMatchingPairs(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{},
+		Response: models.ResponseDetails{},
 	})
 
 	Expect(unit.GetMatchingPairs()).To(HaveLen(1))
@@ -670,7 +670,7 @@ func Test_Simulation_DeleteMatchingPairs(t *testing.T) {
 	unit := models.NewSimulation()
 
 	unit.AddPair(&models.RequestMatcherResponsePair{
-		models.RequestMatcher{
+		RequestMatcher: models.RequestMatcher{
 			Destination: []models.RequestFieldMatchers{
 				{
 					Matcher: matchers.Exact,
@@ -678,10 +678,103 @@ func Test_Simulation_DeleteMatchingPairs(t *testing.T) {
 				},
 			},
 		},
-		models.ResponseDetails{},
+		Response: models.ResponseDetails{},
 	})
 
 	unit.DeleteMatchingPairs()
 
 	Expect(unit.GetMatchingPairs()).To(HaveLen(0))
 }
+
+func Test_Simulation_Replace_SwapsThePairsAndDelaysForThoseOfAnotherSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+	unit.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "old",
+				},
+			},
+		},
+	})
+
+	replacement := models.NewSimulation()
+	replacement.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "new",
+				},
+			},
+		},
+	})
+	replacement.ResponseDelays = &models.ResponseDelayList{
+		{
+			UrlPattern: "new",
+			Delay:      100,
+		},
+	}
+
+	unit.Replace(replacement)
+
+	Expect(unit.GetMatchingPairs()).To(HaveLen(1))
+	Expect(unit.GetMatchingPairs()[0].RequestMatcher.Destination[0].Value).To(Equal("new"))
+	Expect(unit.ResponseDelays).To(Equal(replacement.ResponseDelays))
+}
+
+func Test_Simulation_ResetResponseSequences_ResetsEveryPairWithResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	responses := models.NewResponseSequence(models.ResponseStrategySequential, []models.ResponseDetails{
+		{Body: "first"},
+		{Body: "second"},
+	}, nil)
+
+	unit.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{},
+		Response:       models.ResponseDetails{Body: "first"},
+		Responses:      responses,
+	})
+	unit.AddPairWithoutCheck(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{},
+		Response:       models.ResponseDetails{Body: "single"},
+	})
+
+	responses.Next()
+	Expect(responses.Next().Body).To(Equal("second"))
+
+	unit.ResetResponseSequences()
+
+	Expect(responses.Next().Body).To(Equal("first"))
+}
+
+func Test_Simulation_AddWebSocket_DoesNotAddAWebSocketWithTheSameRequestMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	webSocket := &models.WebSocket{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/socket",
+				},
+			},
+		},
+	}
+
+	Expect(unit.AddWebSocket(webSocket)).To(BeTrue())
+	Expect(unit.AddWebSocket(webSocket)).To(BeFalse())
+	Expect(unit.GetWebSockets()).To(HaveLen(1))
+
+	unit.DeleteWebSockets()
+
+	Expect(unit.GetWebSockets()).To(BeEmpty())
+}
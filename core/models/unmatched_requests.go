@@ -0,0 +1,82 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// UnmatchedRequestsLimit is the number of distinct unmatched requests that are kept,
+// after which new unmatched requests are not added until the list is cleared
+const UnmatchedRequestsLimit = 1000
+
+// UnmatchedRequest is a distinct request that did not match the simulation
+type UnmatchedRequest struct {
+	Request     RequestDetails
+	ClosestMiss *ClosestMiss
+	Count       int
+	FirstSeen   time.Time
+	LastSeen    time.Time
+}
+
+// UnmatchedRequests counts the distinct requests that did not match the simulation,
+// keeping the closest miss of the most recent one. Requests are the same if they
+// have the same hash, so requests that only differ by their headers are counted together.
+type UnmatchedRequests struct {
+	requests map[string]*UnmatchedRequest
+	order    []string
+	mutex    sync.Mutex
+}
+
+func NewUnmatchedRequests() *UnmatchedRequests {
+	return &UnmatchedRequests{
+		requests: map[string]*UnmatchedRequest{},
+	}
+}
+
+func (this *UnmatchedRequests) Add(request RequestDetails, closestMiss *ClosestMiss) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	now := time.Now()
+	key := request.Hash()
+
+	if unmatched, ok := this.requests[key]; ok {
+		unmatched.Count++
+		unmatched.LastSeen = now
+		unmatched.ClosestMiss = closestMiss
+		return
+	}
+
+	if len(this.order) >= UnmatchedRequestsLimit {
+		return
+	}
+
+	this.requests[key] = &UnmatchedRequest{
+		Request:     request,
+		ClosestMiss: closestMiss,
+		Count:       1,
+		FirstSeen:   now,
+		LastSeen:    now,
+	}
+	this.order = append(this.order, key)
+}
+
+// GetAll returns a copy of each unmatched request, in the order they were first seen
+func (this *UnmatchedRequests) GetAll() []UnmatchedRequest {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	unmatchedRequests := []UnmatchedRequest{}
+	for _, key := range this.order {
+		unmatchedRequests = append(unmatchedRequests, *this.requests[key])
+	}
+	return unmatchedRequests
+}
+
+func (this *UnmatchedRequests) DeleteAll() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.requests = map[string]*UnmatchedRequest{}
+	this.order = nil
+}
@@ -0,0 +1,71 @@
+package models_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func Test_UnmatchedRequests_Add_CountsDistinctRequestsInTheOrderTheyWereFirstSeen(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewUnmatchedRequests()
+
+	unit.Add(models.RequestDetails{Method: "GET", Destination: "test.com", Path: "/one"}, nil)
+	unit.Add(models.RequestDetails{Method: "GET", Destination: "test.com", Path: "/two"}, nil)
+	unit.Add(models.RequestDetails{Method: "GET", Destination: "test.com", Path: "/one"}, &models.ClosestMiss{MissedFields: []string{"path"}})
+
+	unmatchedRequests := unit.GetAll()
+	Expect(unmatchedRequests).To(HaveLen(2))
+
+	Expect(unmatchedRequests[0].Request.Path).To(Equal("/one"))
+	Expect(unmatchedRequests[0].Count).To(Equal(2))
+	Expect(unmatchedRequests[0].ClosestMiss.MissedFields).To(Equal([]string{"path"}))
+	Expect(unmatchedRequests[0].LastSeen).ToNot(BeTemporally("<", unmatchedRequests[0].FirstSeen))
+
+	Expect(unmatchedRequests[1].Request.Path).To(Equal("/two"))
+	Expect(unmatchedRequests[1].Count).To(Equal(1))
+	Expect(unmatchedRequests[1].ClosestMiss).To(BeNil())
+}
+
+func Test_UnmatchedRequests_Add_IgnoresHeaders(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewUnmatchedRequests()
+
+	unit.Add(models.RequestDetails{Path: "/", Headers: map[string][]string{"User-Agent": {"one"}}}, nil)
+	unit.Add(models.RequestDetails{Path: "/", Headers: map[string][]string{"User-Agent": {"two"}}}, nil)
+
+	unmatchedRequests := unit.GetAll()
+	Expect(unmatchedRequests).To(HaveLen(1))
+	Expect(unmatchedRequests[0].Count).To(Equal(2))
+}
+
+func Test_UnmatchedRequests_Add_StopsAddingNewRequestsAtTheLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewUnmatchedRequests()
+
+	for i := 0; i < models.UnmatchedRequestsLimit; i++ {
+		unit.Add(models.RequestDetails{Path: "/", Body: strconv.Itoa(i)}, nil)
+	}
+	unit.Add(models.RequestDetails{Path: "/new"}, nil)
+	unit.Add(models.RequestDetails{Path: "/", Body: "0"}, nil)
+
+	unmatchedRequests := unit.GetAll()
+	Expect(unmatchedRequests).To(HaveLen(models.UnmatchedRequestsLimit))
+	Expect(unmatchedRequests[0].Count).To(Equal(2))
+}
+
+func Test_UnmatchedRequests_DeleteAll(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewUnmatchedRequests()
+	unit.Add(models.RequestDetails{Path: "/"}, nil)
+
+	unit.DeleteAll()
+
+	Expect(unit.GetAll()).To(BeEmpty())
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/base64"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// WebSocket accepts the WebSocket handshakes its request matcher matches, sends
+// its open frames, and then answers each frame from the client with the frames
+// of the first message that matches it
+type WebSocket struct {
+	RequestMatcher RequestMatcher
+	Headers        map[string][]string
+	OnOpen         []WebSocketFrame
+	OnMessage      []WebSocketMessage
+}
+
+type WebSocketMessage struct {
+	Body   []RequestFieldMatchers
+	Frames []WebSocketFrame
+}
+
+// WebSocketFrame is a frame sent to the client once it has waited for its delay,
+// in milliseconds, after the frame before it
+type WebSocketFrame struct {
+	Body   string
+	Binary bool
+	Delay  int
+}
+
+func NewWebSocketFromView(view *v2.WebSocketViewV5) *WebSocket {
+	webSocket := &WebSocket{
+		RequestMatcher: NewRequestMatcherFromView(&view.RequestMatcher),
+		Headers:        view.Headers,
+		OnOpen:         newWebSocketFramesFromView(view.OnOpen),
+	}
+
+	for _, messageView := range view.OnMessage {
+		webSocket.OnMessage = append(webSocket.OnMessage, WebSocketMessage{
+			Body:   NewRequestFieldMatchersFromView(messageView.Body),
+			Frames: newWebSocketFramesFromView(messageView.Frames),
+		})
+	}
+
+	return webSocket
+}
+
+func newWebSocketFramesFromView(views []v2.WebSocketFrameViewV5) []WebSocketFrame {
+	var frames []WebSocketFrame
+	for _, view := range views {
+		body := view.Body
+		if view.Binary {
+			decoded, _ := base64.StdEncoding.DecodeString(view.Body)
+			body = string(decoded)
+		}
+
+		frames = append(frames, WebSocketFrame{
+			Body:   body,
+			Binary: view.Binary,
+			Delay:  view.Delay,
+		})
+	}
+	return frames
+}
+
+func (this *WebSocket) BuildView() v2.WebSocketViewV5 {
+	view := v2.WebSocketViewV5{
+		RequestMatcher: this.RequestMatcher.BuildView(),
+		Headers:        this.Headers,
+		OnOpen:         buildWebSocketFrameViews(this.OnOpen),
+	}
+
+	for _, message := range this.OnMessage {
+		var body []v2.MatcherViewV5
+		for _, matcher := range message.Body {
+			body = append(body, matcher.BuildView())
+		}
+
+		view.OnMessage = append(view.OnMessage, v2.WebSocketMessageViewV5{
+			Body:   body,
+			Frames: buildWebSocketFrameViews(message.Frames),
+		})
+	}
+
+	return view
+}
+
+func buildWebSocketFrameViews(frames []WebSocketFrame) []v2.WebSocketFrameViewV5 {
+	views := []v2.WebSocketFrameViewV5{}
+	for _, frame := range frames {
+		body := frame.Body
+		if frame.Binary {
+			body = base64.StdEncoding.EncodeToString([]byte(frame.Body))
+		}
+
+		views = append(views, v2.WebSocketFrameViewV5{
+			Body:   body,
+			Binary: frame.Binary,
+			Delay:  frame.Delay,
+		})
+	}
+	return views
+}
+
+// GetWebSocketFrameMatchValue returns the value that message body matchers match a frame
+// from the client against, which is the base64 encoded body of a binary frame
+func GetWebSocketFrameMatchValue(body []byte, binary bool) string {
+	if binary {
+		return base64.StdEncoding.EncodeToString(body)
+	}
+	return string(body)
+}
@@ -0,0 +1,77 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func Test_NewWebSocketFromView_DecodesBinaryFrames(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewWebSocketFromView(&v2.WebSocketViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/socket")},
+		},
+		OnOpen: []v2.WebSocketFrameViewV5{
+			{Body: "hello", Delay: 10},
+			{Body: "AAEC", Binary: true},
+		},
+		OnMessage: []v2.WebSocketMessageViewV5{
+			{
+				Body:   []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, "ping*")},
+				Frames: []v2.WebSocketFrameViewV5{{Body: "pong"}},
+			},
+		},
+	})
+
+	Expect(unit.RequestMatcher.Path).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/socket"}}))
+	Expect(unit.OnOpen).To(Equal([]models.WebSocketFrame{
+		{Body: "hello", Delay: 10},
+		{Body: string([]byte{0, 1, 2}), Binary: true},
+	}))
+	Expect(unit.OnMessage).To(HaveLen(1))
+	Expect(unit.OnMessage[0].Body).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "ping*"}}))
+	Expect(unit.OnMessage[0].Frames).To(Equal([]models.WebSocketFrame{{Body: "pong"}}))
+}
+
+func Test_WebSocket_BuildView_EncodesBinaryFrames(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.WebSocket{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/socket"}},
+		},
+		Headers: map[string][]string{"Sec-Websocket-Protocol": {"chat"}},
+		OnMessage: []models.WebSocketMessage{
+			{
+				Body: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "AAEC"}},
+				Frames: []models.WebSocketFrame{
+					{Body: string([]byte{0, 1, 2}), Binary: true, Delay: 5},
+				},
+			},
+		},
+	}
+
+	view := unit.BuildView()
+
+	Expect(view.RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/socket")}))
+	Expect(view.Headers).To(Equal(map[string][]string{"Sec-Websocket-Protocol": {"chat"}}))
+	Expect(view.OnOpen).To(BeEmpty())
+	Expect(view.OnMessage).To(Equal([]v2.WebSocketMessageViewV5{
+		{
+			Body:   []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "AAEC")},
+			Frames: []v2.WebSocketFrameViewV5{{Body: "AAEC", Binary: true, Delay: 5}},
+		},
+	}))
+}
+
+func Test_GetWebSocketFrameMatchValue_EncodesBinaryFrames(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(models.GetWebSocketFrameMatchValue([]byte("ping"), false)).To(Equal("ping"))
+	Expect(models.GetWebSocketFrameMatchValue([]byte{0, 1, 2}, true)).To(Equal("AAEC"))
+}
@@ -63,6 +63,40 @@ func (this CaptureMode) Process(request *http.Request, details models.RequestDet
 		return ReturnErrorAndLog(request, err, &pair, "There was an error when forwarding the request to the intended destination", Capture)
 	}
 
+	return captureResponse(this.Hoverfly, request, &pair, response, this.Arguments, Capture)
+}
+
+// hoverflySaver adds captured requests and responses to the simulation
+type hoverflySaver interface {
+	Save(*models.RequestDetails, *models.ResponseDetails, *ModeArguments) error
+}
+
+// captureResponse adds the request of a pair and the response from its destination to the
+// simulation. An event stream is captured as it is passed through to the client, and is
+// added once it ends.
+func captureResponse(hoverfly hoverflySaver, request *http.Request, pair *models.RequestResponsePair, response *http.Response, arguments ModeArguments, mode string) (*http.Response, error) {
+	if arguments.Headers == nil {
+		arguments.Headers = []string{}
+	}
+
+	if isEventStream(response) {
+		response.Body = newCapturedStreamBody(response.Body, func(chunks []models.ResponseChunk) {
+			responseObj := &models.ResponseDetails{
+				Status:  response.StatusCode,
+				Headers: util.GetResponseHeaders(response),
+				Chunks:  chunks,
+			}
+			if err := savePair(hoverfly, pair, responseObj, arguments, mode); err != nil {
+				log.WithFields(log.Fields{
+					"error":   err.Error(),
+					"mode":    mode,
+					"request": GetRequestLogFields(&pair.Request),
+				}).Error("There was an error when saving captured event stream")
+			}
+		})
+		return response, nil
+	}
+
 	respBody, _ := util.GetResponseBody(response)
 	respHeaders := util.GetResponseHeaders(response)
 
@@ -72,21 +106,25 @@ func (this CaptureMode) Process(request *http.Request, details models.RequestDet
 		Headers: respHeaders,
 	}
 
-	if this.Arguments.Headers == nil {
-		this.Arguments.Headers = []string{}
+	if err := savePair(hoverfly, pair, responseObj, arguments, mode); err != nil {
+		return ReturnErrorAndLog(request, err, pair, "There was an error when saving request and response", mode)
 	}
 
+	return response, nil
+}
+
+func savePair(hoverfly hoverflySaver, pair *models.RequestResponsePair, response *models.ResponseDetails, arguments ModeArguments, mode string) error {
 	// saving response body with request/response meta to cache
-	err = this.Hoverfly.Save(&pair.Request, responseObj, &this.Arguments)
+	err := hoverfly.Save(&pair.Request, response, &arguments)
 	if err != nil {
-		return ReturnErrorAndLog(request, err, &pair, "There was an error when saving request and response", Capture)
+		return err
 	}
 
 	log.WithFields(log.Fields{
-		"mode":     Capture,
+		"mode":     mode,
 		"request":  GetRequestLogFields(&pair.Request),
 		"response": GetResponseLogFields(&pair.Response),
 	}).Info("request and response captured")
 
-	return response, nil
+	return nil
 }
@@ -0,0 +1,109 @@
+package modes
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+)
+
+// eventSeparators end an event in a stream of Server-Sent Events
+var eventSeparators = [][]byte{[]byte("\r\n\r\n"), []byte("\n\n")}
+
+func isEventStream(response *http.Response) bool {
+	return strings.Contains(response.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// CapturedStreamBody passes a streamed response body through to the client while
+// recording the events it is made of. Each event becomes a chunk, delayed by the time
+// since the event before it. The chunks are saved once the stream ends.
+type CapturedStreamBody struct {
+	io.ReadCloser
+	last    time.Time
+	pending []byte
+	chunks  []models.ResponseChunk
+	save    func([]models.ResponseChunk)
+	once    sync.Once
+}
+
+func newCapturedStreamBody(body io.ReadCloser, save func([]models.ResponseChunk)) *CapturedStreamBody {
+	return &CapturedStreamBody{
+		ReadCloser: body,
+		last:       time.Now(),
+		save:       save,
+	}
+}
+
+func (b *CapturedStreamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.record(p[:n])
+	}
+	if err != nil {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *CapturedStreamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+func (b *CapturedStreamBody) record(data []byte) {
+	b.pending = append(b.pending, data...)
+	for {
+		end := eventEnd(b.pending)
+		if end < 0 {
+			return
+		}
+		b.addChunk(string(b.pending[:end]))
+		b.pending = b.pending[end:]
+	}
+}
+
+func (b *CapturedStreamBody) addChunk(body string) {
+	now := time.Now()
+	b.chunks = append(b.chunks, models.ResponseChunk{
+		Body:  body,
+		Delay: int(now.Sub(b.last) / time.Millisecond),
+	})
+	b.last = now
+}
+
+// finish saves the chunks once, keeping whatever followed the last complete event as a chunk of its own
+func (b *CapturedStreamBody) finish() {
+	b.once.Do(func() {
+		if len(b.pending) > 0 {
+			b.addChunk(string(b.pending))
+			b.pending = nil
+		}
+		b.save(b.chunks)
+	})
+}
+
+// eventEnd returns where the first event in data ends, including its separator, or -1
+func eventEnd(data []byte) int {
+	end := -1
+	for _, separator := range eventSeparators {
+		if i := bytes.Index(data, separator); i >= 0 && (end < 0 || i+len(separator) < end) {
+			end = i + len(separator)
+		}
+	}
+	return end
+}
+
+// IsCapturedStream returns true if the response is an event stream that is still being captured
+func IsCapturedStream(response *http.Response) bool {
+	if response == nil {
+		return false
+	}
+
+	_, ok := response.Body.(*CapturedStreamBody)
+	return ok
+}
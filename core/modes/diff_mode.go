@@ -15,6 +15,8 @@ import (
 	"io"
 	"io/ioutil"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
@@ -33,6 +35,7 @@ type DiffMode struct {
 	Hoverfly   HoverflyDiff
 	DiffReport v2.DiffReport
 	Arguments  ModeArguments
+	Rules      *DiffRules
 }
 
 func (this *DiffMode) View() v2.ModeView {
@@ -40,12 +43,16 @@ func (this *DiffMode) View() v2.ModeView {
 		Mode: Diff,
 		Arguments: v2.ModeArgumentsView{
 			Headers:          this.Arguments.Headers,
+			DiffRules:        this.Arguments.DiffRules,
 		},
 	}
 }
 
+// SetArguments sets the headers to leave out of the diff and the diff rules, which must
+// have been checked with NewDiffRules
 func (this *DiffMode) SetArguments(arguments ModeArguments) {
 	this.Arguments = arguments
+	this.Rules, _ = NewDiffRules(arguments.DiffRules)
 }
 
 //TODO: We should only need one of these two parameters
@@ -103,21 +110,65 @@ func (this *DiffMode) Process(request *http.Request, details models.RequestDetai
 
 func (this *DiffMode) diffResponse(expected *models.ResponseDetails, actual *models.ResponseDetails, headersBlacklist []string) {
 	if expected.Status != 0 && expected.Status != actual.Status {
-		this.addEntry("status", expected.Status, actual.Status)
+		this.addEntry([]string{"status"}, expected.Status, actual.Status)
 	}
 	this.headerDiff(expected.Headers, actual.Headers, headersBlacklist)
 	this.bodyDiff(expected, actual)
 }
 
-func (this *DiffMode) addEntry(parameterName string, expected interface{}, actual interface{}) {
+// addEntry reports a difference in the field at the path, along with the JSON Patch operation
+// that replaces the expected value with the actual value
+func (this *DiffMode) addEntry(path []string, expected interface{}, actual interface{}) {
+	this.addDiffEntry(path, expected, actual)
+	this.addPatch("replace", path, actual)
+}
+
+// addMissing reports a field of the expected response that the actual response does not have
+func (this *DiffMode) addMissing(path []string, expected interface{}) {
+	this.addDiffEntry(path, expected, nil)
+	this.DiffReport.Patch = append(this.DiffReport.Patch, v2.JsonPatchOperation{
+		Op:   "remove",
+		Path: jsonPointer(path),
+	})
+}
+
+// addUnexpected adds a field that only the actual response has to the patch. Only the fields of the
+// expected response are compared, so the field is not reported as a difference.
+func (this *DiffMode) addUnexpected(path []string, actual interface{}) {
+	this.addPatch("add", path, actual)
+}
+
+func (this *DiffMode) addDiffEntry(path []string, expected interface{}, actual interface{}) {
 	this.DiffReport.DiffEntries = append(this.DiffReport.DiffEntries,
 		v2.DiffReportEntry{
-			Field:    parameterName,
+			Field:    strings.Join(path, "/"),
 			Expected: nullOrValue(expected),
 			Actual:   nullOrValue(actual),
 		})
 }
 
+func (this *DiffMode) addPatch(op string, path []string, value interface{}) {
+	operation := v2.JsonPatchOperation{
+		Op:   op,
+		Path: jsonPointer(path),
+	}
+	if encoded, err := json.Marshal(value); err == nil {
+		operation.Value = encoded
+	}
+	this.DiffReport.Patch = append(this.DiffReport.Patch, operation)
+}
+
+// jsonPointer returns the RFC 6901 JSON Pointer of a path
+func jsonPointer(path []string) string {
+	var pointer string
+	for _, segment := range path {
+		segment = strings.Replace(segment, "~", "~0", -1)
+		segment = strings.Replace(segment, "/", "~1", -1)
+		pointer += "/" + segment
+	}
+	return pointer
+}
+
 func nullOrValue(value interface{}) string {
 	if value == nil {
 		return "null"
@@ -127,47 +178,105 @@ func nullOrValue(value interface{}) string {
 
 func (this *DiffMode) headerDiff(expected map[string][]string, actual map[string][]string, headersBlacklist []string) bool {
 	same := true
-	for k := range expected {
-		shouldContinue := false
-		for _, header := range headersBlacklist {
-			if k == header || header == "*" {
-				shouldContinue = true
-			}
-		}
-		if shouldContinue {
+	for _, k := range sortedHeaderNames(expected) {
+		if isBlacklisted(k, headersBlacklist) {
 			continue
 		}
 		if _, ok := actual[k]; !ok {
-			this.addEntry("header/"+k, expected[k], nil)
+			this.addMissing([]string{"header", k}, expected[k])
 			same = false
-		} else if !reflect.DeepEqual(expected[k], actual[k]) {
-			this.addEntry("header/"+k, expected[k], actual[k])
+		} else if !this.headerValuesEqual(expected[k], actual[k]) {
+			this.addEntry([]string{"header", k}, expected[k], actual[k])
 			same = false
 		}
 
 	}
+
+	for _, k := range sortedHeaderNames(actual) {
+		if _, ok := expected[k]; !ok && !isBlacklisted(k, headersBlacklist) {
+			this.addUnexpected([]string{"header", k}, actual[k])
+		}
+	}
 	return same
 }
 
+func isBlacklisted(header string, headersBlacklist []string) bool {
+	for _, blacklisted := range headersBlacklist {
+		if header == blacklisted || blacklisted == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *DiffMode) headerValuesEqual(expected, actual []string) bool {
+	if this.Rules == nil {
+		return reflect.DeepEqual(expected, actual)
+	}
+
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if !this.Rules.stringsEqual(expected[i], actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedHeaderNames(headers map[string][]string) []string {
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (this *DiffMode) bodyDiff(expected *models.ResponseDetails, actual *models.ResponseDetails) bool {
 	var expectedJson, actualJson interface{}
 
 	err := unmarshalResponseToInterface(expected, &expectedJson)
 	if err != nil {
-		return this.doDeepEqual(expected.Body, actual.Body)
+		return this.textDiff(expected.Body, actual.Body)
 	}
 
 	err = unmarshalResponseToInterface(actual, &actualJson)
 	if err != nil {
-		return this.doDeepEqual(expected.Body, actual.Body)
+		return this.textDiff(expected.Body, actual.Body)
+	}
+
+	expectedObject, expectedIsObject := expectedJson.(map[string]interface{})
+	actualObject, actualIsObject := actualJson.(map[string]interface{})
+	if expectedIsObject && actualIsObject {
+		return this.JsonDiff("body", expectedObject, actualObject)
+	}
+
+	if !this.Rules.jsonEqual(nil, expectedJson, actualJson) {
+		this.addEntry([]string{"body"}, expectedJson, actualJson)
+		return false
+	}
+	return true
+}
+
+// textDiff compares bodies that are not JSON, as XML when there are diff rules
+func (this *DiffMode) textDiff(expected string, actual string) bool {
+	if this.Rules != nil {
+		if equal, isXml := this.Rules.xmlEqual(expected, actual); isXml {
+			if !equal {
+				this.addEntry([]string{"body"}, expected, actual)
+			}
+			return equal
+		}
 	}
 
-	return this.JsonDiff("body", expectedJson.(map[string]interface{}), actualJson.(map[string]interface{}))
+	return this.doDeepEqual(expected, actual)
 }
 
 func (this *DiffMode) doDeepEqual(expected string, actual string) bool {
-	if !reflect.DeepEqual(expected, actual) {
-		this.addEntry("body", expected, actual)
+	if !reflect.DeepEqual(this.Rules.normalise(expected), this.Rules.normalise(actual)) {
+		this.addEntry([]string{"body"}, expected, actual)
 		return false
 	}
 	return true
@@ -242,12 +351,24 @@ func decompress(body []byte, encodings []string) ([]byte, error) {
 	return body, err
 }
 
+// JsonDiff reports the differences in the keys of the expected object, where the field of the
+// objects is the prefix, and adds the keys only the actual object has to the patch. Ignored
+// JSONPaths are relative to the objects.
 func (this *DiffMode) JsonDiff(prefix string, expected map[string]interface{}, actual map[string]interface{}) bool {
+	return this.jsonDiff(strings.Split(prefix, "/"), nil, expected, actual)
+}
+
+func (this *DiffMode) jsonDiff(field []string, path []string, expected map[string]interface{}, actual map[string]interface{}) bool {
 	same := true
-	for k := range expected {
-		param := prefix + "/" + k
+	for _, k := range sortedKeys(expected) {
+		childPath := appendPath(path, k)
+		if this.Rules.isIgnored(childPath) {
+			continue
+		}
+
+		param := append(append([]string{}, field...), childPath...)
 		if _, ok := actual[k]; !ok {
-			this.addEntry(param, expected[k], nil)
+			this.addMissing(param, expected[k])
 			same = false
 		} else if reflect.TypeOf(expected[k]) != reflect.TypeOf(actual[k]) {
 			this.addEntry(param, expected[k], actual[k])
@@ -255,22 +376,33 @@ func (this *DiffMode) JsonDiff(prefix string, expected map[string]interface{}, a
 		} else {
 			switch expected[k].(type) {
 			default:
-				if expected[k] != actual[k] {
+				if !this.Rules.jsonEqual(childPath, expected[k], actual[k]) {
 					this.addEntry(param, expected[k], actual[k])
 					same = false
 				}
 			case map[string]interface{}:
-				if !this.JsonDiff(param, expected[k].(map[string]interface{}), actual[k].(map[string]interface{})) {
-					same = false
-				}
-			case []interface{}:
-				if !reflect.DeepEqual(expected[k], actual[k]) {
-					this.addEntry(param, expected[k], actual[k])
+				if !this.jsonDiff(field, childPath, expected[k].(map[string]interface{}), actual[k].(map[string]interface{})) {
 					same = false
 				}
 			}
 		}
 	}
 
+	for _, k := range sortedKeys(actual) {
+		childPath := appendPath(path, k)
+		if _, ok := expected[k]; !ok && !this.Rules.isIgnored(childPath) {
+			this.addUnexpected(append(append([]string{}, field...), childPath...), actual[k])
+		}
+	}
+
 	return same
 }
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,452 @@
+package modes
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ChrisTrenkamp/goxpath"
+	"github.com/ChrisTrenkamp/goxpath/tree"
+	"github.com/ChrisTrenkamp/goxpath/tree/xmltree"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// DiffRules are how diff mode compares the simulated and actual responses. A nil DiffRules
+// compares values exactly.
+type DiffRules struct {
+	ignoreJsonPaths  [][]jsonPathSegment
+	ignoreXpaths     []goxpath.XPathExec
+	normalisers      []diffNormaliser
+	ignoreArrayOrder bool
+	numericTolerance float64
+}
+
+type diffNormaliser struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewDiffRules compiles the diff rules of a mode, returning an error if a path or pattern is invalid
+func NewDiffRules(view *v2.DiffRulesView) (*DiffRules, error) {
+	if view == nil {
+		return nil, nil
+	}
+
+	rules := &DiffRules{
+		ignoreArrayOrder: view.IgnoreArrayOrder,
+		numericTolerance: view.NumericTolerance,
+	}
+
+	if view.NumericTolerance < 0 {
+		return nil, fmt.Errorf("Numeric tolerance cannot be negative")
+	}
+
+	for _, path := range view.Ignore {
+		if strings.HasPrefix(path, "$") {
+			segments, err := parseJsonPath(path)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid JSONPath %s: %s", path, err.Error())
+			}
+			rules.ignoreJsonPaths = append(rules.ignoreJsonPaths, segments)
+		} else {
+			xpath, err := goxpath.Parse(path)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid XPath %s: %s", path, err.Error())
+			}
+			rules.ignoreXpaths = append(rules.ignoreXpaths, xpath)
+		}
+	}
+
+	for _, normalise := range view.Normalise {
+		pattern, err := regexp.Compile(normalise.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid regex %s: %s", normalise.Pattern, err.Error())
+		}
+		rules.normalisers = append(rules.normalisers, diffNormaliser{
+			pattern:     pattern,
+			replacement: normalise.Replacement,
+		})
+	}
+
+	return rules, nil
+}
+
+// jsonPathSegment is a step of a JSONPath expression, matching a key or an index
+type jsonPathSegment struct {
+	name string
+	// any matches every key or index
+	any bool
+	// descendant allows any number of keys or indexes before the segment
+	descendant bool
+}
+
+// parseJsonPath reads a JSONPath expression made of keys (.name or ['name']), indexes ([0]),
+// wildcards (.* or [*]) and recursive descent (..name)
+func parseJsonPath(path string) ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+
+	i := 1
+	for i < len(path) {
+		segment := jsonPathSegment{}
+
+		if strings.HasPrefix(path[i:], "..") {
+			segment.descendant = true
+			i += 2
+		} else if path[i] == '.' {
+			i++
+		} else if path[i] != '[' {
+			return nil, fmt.Errorf("unexpected %q at %d", path[i], i)
+		}
+
+		if i < len(path) && path[i] == '[' {
+			end := strings.Index(path[i:], "]")
+			if end < 0 {
+				return nil, fmt.Errorf("missing ] after %d", i)
+			}
+			selector := path[i+1 : i+end]
+			i += end + 1
+
+			if selector == "*" {
+				segment.any = true
+			} else if len(selector) > 1 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				segment.name = selector[1 : len(selector)-1]
+			} else if _, err := strconv.Atoi(selector); err == nil {
+				segment.name = selector
+			} else {
+				return nil, fmt.Errorf("unsupported selector [%s]", selector)
+			}
+		} else {
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			segment.name = path[i : i+end]
+			i += end
+
+			if segment.name == "" {
+				return nil, fmt.Errorf("missing name at %d", i)
+			}
+			if segment.name == "*" {
+				segment.any = true
+			}
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+func matchesJsonPath(segments []jsonPathSegment, path []string) bool {
+	if len(segments) == 0 {
+		return len(path) == 0
+	}
+
+	segment := segments[0]
+	for skipped := 0; skipped < len(path); skipped++ {
+		if segment.any || segment.name == path[skipped] {
+			if matchesJsonPath(segments[1:], path[skipped+1:]) {
+				return true
+			}
+		}
+		if !segment.descendant {
+			break
+		}
+	}
+
+	return false
+}
+
+// isIgnored returns true if the value at the path of a JSON body is ignored
+func (this *DiffRules) isIgnored(path []string) bool {
+	if this == nil {
+		return false
+	}
+
+	for _, segments := range this.ignoreJsonPaths {
+		if matchesJsonPath(segments, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (this *DiffRules) normalise(value string) string {
+	if this == nil {
+		return value
+	}
+
+	for _, normaliser := range this.normalisers {
+		value = normaliser.pattern.ReplaceAllString(value, normaliser.replacement)
+	}
+
+	return value
+}
+
+func (this *DiffRules) numbersEqual(expected, actual float64) bool {
+	if this == nil {
+		return expected == actual
+	}
+
+	return math.Abs(expected-actual) <= this.numericTolerance
+}
+
+// stringsEqual compares normalised strings, or numbers within the tolerance when both are numbers
+func (this *DiffRules) stringsEqual(expected, actual string) bool {
+	if this == nil {
+		return expected == actual
+	}
+
+	if this.numericTolerance > 0 {
+		expectedNumber, expectedErr := strconv.ParseFloat(strings.TrimSpace(expected), 64)
+		actualNumber, actualErr := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+		if expectedErr == nil && actualErr == nil {
+			return this.numbersEqual(expectedNumber, actualNumber)
+		}
+	}
+
+	return this.normalise(expected) == this.normalise(actual)
+}
+
+// jsonEqual compares two values of a JSON body at the given path. Unlike the top level of
+// the body, objects in arrays must have the same keys, apart from those that are ignored.
+func (this *DiffRules) jsonEqual(path []string, expected, actual interface{}) bool {
+	if this.isIgnored(path) {
+		return true
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range expectedValue {
+			childPath := appendPath(path, key)
+			if this.isIgnored(childPath) {
+				continue
+			}
+			if other, present := actualValue[key]; !present || !this.jsonEqual(childPath, value, other) {
+				return false
+			}
+		}
+		for key := range actualValue {
+			if _, present := expectedValue[key]; !present && !this.isIgnored(appendPath(path, key)) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok || len(expectedValue) != len(actualValue) {
+			return false
+		}
+		if this != nil && this.ignoreArrayOrder {
+			return this.jsonArrayEqualIgnoringOrder(path, expectedValue, actualValue)
+		}
+		for i := range expectedValue {
+			if !this.jsonEqual(appendPath(path, strconv.Itoa(i)), expectedValue[i], actualValue[i]) {
+				return false
+			}
+		}
+		return true
+	case string:
+		actualValue, ok := actual.(string)
+		return ok && this.normalise(expectedValue) == this.normalise(actualValue)
+	case float64:
+		actualValue, ok := actual.(float64)
+		return ok && this.numbersEqual(expectedValue, actualValue)
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
+
+// jsonArrayEqualIgnoringOrder pairs each expected element with an equal actual element
+func (this *DiffRules) jsonArrayEqualIgnoringOrder(path []string, expected, actual []interface{}) bool {
+	paired := make([]bool, len(actual))
+	for i, expectedElement := range expected {
+		elementPath := appendPath(path, strconv.Itoa(i))
+		found := false
+		for j, actualElement := range actual {
+			if !paired[j] && this.jsonEqual(elementPath, expectedElement, actualElement) {
+				paired[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func appendPath(path []string, segment string) []string {
+	child := make([]string, len(path), len(path)+1)
+	copy(child, path)
+	return append(child, segment)
+}
+
+// xmlEqual compares two XML bodies without the nodes selected by the ignored XPaths, returning
+// false for bodies that are not XML
+func (this *DiffRules) xmlEqual(expected, actual string) (equal bool, isXml bool) {
+	expectedTree, expectedIgnored, err := this.parseXml(expected)
+	if err != nil {
+		return false, false
+	}
+	actualTree, actualIgnored, err := this.parseXml(actual)
+	if err != nil {
+		return false, false
+	}
+
+	comparison := xmlComparison{
+		rules:           this,
+		expectedIgnored: expectedIgnored,
+		actualIgnored:   actualIgnored,
+	}
+	return comparison.nodesEqual(expectedTree, actualTree), true
+}
+
+func (this *DiffRules) parseXml(body string) (tree.Node, map[int]bool, error) {
+	if !strings.HasPrefix(strings.TrimSpace(body), "<") {
+		return nil, nil, fmt.Errorf("not XML")
+	}
+
+	root, err := xmltree.ParseXML(bytes.NewBufferString(body), func(s *xmltree.ParseOptions) {
+		s.Strict = false
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ignored := map[int]bool{}
+	for _, xpath := range this.ignoreXpaths {
+		nodes, err := xpath.ExecNode(root)
+		if err != nil {
+			continue
+		}
+		for _, node := range nodes {
+			ignored[node.Pos()] = true
+		}
+	}
+
+	return root, ignored, nil
+}
+
+type xmlComparison struct {
+	rules           *DiffRules
+	expectedIgnored map[int]bool
+	actualIgnored   map[int]bool
+}
+
+func (this xmlComparison) nodesEqual(expected, actual tree.Node) bool {
+	if expected.GetNodeType() != actual.GetNodeType() {
+		return false
+	}
+
+	switch expected.GetNodeType() {
+	case tree.NtChd, tree.NtAttr:
+		return this.rules.stringsEqual(expected.ResValue(), actual.ResValue())
+	case tree.NtElem:
+		expectedElement, _ := expected.GetToken().(xml.StartElement)
+		actualElement, _ := actual.GetToken().(xml.StartElement)
+		if expectedElement.Name != actualElement.Name {
+			return false
+		}
+		if !this.attributesEqual(expected.(tree.Elem), actual.(tree.Elem)) {
+			return false
+		}
+	}
+
+	expectedElem, expectedOk := expected.(tree.Elem)
+	actualElem, actualOk := actual.(tree.Elem)
+	if !expectedOk || !actualOk {
+		return true
+	}
+
+	expectedChildren := this.children(expectedElem, this.expectedIgnored)
+	actualChildren := this.children(actualElem, this.actualIgnored)
+	if len(expectedChildren) != len(actualChildren) {
+		return false
+	}
+
+	if this.rules.ignoreArrayOrder {
+		paired := make([]bool, len(actualChildren))
+		for _, expectedChild := range expectedChildren {
+			found := false
+			for j, actualChild := range actualChildren {
+				if !paired[j] && this.nodesEqual(expectedChild, actualChild) {
+					paired[j] = true
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := range expectedChildren {
+		if !this.nodesEqual(expectedChildren[i], actualChildren[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (this xmlComparison) attributesEqual(expected, actual tree.Elem) bool {
+	expectedAttributes := this.attributes(expected, this.expectedIgnored)
+	actualAttributes := this.attributes(actual, this.actualIgnored)
+	if len(expectedAttributes) != len(actualAttributes) {
+		return false
+	}
+
+	for name, value := range expectedAttributes {
+		other, present := actualAttributes[name]
+		if !present || !this.rules.stringsEqual(value, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func (this xmlComparison) attributes(element tree.Elem, ignored map[int]bool) map[xml.Name]string {
+	attributes := map[xml.Name]string{}
+	for _, attribute := range element.GetAttrs() {
+		if ignored[attribute.Pos()] {
+			continue
+		}
+		if attr, ok := attribute.GetToken().(xml.Attr); ok {
+			attributes[attr.Name] = attr.Value
+		}
+	}
+	return attributes
+}
+
+// children returns the elements and text of an element that are compared, leaving out
+// ignored nodes, comments, processing instructions and whitespace between elements
+func (this xmlComparison) children(element tree.Elem, ignored map[int]bool) []tree.Node {
+	var children []tree.Node
+	for _, child := range element.GetChildren() {
+		if ignored[child.Pos()] {
+			continue
+		}
+		switch child.GetNodeType() {
+		case tree.NtElem:
+			children = append(children, child)
+		case tree.NtChd:
+			if strings.TrimSpace(child.ResValue()) != "" {
+				children = append(children, child)
+			}
+		}
+	}
+	return children
+}
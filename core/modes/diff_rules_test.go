@@ -0,0 +1,215 @@
+package modes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func newDiffModeWithRules(view *v2.DiffRulesView) *DiffMode {
+	unit := &DiffMode{}
+	unit.SetArguments(ModeArguments{DiffRules: view})
+	return unit
+}
+
+func Test_NewDiffRules_ReturnsNilWithoutRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	rules, err := NewDiffRules(nil)
+
+	Expect(err).To(BeNil())
+	Expect(rules).To(BeNil())
+}
+
+func Test_NewDiffRules_ReturnsErrorForInvalidRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := NewDiffRules(&v2.DiffRulesView{Ignore: []string{"$.foo["}})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("Invalid JSONPath $.foo["))
+
+	_, err = NewDiffRules(&v2.DiffRulesView{Ignore: []string{"/foo["}})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("Invalid XPath /foo["))
+
+	_, err = NewDiffRules(&v2.DiffRulesView{Normalise: []v2.DiffNormaliseView{{Pattern: "[a-"}}})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("Invalid regex [a-"))
+
+	_, err = NewDiffRules(&v2.DiffRulesView{NumericTolerance: -1})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Numeric tolerance cannot be negative"))
+}
+
+func Test_parseJsonPath_MatchesKeysIndexesAndWildcards(t *testing.T) {
+	RegisterTestingT(t)
+
+	for path, matches := range map[string][]string{
+		"$.id":                {"id"},
+		"$['id']":             {"id"},
+		"$.items[0].id":       {"items", "0", "id"},
+		"$.items[*].id":       {"items", "1", "id"},
+		"$.items.*.id":        {"items", "2", "id"},
+		"$..id":               {"items", "2", "id"},
+		"$.meta..generatedAt": {"meta", "trace", "generatedAt"},
+	} {
+		segments, err := parseJsonPath(path)
+		Expect(err).To(BeNil(), path)
+		Expect(matchesJsonPath(segments, matches)).To(BeTrue(), path)
+	}
+
+	segments, err := parseJsonPath("$.items[0].id")
+	Expect(err).To(BeNil())
+	Expect(matchesJsonPath(segments, []string{"items", "1", "id"})).To(BeFalse())
+	Expect(matchesJsonPath(segments, []string{"items", "0"})).To(BeFalse())
+}
+
+func Test_DiffMode_DiffRulesIgnoreJsonPaths(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newDiffModeWithRules(&v2.DiffRulesView{
+		Ignore: []string{"$.generatedAt", "$.items[*].etag"},
+	})
+
+	unit.diffResponse(&models.ResponseDetails{
+		Status: 200,
+		Body:   `{"generatedAt": "2018-01-01", "items": [{"id": 1, "etag": "a"}], "name": "foo"}`,
+	}, &models.ResponseDetails{
+		Status: 200,
+		Body:   `{"generatedAt": "2018-02-02", "items": [{"id": 1, "etag": "b"}], "name": "bar"}`,
+	}, nil)
+
+	Expect(unit.DiffReport.DiffEntries).To(ConsistOf(
+		v2.DiffReportEntry{Field: "body/name", Expected: "foo", Actual: "bar"}))
+}
+
+func Test_DiffMode_DiffRulesNormaliseValues(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newDiffModeWithRules(&v2.DiffRulesView{
+		Normalise: []v2.DiffNormaliseView{
+			{Pattern: "[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}", Replacement: "<uuid>"},
+		},
+	})
+
+	unit.diffResponse(&models.ResponseDetails{
+		Status:  200,
+		Body:    `{"id": "order-7b3e5c52-2b9d-4f2e-8a4a-0c0e6a1f4b11"}`,
+		Headers: map[string][]string{"X-Request-Id": {"5f0d3a4e-2d84-4c53-9b1c-6a2b3f0e9d77"}},
+	}, &models.ResponseDetails{
+		Status:  200,
+		Body:    `{"id": "order-0a9c1b2d-3e4f-4a5b-8c6d-7e8f9a0b1c2d"}`,
+		Headers: map[string][]string{"X-Request-Id": {"e3b0c442-98fc-4c14-9afb-f4c8996fb924"}},
+	}, nil)
+
+	Expect(unit.DiffReport.DiffEntries).To(BeEmpty())
+}
+
+func Test_DiffMode_DiffRulesIgnoreArrayOrder(t *testing.T) {
+	RegisterTestingT(t)
+
+	expected := &models.ResponseDetails{Status: 200, Body: `{"tags": ["a", "b", {"c": 1}]}`}
+	actual := &models.ResponseDetails{Status: 200, Body: `{"tags": [{"c": 1}, "b", "a"]}`}
+
+	unit := newDiffModeWithRules(&v2.DiffRulesView{IgnoreArrayOrder: true})
+	unit.diffResponse(expected, actual, nil)
+	Expect(unit.DiffReport.DiffEntries).To(BeEmpty())
+
+	unit = newDiffModeWithRules(&v2.DiffRulesView{})
+	unit.diffResponse(expected, actual, nil)
+	Expect(unit.DiffReport.DiffEntries).To(HaveLen(1))
+	Expect(unit.DiffReport.DiffEntries[0].Field).To(Equal("body/tags"))
+}
+
+func Test_DiffMode_DiffRulesAllowNumericTolerance(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newDiffModeWithRules(&v2.DiffRulesView{NumericTolerance: 0.01})
+
+	unit.diffResponse(&models.ResponseDetails{
+		Status: 200,
+		Body:   `{"price": 9.99, "total": 20, "rates": [1.5]}`,
+	}, &models.ResponseDetails{
+		Status: 200,
+		Body:   `{"price": 9.995, "total": 21, "rates": [1.501]}`,
+	}, nil)
+
+	Expect(unit.DiffReport.DiffEntries).To(ConsistOf(
+		v2.DiffReportEntry{Field: "body/total", Expected: "20", Actual: "21"}))
+}
+
+func Test_DiffMode_DiffRulesIgnoreXPaths(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newDiffModeWithRules(&v2.DiffRulesView{
+		Ignore: []string{"/order/generatedAt", "/order/item/@etag"},
+	})
+
+	unit.diffResponse(&models.ResponseDetails{
+		Status: 200,
+		Body:   `<order><generatedAt>2018-01-01</generatedAt><item id="1" etag="a">foo</item></order>`,
+	}, &models.ResponseDetails{
+		Status: 200,
+		Body: `<order>
+	<generatedAt>2018-02-02</generatedAt>
+	<item id="1" etag="b">foo</item>
+</order>`,
+	}, nil)
+	Expect(unit.DiffReport.DiffEntries).To(BeEmpty())
+
+	unit.DiffReport = v2.DiffReport{}
+	unit.diffResponse(&models.ResponseDetails{
+		Status: 200,
+		Body:   `<order><item id="1">foo</item></order>`,
+	}, &models.ResponseDetails{
+		Status: 200,
+		Body:   `<order><item id="2">foo</item></order>`,
+	}, nil)
+	Expect(unit.DiffReport.DiffEntries).To(HaveLen(1))
+	Expect(unit.DiffReport.DiffEntries[0].Field).To(Equal("body"))
+}
+
+func Test_DiffMode_DiffRulesCompareTopLevelJsonArrays(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newDiffModeWithRules(&v2.DiffRulesView{Ignore: []string{"$[*].id"}})
+
+	unit.diffResponse(&models.ResponseDetails{
+		Status: 200,
+		Body:   `[{"id": 1, "name": "foo"}]`,
+	}, &models.ResponseDetails{
+		Status: 200,
+		Body:   `[{"id": 2, "name": "foo"}]`,
+	}, nil)
+
+	Expect(unit.DiffReport.DiffEntries).To(BeEmpty())
+}
+
+func Test_DiffMode_ReportsJsonPatchOperations(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &DiffMode{}
+
+	unit.diffResponse(&models.ResponseDetails{
+		Status:  200,
+		Body:    `{"name": "foo", "a/b": {"c~d": 1}, "removed": true}`,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+	}, &models.ResponseDetails{
+		Status:  201,
+		Body:    `{"name": "bar", "a/b": {"c~d": 2}}`,
+		Headers: map[string][]string{"Content-Type": {"text/plain"}},
+	}, nil)
+
+	patch, err := json.Marshal(unit.DiffReport.Patch)
+	Expect(err).To(BeNil())
+	Expect(patch).To(MatchJSON(`[
+		{"op": "replace", "path": "/status", "value": 201},
+		{"op": "replace", "path": "/header/Content-Type", "value": ["text/plain"]},
+		{"op": "replace", "path": "/body/a~1b/c~0d", "value": 2},
+		{"op": "replace", "path": "/body/name", "value": "bar"},
+		{"op": "remove", "path": "/body/removed"}
+	]`))
+}
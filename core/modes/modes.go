@@ -2,6 +2,7 @@ package modes
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/SpectoLabs/goproxy"
+	"github.com/SpectoLabs/hoverfly/core/errors"
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/sirupsen/logrus"
@@ -45,6 +47,8 @@ type ModeArguments struct {
 	MatchingStrategy 	*string
 	Stateful         	bool
 	OverwriteDuplicate	bool
+	CaptureMisses		bool
+	DiffRules		*v2.DiffRulesView
 }
 
 // ReconstructRequest replaces original request with details provided in Constructor Payload.RequestMatcher
@@ -84,6 +88,21 @@ func ReconstructResponse(request *http.Request, pair models.RequestResponsePair)
 
 	response.ContentLength = int64(len(pair.Response.Body))
 	response.Body = ioutil.NopCloser(strings.NewReader(pair.Response.Body))
+
+	// The length of a streamed response is not known until its last chunk has been sent
+	if len(pair.Response.Chunks) > 0 {
+		response.ContentLength = -1
+		response.Body = ioutil.NopCloser(strings.NewReader(models.JoinResponseChunks(pair.Response.Chunks)))
+	}
+
+	if pair.Response.Fault != nil || pair.Response.HasDelay() || pair.Response.Match != nil || len(pair.Response.Chunks) > 0 {
+		withSimulatedResponse(response, &SimulatedResponse{
+			Fault:    pair.Response.Fault,
+			HasDelay: pair.Response.HasDelay(),
+			Match:    pair.Response.Match.BuildView(),
+			Chunks:   pair.Response.Chunks,
+		})
+	}
 	response.StatusCode = pair.Response.Status
 	response.Status = http.StatusText(pair.Response.Status)
 
@@ -112,6 +131,101 @@ func ReconstructResponse(request *http.Request, pair models.RequestResponsePair)
 	return response
 }
 
+// SimulatedResponse is what Hoverfly still needs to know about a simulated response once it
+// has been reconstructed: the fault the proxy injects while writing the response to the client,
+// whether it already had a delay of its own, how the request was matched for the journal, and
+// the chunks the body is streamed in. It is kept in the context of the request of the response,
+// so that it stays with the response whatever its body is replaced with.
+type SimulatedResponse struct {
+	Fault    *models.ResponseFault
+	HasDelay bool
+	Match    *v2.JournalMatchView
+	Chunks   []models.ResponseChunk
+}
+
+type simulatedResponseKey struct{}
+
+// withSimulatedResponse keeps what is known about a simulated response with the response
+func withSimulatedResponse(response *http.Response, simulated *SimulatedResponse) {
+	request := response.Request
+	if request == nil {
+		request = &http.Request{}
+	}
+	response.Request = request.WithContext(context.WithValue(request.Context(), simulatedResponseKey{}, simulated))
+}
+
+// GetSimulatedResponse returns what is known about a simulated response, or nil
+func GetSimulatedResponse(response *http.Response) *SimulatedResponse {
+	if response == nil || response.Request == nil {
+		return nil
+	}
+
+	simulated, _ := response.Request.Context().Value(simulatedResponseKey{}).(*SimulatedResponse)
+	return simulated
+}
+
+// GetResponseFault returns the fault to inject into a response, or nil
+func GetResponseFault(response *http.Response) *models.ResponseFault {
+	if simulated := GetSimulatedResponse(response); simulated != nil {
+		return simulated.Fault
+	}
+
+	return nil
+}
+
+// GetResponseChunks returns the chunks to stream a simulated response in, or nil
+func GetResponseChunks(response *http.Response) []models.ResponseChunk {
+	if simulated := GetSimulatedResponse(response); simulated != nil {
+		return simulated.Chunks
+	}
+
+	return nil
+}
+
+// HasResponseDelay returns true if a simulated response was delayed when it was matched
+func HasResponseDelay(response *http.Response) bool {
+	if simulated := GetSimulatedResponse(response); simulated != nil {
+		return simulated.HasDelay
+	}
+
+	return false
+}
+
+// GetResponseMatch returns how the request for a response was matched, or nil when it was not matched against the simulation
+func GetResponseMatch(response *http.Response) *v2.JournalMatchView {
+	if simulated := GetSimulatedResponse(response); simulated != nil {
+		return simulated.Match
+	}
+
+	return nil
+}
+
+// withMatchingFailure records the closest miss on a response sent when matching failed
+func withMatchingFailure(response *http.Response, matchingErr *errors.HoverflyError) *http.Response {
+	if response == nil {
+		return response
+	}
+
+	match := &v2.JournalMatchView{}
+	if matchingErr.ClosestMiss != nil {
+		match.ClosestMiss = matchingErr.ClosestMiss.BuildView()
+	}
+
+	withSimulatedResponse(response, &SimulatedResponse{Match: match})
+	return response
+}
+
+// keepSimulationActions restores the parts of a simulated response which are
+// not passed to middleware
+func keepSimulationActions(response *models.ResponseDetails, simulated *models.ResponseDetails) {
+	response.Fault = simulated.Fault
+	response.FixedDelay = simulated.FixedDelay
+	response.LogNormalDelay = simulated.LogNormalDelay
+	response.UniformDelay = simulated.UniformDelay
+	response.Chunks = simulated.Chunks
+	response.Match = simulated.Match
+}
+
 func GetRequestLogFields(request *models.RequestDetails) *logrus.Fields {
 	if request == nil {
 		return &log.Fields{
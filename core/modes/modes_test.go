@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/SpectoLabs/hoverfly/core/models"
@@ -139,6 +140,117 @@ func Test_ReconstructResponse_ReturnsAResponseWithBody(t *testing.T) {
 	Expect(string(responseBody)).To(Equal("test body"))
 }
 
+func Test_ReconstructResponse_CarriesFault(t *testing.T) {
+	RegisterTestingT(t)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	pair := models.RequestResponsePair{
+		Response: models.ResponseDetails{
+			Body: "test body",
+			Fault: &models.ResponseFault{
+				TruncateBodyAt: 4,
+			},
+		},
+	}
+
+	response := modes.ReconstructResponse(req, pair)
+
+	Expect(modes.GetResponseFault(response)).To(Equal(&models.ResponseFault{TruncateBodyAt: 4}))
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(responseBody)).To(Equal("test body"))
+}
+
+func Test_GetSimulatedResponse_IsKeptWhenTheBodyIsReplaced(t *testing.T) {
+	RegisterTestingT(t)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	response := modes.ReconstructResponse(req, models.RequestResponsePair{
+		Response: models.ResponseDetails{
+			Body:       "test body",
+			FixedDelay: 100,
+			Fault:      &models.ResponseFault{TruncateBodyAt: 4},
+			Chunks:     []models.ResponseChunk{{Body: "test body"}},
+		},
+	})
+	response.Body = ioutil.NopCloser(strings.NewReader("replaced"))
+
+	Expect(modes.GetSimulatedResponse(response)).To(Equal(&modes.SimulatedResponse{
+		Fault:    &models.ResponseFault{TruncateBodyAt: 4},
+		HasDelay: true,
+		Chunks:   []models.ResponseChunk{{Body: "test body"}},
+	}))
+	Expect(response.Request.URL).To(Equal(req.URL))
+	Expect(modes.GetSimulatedResponse(&http.Response{Request: req})).To(BeNil())
+}
+
+func Test_GetResponseFault_ReturnsNilWithoutFault(t *testing.T) {
+	RegisterTestingT(t)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	response := modes.ReconstructResponse(req, models.RequestResponsePair{})
+
+	Expect(modes.GetResponseFault(response)).To(BeNil())
+	Expect(modes.GetResponseFault(nil)).To(BeNil())
+}
+
+func Test_ReconstructResponse_JoinsChunksIntoBodyOfUnknownLength(t *testing.T) {
+	RegisterTestingT(t)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	chunks := []models.ResponseChunk{
+		{Body: "data: 1\n\n"},
+		{Body: "data: 2\n\n", Delay: 100},
+	}
+	response := modes.ReconstructResponse(req, models.RequestResponsePair{
+		Response: models.ResponseDetails{
+			Status: 200,
+			Chunks: chunks,
+		},
+	})
+
+	Expect(modes.GetResponseChunks(response)).To(Equal(chunks))
+	Expect(response.ContentLength).To(Equal(int64(-1)))
+	Expect(response.Header.Get("Content-Length")).To(BeEmpty())
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(responseBody)).To(Equal("data: 1\n\ndata: 2\n\n"))
+}
+
+func Test_GetResponseChunks_ReturnsNilWithoutChunks(t *testing.T) {
+	RegisterTestingT(t)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	response := modes.ReconstructResponse(req, models.RequestResponsePair{})
+
+	Expect(modes.GetResponseChunks(response)).To(BeNil())
+	Expect(modes.GetResponseChunks(nil)).To(BeNil())
+}
+
+func Test_HasResponseDelay_ReturnsTrueForResponseWithItsOwnDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	delayed := modes.ReconstructResponse(req, models.RequestResponsePair{
+		Response: models.ResponseDetails{
+			FixedDelay: 100,
+		},
+	})
+	notDelayed := modes.ReconstructResponse(req, models.RequestResponsePair{})
+
+	Expect(modes.HasResponseDelay(delayed)).To(BeTrue())
+	Expect(modes.GetResponseFault(delayed)).To(BeNil())
+	Expect(modes.HasResponseDelay(notDelayed)).To(BeFalse())
+}
+
 func Test_ReconstructResponse_ReturnsAResponseWithCorrectContentLength(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -44,12 +44,14 @@ func (this SimulateMode) Process(request *http.Request, details models.RequestDe
 	response, matchingErr := this.Hoverfly.GetResponse(details)
 
 	if matchingErr != nil {
-		return ReturnErrorAndLog(request, matchingErr, &pair, "There was an error when matching", Simulate)
+		response, err := ReturnErrorAndLog(request, matchingErr, &pair, "There was an error when matching", Simulate)
+		return withMatchingFailure(response, matchingErr), err
 	}
 
 	pair.Response = *response
 
 	if pair, err := this.Hoverfly.ApplyMiddleware(pair); err == nil {
+		keepSimulationActions(&pair.Response, response)
 		return ReconstructResponse(request, pair), nil
 	} else {
 		return ReturnErrorAndLog(request, err, &pair, "There was an error when executing middleware", Simulate)
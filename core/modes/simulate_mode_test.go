@@ -18,10 +18,16 @@ func (this hoverflySimulateStub) GetResponse(requestDetails models.RequestDetail
 	if requestDetails.Destination == "positive-match.com" {
 		return &models.ResponseDetails{
 			Status: 200,
+			Match: &models.ResponseMatch{
+				Pair: &models.RequestMatcherResponsePair{},
+			},
 		}, nil
 	} else {
 		return nil, &errors.HoverflyError{
 			Message: "matching-error",
+			ClosestMiss: &models.ClosestMiss{
+				MissedFields: []string{"destination"},
+			},
 		}
 	}
 }
@@ -96,3 +102,38 @@ func Test_SimulateMode_WhenGivenAMatchingRequesAndMiddlewareFaislItReturnsAnErro
 	Expect(string(responseBody)).To(ContainSubstring("There was an error when executing middleware"))
 	Expect(string(responseBody)).To(ContainSubstring("middleware-error"))
 }
+
+func Test_SimulateMode_RecordsTheMatchedPairOnTheResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &modes.SimulateMode{
+		Hoverfly: hoverflySimulateStub{},
+	}
+
+	response, err := unit.Process(&http.Request{}, models.RequestDetails{
+		Destination: "positive-match.com",
+	})
+	Expect(err).To(BeNil())
+
+	match := modes.GetResponseMatch(response)
+	Expect(match).ToNot(BeNil())
+	Expect(match.Matched).To(BeTrue())
+	Expect(match.Pair).ToNot(BeNil())
+}
+
+func Test_SimulateMode_RecordsTheClosestMissOnTheResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &modes.SimulateMode{
+		Hoverfly: hoverflySimulateStub{},
+	}
+
+	response, _ := unit.Process(&http.Request{}, models.RequestDetails{
+		Destination: "negative-match.com",
+	})
+
+	match := modes.GetResponseMatch(response)
+	Expect(match).ToNot(BeNil())
+	Expect(match.Matched).To(BeFalse())
+	Expect(match.ClosestMiss.MissedFields).To(Equal([]string{"destination"}))
+}
@@ -15,20 +15,34 @@ type HoverflySpy interface {
 	GetResponse(models.RequestDetails) (*models.ResponseDetails, *errors.HoverflyError)
 	ApplyMiddleware(models.RequestResponsePair) (models.RequestResponsePair, error)
 	DoRequest(*http.Request) (*http.Response, error)
+	Save(*models.RequestDetails, *models.ResponseDetails, *ModeArguments) error
 }
 
+// SpyMode - simulates the requests that match and forwards the others to the real service.
+// With CaptureMisses, the requests that are forwarded are captured into the simulation using
+// the same headers, stateful and overwrite duplicate arguments as capture mode.
 type SpyMode struct {
 	Hoverfly         HoverflySpy
 	MatchingStrategy string
+	Arguments        ModeArguments
 }
 
 func (this *SpyMode) View() v2.ModeView {
-	return v2.ModeView{
+	view := v2.ModeView{
 		Mode: Spy,
 		Arguments: v2.ModeArgumentsView{
 			MatchingStrategy: &this.MatchingStrategy,
 		},
 	}
+
+	if this.Arguments.CaptureMisses {
+		view.Arguments.CaptureMisses = true
+		view.Arguments.Headers = this.Arguments.Headers
+		view.Arguments.Stateful = this.Arguments.Stateful
+		view.Arguments.OverwriteDuplicate = this.Arguments.OverwriteDuplicate
+	}
+
+	return view
 }
 
 func (this *SpyMode) SetArguments(arguments ModeArguments) {
@@ -37,6 +51,7 @@ func (this *SpyMode) SetArguments(arguments ModeArguments) {
 	} else {
 		this.MatchingStrategy = *arguments.MatchingStrategy
 	}
+	this.Arguments = arguments
 }
 
 //TODO: We should only need one of these two parameters
@@ -54,17 +69,24 @@ func (this SpyMode) Process(request *http.Request, details models.RequestDetails
 			return ReturnErrorAndLog(request, err, &pair, "There was an error when reconstructing the request.", Spy)
 		}
 		response, err := this.Hoverfly.DoRequest(modifiedRequest)
-		if err == nil {
-			log.Info("Going to return response from real server")
-			return response, nil
-		} else {
+		if err != nil {
 			return ReturnErrorAndLog(request, err, &pair, "There was an error when forwarding the request to the intended destination", Spy)
 		}
+
+		if this.Arguments.CaptureMisses {
+			if response, err = captureResponse(this.Hoverfly, request, &pair, response, this.Arguments, Spy); err != nil {
+				return response, err
+			}
+		}
+
+		log.Info("Going to return response from real server")
+		return withMatchingFailure(response, matchingErr), nil
 	}
 
 	pair.Response = *response
 
 	if pair, err := this.Hoverfly.ApplyMiddleware(pair); err == nil {
+		keepSimulationActions(&pair.Response, response)
 		return ReconstructResponse(request, pair), nil
 	} else {
 		return ReturnErrorAndLog(request, err, &pair, "There was an error when executing middleware", Spy)
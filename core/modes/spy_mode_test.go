@@ -13,7 +13,10 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-type hoverflySpyStub struct{}
+type hoverflySpyStub struct {
+	// Capture records what is saved, when it is set
+	Capture *hoverflyCaptureStub
+}
 
 // DoRequest - Stub implementation of modes.HoverflySpy interface
 func (this hoverflySpyStub) DoRequest(request *http.Request) (*http.Response, error) {
@@ -47,6 +50,14 @@ func (this hoverflySpyStub) ApplyMiddleware(pair models.RequestResponsePair) (mo
 	return pair, nil
 }
 
+// Save - Stub implementation of modes.HoverflySpy interface
+func (this hoverflySpyStub) Save(request *models.RequestDetails, response *models.ResponseDetails, modeArgs *modes.ModeArguments) error {
+	if this.Capture != nil {
+		return this.Capture.Save(request, response, modeArgs)
+	}
+	return nil
+}
+
 func Test_SpyMode_WhenGivenAMatchingRequestItReturnsTheCorrectResponse(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -141,3 +152,79 @@ func Test_SpyMode_ShouldReturnErrorOnRemoteServiceCall(t *testing.T) {
 	Expect(string(responseBody)).To(ContainSubstring("Could not reach error.com"))
 
 }
+
+func Test_SpyMode_WithCaptureMisses_SavesNonMatchingRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	capture := &hoverflyCaptureStub{}
+	unit := &modes.SpyMode{
+		Hoverfly: hoverflySpyStub{Capture: capture},
+	}
+	unit.SetArguments(modes.ModeArguments{
+		CaptureMisses: true,
+		Headers:       []string{"Authorization"},
+	})
+
+	requestDetails := models.RequestDetails{
+		Scheme:      "http",
+		Destination: "negative-match.com",
+	}
+
+	request, err := http.NewRequest("GET", "http://negative-match.com", nil)
+	Expect(err).To(BeNil())
+
+	response, err := unit.Process(request, requestDetails)
+	Expect(err).To(BeNil())
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(responseBody)).To(Equal("test"))
+
+	Expect(capture.SavedRequest.Destination).To(Equal("negative-match.com"))
+	Expect(capture.SavedResponse.Body).To(Equal("test"))
+	Expect(capture.SavedHeaders).To(Equal([]string{"Authorization"}))
+}
+
+func Test_SpyMode_WithoutCaptureMisses_DoesNotSaveRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	capture := &hoverflyCaptureStub{}
+	unit := &modes.SpyMode{
+		Hoverfly: hoverflySpyStub{Capture: capture},
+	}
+	unit.SetArguments(modes.ModeArguments{})
+
+	for _, destination := range []string{"positive-match.com", "negative-match.com"} {
+		request, err := http.NewRequest("GET", "http://"+destination, nil)
+		Expect(err).To(BeNil())
+
+		_, err = unit.Process(request, models.RequestDetails{Scheme: "http", Destination: destination})
+		Expect(err).To(BeNil())
+	}
+
+	Expect(capture.SavedRequest).To(BeNil())
+}
+
+func Test_SpyMode_View_ShowsCaptureArgumentsOnlyWhenCapturingMisses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &modes.SpyMode{}
+	unit.SetArguments(modes.ModeArguments{
+		Headers:  []string{"*"},
+		Stateful: true,
+	})
+
+	Expect(unit.View().Arguments.CaptureMisses).To(BeFalse())
+	Expect(unit.View().Arguments.Headers).To(BeNil())
+
+	unit.SetArguments(modes.ModeArguments{
+		Headers:       []string{"*"},
+		Stateful:      true,
+		CaptureMisses: true,
+	})
+
+	Expect(unit.View().Arguments.CaptureMisses).To(BeTrue())
+	Expect(unit.View().Arguments.Headers).To(Equal([]string{"*"}))
+	Expect(unit.View().Arguments.Stateful).To(BeTrue())
+	Expect(*unit.View().Arguments.MatchingStrategy).To(Equal("strongest"))
+}
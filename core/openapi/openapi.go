@@ -0,0 +1,547 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"gopkg.in/yaml.v2"
+)
+
+var methods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+}
+
+var pathParameterRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// Spec holds the parts of an OpenAPI 2 (Swagger) or OpenAPI 3 document
+// that are needed to build a simulation
+type Spec struct {
+	Swagger  string              `json:"swagger"`
+	OpenAPI  string              `json:"openapi"`
+	BasePath string              `json:"basePath"`
+	Produces []string            `json:"produces"`
+	Servers  []Server            `json:"servers"`
+	Paths    map[string]PathItem `json:"paths"`
+
+	document map[string]interface{}
+}
+
+type Server struct {
+	URL       string                    `json:"url"`
+	Variables map[string]ServerVariable `json:"variables"`
+}
+
+type ServerVariable struct {
+	Default string `json:"default"`
+}
+
+type PathItem struct {
+	Ref        string      `json:"$ref"`
+	Parameters []Parameter `json:"parameters"`
+	Get        *Operation  `json:"get"`
+	Put        *Operation  `json:"put"`
+	Post       *Operation  `json:"post"`
+	Delete     *Operation  `json:"delete"`
+	Options    *Operation  `json:"options"`
+	Head       *Operation  `json:"head"`
+	Patch      *Operation  `json:"patch"`
+}
+
+type Operation struct {
+	Parameters []Parameter         `json:"parameters"`
+	Produces   []string            `json:"produces"`
+	Responses  map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref     string  `json:"$ref"`
+	Name    string  `json:"name"`
+	In      string  `json:"in"`
+	Type    string  `json:"type"`
+	Pattern string  `json:"pattern"`
+	Schema  *Schema `json:"schema"`
+}
+
+type Response struct {
+	Ref      string                 `json:"$ref"`
+	Schema   *Schema                `json:"schema"`
+	Examples map[string]interface{} `json:"examples"`
+	Headers  map[string]interface{} `json:"headers"`
+	Content  map[string]MediaType   `json:"content"`
+}
+
+type MediaType struct {
+	Schema   *Schema            `json:"schema"`
+	Example  interface{}        `json:"example"`
+	Examples map[string]Example `json:"examples"`
+}
+
+type Example struct {
+	Value interface{} `json:"value"`
+}
+
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Pattern    string             `json:"pattern"`
+	Properties map[string]*Schema `json:"properties"`
+	Items      *Schema            `json:"items"`
+	Example    interface{}        `json:"example"`
+	Default    interface{}        `json:"default"`
+	Enum       []interface{}      `json:"enum"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+	AllOf      []*Schema          `json:"allOf"`
+	OneOf      []*Schema          `json:"oneOf"`
+	AnyOf      []*Schema          `json:"anyOf"`
+}
+
+// IsSpec reports whether the given JSON or YAML document is an OpenAPI 2 or 3 spec
+// rather than a Hoverfly simulation
+func IsSpec(data []byte) bool {
+	header := struct {
+		Swagger string `json:"swagger" yaml:"swagger"`
+		OpenAPI string `json:"openapi" yaml:"openapi"`
+	}{}
+
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = json.Unmarshal(data, &header)
+	} else {
+		err = yaml.Unmarshal(data, &header)
+	}
+
+	return err == nil && (header.Swagger != "" || header.OpenAPI != "")
+}
+
+// NewSpec parses an OpenAPI 2 or 3 document, which can be either JSON or YAML
+func NewSpec(data []byte) (*Spec, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("Unable to parse OpenAPI spec: %s", err.Error())
+	}
+
+	document = convertYAML(document)
+
+	documentMap, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unable to parse OpenAPI spec: expected an object")
+	}
+
+	spec := &Spec{document: documentMap}
+	if err := decode(documentMap, spec); err != nil {
+		return nil, fmt.Errorf("Unable to parse OpenAPI spec: %s", err.Error())
+	}
+
+	if spec.Swagger == "" && spec.OpenAPI == "" {
+		return nil, fmt.Errorf("Unable to parse OpenAPI spec: missing swagger or openapi version")
+	}
+
+	if !strings.HasPrefix(spec.Swagger, "2") && !strings.HasPrefix(spec.OpenAPI, "3") {
+		return nil, fmt.Errorf("Unsupported OpenAPI version, only OpenAPI 2 and 3 are supported")
+	}
+
+	return spec, nil
+}
+
+// ConvertToPairs parses an OpenAPI spec and builds a request response pair
+// for each operation in it
+func ConvertToPairs(data []byte) ([]v2.RequestMatcherResponsePairViewV5, error) {
+	spec, err := NewSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec.BuildPairs()
+}
+
+// BuildPairs builds a request response pair for each operation. The path is matched
+// exactly unless it has path parameters, in which case it is matched with a regex that
+// matches each parameter within a path segment. The matching strategy chooses the last
+// of the strongest matches, so paths with the most parameters come first, and the paths
+// with fewer parameters that come after them are chosen when both match a request.
+func (this *Spec) BuildPairs() ([]v2.RequestMatcherResponsePairViewV5, error) {
+	paths := []string{}
+	for path := range this.Paths {
+		paths = append(paths, path)
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		iParams := len(pathParameterRegex.FindAllString(paths[i], -1))
+		jParams := len(pathParameterRegex.FindAllString(paths[j], -1))
+		if iParams != jParams {
+			return iParams > jParams
+		}
+		return paths[i] < paths[j]
+	})
+
+	pairs := []v2.RequestMatcherResponsePairViewV5{}
+	for _, path := range paths {
+		pathItem := this.Paths[path]
+		if pathItem.Ref != "" {
+			if err := this.resolve(pathItem.Ref, &pathItem); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, method := range methods {
+			operation := pathItem.getOperation(method)
+			if operation == nil {
+				continue
+			}
+
+			parameters, err := this.pathParameters(pathItem.Parameters, operation.Parameters)
+			if err != nil {
+				return nil, err
+			}
+
+			response, err := this.buildResponse(this.basePath()+path, operation, parameters)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to build response for %s %s: %s", method, path, err.Error())
+			}
+
+			pairs = append(pairs, v2.RequestMatcherResponsePairViewV5{
+				RequestMatcher: v2.RequestMatcherViewV5{
+					Path:   []v2.MatcherViewV5{this.buildPathMatcher(path, parameters)},
+					Method: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, method)},
+				},
+				Response: response,
+			})
+		}
+	}
+
+	return pairs, nil
+}
+
+func (this PathItem) getOperation(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return this.Get
+	case http.MethodPut:
+		return this.Put
+	case http.MethodPost:
+		return this.Post
+	case http.MethodDelete:
+		return this.Delete
+	case http.MethodOptions:
+		return this.Options
+	case http.MethodHead:
+		return this.Head
+	case http.MethodPatch:
+		return this.Patch
+	}
+	return nil
+}
+
+// basePath returns the path every operation is served under, which is the
+// basePath of an OpenAPI 2 spec or the path of the first server of an OpenAPI 3 spec
+func (this *Spec) basePath() string {
+	basePath := this.BasePath
+
+	if len(this.Servers) > 0 {
+		serverURL := this.Servers[0].URL
+		for name, variable := range this.Servers[0].Variables {
+			serverURL = strings.Replace(serverURL, "{"+name+"}", variable.Default, -1)
+		}
+
+		if parsed, err := url.Parse(serverURL); err == nil {
+			basePath = parsed.Path
+		}
+	}
+
+	return strings.TrimSuffix(basePath, "/")
+}
+
+// pathParameters returns the path parameters of an operation by name, with the
+// operation's own parameters taking precedence over those of its path
+func (this *Spec) pathParameters(parameterLists ...[]Parameter) (map[string]Parameter, error) {
+	parameters := map[string]Parameter{}
+	for _, parameterList := range parameterLists {
+		for _, parameter := range parameterList {
+			if parameter.Ref != "" {
+				if err := this.resolve(parameter.Ref, &parameter); err != nil {
+					return nil, err
+				}
+			}
+			if parameter.In == "path" {
+				parameters[parameter.Name] = parameter
+			}
+		}
+	}
+	return parameters, nil
+}
+
+func (this *Spec) buildPathMatcher(path string, parameters map[string]Parameter) v2.MatcherViewV5 {
+	fullPath := this.basePath() + path
+
+	if !pathParameterRegex.MatchString(fullPath) {
+		return v2.NewMatcherView(matchers.Exact, fullPath)
+	}
+
+	names := pathParameterRegex.FindAllStringSubmatch(fullPath, -1)
+	regex := ""
+	for i, segment := range pathParameterRegex.Split(fullPath, -1) {
+		regex += regexp.QuoteMeta(segment)
+		if i < len(names) {
+			regex += parameterRegex(this.parameterSchema(parameters[names[i][1]]))
+		}
+	}
+
+	return v2.NewMatcherView(matchers.Regex, "^"+regex+"$")
+}
+
+// parameterSchema returns the schema of a parameter, which OpenAPI 2
+// declares on the parameter itself
+func (this *Spec) parameterSchema(parameter Parameter) *Schema {
+	if parameter.Schema != nil {
+		return this.resolveSchema(parameter.Schema)
+	}
+	return &Schema{Type: parameter.Type, Pattern: parameter.Pattern}
+}
+
+// parameterRegex matches the value of a path parameter, which is any path segment
+// unless the parameter is a number or has a pattern
+func parameterRegex(schema *Schema) string {
+	if schema.Pattern != "" {
+		return "(" + strings.TrimSuffix(strings.TrimPrefix(schema.Pattern, "^"), "$") + ")"
+	}
+
+	switch schema.Type {
+	case "integer":
+		return "-?[0-9]+"
+	case "number":
+		return "-?[0-9]+(\\.[0-9]+)?"
+	}
+
+	return "[^/]+"
+}
+
+// buildResponse builds the response of the first successful status code of an
+// operation, falling back to the default response. The body comes from an example
+// in the spec, or is synthesised from the response schema as a template.
+func (this *Spec) buildResponse(path string, operation *Operation, parameters map[string]Parameter) (v2.ResponseDetailsViewV5, error) {
+	status, response := selectResponse(operation.Responses)
+	if response.Ref != "" {
+		if err := this.resolve(response.Ref, &response); err != nil {
+			return v2.ResponseDetailsViewV5{}, err
+		}
+	}
+
+	responseView := v2.ResponseDetailsViewV5{
+		Status: status,
+	}
+
+	contentType, schema, example, hasExample := this.selectContent(operation, response)
+	if contentType != "" {
+		responseView.Headers = map[string][]string{
+			"Content-Type": {contentType},
+		}
+	}
+
+	isJSON := contentType == "" || strings.Contains(contentType, "json")
+
+	if hasExample {
+		if text, ok := example.(string); ok && !isJSON {
+			responseView.Body = text
+		} else {
+			body, err := json.Marshal(example)
+			if err != nil {
+				return v2.ResponseDetailsViewV5{}, err
+			}
+			responseView.Body = string(body)
+		}
+	} else if schema != nil && isJSON {
+		synthesiser := &synthesiser{
+			spec:           this,
+			pathParameters: pathParameterIndexes(path, parameters),
+			resolving:      map[string]bool{},
+		}
+		responseView.Body = synthesiser.synthesise(schema, "")
+		responseView.Templated = strings.Contains(responseView.Body, "{{")
+	}
+
+	return responseView, nil
+}
+
+func selectResponse(responses map[string]Response) (int, Response) {
+	codes := []int{}
+	for code := range responses {
+		if status, err := strconv.Atoi(code); err == nil {
+			codes = append(codes, status)
+		}
+	}
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			return code, responses[strconv.Itoa(code)]
+		}
+	}
+
+	if response, ok := responses["default"]; ok {
+		return http.StatusOK, response
+	}
+
+	if len(codes) > 0 {
+		return codes[0], responses[strconv.Itoa(codes[0])]
+	}
+
+	return http.StatusOK, Response{}
+}
+
+// selectContent picks the media type of a response, preferring JSON, and returns
+// its schema and example
+func (this *Spec) selectContent(operation *Operation, response Response) (string, *Schema, interface{}, bool) {
+	if this.OpenAPI != "" {
+		mediaTypes := []string{}
+		for mediaType := range response.Content {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		if len(mediaTypes) == 0 {
+			return "", nil, nil, false
+		}
+
+		mediaType := preferJSON(mediaTypes)
+		content := response.Content[mediaType]
+		if content.Example != nil {
+			return mediaType, content.Schema, content.Example, true
+		}
+
+		names := []string{}
+		for name := range content.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if content.Examples[name].Value != nil {
+				return mediaType, content.Schema, content.Examples[name].Value, true
+			}
+		}
+
+		return mediaType, content.Schema, nil, false
+	}
+
+	mediaTypes := []string{}
+	for mediaType := range response.Examples {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	if len(mediaTypes) > 0 {
+		mediaType := preferJSON(mediaTypes)
+		return mediaType, response.Schema, response.Examples[mediaType], true
+	}
+
+	if response.Schema == nil {
+		return "", nil, nil, false
+	}
+
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = this.Produces
+	}
+	if len(produces) == 0 {
+		return "application/json", response.Schema, nil, false
+	}
+
+	return preferJSON(produces), response.Schema, nil, false
+}
+
+func preferJSON(mediaTypes []string) string {
+	sorted := append([]string{}, mediaTypes...)
+	sort.Strings(sorted)
+
+	for _, mediaType := range sorted {
+		if strings.Contains(mediaType, "json") {
+			return mediaType
+		}
+	}
+	return sorted[0]
+}
+
+// pathParameterIndexes returns the position of each path parameter that is a whole
+// path segment, so that its value can be read from Request.Path in a template
+func pathParameterIndexes(path string, parameters map[string]Parameter) map[string]int {
+	indexes := map[string]int{}
+	for i, segment := range strings.Split(path, "/")[1:] {
+		match := pathParameterRegex.FindStringSubmatch(segment)
+		if match != nil && match[0] == segment {
+			indexes[match[1]] = i
+		}
+	}
+	return indexes
+}
+
+// resolve looks up a local reference such as #/definitions/Pet and decodes it into target
+func (this *Spec) resolve(ref string, target interface{}) error {
+	if !strings.HasPrefix(ref, "#/") {
+		return fmt.Errorf("Unsupported reference %s, only local references are supported", ref)
+	}
+
+	var current interface{} = this.document
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Unable to resolve reference %s", ref)
+		}
+		if current, ok = object[token]; !ok {
+			return fmt.Errorf("Unable to resolve reference %s", ref)
+		}
+	}
+
+	return decode(current, target)
+}
+
+func (this *Spec) resolveSchema(schema *Schema) *Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+
+	resolved := &Schema{}
+	if err := this.resolve(schema.Ref, resolved); err != nil {
+		return &Schema{}
+	}
+	return resolved
+}
+
+func decode(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// convertYAML turns the maps decoded by yaml, which can have keys of any type,
+// into maps with string keys so that they can be encoded as JSON
+func convertYAML(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, item := range typed {
+			converted[fmt.Sprint(key)] = convertYAML(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = convertYAML(item)
+		}
+	}
+	return value
+}
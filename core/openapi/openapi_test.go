@@ -0,0 +1,181 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/openapi"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	"github.com/SpectoLabs/hoverfly/core/templating"
+	. "github.com/onsi/gomega"
+)
+
+func readSpec(path string) []byte {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func render(body string, path string) string {
+	templator := templating.NewTemplator()
+	template, err := templator.ParseTemplate(body)
+	Expect(err).To(BeNil())
+
+	rendered, err := templator.RenderTemplate(template, &models.RequestDetails{Path: path}, map[string]string{})
+	Expect(err).To(BeNil())
+	return rendered
+}
+
+func Test_IsSpec_DetectsOpenAPISpecs(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(openapi.IsSpec(readSpec("testdata/petstore-v2.yaml"))).To(BeTrue())
+	Expect(openapi.IsSpec(readSpec("testdata/petstore-v3.json"))).To(BeTrue())
+	Expect(openapi.IsSpec([]byte(`{"data": {"pairs": []}, "meta": {"schemaVersion": "v5"}}`))).To(BeFalse())
+	Expect(openapi.IsSpec([]byte(`not a spec`))).To(BeFalse())
+}
+
+func Test_NewSpec_ReturnsErrorForInvalidSpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := openapi.NewSpec([]byte(`: not yaml`))
+	Expect(err).ToNot(BeNil())
+
+	_, err = openapi.NewSpec([]byte(`{"paths": {}}`))
+	Expect(err).To(MatchError("Unable to parse OpenAPI spec: missing swagger or openapi version"))
+
+	_, err = openapi.NewSpec([]byte(`{"swagger": "1.2"}`))
+	Expect(err).To(MatchError("Unsupported OpenAPI version, only OpenAPI 2 and 3 are supported"))
+}
+
+func Test_ConvertToPairs_BuildsAPairForEachOperationOfASwaggerSpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	pairs, err := openapi.ConvertToPairs(readSpec("testdata/petstore-v2.yaml"))
+	Expect(err).To(BeNil())
+	Expect(pairs).To(HaveLen(4))
+
+	Expect(pairs[0].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("regex", `^/v1/pets/[^/]+/owner/[^/]+$`)}))
+	Expect(pairs[0].Response.Status).To(Equal(200))
+	Expect(render(pairs[0].Response.Body, "/v1/pets/rex/owner/john")).To(Equal(`{"ownerName":"john"}`))
+
+	Expect(pairs[1].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("regex", `^/v1/pets/-?[0-9]+$`)}))
+	Expect(pairs[1].Response.Status).To(Equal(200))
+	Expect(pairs[1].Response.Templated).To(BeTrue())
+	Expect(pairs[1].Response.Body).To(Equal(`{"id":{{ randomInteger }},"name":"{{ randomString }}","tag":"dog"}`))
+
+	Expect(pairs[2].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("exact", "/v1/pets")}))
+	Expect(pairs[2].RequestMatcher.Method).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("exact", "GET")}))
+	Expect(pairs[2].Response.Status).To(Equal(200))
+	Expect(pairs[2].Response.Body).To(Equal(`[{"id":1,"name":"Rex"}]`))
+	Expect(pairs[2].Response.Headers["Content-Type"]).To(ConsistOf("application/json"))
+	Expect(pairs[2].Response.Templated).To(BeFalse())
+
+	Expect(pairs[3].RequestMatcher.Method).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("exact", "POST")}))
+	Expect(pairs[3].Response.Status).To(Equal(201))
+	Expect(pairs[3].Response.Body).To(BeEmpty())
+	Expect(pairs[3].Response.Headers).To(BeNil())
+}
+
+func Test_ConvertToPairs_MatchesPathParametersWithinASegmentAndPrefersFewerParameters(t *testing.T) {
+	RegisterTestingT(t)
+
+	pairs, err := openapi.ConvertToPairs([]byte(`
+swagger: "2.0"
+paths:
+  /files/{name}:
+    get:
+      responses:
+        200:
+          description: one
+          examples:
+            text/plain: file
+  /files/{name}/{version}:
+    get:
+      responses:
+        200:
+          description: two
+          examples:
+            text/plain: version
+  /files/{name}/latest:
+    get:
+      responses:
+        200:
+          description: three
+          examples:
+            text/plain: latest
+`))
+	Expect(err).To(BeNil())
+
+	simulation := models.NewSimulation()
+	for _, pair := range pairs {
+		simulation.AddPair(models.NewRequestMatcherResponsePairFromView(&pair))
+	}
+
+	match := func(path string) string {
+		result := matching.Match("strongest", models.RequestDetails{Method: "GET", Path: path}, false, simulation, state.NewState())
+		if result.Error != nil {
+			return ""
+		}
+		return result.Pair.Response.Body
+	}
+
+	Expect(match("/files/a")).To(Equal("file"))
+	Expect(match("/files/a/b")).To(Equal("version"))
+	Expect(match("/files/a/latest")).To(Equal("latest"))
+	Expect(match("/files/a/b/c")).To(BeEmpty())
+}
+
+func Test_ConvertToPairs_BuildsAPairForEachOperationOfAnOpenAPI3Spec(t *testing.T) {
+	RegisterTestingT(t)
+
+	pairs, err := openapi.ConvertToPairs(readSpec("testdata/petstore-v3.json"))
+	Expect(err).To(BeNil())
+	Expect(pairs).To(HaveLen(3))
+
+	Expect(pairs[1].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("exact", "/v2/health")}))
+	Expect(pairs[1].Response.Body).To(Equal("OK"))
+	Expect(pairs[1].Response.Headers["Content-Type"]).To(ConsistOf("text/plain"))
+
+	Expect(pairs[2].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("exact", "/v2/pets")}))
+	Expect(pairs[2].Response.Body).To(Equal(`[{"id":1},{"id":2}]`))
+
+	Expect(pairs[0].RequestMatcher.Path).To(Equal([]v2.MatcherViewV5{v2.NewMatcherView("regex", `^/v2/pets/([a-z]+)$`)}))
+	Expect(pairs[0].Response.Templated).To(BeTrue())
+
+	var body map[string]interface{}
+	Expect(json.Unmarshal([]byte(render(pairs[0].Response.Body, "/v2/pets/rex")), &body)).To(Succeed())
+	Expect(body["name"]).To(Equal("Rex"))
+	Expect(body["petId"]).To(Equal("rex"))
+	Expect(body["parent"]).To(BeNil())
+	Expect(body["age"]).To(BeNumerically(">=", 0))
+	Expect(body["age"]).To(BeNumerically("<=", 20))
+	Expect(body["weight"]).To(BeNumerically(">=", 1))
+	Expect(body["weight"]).To(BeNumerically("<=", 50))
+	Expect(body["born"]).ToNot(BeEmpty())
+}
+
+func Test_ConvertToPairs_ReturnsErrorForUnresolvableReference(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := openapi.ConvertToPairs([]byte(`{
+		"swagger": "2.0",
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": {"$ref": "#/responses/Missing"}
+					}
+				}
+			}
+		}
+	}`))
+
+	Expect(err).To(MatchError("Unable to build response for GET /pets: Unable to resolve reference #/responses/Missing"))
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// synthesiser builds a JSON body from a schema. Values that are not given by an
+// example, a default or an enum are rendered as template helpers, and properties
+// named after a path parameter echo the value from the request path.
+type synthesiser struct {
+	spec           *Spec
+	pathParameters map[string]int
+	resolving      map[string]bool
+}
+
+func (this *synthesiser) synthesise(schema *Schema, name string) string {
+	if schema == nil {
+		return "null"
+	}
+
+	if schema.Ref != "" {
+		// Stop at recursive schemas rather than following them forever
+		if this.resolving[schema.Ref] {
+			return "null"
+		}
+		this.resolving[schema.Ref] = true
+		defer delete(this.resolving, schema.Ref)
+
+		return this.synthesise(this.spec.resolveSchema(schema), name)
+	}
+
+	if schema.Example != nil {
+		return literal(schema.Example)
+	}
+	if schema.Default != nil {
+		return literal(schema.Default)
+	}
+	if len(schema.Enum) > 0 {
+		return literal(schema.Enum[0])
+	}
+
+	if len(schema.AllOf) > 0 {
+		return this.synthesiseObject(this.mergeAllOf(schema))
+	}
+	if len(schema.OneOf) > 0 {
+		return this.synthesise(schema.OneOf[0], name)
+	}
+	if len(schema.AnyOf) > 0 {
+		return this.synthesise(schema.AnyOf[0], name)
+	}
+
+	index, isPathParameter := this.pathParameters[name]
+
+	switch schema.Type {
+	case "string":
+		if isPathParameter {
+			return fmt.Sprintf(`"{{ Request.Path.[%d] }}"`, index)
+		}
+		return `"` + stringTemplate(schema.Format) + `"`
+	case "integer":
+		if isPathParameter {
+			return fmt.Sprintf("{{ Request.Path.[%d] }}", index)
+		}
+		if schema.Minimum != nil && schema.Maximum != nil {
+			return fmt.Sprintf("{{ randomIntegerRange %d %d }}", int(*schema.Minimum), int(*schema.Maximum))
+		}
+		return "{{ randomInteger }}"
+	case "number":
+		if schema.Minimum != nil && schema.Maximum != nil {
+			return fmt.Sprintf("{{ randomFloatRange %s %s }}", floatLiteral(*schema.Minimum), floatLiteral(*schema.Maximum))
+		}
+		return "{{ randomFloat }}"
+	case "boolean":
+		return "{{ randomBoolean }}"
+	case "array":
+		return "[" + this.synthesise(schema.Items, "") + "]"
+	}
+
+	if schema.Properties != nil || schema.Type == "object" {
+		return this.synthesiseObject(schema)
+	}
+
+	return "null"
+}
+
+func (this *synthesiser) synthesiseObject(schema *Schema) string {
+	names := []string{}
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := []string{}
+	for _, name := range names {
+		fields = append(fields, literal(name)+":"+this.synthesise(schema.Properties[name], name))
+	}
+
+	object := "{" + strings.Join(fields, ",")
+	// Keep a closing template away from the closing brace, which would
+	// otherwise be read as a triple stash
+	if strings.HasSuffix(object, "}}") {
+		object += " "
+	}
+	return object + "}"
+}
+
+func (this *synthesiser) mergeAllOf(schema *Schema) *Schema {
+	merged := &Schema{Properties: map[string]*Schema{}}
+	for name, property := range schema.Properties {
+		merged.Properties[name] = property
+	}
+
+	for _, part := range schema.AllOf {
+		if part.Ref != "" {
+			if this.resolving[part.Ref] {
+				continue
+			}
+			part = this.spec.resolveSchema(part)
+		}
+		if len(part.AllOf) > 0 {
+			part = this.mergeAllOf(part)
+		}
+		for name, property := range part.Properties {
+			merged.Properties[name] = property
+		}
+	}
+
+	return merged
+}
+
+func stringTemplate(format string) string {
+	switch format {
+	case "uuid":
+		return "{{ randomUuid }}"
+	case "email":
+		return "{{ randomEmail }}"
+	case "date-time":
+		return "{{ iso8601DateTime }}"
+	case "date":
+		return "{{ currentDateTime '2006-01-02' }}"
+	case "ipv4":
+		return "{{ randomIPv4 }}"
+	case "ipv6":
+		return "{{ randomIPv6 }}"
+	}
+	return "{{ randomString }}"
+}
+
+// floatLiteral formats a number so that handlebars parses it as a float
+func floatLiteral(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.Contains(formatted, ".") {
+		formatted += ".0"
+	}
+	return formatted
+}
+
+func literal(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
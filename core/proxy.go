@@ -3,6 +3,7 @@ package hoverfly
 import (
 	"bytes"
 	"encoding/base64"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -17,6 +18,7 @@ import (
 	"github.com/SpectoLabs/goproxy/ext/auth"
 	"github.com/SpectoLabs/hoverfly/core/authentication"
 	"github.com/SpectoLabs/hoverfly/core/authentication/backends"
+	"github.com/SpectoLabs/hoverfly/core/modes"
 	"github.com/SpectoLabs/hoverfly/core/util"
 )
 
@@ -27,22 +29,41 @@ var ProxyAuthorizationHeader string
 func NewProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	ProxyAuthorizationHeader = hoverfly.Cfg.ProxyAuthorizationHeader
 
-	// creating proxy
-	proxy := goproxy.NewProxyHttpServer()
+	// Hoverfly serves the MITM'd tunnels itself, and the requests in them are handled by a proxy
+	// of their own, which takes plain HTTP requests even when this one does not
+	tunnelProxy := newProxyHttpServer(hoverfly, nil)
 
-	proxy.OnRequest(matchesFilter(hoverfly.Cfg.Destination)).
-		HandleConnect(goproxy.FuncHttpsHandler(func(host string, ctx *goproxy.ProxyCtx) (*goproxy.ConnectAction, string) {
-			if hoverfly.Cfg.PlainHttpTunneling && !strings.HasSuffix(host, ":443") {
-				return goproxy.HTTPMitmConnect, host
-			}
-			return goproxy.MitmConnect, host
-		}))
+	// creating proxy
+	proxy := newProxyHttpServer(hoverfly, tunnelProxy)
 
 	if hoverfly.Cfg.HttpsOnly {
 		log.Info("Disabling HTTP")
 		proxy.DisableNonTls(true)
 	}
 
+	// proxy starting message
+	log.WithFields(log.Fields{
+		"Destination": hoverfly.Cfg.Destination,
+		"ProxyPort":   hoverfly.Cfg.ProxyPort,
+		"Mode":        hoverfly.Cfg.GetMode(),
+	}).Info("Proxy prepared...")
+
+	return proxy
+}
+
+// newProxyHttpServer creates a goproxy.ProxyHttpServer with the handlers of the Hoverfly proxy.
+// CONNECT tunnels are served by the tunnel proxy, when there is one.
+func newProxyHttpServer(hoverfly *Hoverfly, tunnelProxy *goproxy.ProxyHttpServer) *goproxy.ProxyHttpServer {
+	proxy := goproxy.NewProxyHttpServer()
+
+	if tunnelProxy != nil {
+		proxy.OnRequest(matchesFilter(hoverfly.Cfg.Destination)).
+			HandleConnect(goproxy.FuncHttpsHandler(func(host string, ctx *goproxy.ProxyCtx) (*goproxy.ConnectAction, string) {
+				isTls := !hoverfly.Cfg.PlainHttpTunneling || strings.HasSuffix(host, ":443")
+				return newTunnelConnectAction(tunnelProxy, isTls, hoverfly.Cfg.Http2), host
+			}))
+	}
+
 	if hoverfly.Cfg.AuthEnabled {
 		log.Info("Enabling proxy authentication")
 		proxyBasicAndBearer(proxy, "hoverfly", func(user, password string) bool {
@@ -64,8 +85,21 @@ func NewProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	proxy.OnRequest(matchesFilter(hoverfly.Cfg.Destination)).DoFunc(
 		func(r *http.Request, ctx *goproxy.ProxyCtx) (*http.Request, *http.Response) {
 			startTime := time.Now()
+			if isWebSocketHandshake(r) {
+				return r, hoverfly.processWebSocket(r, startTime)
+			}
 			resp := hoverfly.processRequest(r)
-			hoverfly.Journal.NewEntry(r, resp, hoverfly.Cfg.Mode, startTime)
+			fault := modes.GetResponseFault(resp)
+			chunks := modes.GetResponseChunks(resp)
+			hoverfly.recordRequest(r, resp, startTime)
+			setTunnelResponse(r, resp)
+			if chunks != nil || modes.IsCapturedStream(resp) {
+				flusher, _ := r.Context().Value(responseWriterKey{}).(http.Flusher)
+				streamResponse(flusher, resp, chunks)
+			}
+			if fault != nil {
+				hoverfly.injectFault(r, resp, fault)
+			}
 			return r, resp
 		})
 
@@ -91,12 +125,6 @@ func NewProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 		})
 
 	proxy.Verbose = hoverfly.Cfg.Verbose
-	// proxy starting message
-	log.WithFields(log.Fields{
-		"Destination": hoverfly.Cfg.Destination,
-		"ProxyPort":   hoverfly.Cfg.ProxyPort,
-		"Mode":        hoverfly.Cfg.GetMode(),
-	}).Info("Proxy prepared...")
 
 	return proxy
 }
@@ -109,9 +137,34 @@ func NewWebserverProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	proxy.NonproxyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
 		r.URL.Scheme = "http"
+		if isWebSocketHandshake(r) {
+			resp := hoverfly.processWebSocket(r, startTime)
+			for name, values := range resp.Header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(resp.StatusCode)
+			io.Copy(w, resp.Body)
+			hoverfly.Counter.Count(hoverfly.Cfg.GetMode())
+			return
+		}
 		resp := hoverfly.processRequest(r)
-		hoverfly.Journal.NewEntry(r, resp, hoverfly.Cfg.Mode, startTime)
-		body, err := util.GetResponseBody(resp)
+		fault := modes.GetResponseFault(resp)
+		chunks := modes.GetResponseChunks(resp)
+		hoverfly.recordRequest(r, resp, startTime)
+
+		streamed := chunks != nil || modes.IsCapturedStream(resp)
+		if streamed {
+			flusher, _ := w.(http.Flusher)
+			streamResponse(flusher, resp, chunks)
+		}
+
+		var body string
+		var err error
+		if fault != nil {
+			hoverfly.injectFault(r, resp, fault)
+		} else if !streamed {
+			body, err = util.GetResponseBody(resp)
+		}
 
 		if err != nil {
 			log.Error("Error reading response body")
@@ -129,8 +182,20 @@ func NewWebserverProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 
 		w.Header().Set("Req", r.RequestURI)
 		w.Header().Set("Resp", resp.Header.Get("Content-Length"))
+		// Trailers are only sent after a body of unknown length
+		if len(resp.Trailer) > 0 {
+			w.Header().Del("Content-Length")
+		}
 		w.WriteHeader(resp.StatusCode)
-		w.Write([]byte(body))
+		if fault == nil && !streamed {
+			w.Write([]byte(body))
+		} else {
+			io.Copy(w, resp.Body)
+		}
+
+		for name, values := range resp.Trailer {
+			w.Header()[http.TrailerPrefix+name] = values
+		}
 
 		hoverfly.Counter.Count(hoverfly.Cfg.GetMode())
 	})
@@ -158,6 +223,23 @@ func NewWebserverProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	return proxy
 }
 
+// recordRequest adds a handled request to the journal of its session, or of Hoverfly, and the request metrics
+func (hf *Hoverfly) recordRequest(request *http.Request, response *http.Response, started time.Time) {
+	requestJournal := hf.Journal
+	if requestSession := getRequestSession(request); requestSession != nil {
+		requestJournal = requestSession.Journal
+	}
+	// A stream that is being captured can only be read as it is written to the client
+	journalled := response
+	if modes.IsCapturedStream(response) {
+		withoutBody := *response
+		withoutBody.Body = ioutil.NopCloser(strings.NewReader(""))
+		journalled = &withoutBody
+	}
+	requestJournal.NewEntryWithMatch(request, journalled, modes.GetResponseMatch(response), hf.Cfg.Mode, started)
+	hf.Metrics.CountRequest(hf.Cfg.Mode, request.Host, request.Method, response.StatusCode, time.Since(started))
+}
+
 func unauthorizedError(request *http.Request, realm, message string) *http.Response {
 	response := auth.BasicUnauthorized(request, realm)
 	response.Body = ioutil.NopCloser(bytes.NewBuffer([]byte(message)))
@@ -169,10 +251,15 @@ func unauthorizedError(request *http.Request, realm, message string) *http.Respo
 func proxyBasicAndBearer(proxy *goproxy.ProxyHttpServer, realm string, basicFunc func(user, passwd string) bool, bearerFunc func(token string) bool) {
 
 	proxy.OnRequest().Do(goproxy.FuncReqHandler(func(req *http.Request, ctx *goproxy.ProxyCtx) (*http.Request, *http.Response) {
+		username := getBasicAuthUsername(req.Header.Get(ProxyAuthorizationHeader))
 		err := authFromHeader(req, basicFunc, bearerFunc)
 		if err != nil {
 			return nil, unauthorizedError(req, realm, err.Error())
 		}
+		// Keep the user on the request, so that requests can be put in a session for each user
+		if username != "" {
+			setContextValue(req, proxyUserContextKey, username)
+		}
 		return req, nil
 	}))
 
@@ -1,9 +1,12 @@
 package hoverfly
 
 import (
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"bufio"
 	"net"
@@ -11,6 +14,10 @@ import (
 	. "github.com/onsi/gomega"
 
 	"net/http/httptest"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
 )
 
 func Test_authFromHeader_ShouldRemoveProxyAuthorizationHeader(t *testing.T) {
@@ -122,6 +129,68 @@ func shouldHandleConnect(t *testing.T, hoverfly *Hoverfly, url string) {
 	Expect(resp.StatusCode).To(Equal(200))
 }
 
+func Test_recordRequest_CountsRequestInMetrics(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Cfg.SetMode("simulate")
+
+	request, _ := http.NewRequest(http.MethodGet, "http://test.com/path", nil)
+	unit.recordRequest(request, &http.Response{
+		StatusCode: 404,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, time.Now())
+
+	Expect(unit.Metrics.Requests.Value("simulate", "test.com", "GET", "404")).To(Equal(float64(1)))
+	Expect(unit.Metrics.RequestDuration.Count("simulate")).To(Equal(uint64(1)))
+}
+
+func Test_recordRequest_RecordsHowTheRequestWasMatchedInTheJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/matched",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+			Body:   "matched",
+		},
+	})
+
+	for _, path := range []string{"/matched", "/unmatched"} {
+		request, _ := http.NewRequest(http.MethodGet, "http://test.com"+path, nil)
+		unit.recordRequest(request, unit.processRequest(request), time.Now())
+	}
+
+	journalView, err := unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(2))
+
+	matched := journalView.Journal[0].Match
+	Expect(matched.Matched).To(BeTrue())
+	Expect(matched.Pair.RequestMatcher.Path[0].Value).To(Equal("/matched"))
+	Expect(matched.ClosestMiss).To(BeNil())
+
+	unmatched := journalView.Journal[1].Match
+	Expect(unmatched.Matched).To(BeFalse())
+	Expect(unmatched.Pair).To(BeNil())
+	Expect(unmatched.ClosestMiss.MissedFields).To(ConsistOf("path"))
+
+	entries, err := unit.Journal.GetFilteredEntries(v2.JournalEntryFilterView{Unmatched: true})
+	Expect(err).To(BeNil())
+	Expect(entries).To(HaveLen(1))
+	Expect(*entries[0].Request.Path).To(Equal("/unmatched"))
+}
+
 func Test_matchesFilter_ShouldMatchHostDestination(t *testing.T) {
 	RegisterTestingT(t)
 	httpResult := matchesFilter("test.com")(&http.Request{
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+)
+
+// Session isolates the simulation, state and journal of one client, so that
+// clients sharing a Hoverfly do not step on each other
+type Session struct {
+	Name       string
+	Created    time.Time
+	Simulation *models.Simulation
+	State      *state.State
+	Journal    *journal.Journal
+	// lastUsed is guarded by the mutex of the sessions the session is in
+	lastUsed time.Time
+}
+
+func NewSession(name string, sessionJournal *journal.Journal) *Session {
+	now := time.Now()
+	return &Session{
+		Name:       name,
+		Created:    now,
+		Simulation: models.NewSimulation(),
+		State:      state.NewState(),
+		Journal:    sessionJournal,
+		lastUsed:   now,
+	}
+}
+
+func (this *Session) GetState() map[string]string {
+	return this.State.GetAllState()
+}
+
+func (this *Session) SetState(state map[string]string) {
+	this.State.SetState(state)
+}
+
+func (this *Session) PatchState(toPatch map[string]string) {
+	this.State.PatchState(toPatch)
+}
+
+func (this *Session) ClearState() {
+	this.State.SetState(map[string]string{})
+	this.Simulation.ResetResponseSequences()
+}
+
+// DefaultLimit is the most sessions kept by default
+const DefaultLimit = 100
+
+// DefaultIdleTimeout is how long a session is kept by default once it is no longer used
+const DefaultIdleTimeout = time.Hour
+
+// Sessions holds the sessions of a Hoverfly, creating them the first time they are used
+type Sessions struct {
+	// Limit is the most sessions kept, the least recently used session is removed to
+	// make room for a new one. There is no limit when it is 0.
+	Limit int
+	// IdleTimeout removes the sessions that have not been used for this long. Sessions
+	// are kept until they are deleted when it is 0.
+	IdleTimeout time.Duration
+	sessions    map[string]*Session
+	newJournal  func() *journal.Journal
+	mutex       sync.RWMutex
+}
+
+// NewSessions returns an empty set of sessions, where each new session keeps
+// its journal in the journal returned by newJournal
+func NewSessions(newJournal func() *journal.Journal) *Sessions {
+	return &Sessions{
+		Limit:       DefaultLimit,
+		IdleTimeout: DefaultIdleTimeout,
+		sessions:    map[string]*Session{},
+		newJournal:  newJournal,
+	}
+}
+
+// Get returns the session with the given name, or nil if there is no such session
+func (this *Sessions) Get(name string) *Session {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.removeIdle(time.Now())
+
+	return this.sessions[name]
+}
+
+// GetOrCreate returns the session with the given name, creating it if there is no such session,
+// and marks it as used
+func (this *Sessions) GetOrCreate(name string) *Session {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	now := time.Now()
+	this.removeIdle(now)
+
+	session, ok := this.sessions[name]
+	if !ok {
+		if this.Limit > 0 && len(this.sessions) >= this.Limit {
+			this.removeLeastRecentlyUsed()
+		}
+		session = NewSession(name, this.newJournal())
+		this.sessions[name] = session
+	}
+	session.lastUsed = now
+	return session
+}
+
+// GetAll returns each session, ordered by name
+func (this *Sessions) GetAll() []*Session {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.removeIdle(time.Now())
+
+	sessions := []*Session{}
+	for _, session := range this.sessions {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Name < sessions[j].Name
+	})
+	return sessions
+}
+
+// Delete removes the session with the given name, returning false if there is no such session
+func (this *Sessions) Delete(name string) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if _, ok := this.sessions[name]; !ok {
+		return false
+	}
+	delete(this.sessions, name)
+	return true
+}
+
+func (this *Sessions) removeIdle(now time.Time) {
+	if this.IdleTimeout <= 0 {
+		return
+	}
+	for name, session := range this.sessions {
+		if now.Sub(session.lastUsed) >= this.IdleTimeout {
+			delete(this.sessions, name)
+		}
+	}
+}
+
+func (this *Sessions) removeLeastRecentlyUsed() {
+	var leastRecentlyUsed *Session
+	for _, session := range this.sessions {
+		if leastRecentlyUsed == nil || session.lastUsed.Before(leastRecentlyUsed.lastUsed) {
+			leastRecentlyUsed = session
+		}
+	}
+	if leastRecentlyUsed != nil {
+		delete(this.sessions, leastRecentlyUsed.Name)
+	}
+}
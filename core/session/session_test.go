@@ -0,0 +1,163 @@
+package session_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/session"
+	. "github.com/onsi/gomega"
+)
+
+func newSessions() *session.Sessions {
+	return session.NewSessions(journal.NewJournal)
+}
+
+func Test_Sessions_GetOrCreate_CreatesSessionsWithTheirOwnSimulationStateAndJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions()
+
+	first := unit.GetOrCreate("first")
+	second := unit.GetOrCreate("second")
+
+	Expect(first.Name).To(Equal("first"))
+	Expect(first.Simulation).ToNot(BeNil())
+	Expect(first.State).ToNot(BeNil())
+	Expect(first.Journal).ToNot(BeNil())
+
+	Expect(first.Simulation).ToNot(BeIdenticalTo(second.Simulation))
+	Expect(first.State).ToNot(BeIdenticalTo(second.State))
+	Expect(first.Journal).ToNot(BeIdenticalTo(second.Journal))
+}
+
+func Test_Sessions_GetOrCreate_ReturnsTheExistingSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions()
+
+	created := unit.GetOrCreate("test")
+	created.PatchState(map[string]string{"page": "home"})
+
+	Expect(unit.GetOrCreate("test")).To(BeIdenticalTo(created))
+	Expect(unit.GetOrCreate("test").GetState()).To(Equal(map[string]string{"page": "home"}))
+}
+
+func Test_Sessions_GetOrCreate_CreatesJournalsWithTheGivenFunction(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := session.NewSessions(func() *journal.Journal {
+		sessionJournal := journal.NewJournal()
+		sessionJournal.EntryLimit = 5
+		return sessionJournal
+	})
+
+	Expect(unit.GetOrCreate("test").Journal.EntryLimit).To(Equal(5))
+}
+
+func Test_Sessions_Get_ReturnsNilForSessionsThatDoNotExist(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions()
+
+	Expect(unit.Get("test")).To(BeNil())
+
+	unit.GetOrCreate("test")
+
+	Expect(unit.Get("test")).ToNot(BeNil())
+}
+
+func Test_Sessions_GetAll_ReturnsSessionsOrderedByName(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions()
+	unit.GetOrCreate("b")
+	unit.GetOrCreate("c")
+	unit.GetOrCreate("a")
+
+	sessions := unit.GetAll()
+
+	Expect(sessions).To(HaveLen(3))
+	Expect(sessions[0].Name).To(Equal("a"))
+	Expect(sessions[1].Name).To(Equal("b"))
+	Expect(sessions[2].Name).To(Equal("c"))
+}
+
+func Test_Sessions_GetOrCreate_RemovesTheLeastRecentlyUsedSessionOverTheLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions()
+	unit.Limit = 2
+
+	unit.GetOrCreate("a")
+	unit.GetOrCreate("b")
+	unit.GetOrCreate("a")
+	unit.GetOrCreate("c")
+
+	Expect(unit.Get("a")).ToNot(BeNil())
+	Expect(unit.Get("b")).To(BeNil())
+	Expect(unit.Get("c")).ToNot(BeNil())
+}
+
+func Test_Sessions_RemovesSessionsThatHaveNotBeenUsedForTheIdleTimeout(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions()
+	unit.IdleTimeout = 50 * time.Millisecond
+
+	unit.GetOrCreate("idle")
+	unit.GetOrCreate("used")
+	time.Sleep(30 * time.Millisecond)
+	unit.GetOrCreate("used")
+	time.Sleep(30 * time.Millisecond)
+
+	Expect(unit.Get("idle")).To(BeNil())
+	Expect(unit.Get("used")).ToNot(BeNil())
+	Expect(unit.GetAll()).To(HaveLen(1))
+}
+
+func Test_Sessions_Delete(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions()
+	unit.GetOrCreate("test")
+
+	Expect(unit.Delete("test")).To(BeTrue())
+	Expect(unit.Get("test")).To(BeNil())
+
+	Expect(unit.Delete("test")).To(BeFalse())
+}
+
+func Test_Session_ClearState(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions().GetOrCreate("test")
+	unit.SetState(map[string]string{"page": "home"})
+
+	unit.ClearState()
+
+	Expect(unit.GetState()).To(BeEmpty())
+}
+
+func Test_Session_GetState_CanBeReadWhileRequestsChangeTheState(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newSessions().GetOrCreate("test")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			unit.PatchState(map[string]string{"page": strconv.Itoa(i)})
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		for range unit.GetState() {
+		}
+	}
+	<-done
+
+	Expect(unit.GetState()).To(Equal(map[string]string{"page": "999"}))
+}
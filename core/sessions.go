@@ -0,0 +1,126 @@
+package hoverfly
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/errors"
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/session"
+)
+
+type contextKey string
+
+const (
+	proxyUserContextKey = contextKey("proxyUser")
+	sessionContextKey   = contextKey("session")
+)
+
+// getSession returns the session of a request, creating it the first time it is used, or nil if the request
+// does not belong to a session. Requests are put in a session by the session header, or by the user they
+// authenticated with the proxy as when sessions are keyed by proxy user. The session is kept on the request,
+// so that it can be journalled in that session.
+func (hf *Hoverfly) getSession(req *http.Request) *session.Session {
+	name := ""
+	if hf.Cfg.SessionHeader != "" {
+		name = req.Header.Get(hf.Cfg.SessionHeader)
+		// The session header is for Hoverfly, it is neither matched nor sent on
+		req.Header.Del(hf.Cfg.SessionHeader)
+	}
+	if name == "" && hf.Cfg.SessionsByProxyUser {
+		name, _ = req.Context().Value(proxyUserContextKey).(string)
+	}
+	if name == "" {
+		return nil
+	}
+
+	requestSession := hf.Sessions.GetOrCreate(name)
+	setContextValue(req, sessionContextKey, requestSession)
+	return requestSession
+}
+
+// getRequestSession returns the session that getSession put the request in, or nil
+func getRequestSession(req *http.Request) *session.Session {
+	requestSession, _ := req.Context().Value(sessionContextKey).(*session.Session)
+	return requestSession
+}
+
+// setContextValue adds a value to the context of a request in place, as goproxy passes
+// the same request to each of its handlers rather than the one returned by the last
+func setContextValue(req *http.Request, key contextKey, value interface{}) {
+	*req = *req.WithContext(context.WithValue(req.Context(), key, value))
+}
+
+// getBasicAuthUsername returns the username of basic credentials in an authorization header
+func getBasicAuthUsername(headerValue string) string {
+	authheader := strings.SplitN(headerValue, " ", 2)
+	if len(authheader) != 2 || authheader[0] != "Basic" {
+		return ""
+	}
+	userpassraw, err := base64.StdEncoding.DecodeString(authheader[1])
+	if err != nil {
+		return ""
+	}
+	return strings.SplitN(string(userpassraw), ":", 2)[0]
+}
+
+// newSessionJournal creates the journal of a new session, with the same limits as the journal of Hoverfly
+func (hf *Hoverfly) newSessionJournal() *journal.Journal {
+	sessionJournal := journal.NewJournal()
+	sessionJournal.EntryLimit = hf.Journal.EntryLimit
+	sessionJournal.MaxBytes = hf.Journal.MaxBytes
+	sessionJournal.MaxAge = hf.Journal.MaxAge
+	return sessionJournal
+}
+
+// sessionMode returns a copy of a mode that matches requests against, and captures requests into,
+// the simulation and state of the session rather than those of Hoverfly
+func (hf *Hoverfly) sessionMode(mode modes.Mode, requestSession *session.Session) modes.Mode {
+	sessionHoverfly := &sessionHoverfly{Hoverfly: hf, session: requestSession}
+
+	switch mode := mode.(type) {
+	case *modes.SimulateMode:
+		sessionMode := *mode
+		sessionMode.Hoverfly = sessionHoverfly
+		return &sessionMode
+	case *modes.SpyMode:
+		sessionMode := *mode
+		sessionMode.Hoverfly = sessionHoverfly
+		return &sessionMode
+	case *modes.CaptureMode:
+		sessionMode := *mode
+		sessionMode.Hoverfly = sessionHoverfly
+		return &sessionMode
+	case *modes.DiffMode:
+		sessionMode := *mode
+		sessionMode.Hoverfly = sessionHoverfly
+		return &sessionMode
+	}
+
+	return mode
+}
+
+// sessionHoverfly is the Hoverfly of the modes that handle the requests of a session
+type sessionHoverfly struct {
+	*Hoverfly
+	session *session.Session
+}
+
+// GetResponse matches the request against the simulation of the session. Sessions do not
+// have a request cache, so that changes to their simulation apply straight away. Requests
+// that do not match are left out of the unmatched requests of Hoverfly, which are misses
+// of its own simulation.
+func (this *sessionHoverfly) GetResponse(requestDetails models.RequestDetails) (*models.ResponseDetails, *errors.HoverflyError) {
+	cacheMatcher := &matching.CacheMatcher{Webserver: this.Cfg.Webserver}
+
+	return this.getResponse(requestDetails, cacheMatcher, []*models.Simulation{this.session.Simulation}, this.session.State, nil)
+}
+
+func (this *sessionHoverfly) Save(request *models.RequestDetails, response *models.ResponseDetails, modeArgs *modes.ModeArguments) error {
+	return saveInSimulation(this.session.Simulation, this.session.State, request, response, modeArgs)
+}
@@ -0,0 +1,186 @@
+package hoverfly
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	. "github.com/onsi/gomega"
+)
+
+func newPathPair(path, body string) *models.RequestMatcherResponsePair {
+	return &models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   path,
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+			Body:   body,
+		},
+	}
+}
+
+// sessionHeader is the header that the tests turn sessions on with, as they are off by default
+const sessionHeader = "Hoverfly-Session"
+
+func newHoverflyWithSessions() *Hoverfly {
+	unit := NewHoverflyWithConfiguration(InitSettings())
+	unit.Cfg.SessionHeader = sessionHeader
+	return unit
+}
+
+func newSessionRequest(path, session string) *http.Request {
+	request, _ := http.NewRequest(http.MethodGet, "http://test.com"+path, nil)
+	if session != "" {
+		request.Header.Set(sessionHeader, session)
+	}
+	return request
+}
+
+func Test_Hoverfly_processRequest_MatchesRequestsInASessionAgainstTheSessionSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newHoverflyWithSessions()
+	unit.Simulation.AddPair(newPathPair("/path", "default"))
+	unit.Sessions.GetOrCreate("first").Simulation.AddPair(newPathPair("/path", "first"))
+
+	response := unit.processRequest(newSessionRequest("/path", "first"))
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(util.GetResponseBody(response)).To(Equal("first"))
+
+	response = unit.processRequest(newSessionRequest("/path", ""))
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(util.GetResponseBody(response)).To(Equal("default"))
+
+	response = unit.processRequest(newSessionRequest("/path", "second"))
+	Expect(response.StatusCode).To(Equal(http.StatusBadGateway))
+
+	Expect(unit.Sessions.Get("second")).ToNot(BeNil())
+}
+
+func Test_Hoverfly_processRequest_LeavesSessionMissesOutOfTheUnmatchedRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newHoverflyWithSessions()
+
+	response := unit.processRequest(newSessionRequest("/path", "first"))
+	Expect(response.StatusCode).To(Equal(http.StatusBadGateway))
+	Expect(unit.GetUnmatchedRequests().Unmatched).To(BeEmpty())
+
+	response = unit.processRequest(newSessionRequest("/path", ""))
+	Expect(response.StatusCode).To(Equal(http.StatusBadGateway))
+	Expect(unit.GetUnmatchedRequests().Unmatched).To(HaveLen(1))
+}
+
+func Test_Hoverfly_processRequest_RemovesTheSessionHeader(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newHoverflyWithSessions()
+
+	request := newSessionRequest("/path", "first")
+	unit.processRequest(request)
+
+	Expect(request.Header).ToNot(HaveKey(sessionHeader))
+}
+
+func Test_Hoverfly_processRequest_KeepsTheStateOfEachSession(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newHoverflyWithSessions()
+
+	pair := newPathPair("/checkout", "checked out")
+	pair.Response.TransitionsState = map[string]string{"page": "checkout"}
+	unit.Sessions.GetOrCreate("first").Simulation.AddPair(pair)
+	unit.Sessions.GetOrCreate("second").Simulation.AddPair(pair)
+
+	unit.processRequest(newSessionRequest("/checkout", "first"))
+
+	Expect(unit.Sessions.Get("first").GetState()).To(Equal(map[string]string{"page": "checkout"}))
+	Expect(unit.Sessions.Get("second").GetState()).To(BeEmpty())
+	Expect(unit.GetState()).To(BeEmpty())
+}
+
+func Test_Hoverfly_processRequest_CapturesRequestsInASessionIntoTheSessionSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	server, unit := testTools(201, `{'message': 'here'}`)
+	defer server.Close()
+
+	unit.Cfg.SessionHeader = sessionHeader
+	unit.Cfg.SetMode("capture")
+	response := unit.processRequest(newSessionRequest("/path", "first"))
+	Expect(response.StatusCode).To(Equal(http.StatusCreated))
+
+	Expect(unit.Sessions.Get("first").Simulation.GetMatchingPairs()).To(HaveLen(1))
+	Expect(unit.Simulation.GetMatchingPairs()).To(BeEmpty())
+}
+
+func Test_Hoverfly_processRequest_IgnoresTheSessionHeaderByDefault(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(InitSettings())
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddPair(newPathPair("/path", "default"))
+
+	response := unit.processRequest(newSessionRequest("/path", "first"))
+	Expect(util.GetResponseBody(response)).To(Equal("default"))
+
+	Expect(unit.Sessions.GetAll()).To(BeEmpty())
+}
+
+func Test_Hoverfly_getSession_UsesTheProxyUserWhenSessionsAreKeyedByProxyUser(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newHoverflyWithSessions()
+
+	request := newSessionRequest("/path", "")
+	setContextValue(request, proxyUserContextKey, "benjih")
+
+	Expect(unit.getSession(request)).To(BeNil())
+
+	unit.Cfg.SessionsByProxyUser = true
+
+	Expect(unit.getSession(request).Name).To(Equal("benjih"))
+	Expect(getRequestSession(request).Name).To(Equal("benjih"))
+
+	request.Header.Set(sessionHeader, "first")
+
+	Expect(unit.getSession(request).Name).To(Equal("first"))
+}
+
+func Test_recordRequest_RecordsRequestsInASessionInTheSessionJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newHoverflyWithSessions()
+	unit.Cfg.SetMode("simulate")
+
+	for _, session := range []string{"first", "first", ""} {
+		request := newSessionRequest("/path", session)
+		unit.recordRequest(request, unit.processRequest(request), time.Now())
+	}
+
+	journalView, err := unit.Sessions.Get("first").Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(2))
+
+	journalView, err = unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+}
+
+func Test_getBasicAuthUsername(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(getBasicAuthUsername("Basic YmVuamloOnBhc3N3b3Jk")).To(Equal("benjih"))
+	Expect(getBasicAuthUsername("Bearer token")).To(Equal(""))
+	Expect(getBasicAuthUsername("Basic not-base64")).To(Equal(""))
+	Expect(getBasicAuthUsername("")).To(Equal(""))
+}
@@ -39,9 +39,13 @@ type Configuration struct {
 
 	ProxyAuthorizationHeader string
 
+	SessionHeader       string
+	SessionsByProxyUser bool
+
 	HttpsOnly bool
 
 	PlainHttpTunneling bool
+	Http2              bool
 	CORS cors.Configs
 
 	NoImportCheck bool
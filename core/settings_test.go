@@ -137,6 +137,14 @@ func Test_InitSettings_SetsPlainHttpTunnelingToFalse(t *testing.T) {
 	Expect(settings.PlainHttpTunneling).To(Equal(false))
 }
 
+func Test_InitSettings_DisablesSessions(t *testing.T) {
+	RegisterTestingT(t)
+
+	settings := InitSettings()
+
+	Expect(settings.SessionHeader).To(Equal(""))
+}
+
 
 func Test_InitSettings_SetsDefaultCacheSize(t *testing.T) {
 	RegisterTestingT(t)
@@ -37,6 +37,17 @@ func (s *State) GetState(key string) (string, bool) {
 	return val, ok
 }
 
+// GetAllState returns a copy of the state, which can be read while requests change the state
+func (s *State) GetAllState() map[string]string {
+	s.RWMutex.RLock()
+	state := make(map[string]string, len(s.State))
+	for k, v := range s.State {
+		state[k] = v
+	}
+	s.RWMutex.RUnlock()
+	return state
+}
+
 func (s *State) SetState(state map[string]string) {
 	s.RWMutex.Lock()
 	s.State = state
@@ -51,6 +62,17 @@ func (s *State) PatchState(toPatch map[string]string) {
 	s.RWMutex.Unlock()
 }
 
+// PatchMissingState sets the keys that are not in the state yet, leaving those that are as they are
+func (s *State) PatchMissingState(toPatch map[string]string) {
+	s.RWMutex.Lock()
+	for k, v := range toPatch {
+		if _, ok := s.State[k]; !ok {
+			s.State[k] = v
+		}
+	}
+	s.RWMutex.Unlock()
+}
+
 func (s *State) RemoveState(toRemove []string) {
 	s.RWMutex.Lock()
 	for _, key := range toRemove {
@@ -68,6 +68,19 @@ func Test_GetState(t *testing.T) {
 	}).Should(Equal("3"))
 }
 
+func Test_GetAllState_ReturnsACopyOfTheState(t *testing.T) {
+	RegisterTestingT(t)
+
+	s := state.NewState()
+	s.PatchState(map[string]string{"test1": "1"})
+
+	allState := s.GetAllState()
+	Expect(allState).To(Equal(map[string]string{"test1": "1"}))
+
+	s.PatchState(map[string]string{"test2": "2"})
+	Expect(allState).To(Equal(map[string]string{"test1": "1"}))
+}
+
 func Test_PatchState(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -102,6 +115,25 @@ func Test_PatchState(t *testing.T) {
 	}))
 }
 
+func Test_PatchMissingState(t *testing.T) {
+	RegisterTestingT(t)
+
+	s := state.NewState()
+	s.State = map[string]string{
+		"test1": "1",
+	}
+
+	s.PatchMissingState(map[string]string{
+		"test1": "modified",
+		"test2": "2",
+	})
+
+	Expect(s.State).To(Equal(map[string]string{
+		"test1": "1",
+		"test2": "2",
+	}))
+}
+
 func Test_RemoveState(t *testing.T) {
 	RegisterTestingT(t)
 
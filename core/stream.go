@@ -0,0 +1,65 @@
+package hoverfly
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+)
+
+// streamResponse makes a response reach the client as it is written rather than once
+// it has been written in full. A simulated response with chunks has its body replaced
+// by them, each sent once its delay has passed.
+func streamResponse(flusher http.Flusher, response *http.Response, chunks []models.ResponseChunk) {
+	if len(chunks) > 0 {
+		response.Body.Close()
+		response.Body = &chunkedBody{chunks: chunks}
+	}
+
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+
+	if flusher != nil {
+		response.Body = &flushingBody{ReadCloser: response.Body, flusher: flusher}
+	}
+}
+
+// chunkedBody reads the body of each chunk in turn, after waiting for its delay
+type chunkedBody struct {
+	chunks    []models.ResponseChunk
+	remaining string
+}
+
+func (b *chunkedBody) Read(p []byte) (int, error) {
+	if len(b.remaining) == 0 {
+		if len(b.chunks) == 0 {
+			return 0, io.EOF
+		}
+
+		chunk := b.chunks[0]
+		b.chunks = b.chunks[1:]
+		time.Sleep(time.Duration(chunk.Delay) * time.Millisecond)
+		b.remaining = chunk.Body
+	}
+
+	n := copy(p, b.remaining)
+	b.remaining = b.remaining[n:]
+	return n, nil
+}
+
+func (b *chunkedBody) Close() error {
+	return nil
+}
+
+// flushingBody pushes what has been written to the client before each read, which may wait
+// for the next part of the body. The first flush sends the headers.
+type flushingBody struct {
+	io.ReadCloser
+	flusher http.Flusher
+}
+
+func (b *flushingBody) Read(p []byte) (int, error) {
+	b.flusher.Flush()
+	return b.ReadCloser.Read(p)
+}
@@ -0,0 +1,151 @@
+package hoverfly
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+var streamedResponse = models.ResponseDetails{
+	Status: 200,
+	Headers: map[string][]string{
+		"Content-Type": {"text/event-stream"},
+	},
+	Chunks: []models.ResponseChunk{
+		{Body: "data: 1\n\n"},
+		{Body: "data: 2\n\n", Delay: 300},
+	},
+}
+
+// expectStreamedChunks reads the events of a response, checking that the first
+// arrives before the second has been sent
+func expectStreamedChunks(response *http.Response) {
+	Expect(response.StatusCode).To(Equal(200))
+	Expect(response.ContentLength).To(Equal(int64(-1)))
+
+	reader := bufio.NewReader(response.Body)
+	start := time.Now()
+
+	first, err := reader.ReadString('\n')
+	Expect(err).To(BeNil())
+	Expect(first).To(Equal("data: 1\n"))
+	Expect(time.Since(start)).To(BeNumerically("<", 250*time.Millisecond))
+
+	rest, err := ioutil.ReadAll(reader)
+	Expect(err).To(BeNil())
+	Expect(string(rest)).To(Equal("\ndata: 2\n\n"))
+	Expect(time.Since(start)).To(BeNumerically(">=", 250*time.Millisecond))
+}
+
+func Test_Hoverfly_Webserver_StreamsChunks(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9791", true, "/events", streamedResponse)
+	defer unit.StopProxy()
+
+	response, err := http.Get("http://localhost:9791/events")
+	Expect(err).To(BeNil())
+	Expect(response.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+	expectStreamedChunks(response)
+}
+
+func Test_Hoverfly_Proxy_StreamsChunks(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := startHoverflyWithResponse("9792", false, "/events", streamedResponse)
+	defer unit.StopProxy()
+
+	proxyUrl, _ := url.Parse(fmt.Sprintf("http://localhost:%s", unit.Cfg.ProxyPort))
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyUrl)}}
+
+	response, err := client.Get("http://hoverfly.io/events")
+	Expect(err).To(BeNil())
+
+	expectStreamedChunks(response)
+
+	journalView, err := unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+}
+
+func Test_Hoverfly_Proxy_RendersTemplatedChunks(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		Response: models.ResponseDetails{
+			Status:    200,
+			Templated: true,
+			Chunks: []models.ResponseChunk{
+				{Body: "data: {{ Request.Path.[0] }}\n\n"},
+			},
+		},
+	})
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	proxyUrl, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyUrl)}}
+
+	response, err := client.Get("http://hoverfly.io/events")
+	Expect(err).To(BeNil())
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("data: events\n\n"))
+	Expect(unit.Simulation.GetMatchingPairs()[0].Response.Chunks[0].Body).To(Equal("data: {{ Request.Path.[0] }}\n\n"))
+}
+
+func Test_Hoverfly_Proxy_CapturesEventStreamAsChunks(t *testing.T) {
+	RegisterTestingT(t)
+
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("event: last\r\ndata: 2\r\n\r\ndata: 3"))
+	}))
+	defer destination.Close()
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Cfg.SetMode("capture")
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	proxyUrl, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyUrl)}}
+
+	response, err := client.Get(destination.URL + "/events")
+	Expect(err).To(BeNil())
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("data: 1\n\nevent: last\r\ndata: 2\r\n\r\ndata: 3"))
+
+	Eventually(unit.Simulation.GetMatchingPairs).Should(HaveLen(1))
+	pair := unit.Simulation.GetMatchingPairs()[0]
+	Expect(pair.Response.Body).To(BeEmpty())
+	Expect(pair.Response.Headers["Content-Type"]).To(Equal([]string{"text/event-stream"}))
+	Expect(pair.Response.Chunks).To(HaveLen(3))
+	Expect(pair.Response.Chunks[0].Body).To(Equal("data: 1\n\n"))
+	Expect(pair.Response.Chunks[1].Body).To(Equal("event: last\r\ndata: 2\r\n\r\n"))
+	Expect(pair.Response.Chunks[1].Delay).To(BeNumerically(">=", 200))
+	Expect(pair.Response.Chunks[2].Body).To(Equal("data: 3"))
+
+	journalView, err := unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+}
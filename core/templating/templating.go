@@ -2,10 +2,14 @@ package templating
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/util"
 	"github.com/aymerick/raymond"
 )
 
@@ -16,12 +20,18 @@ type TemplatingData struct {
 }
 
 type Request struct {
-	QueryParam map[string][]string
-	Path       []string
-	Scheme     string
-	Body       func(queryType, query string, options *raymond.Options) string
-	body       string
-	Method     string
+	QueryParam  map[string][]string
+	Header      map[string][]string
+	Cookie      map[string]string
+	FormData    map[string][]string
+	Path        []string
+	Scheme      string
+	Host        string
+	Destination string
+	URL         string
+	Body        func(queryType, query string, options *raymond.Options) string
+	body        string
+	Method      string
 }
 
 type Templator struct {
@@ -72,16 +82,21 @@ func (*Templator) RenderTemplate(tpl *raymond.Template, requestDetails *models.R
 	return tpl.Exec(ctx)
 }
 
-
 func NewTemplatingDataFromRequest(requestDetails *models.RequestDetails, state map[string]string) *TemplatingData {
 	return &TemplatingData{
 		Request: Request{
-			Path:       strings.Split(requestDetails.Path, "/")[1:],
-			QueryParam: requestDetails.Query,
-			Scheme:     requestDetails.Scheme,
-			Body:       templateHelpers{}.requestBody,
-			body:       requestDetails.Body,
-			Method:     requestDetails.Method,
+			Path:        strings.Split(requestDetails.Path, "/")[1:],
+			QueryParam:  requestDetails.Query,
+			Header:      requestDetails.Headers,
+			Cookie:      getCookies(requestDetails.Headers),
+			FormData:    getFormData(requestDetails),
+			Scheme:      requestDetails.Scheme,
+			Host:        getHost(requestDetails.Destination),
+			Destination: requestDetails.Destination,
+			URL:         getURL(requestDetails),
+			Body:        templateHelpers{}.requestBody,
+			body:        requestDetails.Body,
+			Method:      requestDetails.Method,
 		},
 		State: state,
 		CurrentDateTime: func(a1, a2, a3 string) string {
@@ -90,3 +105,52 @@ func NewTemplatingDataFromRequest(requestDetails *models.RequestDetails, state m
 	}
 
 }
+
+func getHost(destination string) string {
+	host, _, err := net.SplitHostPort(destination)
+	if err != nil {
+		return destination
+	}
+
+	return host
+}
+
+func getURL(requestDetails *models.RequestDetails) string {
+	query := requestDetails.GetRawQuery()
+	if query == "" {
+		query = requestDetails.QueryString()
+	}
+
+	requestUrl := url.URL{
+		Scheme:   requestDetails.Scheme,
+		Host:     requestDetails.Destination,
+		Opaque:   "//" + requestDetails.Destination + requestDetails.Path,
+		RawQuery: query,
+	}
+
+	return requestUrl.String()
+}
+
+func getCookies(headers map[string][]string) map[string]string {
+	cookies := map[string]string{}
+	request := http.Request{Header: headers}
+	for _, cookie := range request.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+
+	return cookies
+}
+
+func getFormData(requestDetails *models.RequestDetails) map[string][]string {
+	contentType := requestDetails.Headers["Content-Type"]
+	if len(contentType) == 0 {
+		return map[string][]string{}
+	}
+
+	formData, err := util.ParseFormBody(requestDetails.Body, contentType[0])
+	if err != nil {
+		return map[string][]string{}
+	}
+
+	return formData
+}
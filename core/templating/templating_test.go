@@ -85,6 +85,106 @@ func Test_ShouldCreateTemplatingDataHttpScheme(t *testing.T) {
 	Expect(actual.Request.Scheme).To(Equal("http"))
 }
 
+func Test_ShouldCreateTemplatingDataHeadersFromRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	actual := templating.NewTemplatingDataFromRequest(&models.RequestDetails{
+		Scheme:      "http",
+		Destination: "test.com",
+		Headers: map[string][]string{
+			"X-Correlation-Id": {"abc-123"},
+		},
+	},
+		make(map[string]string),
+	)
+
+	Expect(actual.Request.Header).To(HaveKeyWithValue("X-Correlation-Id", []string{"abc-123"}))
+}
+
+func Test_ShouldCreateTemplatingDataHostAndDestinationFromRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	actual := templating.NewTemplatingDataFromRequest(&models.RequestDetails{
+		Scheme:      "http",
+		Destination: "test.com:8080",
+	},
+		make(map[string]string),
+	)
+
+	Expect(actual.Request.Host).To(Equal("test.com"))
+	Expect(actual.Request.Destination).To(Equal("test.com:8080"))
+}
+
+func Test_ShouldCreateTemplatingDataURLFromRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	actual := templating.NewTemplatingDataFromRequest(&models.RequestDetails{
+		Scheme:      "https",
+		Destination: "test.com",
+		Path:        "/foo/bar",
+		Query: map[string][]string{
+			"b": {"2"},
+			"a": {"1"},
+		},
+	},
+		make(map[string]string),
+	)
+
+	Expect(actual.Request.URL).To(Equal("https://test.com/foo/bar?a=1&b=2"))
+}
+
+func Test_ShouldCreateTemplatingDataCookiesFromRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	actual := templating.NewTemplatingDataFromRequest(&models.RequestDetails{
+		Scheme:      "http",
+		Destination: "test.com",
+		Headers: map[string][]string{
+			"Cookie": {"session=abc; theme=dark"},
+		},
+	},
+		make(map[string]string),
+	)
+
+	Expect(actual.Request.Cookie).To(HaveKeyWithValue("session", "abc"))
+	Expect(actual.Request.Cookie).To(HaveKeyWithValue("theme", "dark"))
+}
+
+func Test_ShouldCreateTemplatingDataFormDataFromRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	actual := templating.NewTemplatingDataFromRequest(&models.RequestDetails{
+		Scheme:      "http",
+		Destination: "test.com",
+		Headers: map[string][]string{
+			"Content-Type": {"application/x-www-form-urlencoded"},
+		},
+		Body: "name=hoverfly&tags=a&tags=b",
+	},
+		make(map[string]string),
+	)
+
+	Expect(actual.Request.FormData).To(HaveKeyWithValue("name", []string{"hoverfly"}))
+	Expect(actual.Request.FormData).To(HaveKeyWithValue("tags", []string{"a", "b"}))
+}
+
+func Test_ShouldCreateEmptyTemplatingDataFormDataFromRequestWithoutFormBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	actual := templating.NewTemplatingDataFromRequest(&models.RequestDetails{
+		Scheme:      "http",
+		Destination: "test.com",
+		Headers: map[string][]string{
+			"Content-Type": {"application/json"},
+		},
+		Body: `{"name": "hoverfly"}`,
+	},
+		make(map[string]string),
+	)
+
+	Expect(actual.Request.FormData).To(BeEmpty())
+}
+
 func TestApplyTemplateWithQueryParams(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -371,6 +471,43 @@ func Test_ApplyTemplate_ReplaceStringInQueryParams(t *testing.T) {
 	Expect(template).To(Equal(`moo,moo,moo`))
 }
 
+func Test_ApplyTemplate_RequestHeadersHostDestinationAndURL(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{
+		Method:      "GET",
+		Scheme:      "http",
+		Destination: "foo.com:8500",
+		Path:        "/foo/bar",
+		Query: map[string][]string{
+			"id": {"1"},
+		},
+		Headers: map[string][]string{
+			"X-Correlation-Id": {"abc-123"},
+			"Cookie":           {"session=xyz"},
+		},
+	}, make(map[string]string), `{{ Request.Header.X-Correlation-Id }} {{ Request.Host }} {{ Request.Destination }} {{ Request.URL }} {{ Request.Cookie.session }}`)
+
+	Expect(err).To(BeNil())
+
+	Expect(template).To(Equal(`abc-123 foo.com foo.com:8500 http://foo.com:8500/foo/bar?id=1 xyz`))
+}
+
+func Test_ApplyTemplate_RequestFormData(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{
+		Headers: map[string][]string{
+			"Content-Type": {"multipart/form-data; boundary=xxx"},
+		},
+		Body: "--xxx\r\nContent-Disposition: form-data; name=\"username\"\r\n\r\nhoverfly\r\n--xxx--\r\n",
+	}, make(map[string]string), `{{ Request.FormData.username }}`)
+
+	Expect(err).To(BeNil())
+
+	Expect(template).To(Equal(`hoverfly`))
+}
+
 func ApplyTemplate(requestDetails *models.RequestDetails, state map[string]string, responseBody string) (string, error) {
 	templator := templating.NewTemplator()
 	template, _ := templator.ParseTemplate(responseBody)
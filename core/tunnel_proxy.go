@@ -0,0 +1,202 @@
+package hoverfly
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/SpectoLabs/goproxy"
+	"golang.org/x/net/http2"
+)
+
+// http2Preface is what a client sends first on a connection that speaks HTTP/2 without TLS
+const http2Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+// hopHeaders are the headers of HTTP/1.1 that are not allowed in HTTP/2 responses
+var hopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Connection", "Transfer-Encoding", "Upgrade"}
+
+type tunnelResponseKey struct{}
+
+// newTunnelConnectAction MITMs a CONNECT tunnel like goproxy does, but serves the requests in it with
+// net/http, so that a WebSocket handshake can take over the connection. With HTTP/2 enabled the client
+// can also speak HTTP/2, negotiated over TLS or started with the HTTP/2 preface on plain HTTP tunnels.
+// The requests in the tunnel are given to the tunnel proxy as if they had been sent to it directly.
+func newTunnelConnectAction(tunnelProxy *goproxy.ProxyHttpServer, isTls bool, http2Enabled bool) *goproxy.ConnectAction {
+	return &goproxy.ConnectAction{
+		Action: goproxy.ConnectHijack,
+		Hijack: func(connect *http.Request, client net.Conn, ctx *goproxy.ProxyCtx) {
+			defer client.Close()
+
+			if !isTls {
+				reader := bufio.NewReader(client)
+				isHttp2 := false
+				if http2Enabled {
+					preface, err := reader.Peek(len(http2Preface))
+					isHttp2 = err == nil && string(preface) == http2Preface
+				}
+				serveTunnel(tunnelProxy, connect, bufferedConn{Conn: client, reader: reader}, "http", isHttp2)
+				return
+			}
+
+			tlsConfig, err := goproxy.TLSConfigFromCA(&goproxy.GoproxyCa)(connect.URL.Host, ctx)
+			if err != nil {
+				ctx.Warnf("Cannot sign certificate for %s: %v", connect.URL.Host, err)
+				return
+			}
+			tlsConfig.NextProtos = []string{"http/1.1"}
+			if http2Enabled {
+				tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+			}
+
+			tlsClient := tls.Server(client, tlsConfig)
+			if err := tlsClient.Handshake(); err != nil {
+				ctx.Warnf("Cannot handshake client %v: %v", connect.Host, err)
+				return
+			}
+			serveTunnel(tunnelProxy, connect, tlsClient, "https", tlsClient.ConnectionState().NegotiatedProtocol == "h2")
+		},
+	}
+}
+
+// serveTunnel serves the requests a client sends through a CONNECT tunnel until it closes the connection
+func serveTunnel(tunnelProxy *goproxy.ProxyHttpServer, connect *http.Request, conn net.Conn, scheme string, isHttp2 bool) {
+	// A request that hijacks the connection, such as a WebSocket handshake, is still being handled
+	// after the server has stopped, and needs the connection until it is done
+	var handling sync.WaitGroup
+	defer handling.Wait()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handling.Add(1)
+		defer handling.Done()
+
+		r.URL.Scheme = scheme
+		r.URL.Host = connect.Host
+		r.RemoteAddr = connect.RemoteAddr
+
+		var response *http.Response
+		r = r.WithContext(context.WithValue(r.Context(), tunnelResponseKey{}, &response))
+
+		w = tunnelResponseWriter{ResponseWriter: w, response: &response, isHttp2: isHttp2}
+		withResponseWriter(tunnelProxy).ServeHTTP(w, r)
+
+		// Trailers are only known once the body has been read
+		if response != nil {
+			for name, values := range response.Trailer {
+				w.Header()[http.TrailerPrefix+name] = values
+			}
+		}
+	})
+
+	if isHttp2 {
+		server := &http2.Server{}
+		server.ServeConn(conn, &http2.ServeConnOpts{Handler: handler})
+		return
+	}
+
+	listener := newConnListener(conn)
+	server := &http.Server{
+		Handler: handler,
+		ConnState: func(_ net.Conn, state http.ConnState) {
+			if state == http.StateClosed || state == http.StateHijacked {
+				listener.Close()
+			}
+		},
+	}
+	server.Serve(listener)
+}
+
+// setTunnelResponse keeps the response to a request in a tunnel, so that its trailers can be sent
+func setTunnelResponse(request *http.Request, response *http.Response) {
+	if tunnelResponse, ok := request.Context().Value(tunnelResponseKey{}).(**http.Response); ok {
+		*tunnelResponse = response
+	}
+}
+
+// bufferedConn reads from the reader that already buffered the start of the connection
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// tunnelResponseWriter leaves out the headers that HTTP/2 does not allow, and declares the trailers
+// of a response instead of its length, as trailers are only sent after a body of unknown length
+type tunnelResponseWriter struct {
+	http.ResponseWriter
+	response **http.Response
+	isHttp2  bool
+}
+
+func (w tunnelResponseWriter) WriteHeader(statusCode int) {
+	if w.isHttp2 {
+		for _, key := range hopHeaders {
+			w.Header().Del(key)
+		}
+	}
+	if response := *w.response; response != nil && len(response.Trailer) > 0 {
+		w.Header().Del("Content-Length")
+		for name := range response.Trailer {
+			w.Header().Add("Trailer", name)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w tunnelResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w tunnelResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("connection cannot be hijacked")
+	}
+	return hijacker.Hijack()
+}
+
+// connListener accepts a single connection, then waits for it to close
+type connListener struct {
+	conn   net.Conn
+	addr   net.Addr
+	mutex  sync.Mutex
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newConnListener(conn net.Conn) *connListener {
+	return &connListener{conn: conn, addr: conn.LocalAddr(), closed: make(chan struct{})}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	l.mutex.Lock()
+	conn := l.conn
+	l.conn = nil
+	l.mutex.Unlock()
+
+	if conn != nil {
+		return conn, nil
+	}
+
+	<-l.closed
+	return nil, errors.New("connection closed")
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
@@ -0,0 +1,86 @@
+package hoverfly
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/http2"
+)
+
+func newTunnelProxyWithResponse(cfg *Configuration) (*Hoverfly, *httptest.Server) {
+	unit := NewHoverflyWithConfiguration(cfg)
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		Response: models.ResponseDetails{
+			Status:  http.StatusOK,
+			Body:    "response",
+			Headers: map[string][]string{"Trailer": {"Checksum"}, "Checksum": {"abc"}},
+		},
+	})
+
+	return unit, httptest.NewServer(NewProxy(unit))
+}
+
+func Test_NewProxy_WithHttp2ServesHttp1ClientsOverTls(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, proxyServer := newTunnelProxyWithResponse(&Configuration{Http2: true})
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+
+	response, err := client.Get("https://test.com/path")
+	Expect(err).To(BeNil())
+	Expect(response.ProtoMajor).To(Equal(1))
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("response"))
+	Expect(response.Trailer.Get("Checksum")).To(Equal("abc"))
+	Expect(unit.Counter.Counters["simulate"].Count()).To(Equal(int64(1)))
+}
+
+func Test_NewProxy_WithHttp2ServesCleartextHttp2InPlainTunnels(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, proxyServer := newTunnelProxyWithResponse(&Configuration{Http2: true, PlainHttpTunneling: true})
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			conn, err := net.Dial(network, proxyURL.Host)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", addr, addr)
+			if _, err := http.ReadResponse(bufio.NewReader(conn), nil); err != nil {
+				return nil, err
+			}
+			return conn, nil
+		},
+	}}
+
+	response, err := client.Get("http://test.com:8080/path")
+	Expect(err).To(BeNil())
+	Expect(response.ProtoMajor).To(Equal(2))
+
+	body, err := ioutil.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("response"))
+	Expect(response.Trailer.Get("Checksum")).To(Equal("abc"))
+}
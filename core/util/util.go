@@ -3,7 +3,11 @@ package util
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -143,10 +147,48 @@ func GetContentTypeFromHeaders(headers map[string][]string) string {
 		if regexp.MustCompile("[/+]xml$").MatchString(v) {
 			return "xml"
 		}
+		if regexp.MustCompile("^(application/x-www-form-urlencoded|multipart/form-data)").MatchString(v) {
+			return "form"
+		}
 	}
 	return ""
 }
 
+// ParseFormBody will parse an application/x-www-form-urlencoded or
+// multipart/form-data body into its named fields. The Content-Type
+// header value is needed to find the multipart boundary.
+func ParseFormBody(body, contentType string) (map[string][]string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		return url.ParseQuery(body)
+	case "multipart/form-data":
+		fields := map[string][]string{}
+		reader := multipart.NewReader(strings.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return fields, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := ioutil.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+			fields[part.FormName()] = append(fields[part.FormName()], string(value))
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not a form content type", mediaType)
+}
+
 func JSONMarshal(t interface{}) ([]byte, error) {
 	buffer := &bytes.Buffer{}
 	encoder := json.NewEncoder(buffer)
@@ -140,6 +140,22 @@ func Test_GetContentTypeFromHeaders_ReturnsXmlIfXml(t *testing.T) {
 	})).To(Equal("xml"))
 }
 
+func Test_GetContentTypeFromHeaders_ReturnsFormIfUrlEncodedForm(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetContentTypeFromHeaders(map[string][]string{
+		"Content-Type": {"application/x-www-form-urlencoded"},
+	})).To(Equal("form"))
+}
+
+func Test_GetContentTypeFromHeaders_ReturnsFormIfMultipartForm(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetContentTypeFromHeaders(map[string][]string{
+		"Content-Type": {"multipart/form-data; boundary=xxx"},
+	})).To(Equal("form"))
+}
+
 func Test_JSONMarshal_MarshalsIntoJson(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -210,3 +226,36 @@ func Test_CopyMap(t *testing.T) {
 	Expect(newMap["first"]).To(Equal("1"))
 	Expect(newMap["second"]).To(Equal("2"))
 }
+
+func Test_ParseFormBody_ParsesUrlEncodedBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	fields, err := ParseFormBody("a=1&b=2&b=3", "application/x-www-form-urlencoded")
+	Expect(err).To(BeNil())
+
+	Expect(fields).To(HaveKeyWithValue("a", []string{"1"}))
+	Expect(fields).To(HaveKeyWithValue("b", []string{"2", "3"}))
+}
+
+func Test_ParseFormBody_ParsesMultipartBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	body := "--xxx\r\n" +
+		"Content-Disposition: form-data; name=\"a\"\r\n\r\n1\r\n" +
+		"--xxx\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"file.txt\"\r\n\r\ncontents\r\n" +
+		"--xxx--\r\n"
+
+	fields, err := ParseFormBody(body, "multipart/form-data; boundary=xxx")
+	Expect(err).To(BeNil())
+
+	Expect(fields).To(HaveKeyWithValue("a", []string{"1"}))
+	Expect(fields).To(HaveKeyWithValue("file", []string{"contents"}))
+}
+
+func Test_ParseFormBody_ErrorsOnNonFormContentType(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := ParseFormBody(`{"a": 1}`, "application/json")
+	Expect(err).ToNot(BeNil())
+}
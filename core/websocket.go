@@ -0,0 +1,529 @@
+package hoverfly
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/errors"
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+)
+
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// webSocketHandshakeHeaders are only meaningful on the connection they were sent on, so they
+// are neither matched nor captured, and are not sent on to the destination
+var webSocketHandshakeHeaders = []string{
+	"Connection",
+	"Upgrade",
+	"Sec-Websocket-Key",
+	"Sec-Websocket-Version",
+	"Sec-Websocket-Extensions",
+	"Sec-Websocket-Accept",
+	"Proxy-Connection",
+}
+
+var webSocketUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// isWebSocketHandshake returns whether the request is the handshake that opens a WebSocket
+func isWebSocketHandshake(req *http.Request) bool {
+	return req.Method == http.MethodGet &&
+		websocket.IsWebSocketUpgrade(req) &&
+		req.Header.Get("Sec-Websocket-Version") == "13" &&
+		req.Header.Get("Sec-Websocket-Key") != ""
+}
+
+// processWebSocket handles a WebSocket handshake, and records it in the journal. In simulate and spy
+// mode it opens a WebSocket from the simulation whose request matches the handshake. Otherwise, and
+// in spy mode when there is no match, the WebSocket is relayed to the destination, and in capture mode
+// it is added to the simulation once it closes.
+func (hf *Hoverfly) processWebSocket(req *http.Request, started time.Time) *http.Response {
+	requestSession := hf.getSession(req)
+
+	simulation := hf.Simulation
+	simulations := hf.SimulationSets.GetMatchingSimulations(hf.Simulation)
+	requestState := hf.state
+	if requestSession != nil {
+		simulation = requestSession.Simulation
+		simulations = []*models.Simulation{requestSession.Simulation}
+		requestState = requestSession.State
+	}
+
+	var response *http.Response
+	requestDetails, err := models.NewRequestDetailsFromHttpRequest(req)
+	if err != nil {
+		response = modes.ErrorResponse(req, err, "Could not interpret HTTP request")
+	} else {
+		requestDetails.Headers = removeWebSocketHandshakeHeaders(requestDetails.Headers)
+		response = hf.openWebSocket(req, requestDetails, simulation, simulations, requestState)
+	}
+
+	// The body of the handshake response is the connection to the client, so it is left for goproxy
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		hf.recordRequest(req, response, started)
+		return response
+	}
+
+	journalResponse := *response
+	journalResponse.Body = ioutil.NopCloser(bytes.NewBuffer([]byte{}))
+	hf.recordRequest(req, &journalResponse, started)
+
+	return response
+}
+
+func (hf *Hoverfly) openWebSocket(req *http.Request, requestDetails models.RequestDetails, simulation *models.Simulation, simulations []*models.Simulation, requestState *state.State) *http.Response {
+	switch mode := hf.Cfg.GetMode(); mode {
+	case modes.Simulate, modes.Spy:
+		webSocket, matchErr := hf.matchWebSocket(requestDetails, simulations, requestState)
+		if webSocket != nil {
+			return newWebSocketResponse(req, webSocket.Headers, func(client *websocket.Conn) {
+				replayWebSocket(client, webSocket)
+			})
+		}
+
+		if mode == modes.Simulate {
+			response, _ := modes.ReturnErrorAndLog(req, errors.MatchingFailedError(matchErr.ClosestMiss),
+				&models.RequestResponsePair{Request: requestDetails}, "There was an error when matching", modes.Simulate)
+			return response
+		}
+
+		return hf.relayWebSocket(req, nil)
+	case modes.Capture:
+		captureMode := (hf.modeMap[modes.Capture]).(*modes.CaptureMode)
+
+		return hf.relayWebSocket(req, func(webSocket *models.WebSocket, headers map[string][]string) {
+			pair := newRequestMatcherResponsePair(&requestDetails, &models.ResponseDetails{}, &captureMode.Arguments)
+			webSocket.RequestMatcher = pair.RequestMatcher
+			webSocket.Headers = headers
+
+			if simulation.AddWebSocket(webSocket) {
+				log.WithFields(log.Fields{
+					"mode":    modes.Capture,
+					"request": modes.GetRequestLogFields(&requestDetails),
+				}).Info("WebSocket captured")
+			}
+		})
+	}
+
+	return hf.relayWebSocket(req, nil)
+}
+
+// matchWebSocket matches the handshake against the requests of the WebSockets in the simulations,
+// the same way as a request is matched against the requests of their pairs
+func (hf *Hoverfly) matchWebSocket(requestDetails models.RequestDetails, simulations []*models.Simulation, requestState *state.State) (*models.WebSocket, *models.MatchError) {
+	handshakeSimulations := []*models.Simulation{}
+	for _, simulation := range simulations {
+		handshakeSimulation := models.NewSimulation()
+		for _, webSocket := range simulation.GetWebSockets() {
+			handshakeSimulation.AddPairWithoutCheck(&models.RequestMatcherResponsePair{
+				RequestMatcher: webSocket.RequestMatcher,
+				Response: models.ResponseDetails{
+					Status: http.StatusSwitchingProtocols,
+				},
+			})
+		}
+		handshakeSimulations = append(handshakeSimulations, handshakeSimulation)
+	}
+
+	mode := (hf.modeMap[modes.Simulate]).(*modes.SimulateMode)
+	result := matching.MatchSimulations(mode.MatchingStrategy, requestDetails, hf.Cfg.Webserver, handshakeSimulations, requestState)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, simulation := range simulations {
+		for _, webSocket := range simulation.GetWebSockets() {
+			if reflect.DeepEqual(webSocket.RequestMatcher, result.Pair.RequestMatcher) {
+				return &webSocket, nil
+			}
+		}
+	}
+
+	return nil, models.NewMatchError("No match found")
+}
+
+// replayWebSocket sends the open frames of the WebSocket, then the frames of the first message that
+// matches each frame from the client, until the client closes the WebSocket. Frames are sent in order,
+// each after its delay.
+func replayWebSocket(client *websocket.Conn, webSocket *models.WebSocket) {
+	replies := make(chan []models.WebSocketFrame, 64)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for frames := range replies {
+			for _, frame := range frames {
+				time.Sleep(time.Duration(frame.Delay) * time.Millisecond)
+				// Frames that cannot be sent are dropped, as the client has gone
+				client.WriteMessage(getWebSocketMessageType(frame.Binary), []byte(frame.Body))
+			}
+		}
+	}()
+
+	replies <- webSocket.OnOpen
+
+	for {
+		messageType, body, err := client.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		value := models.GetWebSocketFrameMatchValue(body, messageType == websocket.BinaryMessage)
+		if message := findWebSocketMessage(webSocket, value); message != nil {
+			replies <- message.Frames
+		} else {
+			log.WithFields(log.Fields{
+				"body": value,
+			}).Warn("Failed to find matching message for WebSocket frame")
+		}
+	}
+
+	close(replies)
+	<-done
+}
+
+// findWebSocketMessage returns the first message of the WebSocket whose body matchers match the frame
+func findWebSocketMessage(webSocket *models.WebSocket, value string) *models.WebSocketMessage {
+	for i, message := range webSocket.OnMessage {
+		if matching.FieldMatcher(message.Body, value).Matched {
+			return &webSocket.OnMessage[i]
+		}
+	}
+
+	return nil
+}
+
+// relayWebSocket opens a WebSocket to the destination and relays the frames between it and the
+// client. When given a save function, the WebSocket is recorded and saved once it closes.
+func (hf *Hoverfly) relayWebSocket(req *http.Request, save func(*models.WebSocket, map[string][]string)) *http.Response {
+	destination := *req.URL
+	destination.Scheme = "ws"
+	if req.URL.Scheme == "https" {
+		destination.Scheme = "wss"
+	}
+	// Requests tunnelled as plain HTTP only have a path
+	if destination.Host == "" {
+		destination.Host = req.Host
+	}
+
+	client, err := GetHttpClient(hf, req.Host)
+	if err != nil {
+		return modes.ErrorResponse(req, err, "There was an error when forwarding the request to the intended destination")
+	}
+
+	dialer := &websocket.Dialer{}
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		dialer.Proxy = transport.Proxy
+		dialer.TLSClientConfig = transport.TLSClientConfig
+	}
+
+	header := http.Header{}
+	for key, values := range removeWebSocketHandshakeHeaders(req.Header) {
+		if key != ProxyAuthorizationHeader {
+			header[key] = values
+		}
+	}
+
+	upstream, upstreamResponse, err := dialer.Dial(destination.String(), header)
+	if err != nil {
+		// The destination refused the WebSocket, so the client gets its response instead
+		if upstreamResponse != nil {
+			return upstreamResponse
+		}
+		return modes.ErrorResponse(req, err, "There was an error when forwarding the request to the intended destination")
+	}
+
+	headers := removeWebSocketHandshakeHeaders(upstreamResponse.Header)
+
+	return newWebSocketResponse(req, headers, func(client *websocket.Conn) {
+		defer upstream.Close()
+
+		var recorder *webSocketRecorder
+		if save != nil {
+			recorder = newWebSocketRecorder()
+		}
+
+		relayWebSocketFrames(client, upstream, recorder)
+
+		if save != nil {
+			save(recorder.getWebSocket(), headers)
+		}
+	})
+}
+
+// relayWebSocketFrames sends the frames from each side of the WebSocket to the other,
+// until one of them closes it
+func relayWebSocketFrames(client, upstream *websocket.Conn, recorder *webSocketRecorder) {
+	done := make(chan struct{}, 2)
+
+	relay := func(from, to *websocket.Conn, fromClient bool) {
+		defer func() { done <- struct{}{} }()
+		for {
+			messageType, body, err := from.ReadMessage()
+			if err != nil {
+				if closeErr, ok := err.(*websocket.CloseError); ok {
+					to.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeErr.Code, closeErr.Text), time.Now().Add(time.Second))
+				}
+				return
+			}
+
+			if recorder != nil {
+				recorder.record(fromClient, messageType, body)
+			}
+
+			if err := to.WriteMessage(messageType, body); err != nil {
+				return
+			}
+		}
+	}
+
+	go relay(client, upstream, true)
+	go relay(upstream, client, false)
+
+	<-done
+	client.Close()
+	upstream.Close()
+	<-done
+}
+
+const (
+	webSocketOnOpen          = -1
+	webSocketIgnoredResponse = -2
+)
+
+// webSocketRecorder records the frames of a WebSocket in capture mode, along with the time between them.
+// The frames from the destination before the first frame from the client are sent when the WebSocket
+// opens, and those after each frame from the client are the frames of the message that matches it.
+type webSocketRecorder struct {
+	mutex     sync.Mutex
+	webSocket *models.WebSocket
+	message   int
+	last      time.Time
+}
+
+func newWebSocketRecorder() *webSocketRecorder {
+	return &webSocketRecorder{
+		webSocket: &models.WebSocket{},
+		message:   webSocketOnOpen,
+		last:      time.Now(),
+	}
+}
+
+func (this *webSocketRecorder) record(fromClient bool, messageType int, body []byte) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	now := time.Now()
+	delay := int(now.Sub(this.last) / time.Millisecond)
+	this.last = now
+
+	binary := messageType == websocket.BinaryMessage
+
+	if fromClient {
+		value := models.GetWebSocketFrameMatchValue(body, binary)
+
+		// Only the response to the first of the same frames is kept, as the first would always match
+		for _, message := range this.webSocket.OnMessage {
+			if len(message.Body) == 1 && message.Body[0].Value == value {
+				this.message = webSocketIgnoredResponse
+				return
+			}
+		}
+
+		this.webSocket.OnMessage = append(this.webSocket.OnMessage, models.WebSocketMessage{
+			Body: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   value,
+				},
+			},
+		})
+		this.message = len(this.webSocket.OnMessage) - 1
+		return
+	}
+
+	frame := models.WebSocketFrame{
+		Body:   string(body),
+		Binary: binary,
+		Delay:  delay,
+	}
+
+	switch this.message {
+	case webSocketOnOpen:
+		this.webSocket.OnOpen = append(this.webSocket.OnOpen, frame)
+	case webSocketIgnoredResponse:
+	default:
+		message := &this.webSocket.OnMessage[this.message]
+		message.Frames = append(message.Frames, frame)
+	}
+}
+
+func (this *webSocketRecorder) getWebSocket() *models.WebSocket {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	return this.webSocket
+}
+
+// newWebSocketResponse accepts the WebSocket handshake. The body of the response serves the
+// WebSocket once goproxy has written the response to the client.
+func newWebSocketResponse(req *http.Request, headers map[string][]string, serve func(*websocket.Conn)) *http.Response {
+	response := &http.Response{
+		Status:     "101 Switching Protocols",
+		StatusCode: http.StatusSwitchingProtocols,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+		Body: &webSocketBody{
+			request: req,
+			serve:   serve,
+		},
+	}
+
+	for key, values := range headers {
+		for _, value := range values {
+			response.Header.Add(key, value)
+		}
+	}
+
+	response.Header.Set("Upgrade", "websocket")
+	response.Header.Set("Connection", "Upgrade")
+	response.Header.Set("Sec-Websocket-Accept", computeWebSocketAccept(req.Header.Get("Sec-Websocket-Key")))
+
+	return response
+}
+
+func computeWebSocketAccept(key string) string {
+	hash := sha1.New()
+	io.WriteString(hash, key+webSocketGUID)
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// webSocketBody is the body of the response to a WebSocket handshake. goproxy copies the body to the
+// client after writing the response, which gives the body the connection to the client: the TLS
+// connection of a MITM proxied request, or a ResponseWriter that can be hijacked otherwise.
+type webSocketBody struct {
+	request *http.Request
+	serve   func(*websocket.Conn)
+}
+
+func (this *webSocketBody) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (this *webSocketBody) Close() error {
+	return nil
+}
+
+func (this *webSocketBody) WriteTo(w io.Writer) (int64, error) {
+	var conn net.Conn
+	var reader *bufio.Reader
+
+	switch client := w.(type) {
+	case http.Hijacker:
+		hijackedConn, buffer, err := client.Hijack()
+		if err != nil {
+			return 0, err
+		}
+		conn, reader = hijackedConn, buffer.Reader
+	case net.Conn:
+		conn, reader = client, bufio.NewReader(client)
+	default:
+		return 0, fmt.Errorf("Cannot open WebSocket on %T", w)
+	}
+
+	client, err := webSocketUpgrader.Upgrade(&handshakeWrittenResponseWriter{
+		conn: &handshakeWrittenConn{Conn: conn, reader: reader},
+	}, this.request, nil)
+	if err != nil {
+		conn.Close()
+		return 0, err
+	}
+	defer client.Close()
+
+	this.serve(client)
+
+	return 0, nil
+}
+
+// handshakeWrittenResponseWriter gives the WebSocket upgrader the connection to a client that has
+// already been sent the response to its handshake
+type handshakeWrittenResponseWriter struct {
+	conn   *handshakeWrittenConn
+	header http.Header
+}
+
+func (this *handshakeWrittenResponseWriter) Header() http.Header {
+	if this.header == nil {
+		this.header = http.Header{}
+	}
+	return this.header
+}
+
+func (this *handshakeWrittenResponseWriter) Write(p []byte) (int, error) {
+	return this.conn.Write(p)
+}
+
+func (this *handshakeWrittenResponseWriter) WriteHeader(statusCode int) {}
+
+func (this *handshakeWrittenResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return this.conn, bufio.NewReadWriter(bufio.NewReader(this.conn), bufio.NewWriter(this.conn)), nil
+}
+
+// handshakeWrittenConn drops the handshake response that the upgrader writes, which the client already has
+type handshakeWrittenConn struct {
+	net.Conn
+	reader           *bufio.Reader
+	handshakeDropped bool
+}
+
+func (this *handshakeWrittenConn) Read(p []byte) (int, error) {
+	return this.reader.Read(p)
+}
+
+func (this *handshakeWrittenConn) Write(p []byte) (int, error) {
+	if !this.handshakeDropped {
+		this.handshakeDropped = true
+		return len(p), nil
+	}
+	return this.Conn.Write(p)
+}
+
+func removeWebSocketHandshakeHeaders(headers map[string][]string) map[string][]string {
+	filtered := map[string][]string{}
+	for key, values := range headers {
+		filtered[key] = values
+	}
+	for _, header := range webSocketHandshakeHeaders {
+		delete(filtered, header)
+	}
+	return filtered
+}
+
+func getWebSocketMessageType(binary bool) int {
+	if binary {
+		return websocket.BinaryMessage
+	}
+	return websocket.TextMessage
+}
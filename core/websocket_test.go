@@ -0,0 +1,277 @@
+package hoverfly
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/gomega"
+)
+
+func newSimulatedWebSocket() *models.WebSocket {
+	return &models.WebSocket{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/socket",
+				},
+			},
+		},
+		Headers: map[string][]string{
+			"Sec-Websocket-Protocol": {"chat"},
+		},
+		OnOpen: []models.WebSocketFrame{
+			{Body: "hello"},
+		},
+		OnMessage: []models.WebSocketMessage{
+			{
+				Body: []models.RequestFieldMatchers{
+					{
+						Matcher: matchers.Glob,
+						Value:   "ping*",
+					},
+				},
+				Frames: []models.WebSocketFrame{
+					{Body: "pong", Delay: 10},
+					{Body: string([]byte{0, 1}), Binary: true},
+				},
+			},
+		},
+	}
+}
+
+func dialThroughProxy(proxyServer *httptest.Server, destination string) (*websocket.Conn, *http.Response, error) {
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	dialer := &websocket.Dialer{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		Subprotocols:    []string{"chat"},
+	}
+	return dialer.Dial(destination, nil)
+}
+
+func expectSimulatedWebSocket(conn *websocket.Conn, response *http.Response) {
+	Expect(response.StatusCode).To(Equal(http.StatusSwitchingProtocols))
+	Expect(response.Header.Get("Sec-Websocket-Protocol")).To(Equal("chat"))
+
+	messageType, body, err := conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(messageType).To(Equal(websocket.TextMessage))
+	Expect(string(body)).To(Equal("hello"))
+
+	Expect(conn.WriteMessage(websocket.TextMessage, []byte("unmatched"))).To(Succeed())
+	Expect(conn.WriteMessage(websocket.TextMessage, []byte("ping 1"))).To(Succeed())
+
+	_, body, err = conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("pong"))
+
+	messageType, body, err = conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(messageType).To(Equal(websocket.BinaryMessage))
+	Expect(body).To(Equal([]byte{0, 1}))
+}
+
+func Test_NewProxy_SimulatesWebSocketsTunnelledAsPlainHttp(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{PlainHttpTunneling: true})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddWebSocket(newSimulatedWebSocket())
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	conn, response, err := dialThroughProxy(proxyServer, "ws://test.com/socket")
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	expectSimulatedWebSocket(conn, response)
+}
+
+func Test_NewProxy_SimulatesWebSocketsOverHttps(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddWebSocket(newSimulatedWebSocket())
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	conn, response, err := dialThroughProxy(proxyServer, "wss://test.com/socket")
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	expectSimulatedWebSocket(conn, response)
+
+	journalView, err := unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journalView.Journal).To(HaveLen(1))
+	Expect(*journalView.Journal[0].Request.Path).To(Equal("/socket"))
+	Expect(journalView.Journal[0].Response.Status).To(Equal(http.StatusSwitchingProtocols))
+}
+
+func Test_NewWebserverProxy_SimulatesWebSockets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{Webserver: true})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddWebSocket(newSimulatedWebSocket())
+
+	webserver := httptest.NewServer(NewWebserverProxy(unit))
+	defer webserver.Close()
+
+	dialer := &websocket.Dialer{Subprotocols: []string{"chat"}}
+	conn, response, err := dialer.Dial(strings.Replace(webserver.URL, "http", "ws", 1)+"/socket", nil)
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	expectSimulatedWebSocket(conn, response)
+}
+
+func Test_NewProxy_SimulatesWebSocketsWhenSimulationSetsHaveTheSamePriority(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{PlainHttpTunneling: true})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddWebSocket(newSimulatedWebSocket())
+	unit.SimulationSets.GetOrCreate("payments").AddPair(&models.RequestMatcherResponsePair{
+		Response: models.ResponseDetails{Status: http.StatusOK},
+	})
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	conn, response, err := dialThroughProxy(proxyServer, "ws://test.com/socket")
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	expectSimulatedWebSocket(conn, response)
+}
+
+func Test_NewProxy_RejectsWebSocketsThatAreNotMatchedInSimulateMode(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{PlainHttpTunneling: true})
+	unit.Cfg.SetMode("simulate")
+	unit.Simulation.AddWebSocket(newSimulatedWebSocket())
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	_, response, err := dialThroughProxy(proxyServer, "ws://test.com/other")
+	Expect(err).To(Equal(websocket.ErrBadHandshake))
+	Expect(response.StatusCode).To(Equal(http.StatusBadGateway))
+}
+
+func Test_NewProxy_CapturesWebSockets(t *testing.T) {
+	RegisterTestingT(t)
+
+	upgrader := websocket.Upgrader{}
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, http.Header{"Set-Cookie": {"session=1"}})
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteMessage(websocket.TextMessage, []byte("welcome"))
+		for {
+			messageType, body, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(messageType, append([]byte("echo "), body...))
+		}
+	}))
+	defer destination.Close()
+
+	unit := NewHoverflyWithConfiguration(&Configuration{PlainHttpTunneling: true})
+	unit.Cfg.SetMode("capture")
+
+	proxyServer := httptest.NewServer(NewProxy(unit))
+	defer proxyServer.Close()
+
+	conn, response, err := dialThroughProxy(proxyServer, strings.Replace(destination.URL, "http", "ws", 1)+"/socket")
+	Expect(err).To(BeNil())
+	Expect(response.Header.Get("Set-Cookie")).To(Equal("session=1"))
+
+	_, body, err := conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal("welcome"))
+
+	for _, message := range []string{"one", "two", "one"} {
+		Expect(conn.WriteMessage(websocket.TextMessage, []byte(message))).To(Succeed())
+
+		_, body, err := conn.ReadMessage()
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("echo " + message))
+	}
+
+	conn.Close()
+
+	Eventually(func() []models.WebSocket {
+		return unit.Simulation.GetWebSockets()
+	}, time.Second*5).Should(HaveLen(1))
+
+	webSocket := unit.Simulation.GetWebSockets()[0]
+	Expect(webSocket.RequestMatcher.Path).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/socket"}}))
+	Expect(webSocket.RequestMatcher.Headers).To(BeNil())
+	Expect(webSocket.Headers).To(HaveKeyWithValue("Set-Cookie", []string{"session=1"}))
+	Expect(webSocket.Headers).ToNot(HaveKey("Sec-Websocket-Accept"))
+
+	Expect(webSocket.OnOpen).To(HaveLen(1))
+	Expect(webSocket.OnOpen[0].Body).To(Equal("welcome"))
+
+	Expect(webSocket.OnMessage).To(HaveLen(2))
+	Expect(webSocket.OnMessage[0].Body).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "one"}}))
+	Expect(webSocket.OnMessage[0].Frames).To(HaveLen(1))
+	Expect(webSocket.OnMessage[0].Frames[0].Body).To(Equal("echo one"))
+	Expect(webSocket.OnMessage[1].Frames[0].Body).To(Equal("echo two"))
+}
+
+func Test_Hoverfly_PutSimulation_ImportsWebSockets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	webSocketView := newSimulatedWebSocket().BuildView()
+
+	result := unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			WebSockets: []v2.WebSocketViewV5{webSocketView, webSocketView},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v5",
+		},
+	})
+
+	Expect(result.GetError()).To(BeNil())
+	Expect(result.WarningMessages).To(HaveLen(1))
+	Expect(result.WarningMessages[0].Message).To(ContainSubstring("data.websockets[1]"))
+
+	simulationView, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulationView.WebSockets).To(Equal([]v2.WebSocketViewV5{webSocketView}))
+
+	unit.DeleteSimulation()
+
+	Expect(unit.Simulation.GetWebSockets()).To(BeEmpty())
+}
+
+func Test_computeWebSocketAccept(t *testing.T) {
+	RegisterTestingT(t)
+
+	// The example from RFC 6455
+	Expect(computeWebSocketAccept("dGhlIHNhbXBsZSBub25jZQ==")).To(Equal("s3pPLMBiTxaQ9kYGzzhZRbK+xOo="))
+}
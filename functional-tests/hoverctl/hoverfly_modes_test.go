@@ -202,6 +202,17 @@ var _ = Describe("When I use hoverfly-cli", func() {
 				Expect(hoverfly.GetMode().Mode).To(Equal(spy))
 			})
 
+			It("to spy mode and capture misses", func() {
+				output := functional_tests.Run(hoverctlBinary, "mode", "spy", "--capture-misses", "--headers", "Authorization")
+
+				Expect(output).To(ContainSubstring("Hoverfly has been set to spy mode and will capture requests that do not match with the following request headers: [Authorization]"))
+
+				modeView := hoverfly.GetMode()
+				Expect(modeView.Mode).To(Equal(spy))
+				Expect(modeView.Arguments.CaptureMisses).To(BeTrue())
+				Expect(modeView.Arguments.Headers).To(Equal([]string{"Authorization"}))
+			})
+
 			It("to diff mode", func() {
 				output := functional_tests.Run(hoverctlBinary, "mode", "diff")
 
@@ -9,12 +9,17 @@ import (
 )
 
 var urlPattern string
+var exportFormat string
 var exportCmd = &cobra.Command{
 	Use:   "export [path to simulation]",
 	Short: "Export a simulation from Hoverfly",
 	Long: `
 Exports a simulation from Hoverfly. The simulation JSON
 will be written to the file path provided.
+
+Use --format har to export the requests and responses
+in the journal as an HTTP Archive instead, which can
+be opened in browser developer tools.
 	`,
 
 	Run: func(cmd *cobra.Command, args []string) {
@@ -22,7 +27,16 @@ will be written to the file path provided.
 
 		checkArgAndExit(args, "You have not provided a path to simulation", "export")
 
-		simulationData, err := wrapper.ExportSimulation(*target, urlPattern)
+		var simulationData []byte
+		var err error
+		switch exportFormat {
+		case "simulation":
+			simulationData, err = wrapper.ExportSimulation(*target, urlPattern)
+		case "har":
+			simulationData, err = wrapper.ExportJournalAsHAR(*target)
+		default:
+			err = fmt.Errorf("%s is not a supported export format", exportFormat)
+		}
 		handleIfError(err)
 
 		err = configuration.WriteFile(args[0], simulationData)
@@ -36,4 +50,5 @@ func init() {
 	RootCmd.AddCommand(exportCmd)
 
 	exportCmd.Flags().StringVar(&urlPattern, "url-pattern", "", "Export simulation for the urls that matches a pattern, eg. foo.com/api/v(.+)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "simulation", "Format to export, either simulation or har for the journal as an HTTP Archive")
 }
@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
 	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
 	"github.com/spf13/cobra"
 )
 
+var importFormat string
+
 // importCmd represents the import command
 var importCmd = &cobra.Command{
 	Use:   "import [path to simulation]",
@@ -18,16 +24,45 @@ simulation data will be replaced. An absolute or
 relative path to a Hoverfly simulation JSON file
 must be provided. To add multiple simulations,
 use "hoverctl simulation add [paths]" instead.
+
+Use --format openapi to import an OpenAPI 2 or 3
+spec in JSON or YAML. A request response pair is
+created for each operation in the spec.
+
+Use --format wiremock to import WireMock stub
+mappings, either from a mapping file or from a
+mappings directory. Use --format postman to import
+the example responses of a Postman collection.
+Anything that can't be translated is reported as
+a warning.
 	`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		checkTargetAndExit(target)
 
 		checkArgAndExit(args, "You have not provided a path to simulation", "import")
-		simulationData, err := configuration.ReadFile(args[0])
+
+		var simulationData []byte
+		var err error
+		if info, statErr := os.Stat(args[0]); statErr == nil && info.IsDir() && importFormat == "wiremock" {
+			simulationData, err = readWireMockMappingsDirectory(args[0])
+		} else {
+			simulationData, err = configuration.ReadFile(args[0])
+		}
 		handleIfError(err)
 
-		err = wrapper.ImportSimulation(*target, string(simulationData))
+		switch importFormat {
+		case "simulation":
+			err = wrapper.ImportSimulation(*target, string(simulationData))
+		case "openapi":
+			err = wrapper.ImportOpenAPISpec(*target, string(simulationData))
+		case "wiremock":
+			err = wrapper.ImportWireMockMappings(*target, string(simulationData))
+		case "postman":
+			err = wrapper.ImportPostmanCollection(*target, string(simulationData))
+		default:
+			err = fmt.Errorf("%s is not a supported import format", importFormat)
+		}
 		handleIfError(err)
 
 		fmt.Println("Successfully imported simulation from", args[0])
@@ -36,4 +71,38 @@ use "hoverctl simulation add [paths]" instead.
 
 func init() {
 	RootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importFormat, "format", "simulation", "Format of the file to import, either simulation, openapi, wiremock or postman")
+}
+
+// readWireMockMappingsDirectory combines the mapping files of a WireMock
+// mappings directory, each of which can hold one or many mappings
+func readWireMockMappingsDirectory(path string) ([]byte, error) {
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := []json.RawMessage{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Could not read %s", file)
+		}
+
+		var mappingsFile struct {
+			Mappings []json.RawMessage `json:"mappings"`
+		}
+		if err := json.Unmarshal(data, &mappingsFile); err != nil {
+			return nil, fmt.Errorf("%s is not a valid WireMock mapping", file)
+		}
+
+		if mappingsFile.Mappings != nil {
+			mappings = append(mappings, mappingsFile.Mappings...)
+		} else {
+			mappings = append(mappings, json.RawMessage(data))
+		}
+	}
+
+	return json.Marshal(map[string][]json.RawMessage{"mappings": mappings})
 }
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
+	"github.com/spf13/cobra"
+)
+
+var journalTailDestination, journalTailPath, journalTailMethod string
+var journalTailStatus int
+var journalTailUnmatched bool
+
+var journalCmd = &cobra.Command{
+	Use:   "journal",
+	Short: "Inspect the Hoverfly journal",
+	Long: `
+The journal holds the requests Hoverfly has received
+and the responses it returned.
+	`,
+}
+
+var journalTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Follow new journal entries",
+	Long: `
+Prints each new journal entry as Hoverfly records it,
+until stopped. The destination, path and method flags
+accept globs, such as --path "/api/*".
+	`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTargetAndExit(target)
+
+		jsonEntries, _ := cmd.Flags().GetBool("json")
+
+		err := wrapper.TailJournal(*target, journalTailFilter(), func(entry v2.JournalEntryView) {
+			if jsonEntries {
+				entryBytes, _ := json.Marshal(entry)
+				fmt.Println(string(entryBytes))
+			} else {
+				fmt.Println(formatJournalEntry(entry))
+			}
+		})
+		handleIfError(err)
+	},
+}
+
+func journalTailFilter() v2.JournalEntryFilterView {
+	return newJournalEntryFilter(journalTailDestination, journalTailPath, journalTailMethod, journalTailStatus, journalTailUnmatched)
+}
+
+// newJournalEntryFilter builds a journal filter from command flags, using globs for
+// the request fields so that they match exactly unless they contain a wildcard
+func newJournalEntryFilter(destination, path, method string, status int, unmatched bool) v2.JournalEntryFilterView {
+	filter := v2.JournalEntryFilterView{
+		Status:    status,
+		Unmatched: unmatched,
+	}
+
+	requestMatcher := v2.RequestMatcherViewV5{}
+	if destination != "" {
+		requestMatcher.Destination = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, destination)}
+	}
+	if path != "" {
+		requestMatcher.Path = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, path)}
+	}
+	if method != "" {
+		requestMatcher.Method = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, method)}
+	}
+
+	if requestMatcher.Destination != nil || requestMatcher.Path != nil || requestMatcher.Method != nil {
+		filter.Request = &requestMatcher
+	}
+
+	return filter
+}
+
+func formatJournalEntry(entry v2.JournalEntryView) string {
+	formatted := fmt.Sprintf("%s [%s] %s %s %d %.2fms",
+		entry.TimeStarted, entry.Mode, stringValue(entry.Request.Method), formatRequestURL(entry.Request), entry.Response.Status, entry.Latency)
+	if entry.Match != nil && !entry.Match.Matched {
+		formatted = formatted + " (unmatched)"
+	}
+
+	return formatted
+}
+
+func formatRequestURL(request v2.RequestDetailsView) string {
+	url := fmt.Sprintf("%s://%s%s", stringValue(request.Scheme), stringValue(request.Destination), stringValue(request.Path))
+	if query := stringValue(request.Query); query != "" {
+		url = url + "?" + query
+	}
+	return url
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func init() {
+	RootCmd.AddCommand(journalCmd)
+	journalCmd.AddCommand(journalTailCmd)
+
+	journalTailCmd.Flags().StringVar(&journalTailDestination, "destination", "", "Only show entries for requests to this destination")
+	journalTailCmd.Flags().StringVar(&journalTailPath, "path", "", "Only show entries for requests to this path")
+	journalTailCmd.Flags().StringVar(&journalTailMethod, "method", "", "Only show entries for requests with this method")
+	journalTailCmd.Flags().IntVar(&journalTailStatus, "status", 0, "Only show entries with this response status code")
+	journalTailCmd.Flags().BoolVar(&journalTailUnmatched, "unmatched", false, "Only show entries for requests that did not match the simulation")
+	journalTailCmd.Flags().Bool("json", false, "Print each entry in JSON format")
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
 	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
 	"github.com/spf13/cobra"
 )
@@ -14,7 +16,9 @@ var specificHeaders string
 var allHeaders bool
 var stateful bool
 var overwriteDuplicate bool
+var captureMisses bool
 var matchingStrategy string
+var diffRulesFile string
 
 var modeCmd = &cobra.Command{
 	Use:   "mode [capture|diff|simulate|spy|modify|synthesize (optional)]",
@@ -52,8 +56,19 @@ mode is shown.
 				modeView.Arguments.OverwriteDuplicate = overwriteDuplicate
 				setHeaderArgument(modeView)
 				break
+			case modes.Spy:
+				if captureMisses {
+					modeView.Arguments.CaptureMisses = true
+					modeView.Arguments.Stateful = stateful
+					modeView.Arguments.OverwriteDuplicate = overwriteDuplicate
+					setHeaderArgument(modeView)
+				}
+				break
 			case modes.Diff:
 				setHeaderArgument(modeView)
+				if len(diffRulesFile) > 0 {
+					handleIfError(setDiffRulesArgument(modeView, diffRulesFile))
+				}
 				break
 			}
 
@@ -74,6 +89,21 @@ func setHeaderArgument(mode *v2.ModeView) {
 	}
 }
 
+func setDiffRulesArgument(mode *v2.ModeView, file string) error {
+	data, err := configuration.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var diffRules v2.DiffRulesView
+	if err := json.Unmarshal(data, &diffRules); err != nil {
+		return fmt.Errorf("Could not read diff rules from %s: %s", file, err.Error())
+	}
+
+	mode.Arguments.DiffRules = &diffRules
+	return nil
+}
+
 func getExtraInfo(mode *v2.ModeView) string {
 	var extraInfo string
 	switch mode.Mode {
@@ -91,6 +121,16 @@ func getExtraInfo(mode *v2.ModeView) string {
 			}
 		}
 		break
+	case modes.Spy:
+		if mode.Arguments.CaptureMisses {
+			extraInfo = "and will capture requests that do not match"
+			if len(mode.Arguments.Headers) == 1 && mode.Arguments.Headers[0] == "*" {
+				extraInfo += " with all request headers"
+			} else if len(mode.Arguments.Headers) > 0 {
+				extraInfo += fmt.Sprintf(" with the following request headers: %s", mode.Arguments.Headers)
+			}
+		}
+		break
 	case modes.Diff:
 		if len(mode.Arguments.Headers) > 0 {
 			if len(mode.Arguments.Headers) == 1 && mode.Arguments.Headers[0] == "*" {
@@ -99,6 +139,12 @@ func getExtraInfo(mode *v2.ModeView) string {
 				extraInfo = fmt.Sprintf("and will exclude the following response headers from diffing: %s", mode.Arguments.Headers)
 			}
 		}
+		if mode.Arguments.DiffRules != nil {
+			if len(extraInfo) > 0 {
+				extraInfo += " "
+			}
+			extraInfo += "and will compare responses using diff rules"
+		}
 		break
 	}
 
@@ -109,13 +155,17 @@ func init() {
 
 	RootCmd.AddCommand(modeCmd)
 	modeCmd.PersistentFlags().StringVar(&specificHeaders, "headers", "",
-		"A comma separated list of request headers to record (for capture mode) or response headers to ignore (for diff mode) `Content-Type,Authorization`")
+		"A comma separated list of request headers to record (for capture mode and spy mode with --capture-misses) or response headers to ignore (for diff mode) `Content-Type,Authorization`")
 	modeCmd.PersistentFlags().BoolVar(&allHeaders, "all-headers", false,
-		"Record all request headers (for capture mode) or ignore all response headers (for diff mode)")
+		"Record all request headers (for capture mode and spy mode with --capture-misses) or ignore all response headers (for diff mode)")
 	modeCmd.PersistentFlags().StringVar(&matchingStrategy, "matching-strategy", "strongest",
 		"Sets the matching strategy - 'strongest | first'")
 	modeCmd.PersistentFlags().BoolVar(&stateful, "stateful", false,
-		"Record stateful responses as a sequence in capture mode")
+		"Record stateful responses as a sequence in capture mode and spy mode with --capture-misses")
 	modeCmd.PersistentFlags().BoolVar(&overwriteDuplicate, "overwrite-duplicate", false,
-		"Overwrite duplicate requests in capture mode")
+		"Overwrite duplicate requests in capture mode and spy mode with --capture-misses")
+	modeCmd.PersistentFlags().BoolVar(&captureMisses, "capture-misses", false,
+		"Capture the requests that do not match, and their responses from the real service, in spy mode")
+	modeCmd.PersistentFlags().StringVar(&diffRulesFile, "diff-rules", "",
+		"A JSON file of rules for comparing responses in diff mode, with paths to ignore, patterns to normalise, ignoreArrayOrder and numericTolerance")
 }
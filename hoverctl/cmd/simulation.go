@@ -2,6 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
 	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
 	"github.com/spf13/cobra"
@@ -15,6 +19,9 @@ This allows you to manage simulation data in Hoverfly.
 	`,
 }
 
+var simulationName string
+var simulationPriority int
+
 var addSimulationCmd = &cobra.Command{
 	Use:   "add [path to simulations]",
 	Short: "Add one or more simulations into Hoverfly",
@@ -26,6 +33,12 @@ Any request/response pairs that have an identical request
 to those in the existing data will be discarded with a 
 warning message. 
 
+Use --name to add the simulation files to a named
+simulation instead, which is matched alongside the
+existing simulation data and can be enabled, disabled
+and deleted on its own. Named simulations with a higher
+--priority are matched first.
+
 You may provide an absolute or relative path to each 
 simulation file.
 	`,
@@ -35,12 +48,24 @@ simulation file.
 
 		checkArgAndExit(args, "You have not provided a path to simulation", "simulation add")
 
+		var priority *int
+		if cmd.Flags().Changed("priority") {
+			if simulationName == "" {
+				handleIfError(fmt.Errorf("--priority can only be used with --name"))
+			}
+			priority = &simulationPriority
+		}
+
 		for _, arg := range args {
 
 			simulationData, err := configuration.ReadFile(arg)
 			handleIfError(err)
 
-			err = wrapper.AddSimulation(*target, string(simulationData))
+			if simulationName == "" {
+				err = wrapper.AddSimulation(*target, string(simulationData))
+			} else {
+				err = wrapper.AddSimulationSet(*target, simulationName, priority, string(simulationData))
+			}
 			handleIfError(err)
 			fmt.Println("Successfully added simulation from", arg)
 		}
@@ -48,7 +73,150 @@ simulation file.
 	},
 }
 
+var listSimulationsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the named simulations in Hoverfly",
+	Long: `
+Lists the named simulations in Hoverfly, whether each
+of them is enabled, its priority and how many pairs it has.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		sets, err := wrapper.GetSimulationSets(*target)
+		handleIfError(err)
+
+		if len(sets) == 0 {
+			fmt.Println("There are no named simulations")
+			return
+		}
+
+		data := [][]string{{"Name", "Enabled", "Priority", "Pairs"}}
+		for _, set := range sets {
+			data = append(data, []string{set.Name, strconv.FormatBool(set.Enabled), strconv.Itoa(set.Priority), strconv.Itoa(set.Pairs)})
+		}
+		drawTable(data, true)
+	},
+}
+
+var enableSimulationCmd = &cobra.Command{
+	Use:   "enable [name]",
+	Short: "Enable a named simulation",
+	Long: `
+Enables a named simulation, so that requests are matched
+against it again.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setSimulationEnabled(args, true)
+	},
+}
+
+var disableSimulationCmd = &cobra.Command{
+	Use:   "disable [name]",
+	Short: "Disable a named simulation",
+	Long: `
+Disables a named simulation, so that requests are not
+matched against it until it is enabled again.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setSimulationEnabled(args, false)
+	},
+}
+
+var deleteSimulationCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a named simulation",
+	Long: `
+Deletes a named simulation without affecting any other
+simulation data in Hoverfly.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		checkArgAndExit(args, "You have not provided the name of a simulation", "simulation delete")
+
+		err := wrapper.DeleteSimulationSet(*target, args[0])
+		handleIfError(err)
+
+		fmt.Println("Simulation", args[0], "has been deleted")
+	},
+}
+
+var lintSimulationCmd = &cobra.Command{
+	Use:   "lint [path to simulation]",
+	Short: "Find problems in a simulation",
+	Long: `
+Finds problems in a simulation that would not stop it
+being imported, but would stop some of it from working
+as intended. These are pairs that are never matched as
+another pair always matches the same requests with a
+score at least as high, invalid regex, jsonpath and
+xpath matcher values, templates that do not parse and
+state that is required but never set.
+
+Lints the simulation file when one is given, otherwise
+the simulation loaded in Hoverfly. Exits with an error
+when any problems are found.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		simulationData := ""
+		if len(args) > 0 {
+			data, err := configuration.ReadFile(args[0])
+			handleIfError(err)
+			simulationData = string(data)
+		}
+
+		lintView, err := wrapper.LintSimulation(*target, simulationData)
+		handleIfError(err)
+
+		if len(lintView.Problems) == 0 {
+			fmt.Println("No problems found in simulation")
+			return
+		}
+
+		data := [][]string{{"Pair", "Field", "Problem"}}
+		for _, problem := range lintView.Problems {
+			data = append(data, []string{strconv.Itoa(problem.Pair), problem.Field, problem.Message})
+		}
+		drawTable(data, true)
+
+		os.Exit(1)
+	},
+}
+
+func setSimulationEnabled(args []string, enabled bool) {
+	checkTargetAndExit(target)
+
+	command := "simulation disable"
+	if enabled {
+		command = "simulation enable"
+	}
+	checkArgAndExit(args, "You have not provided the name of a simulation", command)
+
+	err := wrapper.UpdateSimulationSet(*target, args[0], v2.SimulationSetSettingsView{Enabled: &enabled})
+	handleIfError(err)
+
+	if enabled {
+		fmt.Println("Simulation", args[0], "has been enabled")
+	} else {
+		fmt.Println("Simulation", args[0], "has been disabled")
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(simulationCmd)
 	simulationCmd.AddCommand(addSimulationCmd)
+	simulationCmd.AddCommand(listSimulationsCmd)
+	simulationCmd.AddCommand(enableSimulationCmd)
+	simulationCmd.AddCommand(disableSimulationCmd)
+	simulationCmd.AddCommand(deleteSimulationCmd)
+	simulationCmd.AddCommand(lintSimulationCmd)
+
+	addSimulationCmd.Flags().StringVar(&simulationName, "name", "", "Add the simulations to the named simulation, creating it if it does not exist")
+	addSimulationCmd.Flags().IntVar(&simulationPriority, "priority", 0, "Priority of the named simulation, those with a higher priority are matched first")
 }
@@ -72,10 +72,13 @@ hoverctl configuration file.
 
 		target.UpstreamProxyUrl, _ = cmd.Flags().GetString("upstream-proxy")
 		target.HttpsOnly, _ = cmd.Flags().GetBool("https-only")
+		target.Http2, _ = cmd.Flags().GetBool("http2")
 		target.CORS, _ = cmd.Flags().GetBool("cors")
 		target.NoImportCheck, _ = cmd.Flags().GetBool("no-import-check")
 
 		target.Simulations, _ = cmd.Flags().GetStringSlice("import")
+		target.SimulationsWatch, _ = cmd.Flags().GetStringSlice("import-watch")
+		target.GrpcDescriptorSets, _ = cmd.Flags().GetStringSlice("grpc-descriptor-set")
 
 		if pacFileLocation, _ := cmd.Flags().GetString("pac-file"); pacFileLocation != "" {
 
@@ -152,6 +155,7 @@ func init() {
 	startCmd.Flags().String("upstream-proxy", "", "A host for which Hoverfly will proxy its requests to")
 	startCmd.Flags().String("pac-file", "", "Configure upstream proxy by PAC file")
 	startCmd.Flags().Bool("https-only", false, "Disable insecure HTTP traffic in Hoverfly")
+	startCmd.Flags().Bool("http2", false, "Accept HTTP/2 traffic, which gRPC needs")
 	startCmd.Flags().String("listen-on-host", "", "Bind hoverfly listener to a host")
 	startCmd.Flags().Bool("cors", false, "Enable CORS support")
 	startCmd.Flags().Bool("no-import-check", false, "Skip duplicate request check when importing simulations")
@@ -166,4 +170,6 @@ func init() {
 	startCmd.Flags().String("password", "", "Password to authenticate Hoverfly")
 
 	startCmd.Flags().StringSlice("import", []string{}, "Simulations to import")
+	startCmd.Flags().StringSlice("import-watch", []string{}, "Simulation files or directories to import, and import again each time they change")
+	startCmd.Flags().StringSlice("grpc-descriptor-set", []string{}, "Descriptor sets of gRPC services, whose messages Hoverfly decodes into JSON")
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
+	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
+	"github.com/spf13/cobra"
+)
+
+var unmatchedExport string
+
+var unmatchedCmd = &cobra.Command{
+	Use:   "unmatched",
+	Short: "Show the requests that did not match the simulation",
+	Long: `
+Shows each distinct request that did not match the
+simulation, how many times it was received and which
+fields of the closest pair it missed on.
+
+Use --export to write a simulation with a pair for each
+unmatched request to a file. Fill in the responses and
+import it to close the gaps in your simulation.
+	`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTargetAndExit(target)
+
+		if unmatchedExport != "" {
+			simulationData, err := wrapper.ExportUnmatchedRequests(*target)
+			handleIfError(err)
+
+			err = configuration.WriteFile(unmatchedExport, simulationData)
+			handleIfError(err)
+
+			fmt.Println("Successfully exported suggested pairs to", unmatchedExport)
+			return
+		}
+
+		unmatched, err := wrapper.GetUnmatchedRequests(*target)
+		handleIfError(err)
+
+		if jsonUnmatched, _ := cmd.Flags().GetBool("json"); jsonUnmatched {
+			unmatchedBytes, _ := json.MarshalIndent(unmatched, "", "\t")
+			fmt.Println(string(unmatchedBytes))
+			return
+		}
+
+		if len(unmatched) == 0 {
+			fmt.Println("There are no unmatched requests")
+			return
+		}
+
+		data := [][]string{{"Count", "Method", "URL", "Closest miss"}}
+		for _, request := range unmatched {
+			data = append(data, []string{
+				strconv.Itoa(request.Count),
+				stringValue(request.Request.Method),
+				formatRequestURL(request.Request),
+				formatClosestMiss(request.ClosestMiss),
+			})
+		}
+		drawTable(data, true)
+	},
+}
+
+var deleteUnmatchedCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes the unmatched requests",
+	Long: `
+Deletes the unmatched requests stored in Hoverfly.
+	`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTargetAndExit(target)
+
+		err := wrapper.DeleteUnmatchedRequests(*target)
+		handleIfError(err)
+
+		fmt.Println("Unmatched requests have been deleted")
+	},
+}
+
+func formatClosestMiss(closestMiss *v2.ClosestMissView) string {
+	if closestMiss == nil {
+		return "-"
+	}
+	return "missed " + strings.Join(closestMiss.MissedFields, ", ")
+}
+
+func init() {
+	RootCmd.AddCommand(unmatchedCmd)
+	unmatchedCmd.AddCommand(deleteUnmatchedCmd)
+
+	unmatchedCmd.Flags().StringVar(&unmatchedExport, "export", "", "Write a simulation with a pair for each unmatched request to this file")
+	unmatchedCmd.Flags().Bool("json", false, "Print the unmatched requests in JSON format")
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
+	"github.com/spf13/cobra"
+)
+
+var verifyDestination, verifyPath, verifyMethod, verifyFile string
+var verifyStatus, verifyTimes, verifyAtLeast, verifyAtMost int
+var verifyNever, verifyUnmatched bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the requests in the Hoverfly journal",
+	Long: `
+Checks how many requests in the Hoverfly journal match
+the given flags, expecting at least one unless --times,
+--at-least, --at-most or --never are given. The
+destination, path and method flags accept globs.
+
+Use --file to read a verification in the format of the
+body of POST /api/v2/journal/verify instead, which can
+check the order of requests with "inOrder".
+
+Exits with a non-zero status when the verification fails.
+	`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTargetAndExit(target)
+
+		verification := v2.JournalVerifyView{}
+		if verifyFile != "" {
+			verificationBytes, err := ioutil.ReadFile(verifyFile)
+			handleIfError(err)
+
+			err = json.Unmarshal(verificationBytes, &verification)
+			if err != nil {
+				handleIfError(fmt.Errorf("Could not read verification from %s\n\n%s", verifyFile, err.Error()))
+			}
+		} else {
+			verification.JournalEntryFilterView = newJournalEntryFilter(verifyDestination, verifyPath, verifyMethod, verifyStatus, verifyUnmatched)
+			verification.Never = verifyNever
+			if cmd.Flags().Changed("times") {
+				verification.Times = &verifyTimes
+			}
+			if cmd.Flags().Changed("at-least") {
+				verification.AtLeast = &verifyAtLeast
+			}
+			if cmd.Flags().Changed("at-most") {
+				verification.AtMost = &verifyAtMost
+			}
+		}
+
+		result, err := wrapper.VerifyJournal(*target, verification)
+		handleIfError(err)
+
+		if result.Passed {
+			fmt.Println(result.Message)
+			return
+		}
+
+		fmt.Fprintln(os.Stderr, result.Message)
+
+		if len(result.Matched) > 0 {
+			fmt.Fprintln(os.Stderr, "\nMatching requests:")
+			for _, entry := range result.Matched {
+				fmt.Fprintln(os.Stderr, formatJournalEntry(entry))
+			}
+		}
+
+		if len(result.Closest) > 0 {
+			fmt.Fprintln(os.Stderr, "\nClosest requests:")
+			for _, miss := range result.Closest {
+				fmt.Fprintf(os.Stderr, "%s (missed: %s)\n", formatJournalEntry(miss.Entry), strings.Join(miss.MissedFields, ", "))
+			}
+		}
+
+		os.Exit(1)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVar(&verifyDestination, "destination", "", "Verify requests to this destination")
+	verifyCmd.Flags().StringVar(&verifyPath, "path", "", "Verify requests to this path")
+	verifyCmd.Flags().StringVar(&verifyMethod, "method", "", "Verify requests with this method")
+	verifyCmd.Flags().IntVar(&verifyStatus, "status", 0, "Verify requests that were responded to with this status code")
+	verifyCmd.Flags().BoolVar(&verifyUnmatched, "unmatched", false, "Verify requests that did not match the simulation")
+	verifyCmd.Flags().IntVar(&verifyTimes, "times", 0, "Expect exactly this many matching requests")
+	verifyCmd.Flags().IntVar(&verifyAtLeast, "at-least", 0, "Expect at least this many matching requests")
+	verifyCmd.Flags().IntVar(&verifyAtMost, "at-most", 0, "Expect at most this many matching requests")
+	verifyCmd.Flags().BoolVar(&verifyNever, "never", false, "Expect no matching requests")
+	verifyCmd.Flags().StringVar(&verifyFile, "file", "", "Read the verification from a JSON file")
+}
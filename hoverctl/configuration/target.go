@@ -26,6 +26,7 @@ type Target struct {
 	UpstreamProxyUrl string `yaml:",omitempty"`
 	PACFile          string `yaml:",omitempty"`
 	HttpsOnly        bool   `yaml:",omitempty"`
+	Http2            bool   `yaml:",omitempty"`
 	CORS             bool   `yaml:",omitempty"`
 	NoImportCheck    bool   `yaml:",omitempty"`
 
@@ -39,6 +40,9 @@ type Target struct {
 	Password    string
 
 	Simulations	[]string	`yaml:",omitempty"`
+	SimulationsWatch	[]string	`yaml:",omitempty"`
+
+	GrpcDescriptorSets []string `yaml:",omitempty"`
 }
 
 func NewDefaultTarget() *Target {
@@ -118,6 +122,10 @@ func (this Target) BuildFlags() Flags {
 		flags = append(flags, "-https-only")
 	}
 
+	if this.Http2 {
+		flags = append(flags, "-http2")
+	}
+
 	if this.AuthEnabled {
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(this.Password), 10)
 		flags = append(flags, "-auth", "-username", this.Username, "-password-hash", string(hashedPassword))
@@ -153,5 +161,15 @@ func (this Target) BuildFlags() Flags {
 		}
 	}
 
+	if len(this.SimulationsWatch) > 0 {
+		for _, val := range this.SimulationsWatch {
+			flags = append(flags, "-import-watch="+val)
+		}
+	}
+
+	for _, descriptorSet := range this.GrpcDescriptorSets {
+		flags = append(flags, "-grpc-descriptor-set="+descriptorSet)
+	}
+
 	return flags
 }
@@ -249,6 +249,33 @@ func Test_Target_BuildFlags_SetImportFlags(t *testing.T) {
 	Expect(unit.BuildFlags()[1]).To(Equal("-import=bar.json"))
 }
 
+func Test_Target_BuildFlags_SetImportWatchFlags(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := Target{
+		SimulationsWatch: []string{"simulations", "foo.json"},
+	}
+
+	Expect(unit.BuildFlags()).To(HaveLen(2))
+	Expect(unit.BuildFlags()[0]).To(Equal("-import-watch=simulations"))
+	Expect(unit.BuildFlags()[1]).To(Equal("-import-watch=foo.json"))
+}
+
+func Test_Target_BuildFlags_SetsHttp2AndGrpcDescriptorSetFlags(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := Target{
+		Http2:              true,
+		GrpcDescriptorSets: []string{"items.pb", "orders.pb"},
+	}
+
+	Expect(unit.BuildFlags()).To(Equal(Flags{
+		"-http2",
+		"-grpc-descriptor-set=items.pb",
+		"-grpc-descriptor-set=orders.pb",
+	}))
+}
+
 func Test_Target_BuildFlags_AddSkipImportCheckFlagWhenTrue(t *testing.T) {
 	RegisterTestingT(t)
 
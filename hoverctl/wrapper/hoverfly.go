@@ -20,16 +20,25 @@ import (
 )
 
 const (
-	v2ApiSimulation  = "/api/v2/simulation"
-	v2ApiMode        = "/api/v2/hoverfly/mode"
-	v2ApiDestination = "/api/v2/hoverfly/destination"
-	v2ApiState       = "/api/v2/state"
-	v2ApiMiddleware  = "/api/v2/hoverfly/middleware"
-	v2ApiPac         = "/api/v2/hoverfly/pac"
-	v2ApiCache       = "/api/v2/cache"
-	v2ApiLogs        = "/api/v2/logs"
-	v2ApiHoverfly    = "/api/v2/hoverfly"
-	v2ApiDiff        = "/api/v2/diff"
+	v2ApiSimulation         = "/api/v2/simulation"
+	v2ApiSimulationOpenAPI  = "/api/v2/simulation/openapi"
+	v2ApiSimulationWireMock = "/api/v2/simulation/wiremock"
+	v2ApiSimulationPostman  = "/api/v2/simulation/postman"
+	v2ApiSimulationLint     = "/api/v2/simulation/lint"
+	v2ApiSimulations        = "/api/v2/simulations"
+	v2ApiMode               = "/api/v2/hoverfly/mode"
+	v2ApiDestination        = "/api/v2/hoverfly/destination"
+	v2ApiState              = "/api/v2/state"
+	v2ApiMiddleware         = "/api/v2/hoverfly/middleware"
+	v2ApiPac                = "/api/v2/hoverfly/pac"
+	v2ApiCache              = "/api/v2/cache"
+	v2ApiLogs               = "/api/v2/logs"
+	v2ApiHoverfly           = "/api/v2/hoverfly"
+	v2ApiDiff               = "/api/v2/diff"
+	v2ApiJournal            = "/api/v2/journal"
+	v2ApiJournalStream      = "/api/v2/journal/stream"
+	v2ApiJournalVerify      = "/api/v2/journal/verify"
+	v2ApiUnmatched          = "/api/v2/unmatched"
 
 	v2ApiShutdown = "/api/v2/shutdown"
 	v2ApiHealth   = "/api/health"
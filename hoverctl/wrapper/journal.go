@@ -0,0 +1,87 @@
+package wrapper
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
+)
+
+// ExportJournalAsHAR gets every journal entry from Hoverfly as an HTTP Archive
+func ExportJournalAsHAR(target configuration.Target) ([]byte, error) {
+	return exportJSON(target, v2ApiJournal+"?format=har", "Could not retrieve journal")
+}
+
+// VerifyJournal asks Hoverfly to check the journal. A verification that fails is not an
+// error, the returned result says whether it passed.
+func VerifyJournal(target configuration.Target, verification v2.JournalVerifyView) (*v2.JournalVerificationView, error) {
+	verificationBytes, err := json.Marshal(verification)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := doRequest(target, "POST", v2ApiJournalVerify, string(verificationBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not verify journal")
+	if err != nil {
+		return nil, err
+	}
+
+	responseBytes, _ := ioutil.ReadAll(response.Body)
+
+	var verificationView v2.JournalVerificationView
+	err = json.Unmarshal(responseBytes, &verificationView)
+	if err != nil {
+		return nil, err
+	}
+
+	return &verificationView, nil
+}
+
+// TailJournal streams new journal entries matching the filter from Hoverfly, passing
+// each of them to handleEntry until Hoverfly closes the stream
+func TailJournal(target configuration.Target, filter v2.JournalEntryFilterView, handleEntry func(v2.JournalEntryView)) error {
+	filterBytes, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+
+	response, err := doRequest(target, "POST", v2ApiJournalStream, string(filterBytes), nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not stream journal")
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(response.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.HasPrefix(line, "data:") {
+			var entry v2.JournalEntryView
+			if jsonErr := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &entry); jsonErr != nil {
+				return jsonErr
+			}
+			handleEntry(entry)
+		}
+
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func Test_ExportJournalAsHAR_GetsHARFromHoverfly(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "GET",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/journal",
+							},
+						},
+						Query: &v2.QueryMatcherViewV5{
+							"format": []v2.MatcherViewV5{
+								{
+									Matcher: matchers.Exact,
+									Value:   "har",
+								},
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"log": {"entries": []}}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	har, err := ExportJournalAsHAR(target)
+	Expect(err).To(BeNil())
+
+	Expect(string(har)).To(Equal("{\n\t\"log\": {\n\t\t\"entries\": []\n\t}\n}"))
+}
+
+func Test_ExportJournalAsHAR_ErrorsWhen_HoverflyNotAccessible(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := ExportJournalAsHAR(inaccessibleTarget)
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not connect to Hoverfly at something:1234"))
+}
+
+func Test_TailJournal_SendsFilterAndHandlesEachEntry(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "POST",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/journal/stream",
+							},
+						},
+						Body: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Json,
+								Value:   `{"request": null, "status": 200}`,
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status:  200,
+						Body:    "data: {\"mode\": \"simulate\"}\n\ndata: {\"mode\": \"capture\"}\n\n",
+						Headers: map[string][]string{"Content-Type": {"text/event-stream"}},
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	modes := []string{}
+	err := TailJournal(target, v2.JournalEntryFilterView{Status: 200}, func(entry v2.JournalEntryView) {
+		modes = append(modes, entry.Mode)
+	})
+	Expect(err).To(BeNil())
+
+	Expect(modes).To(Equal([]string{"simulate", "capture"}))
+}
+
+func Test_TailJournal_ErrorsWhen_HoverflyReturnsError(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/journal/stream",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 500,
+						Body:   `{"error": "Journal disabled"}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	err := TailJournal(target, v2.JournalEntryFilterView{}, func(entry v2.JournalEntryView) {})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not stream journal\n\nJournal disabled"))
+}
+
+func Test_TailJournal_ErrorsWhen_HoverflyNotAccessible(t *testing.T) {
+	RegisterTestingT(t)
+
+	err := TailJournal(inaccessibleTarget, v2.JournalEntryFilterView{}, func(entry v2.JournalEntryView) {})
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not connect to Hoverfly at something:1234"))
+}
+
+func Test_VerifyJournal_SendsVerificationAndReturnsResult(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "POST",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/journal/verify",
+							},
+						},
+						Body: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Json,
+								Value:   `{"request": null, "status": 500, "never": true}`,
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"passed": false, "message": "Found 1 matching request, expected exactly 0", "count": 1, "matched": [{"mode": "simulate"}]}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	verification := v2.JournalVerifyView{Never: true}
+	verification.Status = 500
+
+	result, err := VerifyJournal(target, verification)
+	Expect(err).To(BeNil())
+
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Message).To(Equal("Found 1 matching request, expected exactly 0"))
+	Expect(result.Count).To(Equal(1))
+	Expect(result.Matched).To(HaveLen(1))
+}
+
+func Test_VerifyJournal_ErrorsWhen_HoverflyRejectsVerification(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/journal/verify",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 400,
+						Body:   `{"error": "No \"request\" object in verification"}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	_, err := VerifyJournal(target, v2.JournalVerifyView{})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not verify journal\n\nNo \"request\" object in verification"))
+}
+
+func Test_VerifyJournal_ErrorsWhen_HoverflyNotAccessible(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := VerifyJournal(inaccessibleTarget, v2.JournalVerifyView{})
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not connect to Hoverfly at something:1234"))
+}
@@ -20,6 +20,12 @@ func ExportSimulation(target configuration.Target, urlPattern string) ([]byte, e
 	if len(urlPattern) > 0 {
 		requestUrl = fmt.Sprintf("%s?urlPattern=%s", requestUrl, url.QueryEscape(urlPattern))
 	}
+
+	return exportJSON(target, requestUrl, "Could not retrieve simulation")
+}
+
+// exportJSON gets JSON from Hoverfly and indents it so that it can be written to a file
+func exportJSON(target configuration.Target, requestUrl, errorMessage string) ([]byte, error) {
 	response, err := doRequest(target, "GET", requestUrl, "", nil)
 	if err != nil {
 		return nil, err
@@ -27,7 +33,7 @@ func ExportSimulation(target configuration.Target, urlPattern string) ([]byte, e
 
 	defer response.Body.Close()
 
-	err = handleResponseError(response, "Could not retrieve simulation")
+	err = handleResponseError(response, errorMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -49,12 +55,32 @@ func ExportSimulation(target configuration.Target, urlPattern string) ([]byte, e
 }
 
 func ImportSimulation(target configuration.Target, simulationData string) error {
-	response, err := doRequest(target, "PUT", v2ApiSimulation, simulationData, nil)
+	return importSimulation(target, v2ApiSimulation, simulationData, "Could not import simulation")
+}
+
+// ImportOpenAPISpec replaces the simulation with one built from an OpenAPI 2 or 3 spec
+func ImportOpenAPISpec(target configuration.Target, spec string) error {
+	return importSimulation(target, v2ApiSimulationOpenAPI, spec, "Could not import OpenAPI spec")
+}
+
+// ImportWireMockMappings replaces the simulation with one built from WireMock stub mappings
+func ImportWireMockMappings(target configuration.Target, mappings string) error {
+	return importSimulation(target, v2ApiSimulationWireMock, mappings, "Could not import WireMock mappings")
+}
+
+// ImportPostmanCollection replaces the simulation with one built from the example
+// responses of a Postman collection
+func ImportPostmanCollection(target configuration.Target, collection string) error {
+	return importSimulation(target, v2ApiSimulationPostman, collection, "Could not import Postman collection")
+}
+
+func importSimulation(target configuration.Target, path, data, errorMessage string) error {
+	response, err := doRequest(target, "PUT", path, data, nil)
 	if err != nil {
 		return err
 	}
 
-	err = handleResponseError(response, "Could not import simulation")
+	err = handleResponseError(response, errorMessage)
 	if err != nil {
 		return err
 	}
@@ -96,6 +122,37 @@ func AddSimulation(target configuration.Target, simulationData string) error {
 	return nil
 }
 
+// LintSimulation finds the problems in a simulation, or in the simulation
+// loaded in Hoverfly when no simulation is given
+func LintSimulation(target configuration.Target, simulationData string) (v2.SimulationLintView, error) {
+	method := "GET"
+	if simulationData != "" {
+		method = "POST"
+	}
+
+	response, err := doRequest(target, method, v2ApiSimulationLint, simulationData, nil)
+	if err != nil {
+		return v2.SimulationLintView{}, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not lint simulation")
+	if err != nil {
+		return v2.SimulationLintView{}, err
+	}
+
+	responseBytes, _ := ioutil.ReadAll(response.Body)
+
+	var lintView v2.SimulationLintView
+	err = json.Unmarshal(responseBytes, &lintView)
+	if err != nil {
+		return v2.SimulationLintView{}, err
+	}
+
+	return lintView, nil
+}
+
 // Wipe will call the records endpoint in Hoverfly with a DELETE request, triggering Hoverfly to wipe the database
 func DeleteSimulations(target configuration.Target) error {
 	response, err := doRequest(target, "DELETE", v2ApiSimulation, "", nil)
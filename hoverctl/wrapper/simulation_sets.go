@@ -0,0 +1,100 @@
+package wrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
+)
+
+// GetSimulationSets gets the named simulations that are matched alongside the default simulation
+func GetSimulationSets(target configuration.Target) ([]v2.SimulationSetView, error) {
+	response, err := doRequest(target, "GET", v2ApiSimulations, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not retrieve simulations")
+	if err != nil {
+		return nil, err
+	}
+
+	responseBytes, _ := ioutil.ReadAll(response.Body)
+
+	var setsView v2.SimulationSetsView
+	err = json.Unmarshal(responseBytes, &setsView)
+	if err != nil {
+		return nil, err
+	}
+
+	return setsView.Simulations, nil
+}
+
+// AddSimulationSet adds a simulation to the named simulation, creating it if it does not
+// exist. The priority of the named simulation is only changed when it is given.
+func AddSimulationSet(target configuration.Target, name string, priority *int, simulationData string) error {
+	path := simulationSetPath(name)
+	if priority != nil {
+		path = path + "?priority=" + strconv.Itoa(*priority)
+	}
+
+	response, err := doRequest(target, "POST", path, simulationData, nil)
+	if err != nil {
+		return err
+	}
+
+	err = handleResponseError(response, "Could not add simulation")
+	if err != nil {
+		return err
+	}
+
+	responseBytes, _ := ioutil.ReadAll(response.Body)
+
+	result := &v2.SimulationImportResult{}
+	json.Unmarshal(responseBytes, result)
+
+	for _, warning := range result.WarningMessages {
+		fmt.Println(warning.Message)
+		fmt.Println(warning.DocsLink + "\n")
+	}
+
+	return nil
+}
+
+// UpdateSimulationSet enables or disables a named simulation, or changes its priority
+func UpdateSimulationSet(target configuration.Target, name string, settings v2.SimulationSetSettingsView) error {
+	settingsBytes, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	response, err := doRequest(target, "PATCH", simulationSetPath(name), string(settingsBytes), nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	return handleResponseError(response, "Could not update simulation")
+}
+
+func DeleteSimulationSet(target configuration.Target, name string) error {
+	response, err := doRequest(target, "DELETE", simulationSetPath(name), "", nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	return handleResponseError(response, "Could not delete simulation")
+}
+
+func simulationSetPath(name string) string {
+	return v2ApiSimulations + "/" + url.PathEscape(name)
+}
@@ -0,0 +1,48 @@
+package wrapper
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
+)
+
+// GetUnmatchedRequests gets the distinct requests that did not match the simulation
+func GetUnmatchedRequests(target configuration.Target) ([]v2.UnmatchedRequestView, error) {
+	response, err := doRequest(target, "GET", v2ApiUnmatched, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not retrieve unmatched requests")
+	if err != nil {
+		return nil, err
+	}
+
+	responseBytes, _ := ioutil.ReadAll(response.Body)
+
+	var unmatchedView v2.UnmatchedRequestsView
+	err = json.Unmarshal(responseBytes, &unmatchedView)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmatchedView.Unmatched, nil
+}
+
+// ExportUnmatchedRequests gets a simulation with a pair suggested for each unmatched request
+func ExportUnmatchedRequests(target configuration.Target) ([]byte, error) {
+	return exportJSON(target, v2ApiUnmatched+"?format=simulation", "Could not retrieve unmatched requests")
+}
+
+func DeleteUnmatchedRequests(target configuration.Target) error {
+	response, err := doRequest(target, "DELETE", v2ApiUnmatched, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return handleResponseError(response, "Could not delete unmatched requests")
+}